@@ -2,6 +2,7 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,25 +11,15 @@ import (
 	"github.com/sRRRs-7/GachaPon/token"
 )
 
-type CreateAccountRequest struct {
-	Owner string `json:"owner" binding:"required"`
-	Balance float64 `json:"balance" binding:"required"`
-}
-
+// CreateAccountApi opens an empty account for the authenticated user, currency
+// only comes in through purchases, rewards and the admin grants
 func (server *Server) CreateAccountApi(ctx *gin.Context) {
-	var req CreateAccountRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errRes(err))
-		return
-	}
-
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	arg := db.CreateAccountParams{
 		Owner: authPayload.Username,
-		Balance: int64(req.Balance),
 	}
 
-	account, err := server.store.CreateAccount(ctx, arg)
+	account, err := server.store.CreateAccountTx(ctx, arg)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code.Name() {
@@ -98,9 +89,12 @@ func (server *Server) ListAccountsApi(ctx *gin.Context) {
 
 type UpdateAccountRequest struct {
 	ID 		int64 `json:"id" binding:"required"`
-	Balance int64 `json:"balance" binding:"required"`
+	Balance int64 `json:"balance" binding:"min=0"`
+	ReferenceID string `json:"reference_id" binding:"max=64"`
 }
 
+// UpdateAccountApi sets the balance of an account, posting the difference to the
+// ledger. Only admins reach it.
 func (server *Server) UpdateAccountApi(ctx *gin.Context) {
 	var req UpdateAccountRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -108,44 +102,59 @@ func (server *Server) UpdateAccountApi(ctx *gin.Context) {
 		return
 	}
 
-	arg := db.UpdateAccountParams{
-		ID: req.ID,
-		Balance: req.Balance,
+	arg := db.LedgerTxParams{
+		AccountID: req.ID,
+		Amount: req.Balance,
+		Reason: db.ReasonAdminAdjustment,
+		ReferenceID: req.ReferenceID,
+		SetBalance: true,
 	}
 
-	account, err := server.store.UpdateAccount(ctx, arg)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errRes(err))
-		return
-	}
-
-	ctx.JSON(http.StatusOK, account)
+	server.postLedger(ctx, arg)
 }
 
 type UpdateBalanceRequest struct {
 	ID int64 `json:"id" binding:"required"`
-	Balance int64 `json:"balance" binding:"required"`
+	Amount int64 `json:"amount" binding:"required"`
+	Reason string `json:"reason" binding:"required,oneof=admin_grant refund"`
+	ReferenceID string `json:"reference_id" binding:"max=64"`
 }
 
+// UpdateBalanceApi credits or debits an account with a reason recorded in the
+// ledger. Only admins reach it.
 func (server *Server) UpdateBalanceApi(ctx *gin.Context) {
-	var req UpdateAccountRequest
+	var req UpdateBalanceRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errRes(err))
 		return
 	}
 
-	arg := db.UpdateAccountParams{
-		ID: req.ID,
-		Balance: req.Balance,
+	arg := db.LedgerTxParams{
+		AccountID: req.ID,
+		Amount: req.Amount,
+		Reason: req.Reason,
+		ReferenceID: req.ReferenceID,
 	}
 
-	account, err := server.store.UpdateAccount(ctx, arg)
+	server.postLedger(ctx, arg)
+}
+
+func (server *Server) postLedger(ctx *gin.Context, arg db.LedgerTxParams) {
+	result, err := server.store.LedgerTx(ctx, arg)
 	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		case errors.Is(err, db.ErrInsufficientBalance):
+			ctx.JSON(http.StatusPaymentRequired, errRes(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errRes(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, result.Account)
 }
 
 type DeleteAccountRequest struct {
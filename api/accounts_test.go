@@ -26,28 +26,22 @@ func TestCreateAccountAPI(t *testing.T) {
 
 	testCases := []struct {
 		name          string
-		body          gin.H
 		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(recoder *httptest.ResponseRecorder)
 	}{
 		{
 			name: "OK",
-			body: gin.H{
-				"owner": account.Owner,
-				"balance": account.Balance,
-			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				arg := db.CreateAccountParams{
-					Owner:    account.Owner,
-					Balance:  100,
+					Owner: account.Owner,
 				}
 
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Eq(arg)).
+					CreateAccountTx(gomock.Any(), gomock.Eq(arg)).
 					Times(1).
 					Return(account, nil)
 			},
@@ -58,15 +52,11 @@ func TestCreateAccountAPI(t *testing.T) {
 		},
 		{
 			name: "NoAuthorization",
-			body: gin.H{
-				"owner": account.Owner,
-				"balance": account.Balance,
-			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Any()).
+					CreateAccountTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
@@ -75,16 +65,12 @@ func TestCreateAccountAPI(t *testing.T) {
 		},
 		{
 			name: "InternalError",
-			body: gin.H{
-				"owner": account.Owner,
-				"balance": account.Balance,
-			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Any()).
+					CreateAccountTx(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(db.Account{}, sql.ErrConnDone)
 			},
@@ -107,12 +93,8 @@ func TestCreateAccountAPI(t *testing.T) {
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
-			// Marshal body data to JSON
-			data, err := json.Marshal(tc.body)
-			require.NoError(t, err)
-
 			url := "/account/create"
-			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			request, err := http.NewRequest(http.MethodPost, url, nil)
 			require.NoError(t, err)
 
 			tc.setupAuth(t, request, server.tokenMaker)
@@ -452,10 +434,168 @@ func TestListAccountsAPI(t *testing.T) {
 
 
 
+func TestUpdateBalanceAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+
+	credited := account
+	credited.Balance += 50
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"id":           account.ID,
+				"amount":       50,
+				"reason":       db.ReasonAdminGrant,
+				"reference_id": "ticket-1",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.LedgerTxParams{
+					AccountID:   account.ID,
+					Amount:      50,
+					Reason:      db.ReasonAdminGrant,
+					ReferenceID: "ticket-1",
+				}
+
+				store.EXPECT().
+					LedgerTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.LedgerTxResult{Account: credited}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, credited)
+			},
+		},
+		{
+			name: "UnknownReason",
+			body: gin.H{
+				"id":     account.ID,
+				"amount": 50,
+				"reason": db.ReasonGachaPull,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					LedgerTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InsufficientBalance",
+			body: gin.H{
+				"id":     account.ID,
+				"amount": -account.Balance - 1,
+				"reason": db.ReasonRefund,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					LedgerTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.LedgerTxResult{}, db.ErrInsufficientBalance)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPaymentRequired, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{
+				"id":     account.ID,
+				"amount": 50,
+				"reason": db.ReasonAdminGrant,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					LedgerTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.LedgerTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/account/updateBalance"
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAdminAuthorization(t, request, server.tokenMaker, store)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUpdateAccountAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	// the balance is set through an adjustment posted to the ledger
+	arg := db.LedgerTxParams{
+		AccountID:  account.ID,
+		Amount:     0,
+		Reason:     db.ReasonAdminAdjustment,
+		SetBalance: true,
+	}
+	emptied := account
+	emptied.Balance = 0
+
+	store.EXPECT().
+		LedgerTx(gomock.Any(), gomock.Eq(arg)).
+		Times(1).
+		Return(db.LedgerTxResult{Account: emptied}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{"id": account.ID, "balance": 0})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPut, "/account/updateAccount", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	addAdminAuthorization(t, request, server.tokenMaker, store)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	requireBodyMatchAccount(t, recorder.Body, emptied)
+}
+
 func randomAccount(username string) db.Account {
 	return db.Account{
 		ID:       utils.RandomInt(1, 100),
 		Owner:    username,
+		FreeBalance: 100,
 		Balance:  100,
 	}
 }
@@ -0,0 +1,586 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/gacha"
+)
+
+var (
+	errBannerInactive = errors.New("banner is not active")
+	errFreeMultiPull  = errors.New("multi_cost can't be 0 on a banner with a cost")
+	errNoGuarantee    = errors.New("no item of the banner reaches guarantee_rating")
+	errInvalidPity    = errors.New("soft_pity must be above 0 with a soft_pity_step and below hard_pity")
+)
+
+// defaultMultiCount is the number of pulls of a multi pull when a banner doesn't set it
+const defaultMultiCount = 10
+
+// defaultMultiDiscount is the percent off the pulls of a multi pull when a banner
+// doesn't set its cost, a 10 pull costing 9 single pulls
+const defaultMultiDiscount = 10
+
+// defaultSpendOrder spends free currency before paid currency when a banner doesn't set an order
+const defaultSpendOrder = db.SpendFreeFirst
+
+type CreateBannerRequest struct {
+	BannerName      string    `json:"banner_name" binding:"required"`
+	Cost            int64     `json:"cost" binding:"min=0"`
+	IsActive        bool      `json:"is_active"`
+	StartAt         time.Time `json:"start_at" binding:"required"`
+	EndAt           time.Time `json:"end_at" binding:"required,gtfield=StartAt"`
+	MultiCount      int32     `json:"multi_count" binding:"omitempty,min=1,max=100"`
+	MultiCost       *int64    `json:"multi_cost" binding:"omitempty,min=0"`
+	GuaranteeRating int32     `json:"guarantee_rating" binding:"min=0,max=7"`
+	SoftPity        int32     `json:"soft_pity" binding:"min=0"`
+	HardPity        int32     `json:"hard_pity" binding:"min=0"`
+	SoftPityStep    int64     `json:"soft_pity_step" binding:"min=0"`
+	SparkCost       int64     `json:"spark_cost" binding:"min=0"`
+	SpendOrder      string    `json:"spend_order" binding:"omitempty,oneof=free_first paid_first paid_only"`
+}
+
+// bannerMultiCost returns the cost of a multi pull of a banner, the discounted price of
+// its pulls when the request leaves it out. A multi pull of a banner with a cost is
+// never free.
+func bannerMultiCost(cost int64, multiCount int32, reqCost *int64) (int64, error) {
+	if reqCost == nil {
+		total := cost * int64(multiCount)
+		return total - total*defaultMultiDiscount/100, nil
+	}
+	if *reqCost == 0 && cost > 0 {
+		return 0, errFreeMultiPull
+	}
+	return *reqCost, nil
+}
+
+// checkPity validates the pity rules of a banner, a 0 disabling a pity. The soft
+// pity ramp needs a soft pity to start from and must start before the hard pity.
+func checkPity(soft, hard int32, step int64) error {
+	if step > 0 && soft == 0 {
+		return errInvalidPity
+	}
+	if soft > 0 && hard > 0 && soft >= hard {
+		return errInvalidPity
+	}
+	return nil
+}
+
+// guaranteeReachable writes a 400 and returns false when the guaranteed rating
+// of a banner can't be drawn
+func (server *Server) guaranteeReachable(ctx *gin.Context, bannerID int64, rating int32) bool {
+	err := server.checkGuarantee(ctx, bannerID, rating)
+	if err != nil {
+		if errors.Is(err, errNoGuarantee) {
+			ctx.JSON(http.StatusBadRequest, errRes(err))
+			return false
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return false
+	}
+	return true
+}
+
+func (server *Server) CreateBannerApi(ctx *gin.Context) {
+	var req CreateBannerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	multiCount := req.MultiCount
+	if multiCount == 0 {
+		multiCount = defaultMultiCount
+	}
+
+	multiCost, err := bannerMultiCost(req.Cost, multiCount, req.MultiCost)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	if err := checkPity(req.SoftPity, req.HardPity, req.SoftPityStep); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	if !server.guaranteeReachable(ctx, 0, req.GuaranteeRating) {
+		return
+	}
+
+	spendOrder := req.SpendOrder
+	if spendOrder == "" {
+		spendOrder = defaultSpendOrder
+	}
+
+	arg := db.CreateBannerParams{
+		BannerName:      req.BannerName,
+		Cost:            req.Cost,
+		IsActive:        req.IsActive,
+		StartAt:         req.StartAt,
+		EndAt:           req.EndAt,
+		MultiCount:      multiCount,
+		MultiCost:       multiCost,
+		GuaranteeRating: req.GuaranteeRating,
+		SoftPity:        req.SoftPity,
+		HardPity:        req.HardPity,
+		SoftPityStep:    req.SoftPityStep,
+		SparkCost:       req.SparkCost,
+		SpendOrder:      spendOrder,
+	}
+
+	banner, err := server.store.CreateBanner(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, banner)
+}
+
+type GetBannerRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) GetBannerApi(ctx *gin.Context) {
+	var req GetBannerRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	banner, err := server.store.GetBanner(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, banner)
+}
+
+type ListBannersRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=1,max=50"`
+}
+
+func (server *Server) ListBannersApi(ctx *gin.Context) {
+	var req ListBannersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	arg := db.ListBannersParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	}
+
+	banners, err := server.store.ListBanners(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, banners)
+}
+
+func (server *Server) ListActiveBannersApi(ctx *gin.Context) {
+	banners, err := server.store.ListActiveBanners(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, banners)
+}
+
+type UpdateBannerRequest struct {
+	ID              int64     `json:"id" binding:"required,min=1"`
+	BannerName      string    `json:"banner_name" binding:"required"`
+	Cost            int64     `json:"cost" binding:"min=0"`
+	IsActive        bool      `json:"is_active"`
+	StartAt         time.Time `json:"start_at" binding:"required"`
+	EndAt           time.Time `json:"end_at" binding:"required,gtfield=StartAt"`
+	MultiCount      int32     `json:"multi_count" binding:"required,min=1,max=100"`
+	MultiCost       *int64    `json:"multi_cost" binding:"omitempty,min=0"`
+	GuaranteeRating int32     `json:"guarantee_rating" binding:"min=0,max=7"`
+	SoftPity        int32     `json:"soft_pity" binding:"min=0"`
+	HardPity        int32     `json:"hard_pity" binding:"min=0"`
+	SoftPityStep    int64     `json:"soft_pity_step" binding:"min=0"`
+	SparkCost       int64     `json:"spark_cost" binding:"min=0"`
+	SpendOrder      string    `json:"spend_order" binding:"omitempty,oneof=free_first paid_first paid_only"`
+}
+
+func (server *Server) UpdateBannerApi(ctx *gin.Context) {
+	var req UpdateBannerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	multiCost, err := bannerMultiCost(req.Cost, req.MultiCount, req.MultiCost)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	if err := checkPity(req.SoftPity, req.HardPity, req.SoftPityStep); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	if !server.guaranteeReachable(ctx, req.ID, req.GuaranteeRating) {
+		return
+	}
+
+	spendOrder := req.SpendOrder
+	if spendOrder == "" {
+		spendOrder = defaultSpendOrder
+	}
+
+	arg := db.UpdateBannerParams{
+		ID:              req.ID,
+		BannerName:      req.BannerName,
+		Cost:            req.Cost,
+		IsActive:        req.IsActive,
+		StartAt:         req.StartAt,
+		EndAt:           req.EndAt,
+		MultiCount:      req.MultiCount,
+		MultiCost:       multiCost,
+		GuaranteeRating: req.GuaranteeRating,
+		SoftPity:        req.SoftPity,
+		HardPity:        req.HardPity,
+		SoftPityStep:    req.SoftPityStep,
+		SparkCost:       req.SparkCost,
+		SpendOrder:      spendOrder,
+	}
+
+	banner, err := server.store.UpdateBanner(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, banner)
+}
+
+type DeleteBannerRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) DeleteBannerApi(ctx *gin.Context) {
+	var req DeleteBannerRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	err := server.store.DeleteBanner(ctx, req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+type CreateBannerItemRequest struct {
+	BannerID   int64 `json:"banner_id" binding:"required,min=1"`
+	ItemID     int64 `json:"item_id" binding:"required,min=1"`
+	Weight     int64 `json:"weight" binding:"omitempty,min=0"`
+	IsFeatured bool  `json:"is_featured"`
+}
+
+func (server *Server) CreateBannerItemApi(ctx *gin.Context) {
+	var req CreateBannerItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	// items without an override share the tier evenly
+	weight := req.Weight
+	if weight == 0 {
+		weight = 1
+	}
+
+	arg := db.CreateBannerItemParams{
+		BannerID:   req.BannerID,
+		ItemID:     req.ItemID,
+		Weight:     weight,
+		IsFeatured: req.IsFeatured,
+	}
+
+	bannerItem, err := server.store.CreateBannerItem(ctx, arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation", "foreign_key_violation":
+				ctx.JSON(http.StatusForbidden, errRes(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, bannerItem)
+}
+
+type ListBannerItemsRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) ListBannerItemsApi(ctx *gin.Context) {
+	var req ListBannerItemsRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	bannerItems, err := server.store.ListBannerItems(ctx, req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, bannerItems)
+}
+
+type UpdateBannerItemRequest struct {
+	BannerID   int64 `json:"banner_id" binding:"required,min=1"`
+	ItemID     int64 `json:"item_id" binding:"required,min=1"`
+	Weight     int64 `json:"weight" binding:"min=0"`
+	IsFeatured bool  `json:"is_featured"`
+}
+
+func (server *Server) UpdateBannerItemApi(ctx *gin.Context) {
+	var req UpdateBannerItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	arg := db.UpdateBannerItemParams{
+		BannerID:   req.BannerID,
+		ItemID:     req.ItemID,
+		Weight:     req.Weight,
+		IsFeatured: req.IsFeatured,
+	}
+
+	bannerItem, err := server.store.UpdateBannerItem(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, bannerItem)
+}
+
+type DeleteBannerItemRequest struct {
+	BannerID int64 `uri:"id" binding:"required,min=1"`
+	ItemID   int64 `uri:"item_id" binding:"required,min=1"`
+}
+
+func (server *Server) DeleteBannerItemApi(ctx *gin.Context) {
+	var req DeleteBannerItemRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	arg := db.DeleteBannerItemParams{
+		BannerID: req.BannerID,
+		ItemID:   req.ItemID,
+	}
+
+	err := server.store.DeleteBannerItem(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+type ListBannerRatesRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) ListBannerRatesApi(ctx *gin.Context) {
+	var req ListBannerRatesRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	rates, err := server.store.ListBannerRates(ctx, req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rates)
+}
+
+type UpdateBannerRateRequest struct {
+	BannerID int64 `json:"banner_id" binding:"required,min=1"`
+	Rating   int32 `json:"rating" binding:"required,min=1,max=7"`
+	Weight   int64 `json:"weight" binding:"min=0"`
+}
+
+func (server *Server) UpdateBannerRateApi(ctx *gin.Context) {
+	var req UpdateBannerRateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	arg := db.UpsertBannerRateParams{
+		BannerID: req.BannerID,
+		Rating:   req.Rating,
+		Weight:   req.Weight,
+	}
+
+	rate, err := server.store.UpsertBannerRate(ctx, arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation":
+				ctx.JSON(http.StatusForbidden, errRes(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rate)
+}
+
+// bannerActive reports whether a banner can be drawn from at the given time
+func bannerActive(banner db.Banner, now time.Time) bool {
+	return banner.IsActive && !now.Before(banner.StartAt) && now.Before(banner.EndAt)
+}
+
+// bannerPity returns the pity rules of a banner
+func bannerPity(banner db.Banner) gacha.Pity {
+	return gacha.Pity{
+		Soft: banner.SoftPity,
+		Hard: banner.HardPity,
+		Step: banner.SoftPityStep,
+	}
+}
+
+// bannerPool builds the draw engine pool of a banner along with the items it was built from.
+// Banners without their own rates fall back to the global rarity rates.
+func (server *Server) bannerPool(ctx context.Context, banner db.Banner) (*gacha.Pool, []db.ListBannerPoolRow, error) {
+	rows, err := server.store.ListBannerPool(ctx, banner.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rates, err := server.bannerRates(ctx, banner.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pool, err := gacha.NewPool(poolEntries(rows), rates)
+	return pool, rows, err
+}
+
+// poolEntries returns the draw engine entries of the items of a banner
+func poolEntries(rows []db.ListBannerPoolRow) []gacha.Entry {
+	entries := make([]gacha.Entry, len(rows))
+	for i, row := range rows {
+		entries[i] = gacha.Entry{
+			ItemID: row.ItemID,
+			Rating: row.Rating,
+			Weight: row.Weight,
+		}
+	}
+	return entries
+}
+
+// bannerRates returns the rarity rates of a banner, the global ones when it
+// has none. A banner not created yet has id 0 and the global rates.
+func (server *Server) bannerRates(ctx context.Context, bannerID int64) (gacha.Rates, error) {
+	if bannerID > 0 {
+		bannerRates, err := server.store.ListBannerRates(ctx, bannerID)
+		if err != nil {
+			return nil, err
+		}
+		if len(bannerRates) > 0 {
+			rates := make(gacha.Rates, len(bannerRates))
+			for _, r := range bannerRates {
+				rates[r.Rating] = r.Weight
+			}
+			return rates, nil
+		}
+	}
+
+	globalRates, err := server.store.ListRarityRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newRates(globalRates), nil
+}
+
+// checkGuarantee fails with errNoGuarantee when the multi pulls of a banner can
+// never draw its guaranteed rating. A banner without items yet is checked
+// against its rates only, a banner not created yet has id 0.
+func (server *Server) checkGuarantee(ctx context.Context, bannerID int64, rating int32) error {
+	if rating == 0 {
+		return nil
+	}
+
+	var rows []db.ListBannerPoolRow
+	if bannerID > 0 {
+		var err error
+		rows, err = server.store.ListBannerPool(ctx, bannerID)
+		if err != nil {
+			return err
+		}
+	}
+
+	rates, err := server.bannerRates(ctx, bannerID)
+	if err != nil {
+		return err
+	}
+
+	if len(rows) == 0 {
+		for r, weight := range rates {
+			if r >= rating && weight > 0 {
+				return nil
+			}
+		}
+		return errNoGuarantee
+	}
+
+	pool, err := gacha.NewPool(poolEntries(rows), rates)
+	if err == nil {
+		_, err = pool.AtLeast(rating)
+	}
+	if errors.Is(err, gacha.ErrEmptyPool) {
+		return errNoGuarantee
+	}
+	return err
+}
@@ -0,0 +1,961 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	mockdb "github.com/sRRRs-7/GachaPon/db/mock"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBannerAPI(t *testing.T) {
+	banner := randomBanner()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"banner_name":      banner.BannerName,
+				"cost":             banner.Cost,
+				"is_active":        banner.IsActive,
+				"start_at":         banner.StartAt,
+				"end_at":           banner.EndAt,
+				"multi_count":      banner.MultiCount,
+				"multi_cost":       banner.MultiCost,
+				"guarantee_rating": banner.GuaranteeRating,
+				"soft_pity":        banner.SoftPity,
+				"hard_pity":        banner.HardPity,
+				"soft_pity_step":   banner.SoftPityStep,
+				"spark_cost":       banner.SparkCost,
+				"spend_order":      banner.SpendOrder,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateBannerParams{
+					BannerName:      banner.BannerName,
+					Cost:            banner.Cost,
+					IsActive:        banner.IsActive,
+					StartAt:         banner.StartAt,
+					EndAt:           banner.EndAt,
+					MultiCount:      banner.MultiCount,
+					MultiCost:       banner.MultiCost,
+					GuaranteeRating: banner.GuaranteeRating,
+					SoftPity:        banner.SoftPity,
+					HardPity:        banner.HardPity,
+					SoftPityStep:    banner.SoftPityStep,
+					SparkCost:       banner.SparkCost,
+					SpendOrder:      banner.SpendOrder,
+				}
+
+				// the banner has no rates of its own yet
+				store.EXPECT().
+					ListRarityRates(gomock.Any()).
+					Times(1).
+					Return(randomRarityRates(), nil)
+
+				store.EXPECT().
+					CreateBanner(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(banner, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchBanner(t, recorder.Body, banner)
+			},
+		},
+		{
+			name: "DefaultMultiCount",
+			body: gin.H{
+				"banner_name": banner.BannerName,
+				"cost":        banner.Cost,
+				"is_active":   banner.IsActive,
+				"start_at":    banner.StartAt,
+				"end_at":      banner.EndAt,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateBannerParams{
+					BannerName: banner.BannerName,
+					Cost:       banner.Cost,
+					IsActive:   banner.IsActive,
+					StartAt:    banner.StartAt,
+					EndAt:      banner.EndAt,
+					MultiCount: defaultMultiCount,
+					MultiCost:  banner.Cost * 9,
+					SpendOrder: defaultSpendOrder,
+				}
+
+				store.EXPECT().
+					CreateBanner(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(banner, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "FreeMultiPull",
+			body: gin.H{
+				"banner_name": banner.BannerName,
+				"cost":        banner.Cost,
+				"is_active":   banner.IsActive,
+				"start_at":    banner.StartAt,
+				"end_at":      banner.EndAt,
+				"multi_cost":  0,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateBanner(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "FreeBanner",
+			body: gin.H{
+				"banner_name": banner.BannerName,
+				"cost":        0,
+				"is_active":   banner.IsActive,
+				"start_at":    banner.StartAt,
+				"end_at":      banner.EndAt,
+				"multi_cost":  0,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateBannerParams{
+					BannerName: banner.BannerName,
+					IsActive:   banner.IsActive,
+					StartAt:    banner.StartAt,
+					EndAt:      banner.EndAt,
+					MultiCount: defaultMultiCount,
+					SpendOrder: defaultSpendOrder,
+				}
+
+				store.EXPECT().
+					CreateBanner(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(banner, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "UnreachableGuarantee",
+			body: gin.H{
+				"banner_name":      banner.BannerName,
+				"cost":             banner.Cost,
+				"is_active":        banner.IsActive,
+				"start_at":         banner.StartAt,
+				"end_at":           banner.EndAt,
+				"guarantee_rating": 7,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				rates := randomRarityRates()
+				rates[6].Weight = 0
+
+				store.EXPECT().
+					ListRarityRates(gomock.Any()).
+					Times(1).
+					Return(rates, nil)
+
+				store.EXPECT().
+					CreateBanner(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "SoftPityNotBelowHard",
+			body: gin.H{
+				"banner_name":    banner.BannerName,
+				"cost":           banner.Cost,
+				"is_active":      banner.IsActive,
+				"start_at":       banner.StartAt,
+				"end_at":         banner.EndAt,
+				"soft_pity":      80,
+				"hard_pity":      80,
+				"soft_pity_step": 600,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateBanner(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "SoftPityStepWithoutSoftPity",
+			body: gin.H{
+				"banner_name":    banner.BannerName,
+				"cost":           banner.Cost,
+				"is_active":      banner.IsActive,
+				"start_at":       banner.StartAt,
+				"end_at":         banner.EndAt,
+				"soft_pity":      0,
+				"hard_pity":      80,
+				"soft_pity_step": 600,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateBanner(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidGuaranteeRating",
+			body: gin.H{
+				"banner_name":      banner.BannerName,
+				"cost":             banner.Cost,
+				"is_active":        banner.IsActive,
+				"start_at":         banner.StartAt,
+				"end_at":           banner.EndAt,
+				"guarantee_rating": 8,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateBanner(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidSpendOrder",
+			body: gin.H{
+				"banner_name": banner.BannerName,
+				"cost":        banner.Cost,
+				"is_active":   banner.IsActive,
+				"start_at":    banner.StartAt,
+				"end_at":      banner.EndAt,
+				"spend_order": "paid_last",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateBanner(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "EndBeforeStart",
+			body: gin.H{
+				"banner_name": banner.BannerName,
+				"cost":        banner.Cost,
+				"is_active":   banner.IsActive,
+				"start_at":    banner.EndAt,
+				"end_at":      banner.StartAt,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateBanner(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NegativeCost",
+			body: gin.H{
+				"banner_name": banner.BannerName,
+				"cost":        -1,
+				"is_active":   banner.IsActive,
+				"start_at":    banner.StartAt,
+				"end_at":      banner.EndAt,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateBanner(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"banner_name": banner.BannerName,
+				"cost":        banner.Cost,
+				"is_active":   banner.IsActive,
+				"start_at":    banner.StartAt,
+				"end_at":      banner.EndAt,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateBanner(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Banner{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/banner/create"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAdminAuthorization(t, request, server.tokenMaker, store)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUpdateBannerAPI(t *testing.T) {
+	banner := randomBanner()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "DefaultMultiCost",
+			body: gin.H{
+				"id":          banner.ID,
+				"banner_name": banner.BannerName,
+				"cost":        banner.Cost,
+				"is_active":   banner.IsActive,
+				"start_at":    banner.StartAt,
+				"end_at":      banner.EndAt,
+				"multi_count": 5,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateBannerParams{
+					ID:         banner.ID,
+					BannerName: banner.BannerName,
+					Cost:       banner.Cost,
+					IsActive:   banner.IsActive,
+					StartAt:    banner.StartAt,
+					EndAt:      banner.EndAt,
+					MultiCount: 5,
+					MultiCost:  banner.Cost*5 - banner.Cost*5/10,
+					SpendOrder: defaultSpendOrder,
+				}
+
+				store.EXPECT().
+					UpdateBanner(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(banner, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchBanner(t, recorder.Body, banner)
+			},
+		},
+		{
+			name: "FreeMultiPull",
+			body: gin.H{
+				"id":          banner.ID,
+				"banner_name": banner.BannerName,
+				"cost":        banner.Cost,
+				"is_active":   banner.IsActive,
+				"start_at":    banner.StartAt,
+				"end_at":      banner.EndAt,
+				"multi_count": banner.MultiCount,
+				"multi_cost":  0,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateBanner(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "SoftPityAboveHard",
+			body: gin.H{
+				"id":             banner.ID,
+				"banner_name":    banner.BannerName,
+				"cost":           banner.Cost,
+				"is_active":      banner.IsActive,
+				"start_at":       banner.StartAt,
+				"end_at":         banner.EndAt,
+				"multi_count":    banner.MultiCount,
+				"multi_cost":     banner.MultiCost,
+				"soft_pity":      90,
+				"hard_pity":      80,
+				"soft_pity_step": 600,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateBanner(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "GuaranteeNotInPool",
+			body: gin.H{
+				"id":               banner.ID,
+				"banner_name":      banner.BannerName,
+				"cost":             banner.Cost,
+				"is_active":        banner.IsActive,
+				"start_at":         banner.StartAt,
+				"end_at":           banner.EndAt,
+				"multi_count":      banner.MultiCount,
+				"multi_cost":       banner.MultiCost,
+				"guarantee_rating": 4,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				pool := randomBannerPool()
+				for i := range pool {
+					pool[i].Rating = 3
+				}
+
+				store.EXPECT().
+					ListBannerPool(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(pool, nil)
+
+				store.EXPECT().
+					ListBannerRates(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return([]db.BannerRate{}, nil)
+
+				store.EXPECT().
+					ListRarityRates(gomock.Any()).
+					Times(1).
+					Return(randomRarityRates(), nil)
+
+				store.EXPECT().
+					UpdateBanner(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{
+				"id":          banner.ID,
+				"banner_name": banner.BannerName,
+				"cost":        banner.Cost,
+				"is_active":   banner.IsActive,
+				"start_at":    banner.StartAt,
+				"end_at":      banner.EndAt,
+				"multi_count": banner.MultiCount,
+				"multi_cost":  banner.MultiCost,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateBanner(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Banner{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/banner/update"
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAdminAuthorization(t, request, server.tokenMaker, store)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetBannerAPI(t *testing.T) {
+	banner := randomBanner()
+
+	testCases := []struct {
+		name          string
+		bannerID      int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			bannerID: banner.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(banner, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchBanner(t, recorder.Body, banner)
+			},
+		},
+		{
+			name:     "NotFound",
+			bannerID: banner.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(db.Banner{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidID",
+			bannerID: 0,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InternalError",
+			bannerID: banner.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(db.Banner{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/banner/get/%d", tc.bannerID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListActiveBannersAPI(t *testing.T) {
+	banners := []db.Banner{randomBanner(), randomBanner()}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListActiveBanners(gomock.Any()).
+					Times(1).
+					Return(banners, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchBanners(t, recorder.Body, banners)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListActiveBanners(gomock.Any()).
+					Times(1).
+					Return([]db.Banner{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := "/banner/listActive"
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCreateBannerItemAPI(t *testing.T) {
+	banner := randomBanner()
+	item := randomItem()
+	bannerItem := db.BannerItem{
+		ID:         utils.RandomInt(1, 100),
+		BannerID:   banner.ID,
+		ItemID:     item.ID,
+		Weight:     1,
+		IsFeatured: true,
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "DefaultWeight",
+			body: gin.H{
+				"banner_id":   banner.ID,
+				"item_id":     item.ID,
+				"is_featured": true,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateBannerItemParams{
+					BannerID:   banner.ID,
+					ItemID:     item.ID,
+					Weight:     1,
+					IsFeatured: true,
+				}
+
+				store.EXPECT().
+					CreateBannerItem(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(bannerItem, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "WeightOverride",
+			body: gin.H{
+				"banner_id": banner.ID,
+				"item_id":   item.ID,
+				"weight":    5,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateBannerItemParams{
+					BannerID: banner.ID,
+					ItemID:   item.ID,
+					Weight:   5,
+				}
+
+				store.EXPECT().
+					CreateBannerItem(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(bannerItem, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "DuplicateItem",
+			body: gin.H{
+				"banner_id": banner.ID,
+				"item_id":   item.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateBannerItem(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.BannerItem{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidItemID",
+			body: gin.H{
+				"banner_id": banner.ID,
+				"item_id":   0,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateBannerItem(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/banner/addItem"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAdminAuthorization(t, request, server.tokenMaker, store)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUpdateBannerRateAPI(t *testing.T) {
+	banner := randomBanner()
+	rate := db.BannerRate{
+		BannerID: banner.ID,
+		Rating:   7,
+		Weight:   utils.RandomInt(1, 100),
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"banner_id": rate.BannerID,
+				"rating":    rate.Rating,
+				"weight":    rate.Weight,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpsertBannerRateParams{
+					BannerID: rate.BannerID,
+					Rating:   rate.Rating,
+					Weight:   rate.Weight,
+				}
+
+				store.EXPECT().
+					UpsertBannerRate(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(rate, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "BannerNotFound",
+			body: gin.H{
+				"banner_id": rate.BannerID,
+				"rating":    rate.Rating,
+				"weight":    rate.Weight,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertBannerRate(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.BannerRate{}, &pq.Error{Code: "23503"})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidRating",
+			body: gin.H{
+				"banner_id": rate.BannerID,
+				"rating":    0,
+				"weight":    rate.Weight,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertBannerRate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/banner/updateRate"
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAdminAuthorization(t, request, server.tokenMaker, store)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func randomBanner() db.Banner {
+	now := time.Now().UTC().Truncate(time.Second)
+	return db.Banner{
+		ID:              utils.RandomInt(1, 100),
+		BannerName:      utils.RandomString(8),
+		Cost:            utils.RandomInt(1, 100),
+		IsActive:        true,
+		StartAt:         now.Add(-time.Hour),
+		EndAt:           now.Add(time.Hour),
+		MultiCount:      10,
+		MultiCost:       utils.RandomInt(100, 900),
+		GuaranteeRating: 4,
+		SoftPity:        60,
+		HardPity:        80,
+		SoftPityStep:    600,
+		SparkCost:       300,
+		SpendOrder:      db.SpendFreeFirst,
+	}
+}
+
+func randomBannerPool() []db.ListBannerPoolRow {
+	n := 5
+	pool := make([]db.ListBannerPoolRow, n)
+	for i := 0; i < n; i++ {
+		pool[i] = db.ListBannerPoolRow{
+			ItemID: int64(i + 1),
+			Weight: utils.RandomInt(1, 10),
+			Rating: int32(utils.RandomInt(1, 7)),
+		}
+	}
+	return pool
+}
+
+func requireBodyMatchBanner(t *testing.T, body *bytes.Buffer, banner db.Banner) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotBanner db.Banner
+	err = json.Unmarshal(data, &gotBanner)
+	require.NoError(t, err)
+	require.Equal(t, banner.ID, gotBanner.ID)
+	require.Equal(t, banner.BannerName, gotBanner.BannerName)
+	require.Equal(t, banner.Cost, gotBanner.Cost)
+	require.Equal(t, banner.IsActive, gotBanner.IsActive)
+	require.WithinDuration(t, banner.StartAt, gotBanner.StartAt, time.Second)
+	require.WithinDuration(t, banner.EndAt, gotBanner.EndAt, time.Second)
+	require.Equal(t, banner.MultiCount, gotBanner.MultiCount)
+	require.Equal(t, banner.MultiCost, gotBanner.MultiCost)
+	require.Equal(t, banner.GuaranteeRating, gotBanner.GuaranteeRating)
+}
+
+func requireBodyMatchBanners(t *testing.T, body *bytes.Buffer, banners []db.Banner) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotBanners []db.Banner
+	err = json.Unmarshal(data, &gotBanners)
+	require.NoError(t, err)
+	require.Len(t, gotBanners, len(banners))
+	for i := range banners {
+		require.Equal(t, banners[i].ID, gotBanners[i].ID)
+	}
+}
@@ -0,0 +1,269 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/token"
+)
+
+// codes of the redemption errors, so clients can tell the user why a code was refused
+const (
+	codeCouponInactive     = "coupon_inactive"
+	codeCouponExhausted    = "coupon_exhausted"
+	codeCouponLimitReached = "coupon_limit_reached"
+)
+
+// defaultPerUserLimit lets every user redeem a coupon once when it doesn't set a limit
+const defaultPerUserLimit = 1
+
+var errEmptyCoupon = errors.New("coupon grants nothing")
+
+// couponCode normalizes a code, codes being matched case insensitively
+func couponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+type CreateCouponRequest struct {
+	Code        string `json:"code" binding:"required,alphanum,min=4,max=32"`
+	Amount      int64  `json:"amount" binding:"min=0"`
+	ItemID      int64  `json:"item_id" binding:"omitempty,min=1"`
+	PullTickets int64  `json:"pull_tickets" binding:"min=0"`
+	// MaxRedemptions is the number of redemptions of all users, nil leaves it unlimited
+	MaxRedemptions *int64    `json:"max_redemptions" binding:"omitempty,min=1"`
+	PerUserLimit   int32     `json:"per_user_limit" binding:"omitempty,min=1"`
+	StartAt        time.Time `json:"start_at" binding:"required"`
+	EndAt          time.Time `json:"end_at" binding:"required,gtfield=StartAt"`
+}
+
+func (server *Server) CreateCouponApi(ctx *gin.Context) {
+	var req CreateCouponRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	if req.Amount == 0 && req.ItemID == 0 && req.PullTickets == 0 {
+		ctx.JSON(http.StatusBadRequest, errRes(errEmptyCoupon))
+		return
+	}
+
+	perUserLimit := req.PerUserLimit
+	if perUserLimit == 0 {
+		perUserLimit = defaultPerUserLimit
+	}
+
+	arg := db.CreateCouponParams{
+		Code:           couponCode(req.Code),
+		Amount:         req.Amount,
+		ItemID:         sql.NullInt64{Int64: req.ItemID, Valid: req.ItemID != 0},
+		PullTickets:    req.PullTickets,
+		MaxRedemptions: nullInt64(req.MaxRedemptions),
+		PerUserLimit:   perUserLimit,
+		StartAt:        req.StartAt,
+		EndAt:          req.EndAt,
+	}
+
+	coupon, err := server.store.CreateCoupon(ctx, arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation", "foreign_key_violation":
+				ctx.JSON(http.StatusForbidden, errRes(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, coupon)
+}
+
+type GetCouponRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) GetCouponApi(ctx *gin.Context) {
+	var req GetCouponRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	coupon, err := server.store.GetCoupon(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, coupon)
+}
+
+type ListCouponsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=1,max=50"`
+}
+
+func (server *Server) ListCouponsApi(ctx *gin.Context) {
+	var req ListCouponsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	arg := db.ListCouponsParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	}
+
+	coupons, err := server.store.ListCoupons(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, coupons)
+}
+
+type UpdateCouponRequest struct {
+	ID             int64     `json:"id" binding:"required,min=1"`
+	Amount         int64     `json:"amount" binding:"min=0"`
+	ItemID         int64     `json:"item_id" binding:"omitempty,min=1"`
+	PullTickets    int64     `json:"pull_tickets" binding:"min=0"`
+	MaxRedemptions *int64    `json:"max_redemptions" binding:"omitempty,min=1"`
+	PerUserLimit   int32     `json:"per_user_limit" binding:"required,min=1"`
+	StartAt        time.Time `json:"start_at" binding:"required"`
+	EndAt          time.Time `json:"end_at" binding:"required,gtfield=StartAt"`
+}
+
+// UpdateCouponApi replaces the bundle, limits and window of a coupon, its code
+// and the redemptions so far stay
+func (server *Server) UpdateCouponApi(ctx *gin.Context) {
+	var req UpdateCouponRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	if req.Amount == 0 && req.ItemID == 0 && req.PullTickets == 0 {
+		ctx.JSON(http.StatusBadRequest, errRes(errEmptyCoupon))
+		return
+	}
+
+	arg := db.UpdateCouponParams{
+		ID:             req.ID,
+		Amount:         req.Amount,
+		ItemID:         sql.NullInt64{Int64: req.ItemID, Valid: req.ItemID != 0},
+		PullTickets:    req.PullTickets,
+		MaxRedemptions: nullInt64(req.MaxRedemptions),
+		PerUserLimit:   req.PerUserLimit,
+		StartAt:        req.StartAt,
+		EndAt:          req.EndAt,
+	}
+
+	coupon, err := server.store.UpdateCoupon(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation":
+				ctx.JSON(http.StatusForbidden, errRes(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, coupon)
+}
+
+type DeleteCouponRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// DeleteCouponApi deletes a coupon nobody redeemed yet, redeemed coupons are
+// ended through their end_at instead
+func (server *Server) DeleteCouponApi(ctx *gin.Context) {
+	var req DeleteCouponRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	err := server.store.DeleteCoupon(ctx, req.ID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation":
+				ctx.JSON(http.StatusForbidden, errRes(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+type RedeemCouponRequest struct {
+	AccountID int64  `json:"account_id" binding:"required,min=1"`
+	Code      string `json:"code" binding:"required,max=64"`
+}
+
+// RedeemCouponApi grants the bundle of a code to an account of the authenticated user
+func (server *Server) RedeemCouponApi(ctx *gin.Context) {
+	var req RedeemCouponRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.RedeemCouponTxParams{
+		AccountID: req.AccountID,
+		Owner:     authPayload.Username,
+		Code:      couponCode(req.Code),
+		Now:       time.Now(),
+	}
+
+	result, err := server.store.RedeemCouponTx(ctx, arg)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		case errors.Is(err, db.ErrAccountNotOwned):
+			ctx.JSON(http.StatusUnauthorized, errRes(err))
+			return
+		case errors.Is(err, db.ErrCouponInactive):
+			ctx.JSON(http.StatusForbidden, errCodeRes(codeCouponInactive, err))
+			return
+		case errors.Is(err, db.ErrCouponExhausted):
+			ctx.JSON(http.StatusForbidden, errCodeRes(codeCouponExhausted, err))
+			return
+		case errors.Is(err, db.ErrCouponLimitReached):
+			ctx.JSON(http.StatusForbidden, errCodeRes(codeCouponLimitReached, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,384 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	mockdb "github.com/sRRRs-7/GachaPon/db/mock"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/token"
+	"github.com/sRRRs-7/GachaPon/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func randomCoupon() db.Coupon {
+	return db.Coupon{
+		ID:             utils.RandomInt(1, 100),
+		Code:           "LAUNCH2026",
+		Amount:         300,
+		ItemID:         sql.NullInt64{Int64: utils.RandomInt(1, 100), Valid: true},
+		PullTickets:    1,
+		MaxRedemptions: sql.NullInt64{Int64: 1000, Valid: true},
+		PerUserLimit:   1,
+		StartAt:        time.Now().UTC().Add(-time.Hour).Truncate(time.Second),
+		EndAt:          time.Now().UTC().Add(time.Hour).Truncate(time.Second),
+	}
+}
+
+func TestCreateCouponAPI(t *testing.T) {
+	coupon := randomCoupon()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"code":            "launch2026",
+				"amount":          coupon.Amount,
+				"item_id":         coupon.ItemID.Int64,
+				"pull_tickets":    coupon.PullTickets,
+				"max_redemptions": coupon.MaxRedemptions.Int64,
+				"start_at":        coupon.StartAt,
+				"end_at":          coupon.EndAt,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateCouponParams{
+					Code:           coupon.Code,
+					Amount:         coupon.Amount,
+					ItemID:         coupon.ItemID,
+					PullTickets:    coupon.PullTickets,
+					MaxRedemptions: coupon.MaxRedemptions,
+					PerUserLimit:   defaultPerUserLimit,
+					StartAt:        coupon.StartAt,
+					EndAt:          coupon.EndAt,
+				}
+
+				store.EXPECT().
+					CreateCoupon(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(coupon, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.Coupon
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, coupon.Code, got.Code)
+			},
+		},
+		{
+			name: "Unlimited",
+			body: gin.H{
+				"code":           coupon.Code,
+				"amount":         coupon.Amount,
+				"per_user_limit": 3,
+				"start_at":       coupon.StartAt,
+				"end_at":         coupon.EndAt,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateCouponParams{
+					Code:         coupon.Code,
+					Amount:       coupon.Amount,
+					PerUserLimit: 3,
+					StartAt:      coupon.StartAt,
+					EndAt:        coupon.EndAt,
+				}
+
+				store.EXPECT().
+					CreateCoupon(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(coupon, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "EmptyBundle",
+			body: gin.H{
+				"code":     coupon.Code,
+				"start_at": coupon.StartAt,
+				"end_at":   coupon.EndAt,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCoupon(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCode",
+			body: gin.H{
+				"code":     "LAUNCH-2026",
+				"amount":   coupon.Amount,
+				"start_at": coupon.StartAt,
+				"end_at":   coupon.EndAt,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCoupon(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "EndBeforeStart",
+			body: gin.H{
+				"code":     coupon.Code,
+				"amount":   coupon.Amount,
+				"start_at": coupon.EndAt,
+				"end_at":   coupon.StartAt,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCoupon(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "DuplicateCode",
+			body: gin.H{
+				"code":     coupon.Code,
+				"amount":   coupon.Amount,
+				"start_at": coupon.StartAt,
+				"end_at":   coupon.EndAt,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCoupon(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Coupon{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/coupon/create", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAdminAuthorization(t, request, server.tokenMaker, store)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestRedeemCouponAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+	coupon := randomCoupon()
+
+	redeemErr := func(err error) func(store *mockdb.MockStore) {
+		return func(store *mockdb.MockStore) {
+			store.EXPECT().
+				RedeemCouponTx(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return(db.RedeemCouponTxResult{}, err)
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"account_id": account.ID,
+				"code":       " launch2026 ",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RedeemCouponTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.RedeemCouponTxParams) (db.RedeemCouponTxResult, error) {
+						require.Equal(t, account.ID, arg.AccountID)
+						require.Equal(t, user.UserName, arg.Owner)
+						require.Equal(t, coupon.Code, arg.Code)
+						require.WithinDuration(t, time.Now(), arg.Now, time.Second)
+						return db.RedeemCouponTxResult{Account: account, Coupon: coupon}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownCode",
+			body: gin.H{
+				"account_id": account.ID,
+				"code":       "NOPE",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: redeemErr(sql.ErrNoRows),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Inactive",
+			body: gin.H{
+				"account_id": account.ID,
+				"code":       coupon.Code,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: redeemErr(db.ErrCouponInactive),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorCode(t, recorder, codeCouponInactive)
+			},
+		},
+		{
+			name: "Exhausted",
+			body: gin.H{
+				"account_id": account.ID,
+				"code":       coupon.Code,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: redeemErr(db.ErrCouponExhausted),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorCode(t, recorder, codeCouponExhausted)
+			},
+		},
+		{
+			name: "LimitReached",
+			body: gin.H{
+				"account_id": account.ID,
+				"code":       coupon.Code,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: redeemErr(db.ErrCouponLimitReached),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorCode(t, recorder, codeCouponLimitReached)
+			},
+		},
+		{
+			name: "NotOwned",
+			body: gin.H{
+				"account_id": account.ID,
+				"code":       coupon.Code,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+			},
+			buildStubs: redeemErr(db.ErrAccountNotOwned),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "MissingCode",
+			body: gin.H{
+				"account_id": account.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RedeemCouponTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{
+				"account_id": account.ID,
+				"code":       coupon.Code,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RedeemCouponTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/coupon/redeem", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
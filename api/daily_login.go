@@ -0,0 +1,186 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/token"
+)
+
+var (
+	errInvalidTimezone  = errors.New("unknown time zone")
+	errTimezoneCooldown = errors.New("time zone was changed too recently")
+)
+
+type DailyClaimRequest struct {
+	AccountID int64 `json:"account_id" binding:"required,min=1"`
+}
+
+// DailyClaimApi grants the login reward of the day, a second claim on the same
+// calendar day returns the first one with claimed set to false
+func (server *Server) DailyClaimApi(ctx *gin.Context) {
+	var req DailyClaimRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.DailyClaimTxParams{
+		AccountID: req.AccountID,
+		Owner:     authPayload.Username,
+		Now:       time.Now(),
+	}
+
+	result, err := server.store.DailyClaimTx(ctx, arg)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows, errors.Is(err, db.ErrNoLoginRewards):
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		case errors.Is(err, db.ErrAccountNotOwned):
+			ctx.JSON(http.StatusUnauthorized, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (server *Server) ListLoginRewardsApi(ctx *gin.Context) {
+	rewards, err := server.store.ListLoginRewards(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rewards)
+}
+
+type UpdateLoginRewardRequest struct {
+	Day    int32 `json:"day" binding:"required,min=1"`
+	Amount int64 `json:"amount" binding:"min=0"`
+	ItemID int64 `json:"item_id" binding:"omitempty,min=1"`
+}
+
+func (server *Server) UpdateLoginRewardApi(ctx *gin.Context) {
+	var req UpdateLoginRewardRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	arg := db.UpsertLoginRewardParams{
+		Day:    req.Day,
+		Amount: req.Amount,
+		ItemID: sql.NullInt64{Int64: req.ItemID, Valid: req.ItemID != 0},
+	}
+
+	reward, err := server.store.UpsertLoginReward(ctx, arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation":
+				ctx.JSON(http.StatusForbidden, errRes(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reward)
+}
+
+type DeleteLoginRewardRequest struct {
+	Day int32 `uri:"day" binding:"required,min=1"`
+}
+
+func (server *Server) DeleteLoginRewardApi(ctx *gin.Context) {
+	var req DeleteLoginRewardRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	err := server.store.DeleteLoginReward(ctx, req.Day)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+type UpdateTimezoneRequest struct {
+	Timezone string `json:"timezone" binding:"required"`
+}
+
+type UpdateTimezoneResponse struct {
+	UserName string `json:"user_name"`
+	Timezone string `json:"timezone"`
+}
+
+// UpdateTimezoneApi sets the time zone the calendar days of the authenticated user start in.
+// Moving east starts the next day early, so a user changes it once per cooldown.
+func (server *Server) UpdateTimezoneApi(ctx *gin.Context) {
+	var req UpdateTimezoneRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	// "Local" would follow the time zone of the server
+	if _, err := time.LoadLocation(req.Timezone); err != nil || req.Timezone == "Local" {
+		ctx.JSON(http.StatusBadRequest, errRes(errInvalidTimezone))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	if user.Timezone != req.Timezone {
+		changedBefore := time.Now().Add(-server.config.TimezoneCooldown)
+		if user.TimezoneUpdatedAt.Valid && user.TimezoneUpdatedAt.Time.After(changedBefore) {
+			ctx.JSON(http.StatusTooManyRequests, errRes(errTimezoneCooldown))
+			return
+		}
+
+		arg := db.UpdateUserTimezoneParams{
+			Timezone:      req.Timezone,
+			UserName:      user.UserName,
+			ChangedBefore: sql.NullTime{Time: changedBefore, Valid: true},
+		}
+
+		user, err = server.store.UpdateUserTimezone(ctx, arg)
+		if err != nil {
+			// another change got in since the user was read
+			if err == sql.ErrNoRows {
+				ctx.JSON(http.StatusTooManyRequests, errRes(errTimezoneCooldown))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errRes(err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, UpdateTimezoneResponse{
+		UserName: user.UserName,
+		Timezone: user.Timezone,
+	})
+}
@@ -2,21 +2,28 @@ package api
 
 import (
 	"database/sql"
-	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	db "github.com/sRRRs-7/GachaPon/db/sqlc"
 	"github.com/sRRRs-7/GachaPon/token"
 )
 
+// CreateExchangeRequest lets each side give up to 10 copies
 type CreateExchangeRequest struct {
-	FromAccountID int64 `json:"from_account_id" binding:"required"`
-	ToAccountID   int64 `json:"to_account_id" binding:"required"`
-	ItemID1       int64 `json:"item_id_1" binding:"required"`
-	ItemID2       int64 `json:"item_id_2" binding:"required"`
+	FromAccountID  int64   `json:"from_account_id" binding:"required"`
+	ToAccountID    int64   `json:"to_account_id" binding:"required,nefield=FromAccountID"`
+	FromGalleryIDs []int64 `json:"from_gallery_ids" binding:"max=10,dive,min=1"`
+	ToGalleryIDs   []int64 `json:"to_gallery_ids" binding:"max=10,dive,min=1"`
+	FromAmount     int64   `json:"from_amount" binding:"min=0"`
+	ToAmount       int64   `json:"to_amount" binding:"min=0"`
 }
 
+// CreateExchangeApi offers the copies FromGalleryIDs and the free currency
+// FromAmount of the from account for the copies ToGalleryIDs and the currency
+// ToAmount of the to account. The offer is approved by its proposer and
+// settled once the counterparty approves it too, unless it expires first.
 func (server *Server) CreateExchangeApi(ctx *gin.Context) {
 	var req CreateExchangeRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -24,37 +31,25 @@ func (server *Server) CreateExchangeApi(ctx *gin.Context) {
 		return
 	}
 
-	account ,err := server.store.GetAccount(ctx, req.FromAccountID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errRes(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errRes(err))
-		return
-	}
-
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if account.Owner != authPayload.Username {
-		err := errors.New("from account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errRes(err))
-		return
-	}
-
-	arg := db.ExchangeTxParams{
+	arg := db.CreateTradeOfferTxParams{
 		FromAccountID: req.FromAccountID,
+		Owner: authPayload.Username,
 		ToAccountID: req.ToAccountID,
-		ItemID1: req.ItemID1,
-		ItemID2: req.ItemID2,
+		FromGalleryIDs: req.FromGalleryIDs,
+		ToGalleryIDs: req.ToGalleryIDs,
+		FromAmount: req.FromAmount,
+		ToAmount: req.ToAmount,
+		ExpiresAt: server.tradeOfferExpiry(time.Now()),
 	}
 
-	result, err := server.store.ExchangeTx(ctx, arg)
+	offer, err := server.store.CreateTradeOfferTx(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		tradeErrRes(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, result)
+	ctx.JSON(http.StatusOK, offer)
 }
 
 type GetExchangeRequest struct {
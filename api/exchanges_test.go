@@ -25,18 +25,32 @@ func TestCreateExchangeAPI(t *testing.T) {
 	user2, _ := randomUser(t)
 	account1 := randomAccount(user1.UserName)
 	account2 := randomAccount(user2.UserName)
-	item1 := randomItem()
-	item2 := randomItem()
-	exchange1 := randomExchange(account1, account2, item1)
-	exchange2 := randomExchange(account2, account1, item2)
+	account2.ID = account1.ID + 1
 	gallery1 := randomGallery()
+	gallery1.OwnerID = account1.ID
 	gallery2 := randomGallery()
-
-	exchangeResult := db.ExchangeTxResult{
-		Exchange1: exchange1,
-		Exchange2: exchange2,
-		Gallery1: gallery1,
-		Gallery2: gallery2,
+	gallery2.ID = gallery1.ID + 1
+	gallery2.OwnerID = account2.ID
+	gallery3 := randomGallery()
+	gallery3.ID = gallery1.ID + 2
+	gallery3.OwnerID = account1.ID
+	offer := db.TradeOffer{
+		Approval: randomApproval(account1, account2),
+		Items: []db.TradeItem{
+			randomTradeItem(db.TradeSideRequest, gallery1),
+			randomTradeItem(db.TradeSideRequest, gallery3),
+			randomTradeItem(db.TradeSideResponse, gallery2),
+		},
+	}
+	offer.Approval.FromAmount = 50
+
+	// two copies and some currency for one copy
+	body := gin.H{
+		"from_account_id": account1.ID,
+		"to_account_id": account2.ID,
+		"from_gallery_ids": []int64{gallery1.ID, gallery3.ID},
+		"to_gallery_ids": []int64{gallery2.ID},
+		"from_amount": 50,
 	}
 
 	testCases := []struct {
@@ -48,78 +62,220 @@ func TestCreateExchangeAPI(t *testing.T) {
 	}{
 		{
 			name: "OK",
-			body: gin.H{
-				"from_account_id": exchange1.FromAccountID,
-				"to_account_id": exchange1.ToAccountID,
-				"item_id_1": item1.ID,
-				"item_id_2": item2.ID,
-			},
+			body: body,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.UserName, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().
-					GetAccount(gomock.Any(), gomock.Eq(account1.ID)).
-					Times(1).
-					Return(account1, nil)
-
-				arg := db.ExchangeTxParams{
-					FromAccountID: exchange1.FromAccountID,
-					ToAccountID: exchange1.ToAccountID,
-					ItemID1: item1.ID,
-					ItemID2: item2.ID,
+				arg := db.CreateTradeOfferTxParams{
+					FromAccountID: account1.ID,
+					Owner: user1.UserName,
+					ToAccountID: account2.ID,
+					FromGalleryIDs: []int64{gallery1.ID, gallery3.ID},
+					ToGalleryIDs: []int64{gallery2.ID},
+					FromAmount: 50,
 				}
 
 				store.EXPECT().
-					ExchangeTx(gomock.Any(), gomock.Eq(arg)).
+					CreateTradeOfferTx(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(exchangeResult, nil)
+					DoAndReturn(func(_ interface{}, got db.CreateTradeOfferTxParams) (db.TradeOffer, error) {
+						// offers stay open for the default TTL of the test config
+						require.WithinDuration(t, time.Now().Add(defaultTradeOfferTTL), got.ExpiresAt, time.Second)
+						got.ExpiresAt = time.Time{}
+						require.Equal(t, arg, got)
+						return offer, nil
+					})
+
+				// the copies are swapped only once the counterparty approves
+				store.EXPECT().
+					ExchangeTx(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchExchangeResult(t, recorder.Body, exchangeResult)
+
+				var got db.TradeOffer
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, offer.Approval.ID, got.Approval.ID)
+				require.True(t, got.Approval.FromAApproval)
+				require.False(t, got.Approval.ToAApproval)
+				require.Equal(t, int64(50), got.Approval.FromAmount)
+				require.Len(t, got.Items, 3)
 			},
 		},
 		{
 			name: "NoAuthorization",
+			body: body,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateTradeOfferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "FromAccountNotOwned",
+			body: body,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user2.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateTradeOfferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TradeOffer{}, db.ErrAccountNotOwned)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "SameAccount",
 			body: gin.H{
-				"from_account_id": exchange1.FromAccountID,
-				"to_account_id": exchange1.ToAccountID,
-				"item_id_1": item1.ID,
-				"item_id_2": item2.ID,
+				"from_account_id": account1.ID,
+				"to_account_id": account1.ID,
+				"from_gallery_ids": []int64{gallery1.ID},
+				"to_gallery_ids": []int64{gallery3.ID},
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.UserName, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					ExchangeTx(gomock.Any(), gomock.Any()).
+					CreateTradeOfferTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
-			name: "InternalError",
+			name: "TooManyItems",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id": account2.ID,
+				"from_gallery_ids": []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+				"to_gallery_ids": []int64{gallery2.ID},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateTradeOfferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NegativeAmount",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id": account2.ID,
+				"from_gallery_ids": []int64{gallery1.ID},
+				"to_amount": -10,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateTradeOfferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "EmptySide",
 			body: gin.H{
-				"from_account_id": exchange1.FromAccountID,
-				"to_account_id": exchange1.ToAccountID,
-				"item_id_1": item1.ID,
-				"item_id_2": item2.ID,
+				"from_account_id": account1.ID,
+				"to_account_id": account2.ID,
+				"from_gallery_ids": []int64{gallery1.ID},
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.UserName, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetAccount(gomock.Any(), gomock.Any()).
+					CreateTradeOfferTx(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(account1, nil)
-
+					Return(db.TradeOffer{}, db.ErrInvalidTrade)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "GalleryNotFound",
+			body: body,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					ExchangeTx(gomock.Any(), gomock.Any()).
+					CreateTradeOfferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TradeOffer{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "ItemNotOwned",
+			body: body,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				// the counterparty doesn't hold the copy asked for
+				store.EXPECT().
+					CreateTradeOfferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TradeOffer{}, db.ErrItemNotOwned)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorCode(t, recorder, codeItemNotOwned)
+			},
+		},
+		{
+			name: "InsufficientBalance",
+			body: body,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateTradeOfferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TradeOffer{}, db.ErrInsufficientBalance)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPaymentRequired, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: body,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateTradeOfferTx(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(db.ExchangeTxResult{}, sql.ErrConnDone)
+					Return(db.TradeOffer{}, sql.ErrConnDone)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -638,16 +794,6 @@ func randomExchange(account1, account2 db.Account, item db.Item) db.Exchange {
 	}
 }
 
-func requireBodyMatchExchangeResult(t *testing.T, body *bytes.Buffer, result db.ExchangeTxResult) {
-	data, err := ioutil.ReadAll(body)
-	require.NoError(t, err)
-
-	var gotResult db.ExchangeTxResult
-	err = json.Unmarshal(data, &gotResult)
-	require.NoError(t, err)
-	require.Equal(t, result, gotResult)
-}
-
 func requireBodyMatchExchange(t *testing.T, body *bytes.Buffer, exchange db.Exchange) {
 	data, err := ioutil.ReadAll(body)
 	require.NoError(t, err)
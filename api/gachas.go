@@ -2,59 +2,116 @@ package api
 
 import (
 	"database/sql"
-	"fmt"
-	"math/rand"
+	"errors"
 	"net/http"
-	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
 	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/gacha"
+	"github.com/sRRRs-7/GachaPon/token"
 )
 
+// codeAccountFlagged tells clients the account has to settle its debt before pulling
+const codeAccountFlagged = "account_flagged"
+
 type CreateGachaRequest struct {
 	AccountID int64 `json:"account_id" binding:"required,min=1"`
+	BannerID  int64 `json:"banner_id" binding:"required,min=1"`
+	// UseTickets pays every draw of the pull with a pull ticket instead of currency
+	UseTickets bool `json:"use_tickets"`
 }
 
 func (server *Server) CreateGachaApi(ctx *gin.Context) {
+	server.pullGacha(ctx, false)
+}
+
+// CreateMultiGachaApi draws the multi pull of a banner at its discounted cost
+func (server *Server) CreateMultiGachaApi(ctx *gin.Context) {
+	server.pullGacha(ctx, true)
+}
+
+// pullGacha draws from a banner and charges the account in a single transaction.
+// A multi pull uses the pull count, cost and guaranteed rating of the banner.
+func (server *Server) pullGacha(ctx *gin.Context, multi bool) {
 	var req CreateGachaRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusAccepted, errRes(err))
+		ctx.JSON(http.StatusBadRequest, errRes(err))
 		return
 	}
 
-	wg := &sync.WaitGroup{}
-
-	arg1 := db.ListItemsByIdParams{
-		Limit: 1000,
-		Offset: 0,
-	}
-	wg.Add(1)
-	items, err := server.store.ListItemsById(ctx, arg1)
+	banner, err := server.store.GetBanner(ctx, req.BannerID)
 	if err != nil {
-		fmt.Println("ListItemById error")
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errRes(err))
 		return
 	}
-	wg.Done()
 
-	wg.Add(1)
-	randomNum := rand.Int63n(int64(len(items) + 1))
-	item, err := server.store.GetItem(ctx, randomNum)
+	if !bannerActive(banner, time.Now()) {
+		ctx.JSON(http.StatusForbidden, errRes(errBannerInactive))
+		return
+	}
+
+	pool, _, err := server.bannerPool(ctx, banner)
 	if err != nil {
-		fmt.Println("GetItem error")
+		if errors.Is(err, gacha.ErrEmptyPool) {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errRes(err))
 		return
 	}
-	wg.Done()
 
-	arg2 := db.CreateGachaParams{
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.GachaTxParams{
 		AccountID: req.AccountID,
-		ItemID: item.ID,
+		BannerID: banner.ID,
+		Owner: authPayload.Username,
+		Cost: banner.Cost,
+		SpendOrder: banner.SpendOrder,
+		Pull: gacha.Pull{
+			Count: 1,
+			Pity: bannerPity(banner),
+		},
+		Pool: pool,
 	}
-	wg.Add(1)
-	gacha, err := server.store.CreateGacha(ctx, arg2)
+	if multi {
+		arg.Cost = banner.MultiCost
+		arg.Pull.Count = int(banner.MultiCount)
+		arg.Pull.MinRating = banner.GuaranteeRating
+	}
+	if req.UseTickets {
+		arg.Cost = 0
+		arg.Tickets = int64(arg.Pull.Count)
+	}
+
+	result, err := server.store.GachaTx(ctx, arg)
 	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		case errors.Is(err, db.ErrAccountNotOwned):
+			ctx.JSON(http.StatusUnauthorized, errRes(err))
+			return
+		case errors.Is(err, db.ErrInsufficientBalance), errors.Is(err, db.ErrInsufficientTickets):
+			ctx.JSON(http.StatusPaymentRequired, errRes(err))
+			return
+		case errors.Is(err, db.ErrSpendingCapExceeded):
+			ctx.JSON(http.StatusForbidden, errCodeRes(codeSpendingCapExceeded, err))
+			return
+		case errors.Is(err, db.ErrAccountFlagged):
+			ctx.JSON(http.StatusForbidden, errCodeRes(codeAccountFlagged, err))
+			return
+		case errors.Is(err, gacha.ErrEmptyPool):
+			// no item of the banner reaches its guaranteed rating
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code.Name() {
 			case "unique_violation", "foreign_key_violation":
@@ -65,28 +122,87 @@ func (server *Server) CreateGachaApi(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, errRes(err))
 		return
 	}
-	wg.Done()
 
-	wg.Add(1)
-	arg3 := db.CreateGalleryParams {
-		OwnerID: gacha.AccountID,
-		ItemID: gacha.ItemID,
+	ctx.JSON(http.StatusOK, result)
+}
+
+type GetPityRequest struct {
+	AccountID int64 `form:"account_id" binding:"required,min=1"`
+	BannerID  int64 `form:"banner_id" binding:"required,min=1"`
+}
+
+type PityResponse struct {
+	AccountID int64 `json:"account_id"`
+	BannerID  int64 `json:"banner_id"`
+	Pulls     int32 `json:"pulls"`
+	SoftPity  int32 `json:"soft_pity"`
+	HardPity  int32 `json:"hard_pity"`
+	// PullsToHardPity is the number of pulls left until the top rarity is guaranteed,
+	// 0 when the banner has no hard pity
+	PullsToHardPity int32 `json:"pulls_to_hard_pity"`
+}
+
+func (server *Server) GetPityApi(ctx *gin.Context) {
+	var req GetPityRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
 	}
-	gallery, err := server.store.CreateGallery(ctx, arg3)
+
+	account, err := server.store.GetAccount(ctx, req.AccountID)
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			switch pqErr.Code.Name() {
-			case "unique_violation", "foreign_key_violation":
-				ctx.JSON(http.StatusForbidden, errRes(err))
-				return
-			}
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
 		}
 		ctx.JSON(http.StatusInternalServerError, errRes(err))
 		return
 	}
-	wg.Done()
 
-	ctx.JSON(http.StatusOK, gallery)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		ctx.JSON(http.StatusUnauthorized, errRes(db.ErrAccountNotOwned))
+		return
+	}
+
+	banner, err := server.store.GetBanner(ctx, req.BannerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	arg := db.GetPityParams{
+		AccountID: account.ID,
+		BannerID: banner.ID,
+	}
+
+	// accounts that never pulled on the banner have no counter yet
+	pity, err := server.store.GetPity(ctx, arg)
+	if err != nil && err != sql.ErrNoRows {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	res := PityResponse{
+		AccountID: account.ID,
+		BannerID: banner.ID,
+		Pulls: pity.Pulls,
+		SoftPity: banner.SoftPity,
+		HardPity: banner.HardPity,
+	}
+	if banner.HardPity > 0 {
+		// a counter left above a lowered hard pity is still guaranteed on the next pull
+		res.PullsToHardPity = banner.HardPity - pity.Pulls
+		if res.PullsToHardPity < 1 {
+			res.PullsToHardPity = 1
+		}
+	}
+
+	ctx.JSON(http.StatusOK, res)
 }
 
 type GetGachaRequest struct {
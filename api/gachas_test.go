@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -15,6 +16,7 @@ import (
 	"github.com/golang/mock/gomock"
 	mockdb "github.com/sRRRs-7/GachaPon/db/mock"
 	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/gacha"
 	"github.com/sRRRs-7/GachaPon/token"
 	"github.com/sRRRs-7/GachaPon/utils"
 	"github.com/stretchr/testify/require"
@@ -22,10 +24,605 @@ import (
 
 func TestCreateGachaAPI(t *testing.T) {
 	user, _ := randomUser(t)
-	gacha := randomGacha()
-	item := randomItem()
-	items := randomItems()
-	gallery := randomGallery()
+	account := randomAccount(user.UserName)
+	banner := randomBanner()
+	pool := randomBannerPool()
+	rates := randomRarityRates()
+	result := db.GachaTxResult{
+		Account: account,
+		Gachas: []db.Gacha{randomGacha()},
+		Galleries: []db.Gallery{randomGallery()},
+	}
+
+	inactiveBanner := banner
+	inactiveBanner.IsActive = false
+
+	expiredBanner := banner
+	expiredBanner.EndAt = time.Now().Add(-time.Minute)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id": banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(banner, nil)
+
+				store.EXPECT().
+					ListBannerPool(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(pool, nil)
+
+				store.EXPECT().
+					ListBannerRates(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return([]db.BannerRate{}, nil)
+
+				store.EXPECT().
+					ListRarityRates(gomock.Any()).
+					Times(1).
+					Return(rates, nil)
+
+				store.EXPECT().
+					GachaTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.GachaTxParams) (db.GachaTxResult, error) {
+						require.Equal(t, account.ID, arg.AccountID)
+						require.Equal(t, user.UserName, arg.Owner)
+						require.Equal(t, banner.Cost, arg.Cost)
+						require.Equal(t, banner.ID, arg.BannerID)
+						require.Equal(t, banner.SpendOrder, arg.SpendOrder)
+						require.Equal(t, 1, arg.Pull.Count)
+						require.Zero(t, arg.Pull.MinRating)
+						require.Equal(t, bannerPity(banner), arg.Pull.Pity)
+						src := &gacha.FairSource{ServerSeed: "server", ClientSeed: "client"}
+						requireItemInPool(t, arg.Pool.Draw(src).ItemID, pool)
+						return result, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchGachaResult(t, recorder.Body, result)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id": banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GachaTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "MissingBannerID",
+			body: gin.H{
+				"account_id": account.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BannerNotFound",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id": banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(db.Banner{}, sql.ErrNoRows)
+
+				store.EXPECT().
+					GachaTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "BannerInactive",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id": banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(inactiveBanner, nil)
+
+				store.EXPECT().
+					ListBannerPool(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "BannerExpired",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id": banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(expiredBanner, nil)
+
+				store.EXPECT().
+					ListBannerPool(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "EmptyPool",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id": banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(banner, nil)
+
+				store.EXPECT().
+					ListBannerPool(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return([]db.ListBannerPoolRow{}, nil)
+
+				store.EXPECT().
+					ListBannerRates(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return([]db.BannerRate{}, nil)
+
+				store.EXPECT().
+					ListRarityRates(gomock.Any()).
+					Times(1).
+					Return(rates, nil)
+
+				store.EXPECT().
+					GachaTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "AccountNotFound",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id": banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(banner, nil)
+
+				store.EXPECT().
+					ListBannerPool(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(pool, nil)
+
+				store.EXPECT().
+					ListBannerRates(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return([]db.BannerRate{}, nil)
+
+				store.EXPECT().
+					ListRarityRates(gomock.Any()).
+					Times(1).
+					Return(rates, nil)
+
+				store.EXPECT().
+					GachaTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GachaTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "AccountNotOwned",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id": banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(banner, nil)
+
+				store.EXPECT().
+					ListBannerPool(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(pool, nil)
+
+				store.EXPECT().
+					ListBannerRates(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return([]db.BannerRate{}, nil)
+
+				store.EXPECT().
+					ListRarityRates(gomock.Any()).
+					Times(1).
+					Return(rates, nil)
+
+				store.EXPECT().
+					GachaTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GachaTxResult{}, db.ErrAccountNotOwned)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InsufficientBalance",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id": banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(banner, nil)
+
+				store.EXPECT().
+					ListBannerPool(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(pool, nil)
+
+				store.EXPECT().
+					ListBannerRates(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return([]db.BannerRate{}, nil)
+
+				store.EXPECT().
+					ListRarityRates(gomock.Any()).
+					Times(1).
+					Return(rates, nil)
+
+				store.EXPECT().
+					GachaTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GachaTxResult{}, db.ErrInsufficientBalance)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPaymentRequired, recorder.Code)
+			},
+		},
+		{
+			name: "UseTickets",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id": banner.ID,
+				"use_tickets": true,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(banner, nil)
+
+				store.EXPECT().
+					ListBannerPool(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(pool, nil)
+
+				store.EXPECT().
+					ListBannerRates(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return([]db.BannerRate{}, nil)
+
+				store.EXPECT().
+					ListRarityRates(gomock.Any()).
+					Times(1).
+					Return(rates, nil)
+
+				store.EXPECT().
+					GachaTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.GachaTxParams) (db.GachaTxResult, error) {
+						require.Zero(t, arg.Cost)
+						require.Equal(t, int64(1), arg.Tickets)
+						return result, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InsufficientTickets",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id": banner.ID,
+				"use_tickets": true,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(banner, nil)
+
+				store.EXPECT().
+					ListBannerPool(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(pool, nil)
+
+				store.EXPECT().
+					ListBannerRates(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return([]db.BannerRate{}, nil)
+
+				store.EXPECT().
+					ListRarityRates(gomock.Any()).
+					Times(1).
+					Return(rates, nil)
+
+				store.EXPECT().
+					GachaTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GachaTxResult{}, db.ErrInsufficientTickets)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPaymentRequired, recorder.Code)
+			},
+		},
+		{
+			name: "AccountFlagged",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id": banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(banner, nil)
+
+				store.EXPECT().
+					ListBannerPool(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(pool, nil)
+
+				store.EXPECT().
+					ListBannerRates(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return([]db.BannerRate{}, nil)
+
+				store.EXPECT().
+					ListRarityRates(gomock.Any()).
+					Times(1).
+					Return(rates, nil)
+
+				store.EXPECT().
+					GachaTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GachaTxResult{}, db.ErrAccountFlagged)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorCode(t, recorder, codeAccountFlagged)
+			},
+		},
+		{
+			name: "SpendingCapExceeded",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id": banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(banner, nil)
+
+				store.EXPECT().
+					ListBannerPool(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(pool, nil)
+
+				store.EXPECT().
+					ListBannerRates(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return([]db.BannerRate{}, nil)
+
+				store.EXPECT().
+					ListRarityRates(gomock.Any()).
+					Times(1).
+					Return(rates, nil)
+
+				store.EXPECT().
+					GachaTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GachaTxResult{}, db.ErrSpendingCapExceeded)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorCode(t, recorder, codeSpendingCapExceeded)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id": banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(banner, nil)
+
+				store.EXPECT().
+					ListBannerPool(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(pool, nil)
+
+				store.EXPECT().
+					ListBannerRates(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return([]db.BannerRate{}, nil)
+
+				store.EXPECT().
+					ListRarityRates(gomock.Any()).
+					Times(1).
+					Return(rates, nil)
+
+				store.EXPECT().
+					GachaTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GachaTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/gacha/create"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCreateMultiGachaAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+	banner := randomBanner()
+	pool := randomBannerPool()
+	rates := randomRarityRates()
+
+	result := db.GachaTxResult{Account: account}
+	for i := 0; i < int(banner.MultiCount); i++ {
+		result.Gachas = append(result.Gachas, randomGacha())
+		result.Galleries = append(result.Galleries, randomGallery())
+	}
+
+	buildPoolStubs := func(store *mockdb.MockStore) {
+		store.EXPECT().
+			GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+			Times(1).
+			Return(banner, nil)
+
+		store.EXPECT().
+			ListBannerPool(gomock.Any(), gomock.Eq(banner.ID)).
+			Times(1).
+			Return(pool, nil)
+
+		store.EXPECT().
+			ListBannerRates(gomock.Any(), gomock.Eq(banner.ID)).
+			Times(1).
+			Return([]db.BannerRate{}, nil)
+
+		store.EXPECT().
+			ListRarityRates(gomock.Any()).
+			Times(1).
+			Return(rates, nil)
+	}
 
 	testCases := []struct {
 		name          string
@@ -37,66 +634,232 @@ func TestCreateGachaAPI(t *testing.T) {
 		{
 			name: "OK",
 			body: gin.H{
-				"id": gacha.ID,
-				"account_id": gacha.AccountID,
-				"item_id": gacha.ItemID,
+				"account_id": account.ID,
+				"banner_id": banner.ID,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				arg0 := db.ListItemsByIdParams{
-					Limit: int32(1000),
-					Offset: 0,
-				}
+				buildPoolStubs(store)
 
-				arg1 := db.CreateGachaParams{
-					AccountID: gacha.AccountID,
-					ItemID: item.ID,
-				}
+				store.EXPECT().
+					GachaTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.GachaTxParams) (db.GachaTxResult, error) {
+						require.Equal(t, account.ID, arg.AccountID)
+						require.Equal(t, user.UserName, arg.Owner)
+						require.Equal(t, banner.MultiCost, arg.Cost)
+						require.Equal(t, int(banner.MultiCount), arg.Pull.Count)
+						require.Equal(t, banner.GuaranteeRating, arg.Pull.MinRating)
+						require.Equal(t, bannerPity(banner), arg.Pull.Pity)
+						return result, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchGachaResult(t, recorder.Body, result)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id": banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GachaTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InsufficientBalance",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id": banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				buildPoolStubs(store)
 
-				arg2 := db.CreateGalleryParams{
-					OwnerID: gacha.AccountID,
-					ItemID: gacha.ItemID,
-				}
+				store.EXPECT().
+					GachaTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GachaTxResult{}, db.ErrInsufficientBalance)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPaymentRequired, recorder.Code)
+			},
+		},
+		{
+			name: "UnreachableGuarantee",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id": banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				buildPoolStubs(store)
+
+				store.EXPECT().
+					GachaTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GachaTxResult{}, gacha.ErrEmptyPool)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/gacha/multi"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetPityAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+	other := randomAccount(utils.RandomString(6))
+	banner := randomBanner()
+	pity := db.Pity{
+		AccountID: account.ID,
+		BannerID: banner.ID,
+		Pulls: 25,
+	}
+
+	type Query struct {
+		accountID int64
+		bannerID  int64
+	}
+
+	testCases := []struct {
+		name          string
+		query         Query
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			query: Query{
+				accountID: account.ID,
+				bannerID: banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
 
 				store.EXPECT().
-					ListItemsById(gomock.Any(), gomock.Eq(arg0)).
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
 					Times(1).
-					Return(items, nil)
+					Return(banner, nil)
 
+				arg := db.GetPityParams{
+					AccountID: account.ID,
+					BannerID: banner.ID,
+				}
+				store.EXPECT().
+					GetPity(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(pity, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPity(t, recorder.Body, PityResponse{
+					AccountID: account.ID,
+					BannerID: banner.ID,
+					Pulls: pity.Pulls,
+					SoftPity: banner.SoftPity,
+					HardPity: banner.HardPity,
+					PullsToHardPity: banner.HardPity - pity.Pulls,
+				})
+			},
+		},
+		{
+			name: "NoPullsYet",
+			query: Query{
+				accountID: account.ID,
+				bannerID: banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetItem(gomock.Any(), gomock.Any()).
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
 					Times(1).
-					Return(item, nil)
+					Return(account, nil)
 
 				store.EXPECT().
-					CreateGacha(gomock.Any(), gomock.Eq(arg1)).
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
 					Times(1).
-					Return(gacha, nil)
+					Return(banner, nil)
 
 				store.EXPECT().
-					CreateGallery(gomock.Any(), gomock.Eq(arg2)).
+					GetPity(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(gallery, nil)
+					Return(db.Pity{}, sql.ErrNoRows)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchGallery(t, recorder.Body, gallery)
+				requireBodyMatchPity(t, recorder.Body, PityResponse{
+					AccountID: account.ID,
+					BannerID: banner.ID,
+					SoftPity: banner.SoftPity,
+					HardPity: banner.HardPity,
+					PullsToHardPity: banner.HardPity,
+				})
 			},
 		},
 		{
 			name: "NoAuthorization",
-			body: gin.H{
-				"id": gacha.ID,
-				"account_id": gacha.AccountID,
-				"item_id": gacha.ItemID,
+			query: Query{
+				accountID: account.ID,
+				bannerID: banner.ID,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateGacha(gomock.Any(), gomock.Any()).
+					GetAccount(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
@@ -104,35 +867,93 @@ func TestCreateGachaAPI(t *testing.T) {
 			},
 		},
 		{
-			name: "InternalError",
-			body: gin.H{
-				"id": gacha.ID,
-				"account_id": gacha.AccountID,
-				"item_id": gacha.ItemID,
+			name: "AccountNotOwned",
+			query: Query{
+				accountID: other.ID,
+				bannerID: banner.ID,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				arg0 := db.ListItemsByIdParams{
-					Limit: int32(1000),
-					Offset: 0,
-				}
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(other.ID)).
+					Times(1).
+					Return(other, nil)
+
+				store.EXPECT().
+					GetPity(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "BannerNotFound",
+			query: Query{
+				accountID: account.ID,
+				bannerID: banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
 
 				store.EXPECT().
-					ListItemsById(gomock.Any(), gomock.Eq(arg0)).
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(db.Banner{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "MissingBannerID",
+			query: Query{
+				accountID: account.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			query: Query{
+				accountID: account.ID,
+				bannerID: banner.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
 					Times(1).
-					Return(items, nil)
+					Return(account, nil)
 
 				store.EXPECT().
-					GetItem(gomock.Any(), gomock.Any()).
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
 					Times(1).
-					Return(item, nil)
+					Return(banner, nil)
 
 				store.EXPECT().
-					CreateGacha(gomock.Any(), gomock.Any()).
+					GetPity(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(db.Gacha{}, sql.ErrConnDone)
+					Return(db.Pity{}, sql.ErrConnDone)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -153,12 +974,16 @@ func TestCreateGachaAPI(t *testing.T) {
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
-			data, err := json.Marshal(tc.body)
+			url := "/gacha/pity"
+			request, err := http.NewRequest(http.MethodGet, url, nil)
 			require.NoError(t, err)
 
-			url := "/gacha/create"
-			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
-			require.NoError(t, err)
+			q := request.URL.Query()
+			q.Add("account_id", fmt.Sprintf("%d", tc.query.accountID))
+			if tc.query.bannerID != 0 {
+				q.Add("banner_id", fmt.Sprintf("%d", tc.query.bannerID))
+			}
+			request.URL.RawQuery = q.Encode()
 
 			tc.setupAuth(t, request, server.tokenMaker)
 			server.router.ServeHTTP(recorder, request)
@@ -167,6 +992,74 @@ func TestCreateGachaAPI(t *testing.T) {
 	}
 }
 
+// TestBannerPoolDistribution draws from the pool the gacha endpoints build and
+// checks the observed frequencies against the stored configuration
+func TestBannerPoolDistribution(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// fixed rows and seed keep the test deterministic
+	banner := randomBanner()
+	pool := []db.ListBannerPoolRow{
+		{ItemID: 1, Weight: 5, Rating: 1},
+		{ItemID: 2, Weight: 1, Rating: 1},
+		{ItemID: 3, Weight: 1, Rating: 3},
+		{ItemID: 4, Weight: 2, Rating: 5},
+		{ItemID: 5, Weight: 1, Rating: 5},
+		{ItemID: 6, Weight: 1, Rating: 7},
+	}
+	rates := []db.RarityRate{
+		{Rating: 1, Weight: 4000},
+		{Rating: 2, Weight: 2500},
+		{Rating: 3, Weight: 1500},
+		{Rating: 4, Weight: 1000},
+		{Rating: 5, Weight: 600},
+		{Rating: 6, Weight: 300},
+		{Rating: 7, Weight: 100},
+	}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListBannerPool(gomock.Any(), gomock.Eq(banner.ID)).
+		Times(1).
+		Return(pool, nil)
+
+	store.EXPECT().
+		ListBannerRates(gomock.Any(), gomock.Eq(banner.ID)).
+		Times(1).
+		Return([]db.BannerRate{}, nil)
+
+	store.EXPECT().
+		ListRarityRates(gomock.Any()).
+		Times(1).
+		Return(rates, nil)
+
+	server := newTestServer(t, store)
+	drawPool, _, err := server.bannerPool(context.Background(), banner)
+	require.NoError(t, err)
+
+	// expected odds straight from the stored rows
+	configured := make(gacha.Rates)
+	for _, r := range rates {
+		configured[r.Rating] = r.Weight
+	}
+	var entries []gacha.Entry
+	for _, e := range drawPool.Entries() {
+		for _, row := range pool {
+			if row.ItemID == e.ItemID {
+				entries = append(entries, gacha.Entry{ItemID: row.ItemID, Rating: row.Rating, Weight: row.Weight})
+			}
+		}
+	}
+	require.Len(t, entries, len(pool))
+
+	src := &gacha.FairSource{ServerSeed: "server", ClientSeed: "distribution"}
+	counts := gacha.Simulate(drawPool, src, 200000)
+
+	result := gacha.ChiSquare(counts, gacha.ConfiguredProbabilities(entries, configured))
+	require.Greater(t, result.PValue, 1e-3, "%+v", result)
+}
+
 func TestGetGachaApi(t *testing.T) {
 	user, _ := randomUser(t)
 	gacha := randomGacha()
@@ -486,6 +1379,15 @@ func randomGacha() db.Gacha {
 	}
 }
 
+func requireItemInPool(t *testing.T, itemID int64, pool []db.ListBannerPoolRow) {
+	for _, row := range pool {
+		if row.ItemID == itemID {
+			return
+		}
+	}
+	t.Fatalf("item %d was not drawn from the pool", itemID)
+}
+
 func requireBodyMatchGallery(t *testing.T, body *bytes.Buffer, gallery db.Gallery) {
 	data, err := ioutil.ReadAll(body)
 	require.NoError(t, err)
@@ -496,6 +1398,26 @@ func requireBodyMatchGallery(t *testing.T, body *bytes.Buffer, gallery db.Galler
 	require.Equal(t, gallery, gotGallery)
 }
 
+func requireBodyMatchGachaResult(t *testing.T, body *bytes.Buffer, result db.GachaTxResult) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotResult db.GachaTxResult
+	err = json.Unmarshal(data, &gotResult)
+	require.NoError(t, err)
+	require.Equal(t, result, gotResult)
+}
+
+func requireBodyMatchPity(t *testing.T, body *bytes.Buffer, pity PityResponse) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotPity PityResponse
+	err = json.Unmarshal(data, &gotPity)
+	require.NoError(t, err)
+	require.Equal(t, pity, gotPity)
+}
+
 func requireBodyMatchGacha(t *testing.T, body *bytes.Buffer, gacha db.Gacha) {
 	data, err := ioutil.ReadAll(body)
 	require.NoError(t, err)
@@ -94,4 +94,33 @@ func (server *Server) ListGalleriesByItemIdApi(ctx *gin.Context) {
 	}
 
 	ctx.JSON(http.StatusOK, gallery)
-}
\ No newline at end of file
+}
+
+type ListGalleryQuantitiesRequest struct {
+	OwnerID 	int64 `json:"owner_id" binding:"required,min=1"`
+	PageID  	int32 `json:"page_id" binding:"required,min=1"`
+	PageSize 	int32 `json:"page_size" binding:"required,min=1,max=50"`
+}
+
+// ListGalleryQuantitiesApi counts the copies an account holds of every item
+func (server *Server) ListGalleryQuantitiesApi(ctx *gin.Context) {
+	var req ListGalleryQuantitiesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	arg := db.ListGalleryQuantitiesParams{
+		OwnerID: req.OwnerID,
+		Limit: 	req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	}
+
+	quantities, err := server.store.ListGalleryQuantities(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, quantities)
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/token"
+)
+
+const (
+	idempotencyKeyHeader    = "Idempotency-Key"
+	maxIdempotencyKeyLength = 255
+	// defaultIdempotencyKeyTTL is how long keys are kept when the config doesn't set it
+	defaultIdempotencyKeyTTL = 24 * time.Hour
+)
+
+var (
+	errIdempotencyKeyTooLong    = errors.New("idempotency key is too long")
+	errIdempotencyKeyReused     = errors.New("idempotency key was already used for a different request")
+	errIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+// responseRecorder copies the response body written by the handlers
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// requestHash fingerprints a request by its method, URI and body
+func requestHash(method, uri string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + uri + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// idempotencyMiddleware makes state changing requests carrying an Idempotency-Key
+// header safe to retry. The first request with a key runs and its response is
+// stored, a retry with the same key and body gets the stored response back
+// without running the handler again.
+// A key reused for a different request is rejected. Keys are scoped to the
+// authenticated user, so it must run after authMiddleware, and expire after
+// the ttl, when they can be used again.
+func idempotencyMiddleware(store db.Store, ttl time.Duration) gin.HandlerFunc {
+	if ttl <= 0 {
+		ttl = defaultIdempotencyKeyTTL
+	}
+
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(idempotencyKeyHeader)
+		switch ctx.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			key = ""
+		}
+		if key == "" {
+			ctx.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, errRes(errIdempotencyKeyTooLong))
+			return
+		}
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, errRes(err))
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		arg := db.CreateIdempotencyKeyParams{
+			Username:       authPayload.Username,
+			IdempotencyKey: key,
+			RequestHash:    requestHash(ctx.Request.Method, ctx.Request.URL.RequestURI(), body),
+			ExpiresAt:      time.Now().Add(ttl),
+		}
+
+		// the insert returns no row when the key already exists and hasn't expired
+		_, err = store.CreateIdempotencyKey(ctx, arg)
+		if err == sql.ErrNoRows {
+			replayResponse(ctx, store, arg)
+			return
+		}
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errRes(err))
+			return
+		}
+
+		keyArg := db.DeleteIdempotencyKeyParams{
+			Username:       arg.Username,
+			IdempotencyKey: arg.IdempotencyKey,
+		}
+
+		// a panicking handler never gets a response stored, release the key so
+		// it isn't left in progress and let the recovery middleware answer
+		defer func() {
+			if r := recover(); r != nil {
+				store.DeleteIdempotencyKey(ctx, keyArg)
+				panic(r)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+		ctx.Next()
+
+		// a failed request may not have changed anything, so it can be retried with the same key
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			store.DeleteIdempotencyKey(ctx, keyArg)
+			return
+		}
+
+		_, err = store.UpdateIdempotencyKeyResponse(ctx, db.UpdateIdempotencyKeyResponseParams{
+			Username:       arg.Username,
+			IdempotencyKey: arg.IdempotencyKey,
+			ResponseStatus: int32(status),
+			ResponseBody:   recorder.body.Bytes(),
+			ContentType:    recorder.Header().Get("Content-Type"),
+		})
+		if err != nil {
+			// don't leave the key stuck in progress
+			store.DeleteIdempotencyKey(ctx, keyArg)
+		}
+	}
+}
+
+// replayResponse answers a request whose key was already used
+func replayResponse(ctx *gin.Context, store db.Store, arg db.CreateIdempotencyKeyParams) {
+	stored, err := store.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+		Username:       arg.Username,
+		IdempotencyKey: arg.IdempotencyKey,
+	})
+	if err != nil {
+		// the first request failed and released the key in the meantime
+		if err == sql.ErrNoRows {
+			ctx.AbortWithStatusJSON(http.StatusConflict, errRes(errIdempotencyKeyInProgress))
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	if stored.RequestHash != arg.RequestHash {
+		ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, errRes(errIdempotencyKeyReused))
+		return
+	}
+	if stored.ResponseStatus == 0 {
+		ctx.AbortWithStatusJSON(http.StatusConflict, errRes(errIdempotencyKeyInProgress))
+		return
+	}
+
+	ctx.Data(int(stored.ResponseStatus), stored.ContentType, stored.ResponseBody)
+	ctx.Abort()
+}
@@ -0,0 +1,297 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/sRRRs-7/GachaPon/db/mock"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	// the balance updates are posted by admins
+	user, _ := randomUser(t)
+	user.IsAdmin = true
+	account := randomAccount(user.UserName)
+	key := "retry-1"
+	url := "/account/updateBalance"
+
+	body, err := json.Marshal(gin.H{
+		"id":     account.ID,
+		"amount": 50,
+		"reason": db.ReasonAdminGrant,
+	})
+	require.NoError(t, err)
+	hash := requestHash(http.MethodPut, url, body)
+
+	credited := account
+	credited.Balance += 50
+	storedBody, err := json.Marshal(credited)
+	require.NoError(t, err)
+
+	createArg := db.CreateIdempotencyKeyParams{
+		Username:       user.UserName,
+		IdempotencyKey: key,
+		RequestHash:    hash,
+	}
+	keyArg := db.GetIdempotencyKeyParams{
+		Username:       user.UserName,
+		IdempotencyKey: key,
+	}
+
+	// createKey checks the key of the request is created with the default ttl
+	createKey := func(err error) func(_ interface{}, arg db.CreateIdempotencyKeyParams) (db.IdempotencyKey, error) {
+		return func(_ interface{}, arg db.CreateIdempotencyKeyParams) (db.IdempotencyKey, error) {
+			require.WithinDuration(t, time.Now().Add(defaultIdempotencyKeyTTL), arg.ExpiresAt, time.Second)
+			arg.ExpiresAt = createArg.ExpiresAt
+			require.Equal(t, createArg, arg)
+			return db.IdempotencyKey{}, err
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		key           string
+		body          []byte
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "FirstRequest",
+			key:  key,
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(createKey(nil))
+
+				store.EXPECT().
+					LedgerTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.LedgerTxResult{Account: credited}, nil)
+
+				store.EXPECT().
+					UpdateIdempotencyKeyResponse(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.UpdateIdempotencyKeyResponseParams) (db.IdempotencyKey, error) {
+						require.Equal(t, int32(http.StatusOK), arg.ResponseStatus)
+						require.JSONEq(t, string(storedBody), string(arg.ResponseBody))
+						require.Contains(t, arg.ContentType, "application/json")
+						return db.IdempotencyKey{}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, credited)
+			},
+		},
+		{
+			name: "Replay",
+			key:  key,
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(createKey(sql.ErrNoRows))
+
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Eq(keyArg)).
+					Times(1).
+					Return(db.IdempotencyKey{
+						Username:       user.UserName,
+						IdempotencyKey: key,
+						RequestHash:    hash,
+						ResponseStatus: http.StatusOK,
+						ResponseBody:   storedBody,
+						ContentType:    "application/json; charset=utf-8",
+					}, nil)
+
+				// the balance is not credited twice
+				store.EXPECT().
+					LedgerTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, credited)
+			},
+		},
+		{
+			name: "DifferentBody",
+			key:  key,
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, sql.ErrNoRows)
+
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Eq(keyArg)).
+					Times(1).
+					Return(db.IdempotencyKey{
+						RequestHash:    "other",
+						ResponseStatus: http.StatusOK,
+					}, nil)
+
+				store.EXPECT().
+					LedgerTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "InProgress",
+			key:  key,
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, sql.ErrNoRows)
+
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{RequestHash: hash}, nil)
+
+				store.EXPECT().
+					LedgerTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "InternalErrorReleasesKey",
+			key:  key,
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, nil)
+
+				store.EXPECT().
+					LedgerTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.LedgerTxResult{}, sql.ErrConnDone)
+
+				store.EXPECT().
+					DeleteIdempotencyKey(gomock.Any(), gomock.Eq(db.DeleteIdempotencyKeyParams(keyArg))).
+					Times(1).
+					Return(nil)
+
+				store.EXPECT().
+					UpdateIdempotencyKeyResponse(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "PanicReleasesKey",
+			key:  key,
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, nil)
+
+				store.EXPECT().
+					LedgerTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, _ db.LedgerTxParams) (db.LedgerTxResult, error) {
+						panic("boom")
+					})
+
+				store.EXPECT().
+					DeleteIdempotencyKey(gomock.Any(), gomock.Eq(db.DeleteIdempotencyKeyParams(keyArg))).
+					Times(1).
+					Return(nil)
+
+				store.EXPECT().
+					UpdateIdempotencyKeyResponse(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "NoKey",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				store.EXPECT().
+					LedgerTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.LedgerTxResult{Account: credited}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "KeyTooLong",
+			key:  strings.Repeat("k", maxIdempotencyKeyLength+1),
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUser(gomock.Any(), gomock.Eq(user.UserName)).
+				Times(1).
+				Return(user, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(tc.body))
+			require.NoError(t, err)
+			if tc.key != "" {
+				request.Header.Set(idempotencyKeyHeader, tc.key)
+			}
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/token"
+)
+
+type GetAccountLedgerUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type GetAccountLedgerQuery struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=1,max=50"`
+}
+
+type AccountLedgerResponse struct {
+	AccountID         int64                     `json:"account_id"`
+	Balance           int64                     `json:"balance"`
+	PaidBalance       int64                     `json:"paid_balance"`
+	FreeBalance       int64                     `json:"free_balance"`
+	LedgerBalance     int64                     `json:"ledger_balance"`
+	LedgerPaidBalance int64                     `json:"ledger_paid_balance"`
+	Reconciled        bool                      `json:"reconciled"`
+	Entries           []db.ListAccountLedgerRow `json:"entries"`
+}
+
+// GetAccountLedgerApi lists the ledger entries of an account, newest first,
+// along with its balances reconciled against the ledger
+func (server *Server) GetAccountLedgerApi(ctx *gin.Context) {
+	var uri GetAccountLedgerUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	var req GetAccountLedgerQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	reconciled, err := server.store.ReconcileAccount(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if reconciled.Account.Owner != authPayload.Username {
+		ctx.JSON(http.StatusUnauthorized, errRes(db.ErrAccountNotOwned))
+		return
+	}
+
+	arg := db.ListAccountLedgerParams{
+		AccountID: sql.NullInt64{Int64: uri.ID, Valid: true},
+		Limit:     req.PageSize,
+		Offset:    (req.PageID - 1) * req.PageSize,
+	}
+
+	entries, err := server.store.ListAccountLedger(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, AccountLedgerResponse{
+		AccountID:         reconciled.Account.ID,
+		Balance:           reconciled.Account.Balance,
+		PaidBalance:       reconciled.Account.PaidBalance,
+		FreeBalance:       reconciled.Account.FreeBalance,
+		LedgerBalance:     reconciled.LedgerBalance,
+		LedgerPaidBalance: reconciled.LedgerPaidBalance,
+		Reconciled:        reconciled.Difference == 0 && reconciled.PaidDifference == 0,
+		Entries:           entries,
+	})
+}
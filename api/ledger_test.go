@@ -0,0 +1,235 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/sRRRs-7/GachaPon/db/mock"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/token"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccountLedgerAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+
+	entries := []db.ListAccountLedgerRow{
+		{ID: 4, JournalID: 2, Bucket: db.BucketFree, Amount: -30, Reason: db.ReasonGachaPull, ReferenceID: "7"},
+		{ID: 1, JournalID: 1, Bucket: db.BucketFree, Amount: 130, Reason: db.ReasonOpeningBalance, ReferenceID: db.ReferenceID(account.ID)},
+	}
+	reconciled := db.ReconcileResult{
+		Account:       account,
+		LedgerBalance: account.Balance,
+	}
+
+	testCases := []struct {
+		name          string
+		accountID     int64
+		pageSize      int32
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			accountID: account.ID,
+			pageSize:  10,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReconcileAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(reconciled, nil)
+
+				arg := db.ListAccountLedgerParams{
+					AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+					Limit:     10,
+					Offset:    0,
+				}
+
+				store.EXPECT().
+					ListAccountLedger(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(entries, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got AccountLedgerResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, account.ID, got.AccountID)
+				require.Equal(t, account.Balance, got.LedgerBalance)
+				require.Equal(t, account.FreeBalance, got.FreeBalance)
+				require.Zero(t, got.PaidBalance)
+				require.True(t, got.Reconciled)
+				require.Equal(t, entries, got.Entries)
+			},
+		},
+		{
+			name:      "Unreconciled",
+			accountID: account.ID,
+			pageSize:  10,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReconcileAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(db.ReconcileResult{
+						Account:       account,
+						LedgerBalance: account.Balance - 10,
+						Difference:    10,
+					}, nil)
+
+				store.EXPECT().
+					ListAccountLedger(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(entries, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got AccountLedgerResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.False(t, got.Reconciled)
+			},
+		},
+		{
+			name:      "PaidBucketUnreconciled",
+			accountID: account.ID,
+			pageSize:  10,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReconcileAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(db.ReconcileResult{
+						Account:           account,
+						LedgerBalance:     account.Balance,
+						LedgerPaidBalance: 10,
+						PaidDifference:    -10,
+					}, nil)
+
+				store.EXPECT().
+					ListAccountLedger(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(entries, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got AccountLedgerResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, int64(10), got.LedgerPaidBalance)
+				require.False(t, got.Reconciled)
+			},
+		},
+		{
+			name:      "UnauthorizedUser",
+			accountID: account.ID,
+			pageSize:  10,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReconcileAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(reconciled, nil)
+
+				store.EXPECT().
+					ListAccountLedger(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "NotFound",
+			accountID: account.ID,
+			pageSize:  10,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReconcileAccount(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ReconcileResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidPageSize",
+			accountID: account.ID,
+			pageSize:  100,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReconcileAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			accountID: account.ID,
+			pageSize:  10,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReconcileAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/account/%d/ledger?page_id=1&page_size=%d", tc.accountID, tc.pageSize)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
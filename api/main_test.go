@@ -18,12 +18,14 @@ func TestMain(m *testing.M) {
 
 func newTestServer(t *testing.T, store db.Store) *Server {
 	config := utils.Config{
-		TokenSymmetricKey:   utils.RandomString(32),
-		AccessTokenDuration: time.Minute,
+		TokenSymmetricKey:    utils.RandomString(32),
+		AccessTokenDuration:  time.Minute,
+		PaymentWebhookSecret: utils.RandomString(32),
+		TimezoneCooldown:     24 * time.Hour,
 	}
 
 	server, err := NewServer(config, store)
 	require.NoError(t, err)
 
 	return server
-}
\ No newline at end of file
+}
@@ -0,0 +1,225 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/token"
+)
+
+// codes of the market requests failing on the state of a listing
+const (
+	codeItemListed    = "item_listed"
+	codeListingClosed = "listing_closed"
+)
+
+// marketErrRes writes the response of an error of a market transaction
+func marketErrRes(ctx *gin.Context, err error) {
+	switch {
+	case err == sql.ErrNoRows:
+		ctx.JSON(http.StatusNotFound, errRes(err))
+	case errors.Is(err, db.ErrAccountNotOwned):
+		ctx.JSON(http.StatusUnauthorized, errRes(err))
+	case errors.Is(err, db.ErrOwnListing):
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+	case errors.Is(err, db.ErrItemNotOwned):
+		ctx.JSON(http.StatusForbidden, errCodeRes(codeItemNotOwned, err))
+	case errors.Is(err, db.ErrItemListed):
+		// the copy is already held in escrow by another listing
+		ctx.JSON(http.StatusConflict, errCodeRes(codeItemListed, err))
+	case errors.Is(err, db.ErrItemLocked):
+		ctx.JSON(http.StatusConflict, errCodeRes(codeItemLocked, err))
+	case errors.Is(err, db.ErrListingClosed):
+		ctx.JSON(http.StatusForbidden, errCodeRes(codeListingClosed, err))
+	case errors.Is(err, db.ErrInsufficientBalance):
+		ctx.JSON(http.StatusPaymentRequired, errRes(err))
+	case errors.Is(err, db.ErrAccountFlagged):
+		ctx.JSON(http.StatusForbidden, errCodeRes(codeAccountFlagged, err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+	}
+}
+
+type CreateListingRequest struct {
+	AccountID int64 `json:"account_id" binding:"required,min=1"`
+	GalleryID int64 `json:"gallery_id" binding:"required,min=1"`
+	Price     int64 `json:"price" binding:"required,min=1"`
+}
+
+// CreateListingApi puts a copy of an account of the authenticated user on the
+// market for a price of free currency
+func (server *Server) CreateListingApi(ctx *gin.Context) {
+	var req CreateListingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.CreateListingTxParams{
+		AccountID: req.AccountID,
+		Owner:     authPayload.Username,
+		GalleryID: req.GalleryID,
+		Price:     req.Price,
+	}
+
+	listing, err := server.store.CreateListingTx(ctx, arg)
+	if err != nil {
+		marketErrRes(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, listing)
+}
+
+type ListingRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// GetListingApi returns a listing, sold and cancelled ones included
+func (server *Server) GetListingApi(ctx *gin.Context) {
+	var req ListingRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	listing, err := server.store.GetListing(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, listing)
+}
+
+type ListMarketListingsRequest struct {
+	// CategoryID, the ratings and the prices filter the listings, a zero
+	// category or max leaving them unfiltered
+	CategoryID int32 `form:"category_id" binding:"min=0"`
+	MinRating  int32 `form:"min_rating" binding:"min=0"`
+	MaxRating  int32 `form:"max_rating" binding:"min=0"`
+	MinPrice   int64 `form:"min_price" binding:"min=0"`
+	MaxPrice   int64 `form:"max_price" binding:"min=0"`
+	PageID     int32 `form:"page_id" binding:"required,min=1"`
+	PageSize   int32 `form:"page_size" binding:"required,min=1,max=50"`
+}
+
+// ListMarketListingsApi browses the active listings with their items, cheapest first
+func (server *Server) ListMarketListingsApi(ctx *gin.Context) {
+	var req ListMarketListingsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	arg := db.ListMarketListingsParams{
+		CategoryID: req.CategoryID,
+		MinRating:  req.MinRating,
+		MaxRating:  req.MaxRating,
+		MinPrice:   req.MinPrice,
+		MaxPrice:   req.MaxPrice,
+		PageLimit:  req.PageSize,
+		PageOffset: (req.PageID - 1) * req.PageSize,
+	}
+	if arg.MaxRating == 0 {
+		arg.MaxRating = math.MaxInt32
+	}
+	if arg.MaxPrice == 0 {
+		arg.MaxPrice = math.MaxInt64
+	}
+
+	listings, err := server.store.ListMarketListings(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, listings)
+}
+
+// ListSellerListingsApi lists the listings of an account of the authenticated user, newest first
+func (server *Server) ListSellerListingsApi(ctx *gin.Context) {
+	req, ok := server.bindTradeList(ctx)
+	if !ok {
+		return
+	}
+
+	arg := db.ListSellerListingsParams{
+		SellerID: req.AccountID,
+		Limit:    req.PageSize,
+		Offset:   (req.PageID - 1) * req.PageSize,
+	}
+
+	listings, err := server.store.ListSellerListings(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, listings)
+}
+
+// CancelListingApi takes a listing of the authenticated user off the market
+func (server *Server) CancelListingApi(ctx *gin.Context) {
+	var req ListingRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.CancelListingTxParams{
+		ListingID: req.ID,
+		Owner:     authPayload.Username,
+	}
+
+	listing, err := server.store.CancelListingTx(ctx, arg)
+	if err != nil {
+		marketErrRes(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, listing)
+}
+
+type BuyListingRequest struct {
+	AccountID int64 `json:"account_id" binding:"required,min=1"`
+	ListingID int64 `json:"listing_id" binding:"required,min=1"`
+}
+
+// BuyListingApi buys the copy of a listing for an account of the authenticated
+// user, the seller getting the price less the market fee
+func (server *Server) BuyListingApi(ctx *gin.Context) {
+	var req BuyListingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.BuyListingTxParams{
+		ListingID:  req.ListingID,
+		AccountID:  req.AccountID,
+		Owner:      authPayload.Username,
+		FeePercent: server.config.MarketFeePercent,
+		Now:        time.Now(),
+	}
+
+	result, err := server.store.BuyListingTx(ctx, arg)
+	if err != nil {
+		marketErrRes(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
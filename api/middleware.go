@@ -1,12 +1,14 @@
 package api
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
 	"github.com/sRRRs-7/GachaPon/token"
 )
 
@@ -49,4 +51,23 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		ctx.Set(authorizationPayloadKey, payload)
 		ctx.Next()
 	}
-}
\ No newline at end of file
+}
+var errNotAdmin = errors.New("user is not an admin")
+
+// adminMiddleware lets only admins through, it runs after authMiddleware
+func adminMiddleware(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		user, err := store.GetUser(ctx, authPayload.Username)
+		if err != nil && err != sql.ErrNoRows {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errRes(err))
+			return
+		}
+		if err == sql.ErrNoRows || !user.IsAdmin {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errRes(errNotAdmin))
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"database/sql"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -8,10 +9,16 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/sRRRs-7/GachaPon/db/mock"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
 	"github.com/sRRRs-7/GachaPon/token"
 	"github.com/stretchr/testify/require"
 )
 
+// adminUserName is the user the tests of the admin routes are authenticated as
+const adminUserName = "admin"
+
 func addAuthorization(
 	t *testing.T,
 	request *http.Request,
@@ -28,6 +35,17 @@ func addAuthorization(
 	request.Header.Set(authorizationHeaderKey, authorizationHeader)
 }
 
+// addAdminAuthorization authenticates the request as an admin, whose user the
+// admin middleware looks up once
+func addAdminAuthorization(t *testing.T, request *http.Request, tokenMaker token.Maker, store *mockdb.MockStore) {
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(adminUserName)).
+		Times(1).
+		Return(db.User{UserName: adminUserName, IsAdmin: true}, nil)
+
+	addAuthorization(t, request, tokenMaker, authorizationTypeBearer, adminUserName, time.Minute)
+}
+
 func TestAuthMiddleware(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -105,4 +123,155 @@ func TestAuthMiddleware(t *testing.T) {
 			tc.checkResponse(t, recorder)
 		})
 	}
-}
\ No newline at end of file
+}
+func TestAdminMiddleware(t *testing.T) {
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq("user")).
+					Times(1).
+					Return(db.User{UserName: "user", IsAdmin: true}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotAdmin",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq("user")).
+					Times(1).
+					Return(db.User{UserName: "user"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "UserNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq("user")).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+
+			adminPath := "/admin"
+			server.router.GET(
+				adminPath,
+				authMiddleware(server.tokenMaker),
+				adminMiddleware(server.store),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequest("GET", adminPath, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, "user", time.Minute)
+			server.router.ServeHTTP(recorder, req)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+// TestAdminRoutes checks every admin route turns away anonymous and player requests
+func TestAdminRoutes(t *testing.T) {
+	routes := []struct {
+		method string
+		url    string
+	}{
+		{http.MethodPut, "/rate/update"},
+		{http.MethodPost, "/banner/create"},
+		{http.MethodPut, "/banner/update"},
+		{http.MethodDelete, "/banner/delete/1"},
+		{http.MethodPost, "/banner/addItem"},
+		{http.MethodPut, "/banner/updateItem"},
+		{http.MethodDelete, "/banner/removeItem/1/1"},
+		{http.MethodPut, "/banner/updateRate"},
+		{http.MethodPut, "/account/updateAccount"},
+		{http.MethodPut, "/account/updateBalance"},
+		{http.MethodPost, "/spendingCap/create"},
+		{http.MethodGet, "/spendingCap/list"},
+		{http.MethodPut, "/spendingCap/update"},
+		{http.MethodDelete, "/spendingCap/delete/1"},
+		{http.MethodPost, "/coupon/create"},
+		{http.MethodGet, "/coupon/get/1"},
+		{http.MethodGet, "/coupon/list"},
+		{http.MethodPut, "/coupon/update"},
+		{http.MethodDelete, "/coupon/delete/1"},
+		{http.MethodPost, "/shop/create"},
+		{http.MethodGet, "/shop/get/1"},
+		{http.MethodGet, "/shop/list"},
+		{http.MethodPut, "/shop/update"},
+		{http.MethodDelete, "/shop/delete/1"},
+		{http.MethodPost, "/shop/rotate"},
+		{http.MethodPut, "/loginReward/update"},
+		{http.MethodDelete, "/loginReward/delete/1"},
+	}
+
+	for _, route := range routes {
+		t.Run(route.method+" "+route.url, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUser(gomock.Any(), gomock.Eq("player")).
+				Times(1).
+				Return(db.User{UserName: "player"}, nil)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(route.method, route.url, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+			recorder = httptest.NewRecorder()
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "player", time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusForbidden, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/payments"
+	"github.com/sRRRs-7/GachaPon/token"
+)
+
+const paymentSignatureHeader = "X-Payment-Signature"
+
+var errPackNotFound = errors.New("pack not found")
+
+func (server *Server) ListPacksApi(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, payments.ListPacks())
+}
+
+type CreateOrderRequest struct {
+	AccountID int64  `json:"account_id" binding:"required,min=1"`
+	PackID    string `json:"pack_id" binding:"required"`
+}
+
+type OrderResponse struct {
+	Order    db.Order          `json:"order"`
+	Checkout payments.Checkout `json:"checkout"`
+}
+
+// CreateOrderApi opens a pending order for a pack and starts its checkout at the payment provider.
+// The balance is credited once the provider reports the payment on the webhook.
+func (server *Server) CreateOrderApi(ctx *gin.Context) {
+	var req CreateOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	pack, ok := payments.FindPack(req.PackID)
+	if !ok {
+		ctx.JSON(http.StatusNotFound, errRes(errPackNotFound))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, req.AccountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		ctx.JSON(http.StatusUnauthorized, errRes(db.ErrAccountNotOwned))
+		return
+	}
+
+	arg := db.CreateOrderParams{
+		AccountID: account.ID,
+		PackID:    pack.ID,
+		Amount:    pack.Amount,
+		Price:     pack.Price,
+		Currency:  pack.Currency,
+		Provider:  server.payments.Name(),
+	}
+
+	order, err := server.store.CreateOrderTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrSpendingCapExceeded) {
+			ctx.JSON(http.StatusForbidden, errCodeRes(codeSpendingCapExceeded, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	checkout, err := server.payments.CreateCheckout(ctx, payments.CheckoutRequest{
+		OrderID:  order.ID,
+		Username: authPayload.Username,
+		Pack:     pack,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusBadGateway, errRes(err))
+		return
+	}
+
+	order, err = server.store.UpdateOrderProviderRef(ctx, db.UpdateOrderProviderRefParams{
+		ID:          order.ID,
+		ProviderRef: checkout.ProviderRef,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, OrderResponse{Order: order, Checkout: checkout})
+}
+
+type GetOrderRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) GetOrderApi(ctx *gin.Context) {
+	var req GetOrderRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	order, err := server.store.GetOrder(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, order.AccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		ctx.JSON(http.StatusUnauthorized, errRes(db.ErrAccountNotOwned))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, order)
+}
+
+// PaymentWebhookApi applies a payment event signed by the provider to its order.
+// Events already applied are acknowledged again without crediting twice.
+// Refunds and chargebacks revoke pulled copies when the config enables it.
+func (server *Server) PaymentWebhookApi(ctx *gin.Context) {
+	payload, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	event, err := server.payments.VerifyWebhook(payload, ctx.GetHeader(paymentSignatureHeader))
+	if err != nil {
+		if errors.Is(err, payments.ErrInvalidSignature) {
+			ctx.JSON(http.StatusUnauthorized, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	arg := db.PaymentEventTxParams{
+		OrderID:     event.OrderID,
+		Event:       event.Type,
+		ProviderRef: event.ProviderRef,
+		RevokeItems: server.config.ReversalRevokeItems,
+	}
+
+	result, err := server.store.PaymentEventTx(ctx, arg)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		case errors.Is(err, db.ErrProviderRefMismatch):
+			ctx.JSON(http.StatusBadRequest, errRes(err))
+			return
+		case errors.Is(err, payments.ErrInvalidTransition):
+			ctx.JSON(http.StatusConflict, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
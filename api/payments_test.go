@@ -0,0 +1,528 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/sRRRs-7/GachaPon/db/mock"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/payments"
+	"github.com/sRRRs-7/GachaPon/token"
+	"github.com/sRRRs-7/GachaPon/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateOrderAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+	pack, _ := payments.FindPack("small")
+	order := randomOrder(account, pack)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"account_id": account.ID,
+				"pack_id":    pack.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				arg := db.CreateOrderParams{
+					AccountID: account.ID,
+					PackID:    pack.ID,
+					Amount:    pack.Amount,
+					Price:     pack.Price,
+					Currency:  pack.Currency,
+					Provider:  payments.FakeProviderName,
+				}
+
+				store.EXPECT().
+					CreateOrderTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(order, nil)
+
+				store.EXPECT().
+					UpdateOrderProviderRef(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.UpdateOrderProviderRefParams) (db.Order, error) {
+						require.Equal(t, order.ID, arg.ID)
+						updated := order
+						updated.ProviderRef = arg.ProviderRef
+						return updated, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got OrderResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, payments.StatusPending, got.Order.Status)
+				require.NotEmpty(t, got.Checkout.PaymentURL)
+				require.Equal(t, got.Checkout.ProviderRef, got.Order.ProviderRef)
+			},
+		},
+		{
+			name: "SpendingCapExceeded",
+			body: gin.H{
+				"account_id": account.ID,
+				"pack_id":    pack.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				store.EXPECT().
+					CreateOrderTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Order{}, db.ErrSpendingCapExceeded)
+
+				store.EXPECT().
+					UpdateOrderProviderRef(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorCode(t, recorder, codeSpendingCapExceeded)
+			},
+		},
+		{
+			name: "UnknownPack",
+			body: gin.H{
+				"account_id": account.ID,
+				"pack_id":    "missing",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateOrderTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			body: gin.H{
+				"account_id": account.ID,
+				"pack_id":    pack.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				store.EXPECT().
+					CreateOrderTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{
+				"account_id": account.ID,
+				"pack_id":    pack.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateOrderTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/payment/order", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetOrderAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+	pack, _ := payments.FindPack("small")
+	order := randomOrder(account, pack)
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user.UserName,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetOrder(gomock.Any(), gomock.Eq(order.ID)).
+					Times(1).
+					Return(order, nil)
+
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.Order
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, order.ID, got.ID)
+			},
+		},
+		{
+			name:     "UnauthorizedUser",
+			username: "unauthorized_user",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetOrder(gomock.Any(), gomock.Eq(order.ID)).
+					Times(1).
+					Return(order, nil)
+
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			username: user.UserName,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetOrder(gomock.Any(), gomock.Eq(order.ID)).
+					Times(1).
+					Return(db.Order{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/payment/order/%d", order.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestPaymentWebhookAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+	pack, _ := payments.FindPack("small")
+	order := randomOrder(account, pack)
+
+	event := payments.Event{
+		Type:        payments.EventPaid,
+		OrderID:     order.ID,
+		ProviderRef: order.ProviderRef,
+	}
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	credited := order
+	credited.Status = payments.StatusCredited
+
+	sign := func(server *Server, payload []byte) string {
+		return server.payments.(*payments.FakeProvider).Sign(payload)
+	}
+
+	testCases := []struct {
+		name          string
+		signature     func(server *Server) string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			signature: func(server *Server) string {
+				return sign(server, payload)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.PaymentEventTxParams{
+					OrderID:     order.ID,
+					Event:       payments.EventPaid,
+					ProviderRef: order.ProviderRef,
+				}
+
+				store.EXPECT().
+					PaymentEventTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.PaymentEventTxResult{Order: credited, Applied: true}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.PaymentEventTxResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.True(t, got.Applied)
+				require.Equal(t, payments.StatusCredited, got.Order.Status)
+			},
+		},
+		{
+			name: "Redelivered",
+			signature: func(server *Server) string {
+				return sign(server, payload)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					PaymentEventTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PaymentEventTxResult{Order: credited}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.PaymentEventTxResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.False(t, got.Applied)
+			},
+		},
+		{
+			name: "InvalidSignature",
+			signature: func(server *Server) string {
+				return sign(server, []byte("other payload"))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					PaymentEventTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidTransition",
+			signature: func(server *Server) string {
+				return sign(server, payload)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					PaymentEventTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PaymentEventTxResult{}, fmt.Errorf("%w: refunded to paid", payments.ErrInvalidTransition))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "OrderNotFound",
+			signature: func(server *Server) string {
+				return sign(server, payload)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					PaymentEventTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PaymentEventTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/payment/webhook", bytes.NewReader(payload))
+			require.NoError(t, err)
+			request.Header.Set(paymentSignatureHeader, tc.signature(server))
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestPaymentWebhookChargebackAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+	pack, _ := payments.FindPack("medium")
+	order := randomOrder(account, pack)
+
+	payload, err := json.Marshal(payments.Event{
+		Type:        payments.EventChargeback,
+		OrderID:     order.ID,
+		ProviderRef: order.ProviderRef,
+	})
+	require.NoError(t, err)
+
+	for _, revoke := range []bool{false, true} {
+		ctrl := gomock.NewController(t)
+		store := mockdb.NewMockStore(ctrl)
+
+		chargedBack := order
+		chargedBack.Status = payments.StatusChargedBack
+		flagged := account
+		flagged.PaidBalance = -200
+		flagged.FlaggedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+		arg := db.PaymentEventTxParams{
+			OrderID:     order.ID,
+			Event:       payments.EventChargeback,
+			ProviderRef: order.ProviderRef,
+			RevokeItems: revoke,
+		}
+		store.EXPECT().
+			PaymentEventTx(gomock.Any(), gomock.Eq(arg)).
+			Times(1).
+			Return(db.PaymentEventTxResult{
+				Order:    chargedBack,
+				Applied:  true,
+				Reversal: &db.Reversal{Account: flagged, Debt: 200},
+			}, nil)
+
+		server := newTestServer(t, store)
+		server.config.ReversalRevokeItems = revoke
+		recorder := httptest.NewRecorder()
+
+		request, err := http.NewRequest(http.MethodPost, "/payment/webhook", bytes.NewReader(payload))
+		require.NoError(t, err)
+		request.Header.Set(paymentSignatureHeader, server.payments.(*payments.FakeProvider).Sign(payload))
+
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var got db.PaymentEventTxResult
+		err = json.Unmarshal(recorder.Body.Bytes(), &got)
+		require.NoError(t, err)
+		require.Equal(t, payments.StatusChargedBack, got.Order.Status)
+		require.NotNil(t, got.Reversal)
+		require.Equal(t, int64(200), got.Reversal.Debt)
+
+		ctrl.Finish()
+	}
+}
+
+func TestListPacksAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/payment/packs", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var got []payments.Pack
+	err = json.Unmarshal(recorder.Body.Bytes(), &got)
+	require.NoError(t, err)
+	require.Equal(t, payments.ListPacks(), got)
+}
+
+func randomOrder(account db.Account, pack payments.Pack) db.Order {
+	id := utils.RandomInt(1, 1000)
+	return db.Order{
+		ID:          id,
+		AccountID:   account.ID,
+		PackID:      pack.ID,
+		Amount:      pack.Amount,
+		Price:       pack.Price,
+		Currency:    pack.Currency,
+		Status:      payments.StatusPending,
+		Provider:    payments.FakeProviderName,
+		ProviderRef: fmt.Sprintf("fake_%d", id),
+	}
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"text/tabwriter"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/gacha"
+)
+
+func (server *Server) ListRarityRatesApi(ctx *gin.Context) {
+	rates, err := server.store.ListRarityRates(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rates)
+}
+
+type UpdateRarityRateRequest struct {
+	Rating int32 `json:"rating" binding:"required,min=1,max=7"`
+	Weight int64 `json:"weight" binding:"min=0"`
+}
+
+func (server *Server) UpdateRarityRateApi(ctx *gin.Context) {
+	var req UpdateRarityRateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	arg := db.UpsertRarityRateParams{
+		Rating: req.Rating,
+		Weight: req.Weight,
+	}
+
+	rate, err := server.store.UpsertRarityRate(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rate)
+}
+
+// newRates converts the stored rarity rates into draw engine rates
+func newRates(rows []db.RarityRate) gacha.Rates {
+	rates := make(gacha.Rates, len(rows))
+	for _, r := range rows {
+		rates[r.Rating] = r.Weight
+	}
+	return rates
+}
+
+type GetGachaRatesRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type GetGachaRatesQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=json text"`
+}
+
+// ItemRate is the chance of drawing an item of a banner on a single pull
+type ItemRate struct {
+	ItemID      int64   `json:"item_id"`
+	ItemName    string  `json:"item_name"`
+	Rating      int32   `json:"rating"`
+	IsFeatured  bool    `json:"is_featured"`
+	Probability float64 `json:"probability"`
+}
+
+type GachaRatesResponse struct {
+	BannerID   int64            `json:"banner_id"`
+	BannerName string           `json:"banner_name"`
+	Tiers      []gacha.TierOdds `json:"tiers"`
+	Items      []ItemRate       `json:"items"`
+	// Guaranteed holds the tier odds of the last draw of a multi pull
+	// when none of the earlier draws reached the guaranteed rating
+	Guaranteed      []gacha.TierOdds `json:"guaranteed,omitempty"`
+	MultiCount      int32            `json:"multi_count"`
+	GuaranteeRating int32            `json:"guarantee_rating"`
+	SoftPity        int32            `json:"soft_pity"`
+	HardPity        int32            `json:"hard_pity"`
+	SoftPityStep    int64            `json:"soft_pity_step"`
+}
+
+// GetGachaRatesApi discloses the drop rates of a banner.
+// The rates are computed from the pool the draw engine uses, so they cannot
+// drift from the real odds. format=text returns them as a plain text table.
+func (server *Server) GetGachaRatesApi(ctx *gin.Context) {
+	var req GetGachaRatesRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	var query GetGachaRatesQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	banner, err := server.store.GetBanner(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	pool, rows, err := server.bannerPool(ctx, banner)
+	if err != nil {
+		if errors.Is(err, gacha.ErrEmptyPool) {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	res := GachaRatesResponse{
+		BannerID:        banner.ID,
+		BannerName:      banner.BannerName,
+		MultiCount:      banner.MultiCount,
+		GuaranteeRating: banner.GuaranteeRating,
+		SoftPity:        banner.SoftPity,
+		HardPity:        banner.HardPity,
+		SoftPityStep:    banner.SoftPityStep,
+	}
+
+	odds := pool.Odds()
+	res.Tiers = odds.Tiers
+
+	items := make(map[int64]db.ListBannerPoolRow, len(rows))
+	for _, row := range rows {
+		items[row.ItemID] = row
+	}
+	res.Items = make([]ItemRate, len(odds.Entries))
+	for i, e := range odds.Entries {
+		res.Items[i] = ItemRate{
+			ItemID:      e.ItemID,
+			ItemName:    items[e.ItemID].ItemName,
+			Rating:      e.Rating,
+			IsFeatured:  items[e.ItemID].IsFeatured,
+			Probability: e.Probability,
+		}
+	}
+
+	if banner.GuaranteeRating > 0 {
+		guaranteed, err := pool.AtLeast(banner.GuaranteeRating)
+		if err == nil {
+			res.Guaranteed = guaranteed.Odds().Tiers
+		}
+	}
+
+	if query.Format == "text" {
+		ctx.String(http.StatusOK, ratesTable(res))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ratesTable renders the drop rates of a banner as a human readable table
+func ratesTable(res GachaRatesResponse) string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "%s (banner %d)\n\n", res.BannerName, res.BannerID)
+
+	fmt.Fprintln(w, "RARITY\tRATE")
+	for i := len(res.Tiers) - 1; i >= 0; i-- {
+		fmt.Fprintf(w, "%d\t%s\n", res.Tiers[i].Rating, percent(res.Tiers[i].Probability))
+	}
+
+	fmt.Fprintln(w, "\nITEM\tRARITY\tRATE")
+	for i := len(res.Items) - 1; i >= 0; i-- {
+		item := res.Items[i]
+		name := item.ItemName
+		if item.IsFeatured {
+			name += " (featured)"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", name, item.Rating, percent(item.Probability))
+	}
+
+	if len(res.Guaranteed) > 0 {
+		fmt.Fprintf(w, "\nA multi pull of %d draws guarantees rarity %d or higher.\n", res.MultiCount, res.GuaranteeRating)
+		fmt.Fprintln(w, "When no earlier draw reached it, the last draw uses these rates:")
+		fmt.Fprintln(w, "RARITY\tRATE")
+		for i := len(res.Guaranteed) - 1; i >= 0; i-- {
+			fmt.Fprintf(w, "%d\t%s\n", res.Guaranteed[i].Rating, percent(res.Guaranteed[i].Probability))
+		}
+	}
+
+	if res.SoftPity > 0 && res.SoftPityStep > 0 {
+		fmt.Fprintf(w, "\nAfter %d pulls without the top rarity, its rate weight rises by %d on every pull.\n", res.SoftPity, res.SoftPityStep)
+	}
+	if res.HardPity > 0 {
+		fmt.Fprintf(w, "\nThe top rarity is guaranteed on pull %d without it.\n", res.HardPity)
+	}
+
+	w.Flush()
+	return buf.String()
+}
+
+func percent(p float64) string {
+	return fmt.Sprintf("%.4f%%", p*100)
+}
@@ -0,0 +1,360 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/sRRRs-7/GachaPon/db/mock"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRarityRatesAPI(t *testing.T) {
+	rates := randomRarityRates()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListRarityRates(gomock.Any()).
+					Times(1).
+					Return(rates, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchRarityRates(t, recorder.Body, rates)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListRarityRates(gomock.Any()).
+					Times(1).
+					Return([]db.RarityRate{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := "/rate/list"
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUpdateRarityRateAPI(t *testing.T) {
+	rate := randomRarityRates()[6]
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"rating": rate.Rating,
+				"weight": rate.Weight,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpsertRarityRateParams{
+					Rating: rate.Rating,
+					Weight: rate.Weight,
+				}
+
+				store.EXPECT().
+					UpsertRarityRate(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(rate, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidRating",
+			body: gin.H{
+				"rating": 8,
+				"weight": rate.Weight,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertRarityRate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NegativeWeight",
+			body: gin.H{
+				"rating": rate.Rating,
+				"weight": -1,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertRarityRate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"rating": rate.Rating,
+				"weight": rate.Weight,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertRarityRate(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RarityRate{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/rate/update"
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAdminAuthorization(t, request, server.tokenMaker, store)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetGachaRatesAPI(t *testing.T) {
+	banner := randomBanner()
+	pool := []db.ListBannerPoolRow{
+		{ItemID: 1, Weight: 1, ItemName: "common", Rating: 1},
+		{ItemID: 2, Weight: 3, ItemName: "common+", Rating: 1},
+		{ItemID: 3, Weight: 1, ItemName: "rare", Rating: 4},
+		{ItemID: 4, Weight: 1, ItemName: "legend", Rating: 7, IsFeatured: true},
+	}
+	bannerRates := []db.BannerRate{
+		{BannerID: banner.ID, Rating: 1, Weight: 90},
+		{BannerID: banner.ID, Rating: 4, Weight: 9},
+		{BannerID: banner.ID, Rating: 7, Weight: 1},
+	}
+
+	buildPoolStubs := func(store *mockdb.MockStore) {
+		store.EXPECT().
+			GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+			Times(1).
+			Return(banner, nil)
+
+		store.EXPECT().
+			ListBannerPool(gomock.Any(), gomock.Eq(banner.ID)).
+			Times(1).
+			Return(pool, nil)
+
+		store.EXPECT().
+			ListBannerRates(gomock.Any(), gomock.Eq(banner.ID)).
+			Times(1).
+			Return(bannerRates, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		format        string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			buildStubs: buildPoolStubs,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				data, err := ioutil.ReadAll(recorder.Body)
+				require.NoError(t, err)
+
+				var res GachaRatesResponse
+				err = json.Unmarshal(data, &res)
+				require.NoError(t, err)
+
+				require.Equal(t, banner.ID, res.BannerID)
+				require.Len(t, res.Tiers, 3)
+				require.InDelta(t, 0.9, res.Tiers[0].Probability, 1e-9)
+				require.InDelta(t, 0.09, res.Tiers[1].Probability, 1e-9)
+				require.InDelta(t, 0.01, res.Tiers[2].Probability, 1e-9)
+
+				require.Len(t, res.Items, len(pool))
+				require.Equal(t, "common", res.Items[0].ItemName)
+				require.InDelta(t, 0.225, res.Items[0].Probability, 1e-9)
+				require.InDelta(t, 0.675, res.Items[1].Probability, 1e-9)
+				require.True(t, res.Items[3].IsFeatured)
+
+				// the guaranteed draw of a multi pull only holds rarity 4 and up
+				require.Len(t, res.Guaranteed, 2)
+				require.InDelta(t, 0.9, res.Guaranteed[0].Probability, 1e-9)
+				require.InDelta(t, 0.1, res.Guaranteed[1].Probability, 1e-9)
+			},
+		},
+		{
+			name:       "Text",
+			format:     "text",
+			buildStubs: buildPoolStubs,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Content-Type"), "text/plain")
+
+				body := recorder.Body.String()
+				require.Contains(t, body, banner.BannerName)
+				require.Contains(t, body, "90.0000%")
+				require.Contains(t, body, "legend (featured)")
+				require.Contains(t, body, "1.0000%")
+			},
+		},
+		{
+			name:   "InvalidFormat",
+			format: "xml",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BannerNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(db.Banner{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "EmptyPool",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(banner, nil)
+
+				store.EXPECT().
+					ListBannerPool(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return([]db.ListBannerPoolRow{}, nil)
+
+				store.EXPECT().
+					ListBannerRates(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(bannerRates, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/gacha/rates/%d", banner.ID)
+			if tc.format != "" {
+				url += "?format=" + tc.format
+			}
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func randomRarityRates() []db.RarityRate {
+	rates := make([]db.RarityRate, 7)
+	for i := range rates {
+		rates[i] = db.RarityRate{
+			Rating: int32(i + 1),
+			Weight: utils.RandomInt(1, 1000),
+		}
+	}
+	return rates
+}
+
+func requireBodyMatchRarityRates(t *testing.T, body *bytes.Buffer, rates []db.RarityRate) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotRates []db.RarityRate
+	err = json.Unmarshal(data, &gotRates)
+	require.NoError(t, err)
+	require.Equal(t, rates, gotRates)
+}
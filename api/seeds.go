@@ -0,0 +1,259 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/gacha"
+	"github.com/sRRRs-7/GachaPon/token"
+)
+
+var (
+	errGachaNotVerifiable = errors.New("gacha was drawn before seed commitments")
+	errSnapshotMismatch   = errors.New("pool snapshot doesn't match its digest")
+)
+
+// SeedResponse is a seed without its server seed, which stays secret until revealed
+type SeedResponse struct {
+	ID             int64     `json:"id"`
+	AccountID      int64     `json:"account_id"`
+	ServerSeedHash string    `json:"server_seed_hash"`
+	ClientSeed     string    `json:"client_seed"`
+	Nonce          int64     `json:"nonce"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func newSeedResponse(seed db.Seed) SeedResponse {
+	return SeedResponse{
+		ID:             seed.ID,
+		AccountID:      seed.AccountID,
+		ServerSeedHash: seed.ServerSeedHash,
+		ClientSeed:     seed.ClientSeed,
+		Nonce:          seed.Nonce,
+		CreatedAt:      seed.CreatedAt,
+	}
+}
+
+type GetSeedRequest struct {
+	AccountID int64 `form:"account_id" binding:"required,min=1"`
+}
+
+// GetSeedApi returns the commitment of the seed the next pulls of an account will use
+func (server *Server) GetSeedApi(ctx *gin.Context) {
+	var req GetSeedRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, req.AccountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		ctx.JSON(http.StatusUnauthorized, errRes(db.ErrAccountNotOwned))
+		return
+	}
+
+	seed, err := server.store.GetActiveSeed(ctx, account.ID)
+	if err == sql.ErrNoRows {
+		// commit to a first seed, so the player sees its hash before pulling
+		var result db.RotateSeedTxResult
+		result, err = server.store.RotateSeedTx(ctx, db.RotateSeedTxParams{
+			AccountID: account.ID,
+			Owner:     authPayload.Username,
+		})
+		seed = result.Active
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newSeedResponse(seed))
+}
+
+type RotateSeedRequest struct {
+	AccountID  int64  `json:"account_id" binding:"required,min=1"`
+	ClientSeed string `json:"client_seed" binding:"omitempty,alphanum,max=64"`
+}
+
+type RotateSeedResponse struct {
+	// Revealed is the previous seed with its server seed, nil when there was none
+	Revealed *db.Seed     `json:"revealed,omitempty"`
+	Active   SeedResponse `json:"active"`
+}
+
+// RotateSeedApi reveals the current seed of an account and commits to a new one
+// using the client seed chosen by the player
+func (server *Server) RotateSeedApi(ctx *gin.Context) {
+	var req RotateSeedRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.RotateSeedTxParams{
+		AccountID:  req.AccountID,
+		Owner:      authPayload.Username,
+		ClientSeed: req.ClientSeed,
+	}
+
+	result, err := server.store.RotateSeedTx(ctx, arg)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		case errors.Is(err, db.ErrAccountNotOwned):
+			ctx.JSON(http.StatusUnauthorized, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	res := RotateSeedResponse{
+		Active: newSeedResponse(result.Active),
+	}
+	if result.Revealed.ID != 0 {
+		res.Revealed = &result.Revealed
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+type VerifyGachaRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type VerifyGachaResponse struct {
+	Gacha          db.Gacha `json:"gacha"`
+	ServerSeedHash string   `json:"server_seed_hash"`
+	// ServerSeed is only set once the seed has been revealed by a rotation
+	ServerSeed string  `json:"server_seed,omitempty"`
+	ClientSeed string  `json:"client_seed"`
+	Nonce      int64   `json:"nonce"`
+	Roll       float64 `json:"roll"`
+	Revealed   bool    `json:"revealed"`
+	// Pool is the snapshot of the pool and pity rules the gacha was drawn from,
+	// unset for gachas drawn before snapshots were kept
+	Pool *gacha.Snapshot `json:"pool,omitempty"`
+	// ReplayedItemID is the item the roll draws again from the pool
+	ReplayedItemID int64 `json:"replayed_item_id,omitempty"`
+	// Verified reports that the revealed seed matches its commitment,
+	// derives the stored roll, and that the roll draws the stored item
+	Verified bool `json:"verified"`
+}
+
+// VerifyGachaApi returns everything needed to recompute the roll of a gacha.
+// The roll of a draw is gacha.Roll(server_seed, client_seed, nonce), and it picks
+// the item through the cumulative drop rates of the pool snapshot, after the
+// pity counter and guarantee of the gacha have been applied.
+func (server *Server) VerifyGachaApi(ctx *gin.Context) {
+	var req VerifyGachaRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	record, err := server.store.GetGacha(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, record.AccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		ctx.JSON(http.StatusUnauthorized, errRes(db.ErrAccountNotOwned))
+		return
+	}
+
+	if !record.SeedID.Valid {
+		ctx.JSON(http.StatusNotFound, errRes(errGachaNotVerifiable))
+		return
+	}
+
+	seed, err := server.store.GetSeed(ctx, record.SeedID.Int64)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	res := VerifyGachaResponse{
+		Gacha:          record,
+		ServerSeedHash: seed.ServerSeedHash,
+		ClientSeed:     seed.ClientSeed,
+		Nonce:          record.Nonce,
+		Roll:           record.Roll,
+		Revealed:       seed.RevealedAt.Valid,
+	}
+
+	if record.PoolDigest != "" {
+		res.Pool, err = server.poolSnapshot(ctx, record.PoolDigest)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errRes(err))
+			return
+		}
+		entry, err := res.Pool.Replay(record.Roll, record.PityPulls, record.MinRating)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errRes(err))
+			return
+		}
+		res.ReplayedItemID = entry.ItemID
+	}
+
+	if res.Revealed {
+		res.ServerSeed = seed.ServerSeed
+		res.Verified = gacha.HashSeed(seed.ServerSeed) == seed.ServerSeedHash &&
+			gacha.Roll(seed.ServerSeed, seed.ClientSeed, record.Nonce) == record.Roll &&
+			res.Pool != nil && res.ReplayedItemID == record.ItemID
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// poolSnapshot reads the pool snapshot of a digest, and checks it still encodes
+// to that digest
+func (server *Server) poolSnapshot(ctx *gin.Context, digest string) (*gacha.Snapshot, error) {
+	stored, err := server.store.GetPoolSnapshot(ctx, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot gacha.Snapshot
+	if err := json.Unmarshal(stored.Snapshot, &snapshot); err != nil {
+		return nil, err
+	}
+	_, encoded, err := snapshot.Encode()
+	if err != nil {
+		return nil, err
+	}
+	if encoded != digest {
+		return nil, errSnapshotMismatch
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,595 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/sRRRs-7/GachaPon/db/mock"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/gacha"
+	"github.com/sRRRs-7/GachaPon/token"
+	"github.com/sRRRs-7/GachaPon/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSeedAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+	seed := randomSeed(t, account)
+
+	testCases := []struct {
+		name          string
+		accountID     int64
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			accountID: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				store.EXPECT().
+					GetActiveSeed(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(seed, nil)
+
+				store.EXPECT().
+					RotateSeedTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchSeed(t, recorder.Body, seed)
+			},
+		},
+		{
+			name:      "FirstSeed",
+			accountID: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				store.EXPECT().
+					GetActiveSeed(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(db.Seed{}, sql.ErrNoRows)
+
+				arg := db.RotateSeedTxParams{
+					AccountID: account.ID,
+					Owner:     user.UserName,
+				}
+				store.EXPECT().
+					RotateSeedTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.RotateSeedTxResult{Active: seed}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchSeed(t, recorder.Body, seed)
+			},
+		},
+		{
+			name:      "AccountNotOwned",
+			accountID: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				store.EXPECT().
+					GetActiveSeed(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			accountID: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/gacha/seed?account_id=%d", tc.accountID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestRotateSeedAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+	revealed := randomSeed(t, account)
+	revealed.RevealedAt = sql.NullTime{Time: time.Now().UTC().Truncate(time.Second), Valid: true}
+	active := randomSeed(t, account)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"account_id":  account.ID,
+				"client_seed": active.ClientSeed,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.RotateSeedTxParams{
+					AccountID:  account.ID,
+					Owner:      user.UserName,
+					ClientSeed: active.ClientSeed,
+				}
+				store.EXPECT().
+					RotateSeedTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.RotateSeedTxResult{Revealed: revealed, Active: active}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				data, err := ioutil.ReadAll(recorder.Body)
+				require.NoError(t, err)
+
+				var res RotateSeedResponse
+				err = json.Unmarshal(data, &res)
+				require.NoError(t, err)
+				require.Equal(t, revealed.ServerSeed, res.Revealed.ServerSeed)
+				require.Equal(t, newSeedResponse(active), res.Active)
+
+				// the server seed of the active seed must stay secret
+				require.NotContains(t, string(data), active.ServerSeed)
+			},
+		},
+		{
+			name: "InvalidClientSeed",
+			body: gin.H{
+				"account_id":  account.ID,
+				"client_seed": "not a seed!",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RotateSeedTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AccountNotOwned",
+			body: gin.H{
+				"account_id": account.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RotateSeedTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RotateSeedTxResult{}, db.ErrAccountNotOwned)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AccountNotFound",
+			body: gin.H{
+				"account_id": account.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RotateSeedTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RotateSeedTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/gacha/seed/rotate"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestVerifyGachaAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+
+	seed := randomSeed(t, account)
+	revealedSeed := seed
+	revealedSeed.RevealedAt = sql.NullTime{Time: time.Now().UTC().Truncate(time.Second), Valid: true}
+
+	record := randomGacha()
+	record.AccountID = account.ID
+	record.SeedID = sql.NullInt64{Int64: seed.ID, Valid: true}
+	record.Nonce = utils.RandomInt(0, 100)
+	record.Roll = gacha.Roll(seed.ServerSeed, seed.ClientSeed, record.Nonce)
+
+	snapshot := randomPoolSnapshot(t, record.ItemID)
+	record.PoolDigest = snapshot.Digest
+
+	tampered := record
+	tampered.Roll = 0.5
+
+	// the pool snapshot draws another item than the one stored
+	swapped := record
+	swapped.ItemID = record.ItemID + 1
+
+	withoutPool := record
+	withoutPool.PoolDigest = ""
+
+	legacy := record
+	legacy.SeedID = sql.NullInt64{}
+
+	testCases := []struct {
+		name          string
+		record        db.Gacha
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Verified",
+			record: record,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				buildVerifyStubs(store, record, account, revealedSeed, snapshot)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				res := readVerifyResponse(t, recorder.Body)
+				require.True(t, res.Revealed)
+				require.True(t, res.Verified)
+				require.Equal(t, seed.ServerSeed, res.ServerSeed)
+				require.Equal(t, record.Roll, res.Roll)
+				require.NotNil(t, res.Pool)
+				require.Equal(t, record.ItemID, res.ReplayedItemID)
+			},
+		},
+		{
+			name:   "ItemNotReplayed",
+			record: swapped,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				buildVerifyStubs(store, swapped, account, revealedSeed, snapshot)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				res := readVerifyResponse(t, recorder.Body)
+				require.True(t, res.Revealed)
+				require.False(t, res.Verified)
+				require.Equal(t, record.ItemID, res.ReplayedItemID)
+			},
+		},
+		{
+			name:   "WithoutPool",
+			record: withoutPool,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				buildVerifyStubs(store, withoutPool, account, revealedSeed, db.PoolSnapshot{})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				res := readVerifyResponse(t, recorder.Body)
+				require.True(t, res.Revealed)
+				require.False(t, res.Verified)
+				require.Nil(t, res.Pool)
+			},
+		},
+		{
+			name:   "SnapshotMismatch",
+			record: record,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				altered := snapshot
+				altered.Snapshot = randomPoolSnapshot(t, record.ItemID+1).Snapshot
+				buildVerifyStubs(store, record, account, revealedSeed, altered)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:   "NotRevealed",
+			record: record,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				buildVerifyStubs(store, record, account, seed, snapshot)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				res := readVerifyResponse(t, recorder.Body)
+				require.False(t, res.Revealed)
+				require.False(t, res.Verified)
+				require.Empty(t, res.ServerSeed)
+				require.Equal(t, seed.ServerSeedHash, res.ServerSeedHash)
+			},
+		},
+		{
+			name:   "Tampered",
+			record: tampered,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				buildVerifyStubs(store, tampered, account, revealedSeed, snapshot)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				res := readVerifyResponse(t, recorder.Body)
+				require.True(t, res.Revealed)
+				require.False(t, res.Verified)
+			},
+		},
+		{
+			name:   "DrawnBeforeSeeds",
+			record: legacy,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGacha(gomock.Any(), gomock.Eq(legacy.ID)).
+					Times(1).
+					Return(legacy, nil)
+
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				store.EXPECT().
+					GetSeed(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "NotOwner",
+			record: record,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGacha(gomock.Any(), gomock.Eq(record.ID)).
+					Times(1).
+					Return(record, nil)
+
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				store.EXPECT().
+					GetSeed(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "NotFound",
+			record: record,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGacha(gomock.Any(), gomock.Eq(record.ID)).
+					Times(1).
+					Return(db.Gacha{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/gacha/verify/%d", tc.record.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func buildVerifyStubs(store *mockdb.MockStore, record db.Gacha, account db.Account, seed db.Seed, snapshot db.PoolSnapshot) {
+	store.EXPECT().
+		GetGacha(gomock.Any(), gomock.Eq(record.ID)).
+		Times(1).
+		Return(record, nil)
+
+	store.EXPECT().
+		GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+		Times(1).
+		Return(account, nil)
+
+	store.EXPECT().
+		GetSeed(gomock.Any(), gomock.Eq(seed.ID)).
+		Times(1).
+		Return(seed, nil)
+
+	if record.PoolDigest == "" {
+		store.EXPECT().
+			GetPoolSnapshot(gomock.Any(), gomock.Any()).
+			Times(0)
+		return
+	}
+	store.EXPECT().
+		GetPoolSnapshot(gomock.Any(), gomock.Eq(record.PoolDigest)).
+		Times(1).
+		Return(snapshot, nil)
+}
+
+// randomPoolSnapshot is the snapshot of a pool that only draws itemID
+func randomPoolSnapshot(t *testing.T, itemID int64) db.PoolSnapshot {
+	pool, err := gacha.NewPool([]gacha.Entry{{ItemID: itemID, Rating: 1, Weight: 1}}, gacha.Rates{1: 100})
+	require.NoError(t, err)
+
+	data, digest, err := gacha.NewSnapshot(pool, gacha.Pity{}).Encode()
+	require.NoError(t, err)
+
+	return db.PoolSnapshot{
+		Digest:    digest,
+		Snapshot:  data,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func randomSeed(t *testing.T, account db.Account) db.Seed {
+	serverSeed, err := gacha.NewSeed()
+	require.NoError(t, err)
+
+	return db.Seed{
+		ID:             utils.RandomInt(1, 1000),
+		AccountID:      account.ID,
+		ServerSeed:     serverSeed,
+		ServerSeedHash: gacha.HashSeed(serverSeed),
+		ClientSeed:     utils.RandomString(16),
+		Nonce:          utils.RandomInt(0, 100),
+		CreatedAt:      time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func readVerifyResponse(t *testing.T, body *bytes.Buffer) VerifyGachaResponse {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var res VerifyGachaResponse
+	err = json.Unmarshal(data, &res)
+	require.NoError(t, err)
+	return res
+}
+
+func requireBodyMatchSeed(t *testing.T, body *bytes.Buffer, seed db.Seed) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotSeed SeedResponse
+	err = json.Unmarshal(data, &gotSeed)
+	require.NoError(t, err)
+	require.Equal(t, newSeedResponse(seed), gotSeed)
+	require.NotContains(t, string(data), seed.ServerSeed)
+}
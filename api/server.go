@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/payments"
 	"github.com/sRRRs-7/GachaPon/token"
 	"github.com/sRRRs-7/GachaPon/utils"
 )
@@ -13,6 +14,7 @@ type Server struct {
 	config     utils.Config
 	store      db.Store
 	tokenMaker token.Maker
+	payments   payments.PaymentProvider
 	router     *gin.Engine
 }
 
@@ -22,10 +24,16 @@ func NewServer(config utils.Config, store db.Store) (*Server, error) {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
 
+	provider, err := payments.NewProvider(config.PaymentProvider, config.PaymentWebhookSecret)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create payment provider: %w", err)
+	}
+
 	server := &Server{
 		config:     config,
 		store:      store,
 		tokenMaker: tokenMaker,
+		payments:   provider,
 	}
 
 	server.setupRouter()
@@ -41,6 +49,10 @@ func (server *Server) setupRouter() {
 	userRouter.POST("/login", server.LoginUserApi)
 	userRouter.GET("/get/:user_name", server.GetUserApi)
 
+	meRouter := router.Group("/user/me").Use(authMiddleware(server.tokenMaker))
+	meRouter.GET("/spending", server.GetSpendingApi)
+	meRouter.PUT("/timezone", server.UpdateTimezoneApi)
+
 	itemRouter := router.Group("/item")
 	itemRouter.POST("/create", server.CreateItemApi)
 	itemRouter.GET("/get/:id", server.GetItemApi)
@@ -57,31 +69,140 @@ func (server *Server) setupRouter() {
 	categoryRouter.GET("/get/:category", server.GetCategoryApi)
 	categoryRouter.GET("/list", server.ListCategoryApi)
 
-	// authenticated router
-	accountRouter := router.Group("/account").Use(authMiddleware(server.tokenMaker))
+	router.GET("/rate/list", server.ListRarityRatesApi)
+
+	// the drop rates are disclosed to everyone but only changed by admins
+	rateRouter := router.Group("/rate").Use(authMiddleware(server.tokenMaker), adminMiddleware(server.store))
+	rateRouter.PUT("/update", server.UpdateRarityRateApi)
+
+	router.GET("/loginReward/list", server.ListLoginRewardsApi)
+
+	// the reward calendar is public but only changed by admins
+	loginRewardRouter := router.Group("/loginReward").Use(authMiddleware(server.tokenMaker), adminMiddleware(server.store))
+	loginRewardRouter.PUT("/update", server.UpdateLoginRewardApi)
+	loginRewardRouter.DELETE("/delete/:day", server.DeleteLoginRewardApi)
+
+	spendingCapRouter := router.Group("/spendingCap").Use(authMiddleware(server.tokenMaker), adminMiddleware(server.store))
+	spendingCapRouter.POST("/create", server.CreateSpendingCapApi)
+	spendingCapRouter.GET("/list", server.ListSpendingCapsApi)
+	spendingCapRouter.PUT("/update", server.UpdateSpendingCapApi)
+	spendingCapRouter.DELETE("/delete/:id", server.DeleteSpendingCapApi)
+
+	// coupons are managed by admins, players only redeem them
+	couponRouter := router.Group("/coupon").Use(authMiddleware(server.tokenMaker), adminMiddleware(server.store))
+	couponRouter.POST("/create", server.CreateCouponApi)
+	couponRouter.GET("/get/:id", server.GetCouponApi)
+	couponRouter.GET("/list", server.ListCouponsApi)
+	couponRouter.PUT("/update", server.UpdateCouponApi)
+	couponRouter.DELETE("/delete/:id", server.DeleteCouponApi)
+
+	// the offers on sale are public, the rest of the shop is managed by admins like
+	// banners, the daily rotation also runs as a job
+	router.GET("/shop/offers", server.ListActiveShopOffersApi)
+
+	shopRouter := router.Group("/shop").Use(authMiddleware(server.tokenMaker), adminMiddleware(server.store))
+	shopRouter.POST("/create", server.CreateShopOfferApi)
+	shopRouter.GET("/get/:id", server.GetShopOfferApi)
+	shopRouter.GET("/list", server.ListShopOffersApi)
+	shopRouter.PUT("/update", server.UpdateShopOfferApi)
+	shopRouter.DELETE("/delete/:id", server.DeleteShopOfferApi)
+	shopRouter.POST("/rotate", server.RotateShopApi)
+
+	router.GET("/banner/get/:id", server.GetBannerApi)
+	router.GET("/banner/list", server.ListBannersApi)
+	router.GET("/banner/listActive", server.ListActiveBannersApi)
+	router.GET("/banner/items/:id", server.ListBannerItemsApi)
+	router.GET("/banner/rates/:id", server.ListBannerRatesApi)
+
+	bannerRouter := router.Group("/banner").Use(authMiddleware(server.tokenMaker), adminMiddleware(server.store))
+	bannerRouter.POST("/create", server.CreateBannerApi)
+	bannerRouter.PUT("/update", server.UpdateBannerApi)
+	bannerRouter.DELETE("/delete/:id", server.DeleteBannerApi)
+	bannerRouter.POST("/addItem", server.CreateBannerItemApi)
+	bannerRouter.PUT("/updateItem", server.UpdateBannerItemApi)
+	bannerRouter.DELETE("/removeItem/:id/:item_id", server.DeleteBannerItemApi)
+	bannerRouter.PUT("/updateRate", server.UpdateBannerRateApi)
+
+	// authenticated router, state changing requests accept an Idempotency-Key header
+	accountRouter := router.Group("/account").Use(authMiddleware(server.tokenMaker), idempotencyMiddleware(server.store, server.config.IdempotencyKeyTTL))
 	accountRouter.POST("/create", server.CreateAccountApi)
 	accountRouter.GET("/get/:id", server.GetAccountApi)
 	accountRouter.GET("/list", server.ListAccountsApi)
-	accountRouter.PUT("/updateAccount", server.UpdateAccountApi)
-	accountRouter.PUT("/updateBalance", server.UpdateBalanceApi)
 	accountRouter.DELETE("/delete/:id", server.DeleteAccountApi)
+	accountRouter.GET("/:id/ledger", server.GetAccountLedgerApi)
+	accountRouter.POST("/dailyClaim", server.DailyClaimApi)
 
-	galleryRouter := router.Group("/gallery").Use(authMiddleware(server.tokenMaker))
+	// the admin entries of the ledger are never posted by players
+	accountAdminRouter := router.Group("/account").Use(authMiddleware(server.tokenMaker), adminMiddleware(server.store), idempotencyMiddleware(server.store, server.config.IdempotencyKeyTTL))
+	accountAdminRouter.PUT("/updateAccount", server.UpdateAccountApi)
+	accountAdminRouter.PUT("/updateBalance", server.UpdateBalanceApi)
+
+	galleryRouter := router.Group("/gallery").Use(authMiddleware(server.tokenMaker), idempotencyMiddleware(server.store, server.config.IdempotencyKeyTTL))
 	galleryRouter.GET("/get/:id", server.GetGalleryApi)
 	galleryRouter.GET("/listById", server.ListGalleriesByIdApi)
 	galleryRouter.GET("/listByItemId", server.ListGalleriesByItemIdApi)
+	galleryRouter.GET("/quantities", server.ListGalleryQuantitiesApi)
+	galleryRouter.POST("/convert", server.ConvertGalleriesApi)
+
+	// drop rates are public, every other gacha route needs a token
+	router.GET("/gacha/rates/:id", server.GetGachaRatesApi)
 
-	gachaRouter := router.Group("/gacha").Use(authMiddleware(server.tokenMaker))
+	gachaRouter := router.Group("/gacha").Use(authMiddleware(server.tokenMaker), idempotencyMiddleware(server.store, server.config.IdempotencyKeyTTL))
 	gachaRouter.POST("/create", server.CreateGachaApi)
+	gachaRouter.POST("/multi", server.CreateMultiGachaApi)
+	gachaRouter.POST("/spark", server.SparkApi)
+	gachaRouter.GET("/pity", server.GetPityApi)
+	gachaRouter.GET("/seed", server.GetSeedApi)
+	gachaRouter.POST("/seed/rotate", server.RotateSeedApi)
+	gachaRouter.GET("/verify/:id", server.VerifyGachaApi)
 	gachaRouter.GET("/get/:id", server.GetGachaApi)
 	gachaRouter.GET("/list", server.ListGachaApi)
 
-	exchangeRouter := router.Group("/exchange").Use(authMiddleware(server.tokenMaker))
+	exchangeRouter := router.Group("/exchange").Use(authMiddleware(server.tokenMaker), idempotencyMiddleware(server.store, server.config.IdempotencyKeyTTL))
 	exchangeRouter.POST("/create", server.CreateExchangeApi)
+	exchangeRouter.PUT("/request", server.ApproveTradeRequestApi)
+	exchangeRouter.PUT("/response", server.ApproveTradeResponseApi)
+	exchangeRouter.GET("/offer/:id", server.GetTradeOfferApi)
+	exchangeRouter.DELETE("/offer/:id", server.DeleteTradeOfferApi)
+	exchangeRouter.POST("/offer/:id/counter", server.CounterTradeOfferApi)
+	exchangeRouter.GET("/offer/:id/history", server.TradeHistoryApi)
+	exchangeRouter.GET("/incoming", server.ListIncomingTradesApi)
+	exchangeRouter.GET("/outgoing", server.ListOutgoingTradesApi)
 	exchangeRouter.GET("/get/:id", server.GetExchangeApi)
 	exchangeRouter.GET("/listFromExchange", server.ListExchangeFromAccountApi)
 	exchangeRouter.GET("/listToExchange", server.ListExchangeToAccountApi)
 
+	// listings can be browsed without a token, selling and buying need one
+	router.GET("/market/listings", server.ListMarketListingsApi)
+	router.GET("/market/get/:id", server.GetListingApi)
+
+	marketRouter := router.Group("/market").Use(authMiddleware(server.tokenMaker), idempotencyMiddleware(server.store, server.config.IdempotencyKeyTTL))
+	marketRouter.POST("/list", server.CreateListingApi)
+	marketRouter.GET("/listBySeller", server.ListSellerListingsApi)
+	marketRouter.DELETE("/cancel/:id", server.CancelListingApi)
+	marketRouter.POST("/buy", server.BuyListingApi)
+
+	// packs and provider webhooks are public, the webhook is authenticated by its signature
+	router.GET("/payment/packs", server.ListPacksApi)
+	router.POST("/payment/webhook", server.PaymentWebhookApi)
+
+	paymentRouter := router.Group("/payment").Use(authMiddleware(server.tokenMaker), idempotencyMiddleware(server.store, server.config.IdempotencyKeyTTL))
+	paymentRouter.POST("/order", server.CreateOrderApi)
+	paymentRouter.GET("/order/:id", server.GetOrderApi)
+
+	redeemRouter := router.Group("/coupon").Use(authMiddleware(server.tokenMaker), idempotencyMiddleware(server.store, server.config.IdempotencyKeyTTL))
+	redeemRouter.POST("/redeem", server.RedeemCouponApi)
+
+	purchaseRouter := router.Group("/shop").Use(authMiddleware(server.tokenMaker), idempotencyMiddleware(server.store, server.config.IdempotencyKeyTTL))
+	purchaseRouter.POST("/purchase", server.ShopPurchaseApi)
+
+	router.GET("/subscription/passes", server.ListPassesApi)
+
+	subscriptionRouter := router.Group("/subscription").Use(authMiddleware(server.tokenMaker), idempotencyMiddleware(server.store, server.config.IdempotencyKeyTTL))
+	subscriptionRouter.POST("/buy", server.SubscribeApi)
+	subscriptionRouter.POST("/claim", server.SubscriptionClaimApi)
+	subscriptionRouter.GET("/list", server.ListSubscriptionsApi)
+
 	server.router = router
 }
 
@@ -92,4 +213,9 @@ func (server *Server) Start(address string) error {
 
 func errRes(err error) gin.H {
 	return gin.H{"error": err.Error()}
+}
+
+// errCodeRes adds a stable code to errors clients have to act on
+func errCodeRes(code string, err error) gin.H {
+	return gin.H{"error": err.Error(), "code": code}
 }
\ No newline at end of file
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/token"
+)
+
+var errSparkDisabled = errors.New("banner doesn't allow sparking")
+
+type ConvertGalleriesRequest struct {
+	AccountID  int64   `json:"account_id" binding:"required,min=1"`
+	GalleryIDs []int64 `json:"gallery_ids" binding:"required,min=1,max=100,unique,dive,min=1"`
+}
+
+// ConvertGalleriesApi turns copies held by an account into shards
+func (server *Server) ConvertGalleriesApi(ctx *gin.Context) {
+	var req ConvertGalleriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.ConvertTxParams{
+		AccountID: req.AccountID,
+		Owner: authPayload.Username,
+		GalleryIDs: req.GalleryIDs,
+	}
+
+	result, err := server.store.ConvertTx(ctx, arg)
+	if err != nil {
+		switch {
+		// a missing account or a copy held by another account
+		case err == sql.ErrNoRows:
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		case errors.Is(err, db.ErrAccountNotOwned):
+			ctx.JSON(http.StatusUnauthorized, errRes(err))
+			return
+		case errors.Is(err, db.ErrItemListed):
+			ctx.JSON(http.StatusConflict, errCodeRes(codeItemListed, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type SparkRequest struct {
+	AccountID int64 `json:"account_id" binding:"required,min=1"`
+	BannerID  int64 `json:"banner_id" binding:"required,min=1"`
+	ItemID    int64 `json:"item_id" binding:"required,min=1"`
+}
+
+// SparkApi spends the spark cost of an active banner in shards on an item of its pool
+func (server *Server) SparkApi(ctx *gin.Context) {
+	var req SparkRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	banner, err := server.store.GetBanner(ctx, req.BannerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	if !bannerActive(banner, time.Now()) {
+		ctx.JSON(http.StatusForbidden, errRes(errBannerInactive))
+		return
+	}
+	if banner.SparkCost <= 0 {
+		ctx.JSON(http.StatusForbidden, errRes(errSparkDisabled))
+		return
+	}
+
+	bannerItem, err := server.store.GetBannerItem(ctx, db.GetBannerItemParams{
+		BannerID: banner.ID,
+		ItemID: req.ItemID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.SparkTxParams{
+		AccountID: req.AccountID,
+		Owner: authPayload.Username,
+		ItemID: bannerItem.ItemID,
+		Cost: banner.SparkCost,
+	}
+
+	result, err := server.store.SparkTx(ctx, arg)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		case errors.Is(err, db.ErrAccountNotOwned):
+			ctx.JSON(http.StatusUnauthorized, errRes(err))
+			return
+		case errors.Is(err, db.ErrInsufficientShards):
+			ctx.JSON(http.StatusPaymentRequired, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
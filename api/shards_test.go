@@ -0,0 +1,462 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/sRRRs-7/GachaPon/db/mock"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/token"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertGalleriesAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+	gallery1 := randomGallery()
+	gallery2 := randomGallery()
+	gallery2.ID = gallery1.ID + 1
+
+	result := db.ConvertTxResult{
+		Account:   account,
+		Galleries: []db.Gallery{gallery1, gallery2},
+		Shards:    15,
+	}
+	result.Account.Shards = result.Shards
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"account_id":  account.ID,
+				"gallery_ids": []int64{gallery1.ID, gallery2.ID},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ConvertTxParams{
+					AccountID:  account.ID,
+					Owner:      user.UserName,
+					GalleryIDs: []int64{gallery1.ID, gallery2.ID},
+				}
+
+				store.EXPECT().
+					ConvertTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(result, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.ConvertTxResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, result.Shards, got.Shards)
+				require.Equal(t, result.Account.Shards, got.Account.Shards)
+				require.Len(t, got.Galleries, 2)
+			},
+		},
+		{
+			name: "DuplicateGalleryIDs",
+			body: gin.H{
+				"account_id":  account.ID,
+				"gallery_ids": []int64{gallery1.ID, gallery1.ID},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ConvertTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoGalleryIDs",
+			body: gin.H{
+				"account_id":  account.ID,
+				"gallery_ids": []int64{},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ConvertTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "GalleryNotOwned",
+			body: gin.H{
+				"account_id":  account.ID,
+				"gallery_ids": []int64{gallery1.ID},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ConvertTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ConvertTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "GalleryListed",
+			body: gin.H{
+				"account_id":  account.ID,
+				"gallery_ids": []int64{gallery1.ID},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ConvertTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ConvertTxResult{}, db.ErrItemListed)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorCode(t, recorder, codeItemListed)
+			},
+		},
+		{
+			name: "AccountNotOwned",
+			body: gin.H{
+				"account_id":  account.ID,
+				"gallery_ids": []int64{gallery1.ID},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ConvertTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ConvertTxResult{}, db.ErrAccountNotOwned)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{
+				"account_id":  account.ID,
+				"gallery_ids": []int64{gallery1.ID},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ConvertTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"account_id":  account.ID,
+				"gallery_ids": []int64{gallery1.ID},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ConvertTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ConvertTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/gallery/convert"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestSparkAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+	banner := randomBanner()
+	gallery := randomGallery()
+	gallery.OwnerID = account.ID
+
+	bannerItem := db.BannerItem{
+		BannerID: banner.ID,
+		ItemID:   gallery.ItemID,
+		Weight:   1,
+	}
+
+	disabled := banner
+	disabled.SparkCost = 0
+
+	inactive := banner
+	inactive.IsActive = false
+
+	result := db.SparkTxResult{
+		Account: account,
+		Gallery: gallery,
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id":  banner.ID,
+				"item_id":    bannerItem.ItemID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Eq(banner.ID)).
+					Times(1).
+					Return(banner, nil)
+
+				store.EXPECT().
+					GetBannerItem(gomock.Any(), gomock.Eq(db.GetBannerItemParams{
+						BannerID: banner.ID,
+						ItemID:   bannerItem.ItemID,
+					})).
+					Times(1).
+					Return(bannerItem, nil)
+
+				arg := db.SparkTxParams{
+					AccountID: account.ID,
+					Owner:     user.UserName,
+					ItemID:    bannerItem.ItemID,
+					Cost:      banner.SparkCost,
+				}
+
+				store.EXPECT().
+					SparkTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(result, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.SparkTxResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, result.Gallery.ID, got.Gallery.ID)
+				require.Equal(t, result.Gallery.ItemID, got.Gallery.ItemID)
+			},
+		},
+		{
+			name: "SparkDisabled",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id":  banner.ID,
+				"item_id":    bannerItem.ItemID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(disabled, nil)
+
+				store.EXPECT().
+					SparkTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "BannerInactive",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id":  banner.ID,
+				"item_id":    bannerItem.ItemID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(inactive, nil)
+
+				store.EXPECT().
+					SparkTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "ItemNotInBanner",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id":  banner.ID,
+				"item_id":    bannerItem.ItemID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(banner, nil)
+
+				store.EXPECT().
+					GetBannerItem(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.BannerItem{}, sql.ErrNoRows)
+
+				store.EXPECT().
+					SparkTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InsufficientShards",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id":  banner.ID,
+				"item_id":    bannerItem.ItemID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(banner, nil)
+
+				store.EXPECT().
+					GetBannerItem(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(bannerItem, nil)
+
+				store.EXPECT().
+					SparkTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.SparkTxResult{}, db.ErrInsufficientShards)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPaymentRequired, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidItemID",
+			body: gin.H{
+				"account_id": account.ID,
+				"banner_id":  banner.ID,
+				"item_id":    0,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBanner(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/gacha/spark"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/shop"
+	"github.com/sRRRs-7/GachaPon/token"
+)
+
+// codes of the purchase errors, so clients can tell the user why an offer was refused
+const (
+	codeOfferInactive     = "offer_inactive"
+	codeOutOfStock        = "out_of_stock"
+	codeOfferLimitReached = "offer_limit_reached"
+)
+
+// nullInt32 turns an optional limit of a request into a nullable column
+func nullInt32(limit *int32) sql.NullInt32 {
+	if limit == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: *limit, Valid: true}
+}
+
+type CreateShopOfferRequest struct {
+	ItemID     int64  `json:"item_id" binding:"required,min=1"`
+	Price      int64  `json:"price" binding:"min=0"`
+	SpendOrder string `json:"spend_order" binding:"omitempty,oneof=free_first paid_first paid_only"`
+	// Stock and PerUserLimit are the copies of all users and of every user, nil leaves them unlimited
+	Stock        *int64    `json:"stock" binding:"omitempty,min=1"`
+	PerUserLimit *int32    `json:"per_user_limit" binding:"omitempty,min=1"`
+	StartAt      time.Time `json:"start_at" binding:"required"`
+	EndAt        time.Time `json:"end_at" binding:"required,gtfield=StartAt"`
+}
+
+func (server *Server) CreateShopOfferApi(ctx *gin.Context) {
+	var req CreateShopOfferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	spendOrder := req.SpendOrder
+	if spendOrder == "" {
+		spendOrder = defaultSpendOrder
+	}
+
+	arg := db.CreateShopOfferParams{
+		ItemID:       req.ItemID,
+		Price:        req.Price,
+		SpendOrder:   spendOrder,
+		Stock:        nullInt64(req.Stock),
+		PerUserLimit: nullInt32(req.PerUserLimit),
+		StartAt:      req.StartAt,
+		EndAt:        req.EndAt,
+	}
+
+	offer, err := server.store.CreateShopOffer(ctx, arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation":
+				ctx.JSON(http.StatusForbidden, errRes(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, offer)
+}
+
+type GetShopOfferRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) GetShopOfferApi(ctx *gin.Context) {
+	var req GetShopOfferRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	offer, err := server.store.GetShopOffer(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, offer)
+}
+
+type ListShopOffersRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=1,max=50"`
+}
+
+// ListShopOffersApi lists every offer, ended and upcoming ones included
+func (server *Server) ListShopOffersApi(ctx *gin.Context) {
+	var req ListShopOffersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	arg := db.ListShopOffersParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	}
+
+	offers, err := server.store.ListShopOffers(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, offers)
+}
+
+// ListActiveShopOffersApi lists the offers on sale with their items
+func (server *Server) ListActiveShopOffersApi(ctx *gin.Context) {
+	offers, err := server.store.ListActiveShopOffers(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, offers)
+}
+
+type UpdateShopOfferRequest struct {
+	ID           int64     `json:"id" binding:"required,min=1"`
+	Price        int64     `json:"price" binding:"min=0"`
+	SpendOrder   string    `json:"spend_order" binding:"omitempty,oneof=free_first paid_first paid_only"`
+	Stock        *int64    `json:"stock" binding:"omitempty,min=1"`
+	PerUserLimit *int32    `json:"per_user_limit" binding:"omitempty,min=1"`
+	StartAt      time.Time `json:"start_at" binding:"required"`
+	EndAt        time.Time `json:"end_at" binding:"required,gtfield=StartAt"`
+}
+
+// UpdateShopOfferApi replaces the price, limits and window of an offer, its
+// item and the copies sold so far stay
+func (server *Server) UpdateShopOfferApi(ctx *gin.Context) {
+	var req UpdateShopOfferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	spendOrder := req.SpendOrder
+	if spendOrder == "" {
+		spendOrder = defaultSpendOrder
+	}
+
+	arg := db.UpdateShopOfferParams{
+		ID:           req.ID,
+		Price:        req.Price,
+		SpendOrder:   spendOrder,
+		Stock:        nullInt64(req.Stock),
+		PerUserLimit: nullInt32(req.PerUserLimit),
+		StartAt:      req.StartAt,
+		EndAt:        req.EndAt,
+	}
+
+	offer, err := server.store.UpdateShopOffer(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, offer)
+}
+
+type DeleteShopOfferRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// DeleteShopOfferApi deletes an offer nobody bought yet, sold offers are
+// ended through their end_at instead
+func (server *Server) DeleteShopOfferApi(ctx *gin.Context) {
+	var req DeleteShopOfferRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	err := server.store.DeleteShopOffer(ctx, req.ID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation":
+				ctx.JSON(http.StatusForbidden, errRes(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+type RotateShopRequest struct {
+	// Day defaults to today, the rotation of a day is generated once
+	Day time.Time `json:"day"`
+}
+
+// RotateShopApi generates the rotating offers of a day, a day already rotated
+// returns its offers unchanged
+func (server *Server) RotateShopApi(ctx *gin.Context) {
+	var req RotateShopRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	day := req.Day
+	if day.IsZero() {
+		day = time.Now()
+	}
+
+	arg := db.RotateShopTxParams{
+		Day:    day,
+		Size:   shop.RotationSize,
+		Source: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	result, err := server.store.RotateShopTx(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type ShopPurchaseRequest struct {
+	AccountID int64 `json:"account_id" binding:"required,min=1"`
+	OfferID   int64 `json:"offer_id" binding:"required,min=1"`
+}
+
+// ShopPurchaseApi buys a copy of the item of an offer for an account of the authenticated user
+func (server *Server) ShopPurchaseApi(ctx *gin.Context) {
+	var req ShopPurchaseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.ShopPurchaseTxParams{
+		AccountID: req.AccountID,
+		Owner:     authPayload.Username,
+		OfferID:   req.OfferID,
+		Now:       time.Now(),
+	}
+
+	result, err := server.store.ShopPurchaseTx(ctx, arg)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		case errors.Is(err, db.ErrAccountNotOwned):
+			ctx.JSON(http.StatusUnauthorized, errRes(err))
+			return
+		case errors.Is(err, db.ErrInsufficientBalance):
+			ctx.JSON(http.StatusPaymentRequired, errRes(err))
+			return
+		case errors.Is(err, db.ErrSpendingCapExceeded):
+			ctx.JSON(http.StatusForbidden, errCodeRes(codeSpendingCapExceeded, err))
+			return
+		case errors.Is(err, db.ErrAccountFlagged):
+			ctx.JSON(http.StatusForbidden, errCodeRes(codeAccountFlagged, err))
+			return
+		case errors.Is(err, db.ErrOfferInactive):
+			ctx.JSON(http.StatusForbidden, errCodeRes(codeOfferInactive, err))
+			return
+		case errors.Is(err, db.ErrOutOfStock):
+			ctx.JSON(http.StatusForbidden, errCodeRes(codeOutOfStock, err))
+			return
+		case errors.Is(err, db.ErrOfferLimitReached):
+			ctx.JSON(http.StatusForbidden, errCodeRes(codeOfferLimitReached, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,459 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	mockdb "github.com/sRRRs-7/GachaPon/db/mock"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/shop"
+	"github.com/sRRRs-7/GachaPon/token"
+	"github.com/sRRRs-7/GachaPon/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func randomShopOffer() db.ShopOffer {
+	return db.ShopOffer{
+		ID:           utils.RandomInt(1, 100),
+		ItemID:       utils.RandomInt(1, 100),
+		Price:        500,
+		SpendOrder:   db.SpendFreeFirst,
+		Stock:        sql.NullInt64{Int64: 100, Valid: true},
+		PerUserLimit: sql.NullInt32{Int32: 2, Valid: true},
+		StartAt:      time.Now().UTC().Add(-time.Hour).Truncate(time.Second),
+		EndAt:        time.Now().UTC().Add(time.Hour).Truncate(time.Second),
+	}
+}
+
+func TestCreateShopOfferAPI(t *testing.T) {
+	offer := randomShopOffer()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"item_id":        offer.ItemID,
+				"price":          offer.Price,
+				"stock":          offer.Stock.Int64,
+				"per_user_limit": offer.PerUserLimit.Int32,
+				"start_at":       offer.StartAt,
+				"end_at":         offer.EndAt,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateShopOfferParams{
+					ItemID:       offer.ItemID,
+					Price:        offer.Price,
+					SpendOrder:   defaultSpendOrder,
+					Stock:        offer.Stock,
+					PerUserLimit: offer.PerUserLimit,
+					StartAt:      offer.StartAt,
+					EndAt:        offer.EndAt,
+				}
+
+				store.EXPECT().
+					CreateShopOffer(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(offer, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.ShopOffer
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, offer.ID, got.ID)
+			},
+		},
+		{
+			name: "Unlimited",
+			body: gin.H{
+				"item_id":     offer.ItemID,
+				"price":       offer.Price,
+				"spend_order": db.SpendPaidOnly,
+				"start_at":    offer.StartAt,
+				"end_at":      offer.EndAt,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateShopOfferParams{
+					ItemID:     offer.ItemID,
+					Price:      offer.Price,
+					SpendOrder: db.SpendPaidOnly,
+					StartAt:    offer.StartAt,
+					EndAt:      offer.EndAt,
+				}
+
+				store.EXPECT().
+					CreateShopOffer(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(offer, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidSpendOrder",
+			body: gin.H{
+				"item_id":     offer.ItemID,
+				"price":       offer.Price,
+				"spend_order": "paid_last",
+				"start_at":    offer.StartAt,
+				"end_at":      offer.EndAt,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateShopOffer(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NegativePrice",
+			body: gin.H{
+				"item_id":  offer.ItemID,
+				"price":    -1,
+				"start_at": offer.StartAt,
+				"end_at":   offer.EndAt,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateShopOffer(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "EndBeforeStart",
+			body: gin.H{
+				"item_id":  offer.ItemID,
+				"price":    offer.Price,
+				"start_at": offer.EndAt,
+				"end_at":   offer.StartAt,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateShopOffer(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownItem",
+			body: gin.H{
+				"item_id":  offer.ItemID,
+				"price":    offer.Price,
+				"start_at": offer.StartAt,
+				"end_at":   offer.EndAt,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateShopOffer(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ShopOffer{}, &pq.Error{Code: "23503"})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/shop/create", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAdminAuthorization(t, request, server.tokenMaker, store)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestRotateShopAPI(t *testing.T) {
+	day := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"day": day,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RotateShopTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.RotateShopTxParams) (db.RotateShopTxResult, error) {
+						require.True(t, day.Equal(arg.Day))
+						require.Equal(t, shop.RotationSize, arg.Size)
+						require.NotNil(t, arg.Source)
+						return db.RotateShopTxResult{Created: true}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Today",
+			body: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RotateShopTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.RotateShopTxParams) (db.RotateShopTxResult, error) {
+						require.WithinDuration(t, time.Now(), arg.Day, time.Second)
+						return db.RotateShopTxResult{}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RotateShopTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RotateShopTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/shop/rotate", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAdminAuthorization(t, request, server.tokenMaker, store)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestShopPurchaseAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+	offer := randomShopOffer()
+
+	purchaseErr := func(err error) func(store *mockdb.MockStore) {
+		return func(store *mockdb.MockStore) {
+			store.EXPECT().
+				ShopPurchaseTx(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return(db.ShopPurchaseTxResult{}, err)
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ShopPurchaseTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.ShopPurchaseTxParams) (db.ShopPurchaseTxResult, error) {
+						require.Equal(t, account.ID, arg.AccountID)
+						require.Equal(t, user.UserName, arg.Owner)
+						require.Equal(t, offer.ID, arg.OfferID)
+						require.WithinDuration(t, time.Now(), arg.Now, time.Second)
+						return db.ShopPurchaseTxResult{Account: account, Offer: offer}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: purchaseErr(sql.ErrNoRows),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NotOwned",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+			},
+			buildStubs: purchaseErr(db.ErrAccountNotOwned),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InsufficientBalance",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: purchaseErr(db.ErrInsufficientBalance),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPaymentRequired, recorder.Code)
+			},
+		},
+		{
+			name: "SpendingCapExceeded",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: purchaseErr(db.ErrSpendingCapExceeded),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorCode(t, recorder, codeSpendingCapExceeded)
+			},
+		},
+		{
+			name: "AccountFlagged",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: purchaseErr(db.ErrAccountFlagged),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorCode(t, recorder, codeAccountFlagged)
+			},
+		},
+		{
+			name: "Inactive",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: purchaseErr(db.ErrOfferInactive),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorCode(t, recorder, codeOfferInactive)
+			},
+		},
+		{
+			name: "OutOfStock",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: purchaseErr(db.ErrOutOfStock),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorCode(t, recorder, codeOutOfStock)
+			},
+		},
+		{
+			name: "LimitReached",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: purchaseErr(db.ErrOfferLimitReached),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorCode(t, recorder, codeOfferLimitReached)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ShopPurchaseTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"account_id": account.ID,
+				"offer_id":   offer.ID,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/shop/purchase", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/token"
+)
+
+// codeSpendingCapExceeded tells clients a purchase or pull was refused by the monthly cap
+const codeSpendingCapExceeded = "spending_cap_exceeded"
+
+// GetSpendingApi returns the month to date spending of the authenticated user against their cap
+func (server *Server) GetSpendingApi(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	account, err := server.store.GetAccountByOwner(ctx, authPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	spending, err := server.store.MonthlySpending(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, spending)
+}
+
+type CreateSpendingCapRequest struct {
+	Region string `json:"region" binding:"required,iso3166_1_alpha2"`
+	MinAge int32  `json:"min_age" binding:"min=0,max=150"`
+	// MonthlyLimit left out makes the band uncapped
+	MonthlyLimit *int64 `json:"monthly_limit" binding:"omitempty,min=0"`
+}
+
+func (server *Server) CreateSpendingCapApi(ctx *gin.Context) {
+	var req CreateSpendingCapRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	arg := db.CreateSpendingCapParams{
+		Region:       req.Region,
+		MinAge:       req.MinAge,
+		MonthlyLimit: nullInt64(req.MonthlyLimit),
+	}
+
+	band, err := server.store.CreateSpendingCap(ctx, arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusForbidden, errRes(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, band)
+}
+
+type ListSpendingCapsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=1,max=50"`
+}
+
+func (server *Server) ListSpendingCapsApi(ctx *gin.Context) {
+	var req ListSpendingCapsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	arg := db.ListSpendingCapsParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	}
+
+	bands, err := server.store.ListSpendingCaps(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, bands)
+}
+
+type UpdateSpendingCapRequest struct {
+	ID           int64  `json:"id" binding:"required,min=1"`
+	MonthlyLimit *int64 `json:"monthly_limit" binding:"omitempty,min=0"`
+}
+
+func (server *Server) UpdateSpendingCapApi(ctx *gin.Context) {
+	var req UpdateSpendingCapRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	arg := db.UpdateSpendingCapParams{
+		ID:           req.ID,
+		MonthlyLimit: nullInt64(req.MonthlyLimit),
+	}
+
+	band, err := server.store.UpdateSpendingCap(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, band)
+}
+
+type DeleteSpendingCapRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) DeleteSpendingCapApi(ctx *gin.Context) {
+	var req DeleteSpendingCapRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	err := server.store.DeleteSpendingCap(ctx, req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// nullInt64 turns an optional limit of a request into a nullable column, nil meaning no limit
+func nullInt64(limit *int64) sql.NullInt64 {
+	if limit == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *limit, Valid: true}
+}
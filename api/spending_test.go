@@ -0,0 +1,251 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/sRRRs-7/GachaPon/db/mock"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/token"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSpendingAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+
+	spending := db.Spending{
+		AccountID: account.ID,
+		Region:    "JP",
+		Age:       15,
+		Since:     db.MonthStart(time.Now()),
+		Capped:    true,
+		Limit:     5000,
+		Purchased: 1200,
+		Spent:     300,
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountByOwner(gomock.Any(), gomock.Eq(user.UserName)).
+					Times(1).
+					Return(account, nil)
+
+				store.EXPECT().
+					MonthlySpending(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(spending, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.Spending
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, spending.Limit, got.Limit)
+				require.Equal(t, spending.Purchased, got.Purchased)
+				require.Equal(t, spending.Spent, got.Spent)
+				require.True(t, got.Capped)
+			},
+		},
+		{
+			name: "NoAccount",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountByOwner(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Account{}, sql.ErrNoRows)
+
+				store.EXPECT().
+					MonthlySpending(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountByOwner(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/user/me/spending", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCreateSpendingCapAPI(t *testing.T) {
+	band := db.SpendingCap{
+		ID:           1,
+		Region:       "JP",
+		MinAge:       16,
+		MonthlyLimit: sql.NullInt64{Int64: 10000, Valid: true},
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"region":        band.Region,
+				"min_age":       band.MinAge,
+				"monthly_limit": band.MonthlyLimit.Int64,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateSpendingCapParams{
+					Region:       band.Region,
+					MinAge:       band.MinAge,
+					MonthlyLimit: band.MonthlyLimit,
+				}
+
+				store.EXPECT().
+					CreateSpendingCap(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(band, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Uncapped",
+			body: gin.H{
+				"region":  band.Region,
+				"min_age": 20,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateSpendingCapParams{
+					Region: band.Region,
+					MinAge: 20,
+				}
+
+				store.EXPECT().
+					CreateSpendingCap(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.SpendingCap{Region: band.Region, MinAge: 20}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidRegion",
+			body: gin.H{
+				"region":        "Japan",
+				"min_age":       band.MinAge,
+				"monthly_limit": band.MonthlyLimit.Int64,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateSpendingCap(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NegativeLimit",
+			body: gin.H{
+				"region":        band.Region,
+				"min_age":       band.MinAge,
+				"monthly_limit": -1,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateSpendingCap(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/spendingCap/create", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAdminAuthorization(t, request, server.tokenMaker, store)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func requireErrorCode(t *testing.T, recorder *httptest.ResponseRecorder, code string) {
+	var got struct {
+		Code string `json:"code"`
+	}
+	err := json.Unmarshal(recorder.Body.Bytes(), &got)
+	require.NoError(t, err)
+	require.Equal(t, code, got.Code)
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/pass"
+	"github.com/sRRRs-7/GachaPon/token"
+)
+
+// codes of the pass errors, so clients can tell the user why a pass was refused
+const (
+	codePassStackFull = "pass_stack_full"
+	codeNoActivePass  = "no_active_pass"
+)
+
+// passSpendOrder makes passes a paid currency purchase
+const passSpendOrder = db.SpendPaidOnly
+
+var errPassNotFound = errors.New("pass not found")
+
+func (server *Server) ListPassesApi(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, pass.List())
+}
+
+type SubscribeRequest struct {
+	AccountID int64  `json:"account_id" binding:"required,min=1"`
+	PassID    string `json:"pass_id" binding:"required"`
+}
+
+// SubscribeApi buys a pass for an account of the authenticated user,
+// a pass bought while another one lasts starts when it expires
+func (server *Server) SubscribeApi(ctx *gin.Context) {
+	var req SubscribeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	p, ok := pass.Find(req.PassID)
+	if !ok {
+		ctx.JSON(http.StatusNotFound, errRes(errPassNotFound))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.SubscribeTxParams{
+		AccountID:  req.AccountID,
+		Owner:      authPayload.Username,
+		Pass:       p,
+		SpendOrder: passSpendOrder,
+		Now:        time.Now(),
+	}
+
+	result, err := server.store.SubscribeTx(ctx, arg)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		case errors.Is(err, db.ErrAccountNotOwned):
+			ctx.JSON(http.StatusUnauthorized, errRes(err))
+			return
+		case errors.Is(err, db.ErrInsufficientBalance):
+			ctx.JSON(http.StatusPaymentRequired, errRes(err))
+			return
+		case errors.Is(err, db.ErrSpendingCapExceeded):
+			ctx.JSON(http.StatusForbidden, errCodeRes(codeSpendingCapExceeded, err))
+			return
+		case errors.Is(err, db.ErrAccountFlagged):
+			ctx.JSON(http.StatusForbidden, errCodeRes(codeAccountFlagged, err))
+			return
+		case errors.Is(err, pass.ErrStackFull):
+			ctx.JSON(http.StatusForbidden, errCodeRes(codePassStackFull, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type SubscriptionClaimRequest struct {
+	AccountID int64 `json:"account_id" binding:"required,min=1"`
+}
+
+// SubscriptionClaimApi grants the daily amount of the pass of an account of the authenticated user
+func (server *Server) SubscriptionClaimApi(ctx *gin.Context) {
+	var req SubscriptionClaimRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.SubscriptionClaimTxParams{
+		AccountID: req.AccountID,
+		Owner:     authPayload.Username,
+		Now:       time.Now(),
+	}
+
+	result, err := server.store.SubscriptionClaimTx(ctx, arg)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		case errors.Is(err, db.ErrAccountNotOwned):
+			ctx.JSON(http.StatusUnauthorized, errRes(err))
+			return
+		case errors.Is(err, db.ErrNoActivePass):
+			ctx.JSON(http.StatusForbidden, errCodeRes(codeNoActivePass, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type ListSubscriptionsRequest struct {
+	AccountID int64 `form:"account_id" binding:"required,min=1"`
+	PageID    int32 `form:"page_id" binding:"required,min=1"`
+	PageSize  int32 `form:"page_size" binding:"required,min=1,max=50"`
+}
+
+// ListSubscriptionsApi lists the passes of an account of the authenticated user, latest first
+func (server *Server) ListSubscriptionsApi(ctx *gin.Context) {
+	var req ListSubscriptionsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, req.AccountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		ctx.JSON(http.StatusUnauthorized, errRes(db.ErrAccountNotOwned))
+		return
+	}
+
+	arg := db.ListSubscriptionsParams{
+		AccountID: account.ID,
+		Limit:     req.PageSize,
+		Offset:    (req.PageID - 1) * req.PageSize,
+	}
+
+	subscriptions, err := server.store.ListSubscriptions(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subscriptions)
+}
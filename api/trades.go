@@ -0,0 +1,334 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/token"
+)
+
+var errNotTradeParty = errors.New("trade offer doesn't involve the authenticated user")
+
+// codes of the trades failing on their copies or their state
+const (
+	codeItemNotOwned = "item_not_owned"
+	codeItemLocked   = "item_locked"
+	codeTradeClosed  = "trade_closed"
+	codeTradeExpired = "trade_expired"
+)
+
+// defaultTradeOfferTTL is how long offers stay open when the config doesn't set it
+const defaultTradeOfferTTL = 72 * time.Hour
+
+// tradeOfferExpiry is the expiry of an offer made now
+func (server *Server) tradeOfferExpiry(now time.Time) time.Time {
+	ttl := server.config.TradeOfferTTL
+	if ttl <= 0 {
+		ttl = defaultTradeOfferTTL
+	}
+	return now.Add(ttl)
+}
+
+// tradeErrRes writes the response of an error of a trade transaction
+func tradeErrRes(ctx *gin.Context, err error) {
+	switch {
+	case err == sql.ErrNoRows:
+		ctx.JSON(http.StatusNotFound, errRes(err))
+	case errors.Is(err, db.ErrAccountNotOwned):
+		ctx.JSON(http.StatusUnauthorized, errRes(err))
+	case errors.Is(err, db.ErrInvalidTrade), errors.Is(err, db.ErrSameAccount):
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+	case errors.Is(err, db.ErrItemNotOwned):
+		// one of the copies isn't held by its side, or left it after the offer was made
+		ctx.JSON(http.StatusForbidden, errCodeRes(codeItemNotOwned, err))
+	case errors.Is(err, db.ErrItemLocked):
+		// another trade of one of the copies is running, the client may retry
+		ctx.JSON(http.StatusConflict, errCodeRes(codeItemLocked, err))
+	case errors.Is(err, db.ErrItemListed):
+		// one of the copies is held in escrow by a market listing
+		ctx.JSON(http.StatusConflict, errCodeRes(codeItemListed, err))
+	case errors.Is(err, db.ErrTradeClosed):
+		ctx.JSON(http.StatusForbidden, errCodeRes(codeTradeClosed, err))
+	case errors.Is(err, db.ErrTradeExpired):
+		ctx.JSON(http.StatusForbidden, errCodeRes(codeTradeExpired, err))
+	case errors.Is(err, db.ErrInsufficientBalance):
+		// a side lacks, or spent, the currency it adds to the offer
+		ctx.JSON(http.StatusPaymentRequired, errRes(err))
+	case errors.Is(err, db.ErrAccountFlagged):
+		ctx.JSON(http.StatusForbidden, errCodeRes(codeAccountFlagged, err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+	}
+}
+
+type ApproveTradeRequest struct {
+	ID       int64 `json:"id" binding:"required,min=1"`
+	Approved bool  `json:"approved"`
+}
+
+// ApproveTradeRequestApi sets the approval of the proposer of a trade offer
+func (server *Server) ApproveTradeRequestApi(ctx *gin.Context) {
+	server.approveTrade(ctx, db.TradeSideRequest)
+}
+
+// ApproveTradeResponseApi accepts a trade offer for its counterparty, or takes
+// the acceptance back while the proposer doesn't approve it
+func (server *Server) ApproveTradeResponseApi(ctx *gin.Context) {
+	server.approveTrade(ctx, db.TradeSideResponse)
+}
+
+// approveTrade sets the approval of one side of a trade offer, the copies
+// being swapped once both sides approved it
+func (server *Server) approveTrade(ctx *gin.Context, side string) {
+	var req ApproveTradeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.ApproveTradeTxParams{
+		ApprovalID: req.ID,
+		Owner:      authPayload.Username,
+		Side:       side,
+		Approved:   req.Approved,
+		Now:        time.Now(),
+	}
+
+	result, err := server.store.ApproveTradeTx(ctx, arg)
+	if err != nil {
+		tradeErrRes(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type TradeOfferRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// GetTradeOfferApi returns a trade offer with the copies of both its sides
+func (server *Server) GetTradeOfferApi(ctx *gin.Context) {
+	var req TradeOfferRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	approval, ok := server.tradeOffer(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	items, err := server.store.ListTradeItems(ctx, approval.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, db.TradeOffer{Approval: approval, Items: items})
+}
+
+// DeleteTradeOfferApi closes an open trade offer, its proposer cancels it
+// and its counterparty rejects it. The offer stays in the history of both.
+func (server *Server) DeleteTradeOfferApi(ctx *gin.Context) {
+	var req TradeOfferRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.CloseTradeOfferTxParams{
+		ApprovalID: req.ID,
+		Owner:      authPayload.Username,
+		Now:        time.Now(),
+	}
+
+	approval, err := server.store.CloseTradeOfferTx(ctx, arg)
+	if err != nil {
+		tradeErrRes(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, approval)
+}
+
+type CounterTradeOfferRequest struct {
+	FromGalleryIDs []int64 `json:"from_gallery_ids" binding:"max=10,dive,min=1"`
+	ToGalleryIDs   []int64 `json:"to_gallery_ids" binding:"max=10,dive,min=1"`
+	FromAmount     int64   `json:"from_amount" binding:"min=0"`
+	ToAmount       int64   `json:"to_amount" binding:"min=0"`
+}
+
+// CounterTradeOfferApi replaces an open offer made to the authenticated user
+// with a counter-offer to its proposer. The from side of the counter-offer is
+// the counterparty of the countered offer.
+func (server *Server) CounterTradeOfferApi(ctx *gin.Context) {
+	var uri TradeOfferRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	var req CounterTradeOfferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	now := time.Now()
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.CounterTradeOfferTxParams{
+		ApprovalID:     uri.ID,
+		Owner:          authPayload.Username,
+		FromGalleryIDs: req.FromGalleryIDs,
+		ToGalleryIDs:   req.ToGalleryIDs,
+		FromAmount:     req.FromAmount,
+		ToAmount:       req.ToAmount,
+		ExpiresAt:      server.tradeOfferExpiry(now),
+		Now:            now,
+	}
+
+	offer, err := server.store.CounterTradeOfferTx(ctx, arg)
+	if err != nil {
+		tradeErrRes(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, offer)
+}
+
+// TradeHistoryApi lists the events of the negotiation a trade offer belongs
+// to, from the first offer through its counter-offers, oldest first
+func (server *Server) TradeHistoryApi(ctx *gin.Context) {
+	var req TradeOfferRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return
+	}
+
+	approval, ok := server.tradeOffer(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	events, err := server.store.ListTradeEvents(ctx, db.TradeThread(approval))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, events)
+}
+
+// tradeOffer reads a trade offer one of whose accounts belongs to the
+// authenticated user, writing the error response otherwise
+func (server *Server) tradeOffer(ctx *gin.Context, id int64) (db.Approval, bool) {
+	approval, err := server.store.GetApproval(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return approval, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return approval, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	for _, accountID := range []int64{approval.FromAccountID, approval.ToAccountID} {
+		account, err := server.store.GetAccount(ctx, accountID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errRes(err))
+			return approval, false
+		}
+		if account.Owner == authPayload.Username {
+			return approval, true
+		}
+	}
+
+	ctx.JSON(http.StatusUnauthorized, errRes(errNotTradeParty))
+	return approval, false
+}
+
+type ListTradeOffersRequest struct {
+	AccountID int64 `form:"account_id" binding:"required,min=1"`
+	PageID    int32 `form:"page_id" binding:"required,min=1"`
+	PageSize  int32 `form:"page_size" binding:"required,min=1,max=50"`
+}
+
+// ListIncomingTradesApi lists the open offers made to an account of the authenticated user, newest first
+func (server *Server) ListIncomingTradesApi(ctx *gin.Context) {
+	req, ok := server.bindTradeList(ctx)
+	if !ok {
+		return
+	}
+
+	arg := db.ListIncomingApprovalsParams{
+		ToAccountID: req.AccountID,
+		Limit:       req.PageSize,
+		Offset:      (req.PageID - 1) * req.PageSize,
+	}
+
+	approvals, err := server.store.ListIncomingApprovals(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, approvals)
+}
+
+// ListOutgoingTradesApi lists the open offers made by an account of the authenticated user, newest first
+func (server *Server) ListOutgoingTradesApi(ctx *gin.Context) {
+	req, ok := server.bindTradeList(ctx)
+	if !ok {
+		return
+	}
+
+	arg := db.ListOutgoingApprovalsParams{
+		FromAccountID: req.AccountID,
+		Limit:         req.PageSize,
+		Offset:        (req.PageID - 1) * req.PageSize,
+	}
+
+	approvals, err := server.store.ListOutgoingApprovals(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, approvals)
+}
+
+// bindTradeList binds a trade or listing list query whose account belongs to
+// the authenticated user, writing the error response otherwise
+func (server *Server) bindTradeList(ctx *gin.Context) (ListTradeOffersRequest, bool) {
+	var req ListTradeOffersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errRes(err))
+		return req, false
+	}
+
+	account, err := server.store.GetAccount(ctx, req.AccountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errRes(err))
+			return req, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errRes(err))
+		return req, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		ctx.JSON(http.StatusUnauthorized, errRes(db.ErrAccountNotOwned))
+		return req, false
+	}
+	return req, true
+}
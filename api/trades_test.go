@@ -0,0 +1,806 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/sRRRs-7/GachaPon/db/mock"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/token"
+	"github.com/sRRRs-7/GachaPon/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func randomApproval(from, to db.Account) db.Approval {
+	return db.Approval{
+		ID:            utils.RandomInt(1, 100),
+		FromAccountID: from.ID,
+		FromAApproval: true,
+		ToAccountID:   to.ID,
+	}
+}
+
+func randomTradeItem(side string, gallery db.Gallery) db.TradeItem {
+	return db.TradeItem{
+		ID:        utils.RandomInt(1, 1000),
+		Side:      side,
+		GalleryID: sql.NullInt64{Int64: gallery.ID, Valid: true},
+		ItemID:    gallery.ItemID,
+	}
+}
+
+// requireApproveTradeParams compares the params of an approval made now
+func requireApproveTradeParams(t *testing.T, want, got db.ApproveTradeTxParams) {
+	require.WithinDuration(t, time.Now(), got.Now, time.Second)
+	got.Now = time.Time{}
+	require.Equal(t, want, got)
+}
+
+func TestApproveTradeAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	approval := randomApproval(randomAccount(user.UserName), randomAccount(user.UserName))
+
+	testCases := []struct {
+		name          string
+		url           string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Accept",
+			url:  "/exchange/response",
+			body: gin.H{
+				"id":       approval.ID,
+				"approved": true,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ApproveTradeTxParams{
+					ApprovalID: approval.ID,
+					Owner:      user.UserName,
+					Side:       db.TradeSideResponse,
+					Approved:   true,
+				}
+
+				accepted := approval
+				accepted.ToAApproval = true
+				store.EXPECT().
+					ApproveTradeTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, got db.ApproveTradeTxParams) (db.ApproveTradeTxResult, error) {
+						requireApproveTradeParams(t, arg, got)
+						return db.ApproveTradeTxResult{Approval: accepted, Exchange: &db.ExchangeTxResult{}}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.ApproveTradeTxResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.NotNil(t, got.Exchange)
+			},
+		},
+		{
+			name: "Withdraw",
+			url:  "/exchange/request",
+			body: gin.H{
+				"id":       approval.ID,
+				"approved": false,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ApproveTradeTxParams{
+					ApprovalID: approval.ID,
+					Owner:      user.UserName,
+					Side:       db.TradeSideRequest,
+					Approved:   false,
+				}
+
+				store.EXPECT().
+					ApproveTradeTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, got db.ApproveTradeTxParams) (db.ApproveTradeTxResult, error) {
+						requireApproveTradeParams(t, arg, got)
+						return db.ApproveTradeTxResult{Approval: approval}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.ApproveTradeTxResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Nil(t, got.Exchange)
+			},
+		},
+		{
+			name: "OtherSide",
+			url:  "/exchange/response",
+			body: gin.H{
+				"id":       approval.ID,
+				"approved": true,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApproveTradeTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApproveTradeTxResult{}, db.ErrAccountNotOwned)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			url:  "/exchange/response",
+			body: gin.H{
+				"id":       approval.ID,
+				"approved": true,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApproveTradeTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApproveTradeTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "ItemNotOwned",
+			url:  "/exchange/response",
+			body: gin.H{
+				"id":       approval.ID,
+				"approved": true,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApproveTradeTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApproveTradeTxResult{}, db.ErrItemNotOwned)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorCode(t, recorder, codeItemNotOwned)
+			},
+		},
+		{
+			name: "ItemLocked",
+			url:  "/exchange/response",
+			body: gin.H{
+				"id":       approval.ID,
+				"approved": true,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApproveTradeTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApproveTradeTxResult{}, db.ErrItemLocked)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorCode(t, recorder, codeItemLocked)
+			},
+		},
+		{
+			name: "Closed",
+			url:  "/exchange/response",
+			body: gin.H{
+				"id":       approval.ID,
+				"approved": true,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApproveTradeTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApproveTradeTxResult{}, db.ErrTradeClosed)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorCode(t, recorder, codeTradeClosed)
+			},
+		},
+		{
+			name: "MissingID",
+			url:  "/exchange/response",
+			body: gin.H{
+				"approved": true,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApproveTradeTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			url:  "/exchange/response",
+			body: gin.H{
+				"id":       approval.ID,
+				"approved": true,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApproveTradeTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, tc.url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetTradeOfferAPI(t *testing.T) {
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+	from := randomAccount(user1.UserName)
+	to := randomAccount(user2.UserName)
+	approval := randomApproval(from, to)
+	approval.ToAmount = 100
+	items := []db.TradeItem{
+		randomTradeItem(db.TradeSideRequest, randomGallery()),
+		randomTradeItem(db.TradeSideRequest, randomGallery()),
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApproval(gomock.Any(), gomock.Eq(approval.ID)).
+					Times(1).
+					Return(approval, nil)
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(from.ID)).
+					Times(1).
+					Return(from, nil)
+				store.EXPECT().
+					ListTradeItems(gomock.Any(), gomock.Eq(approval.ID)).
+					Times(1).
+					Return(items, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.TradeOffer
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, approval, got.Approval)
+				require.Equal(t, items, got.Items)
+			},
+		},
+		{
+			name: "NotParty",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApproval(gomock.Any(), gomock.Eq(approval.ID)).
+					Times(1).
+					Return(approval, nil)
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(2).
+					DoAndReturn(func(_ interface{}, id int64) (db.Account, error) {
+						if id == from.ID {
+							return from, nil
+						}
+						return to, nil
+					})
+				store.EXPECT().
+					ListTradeItems(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/exchange/offer/%d", approval.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDeleteTradeOfferAPI(t *testing.T) {
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+	from := randomAccount(user1.UserName)
+	to := randomAccount(user2.UserName)
+	approval := randomApproval(from, to)
+
+	closeTx := func(owner string, closed db.Approval, err error) func(store *mockdb.MockStore) {
+		return func(store *mockdb.MockStore) {
+			store.EXPECT().
+				CloseTradeOfferTx(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ interface{}, arg db.CloseTradeOfferTxParams) (db.Approval, error) {
+					require.Equal(t, approval.ID, arg.ApprovalID)
+					require.Equal(t, owner, arg.Owner)
+					require.WithinDuration(t, time.Now(), arg.Now, time.Second)
+					return closed, err
+				})
+		}
+	}
+	cancelled := approval
+	cancelled.Status = db.TradeCancelled
+	rejected := approval
+	rejected.Status = db.TradeRejected
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Cancel",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.UserName, time.Minute)
+			},
+			buildStubs: closeTx(user1.UserName, cancelled, nil),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.Approval
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, db.TradeCancelled, got.Status)
+			},
+		},
+		{
+			name: "Reject",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user2.UserName, time.Minute)
+			},
+			buildStubs: closeTx(user2.UserName, rejected, nil),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.Approval
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, db.TradeRejected, got.Status)
+			},
+		},
+		{
+			name: "NotParty",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+			},
+			buildStubs: closeTx("unauthorized_user", db.Approval{}, db.ErrAccountNotOwned),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Closed",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.UserName, time.Minute)
+			},
+			buildStubs: closeTx(user1.UserName, db.Approval{}, db.ErrTradeClosed),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorCode(t, recorder, codeTradeClosed)
+			},
+		},
+		{
+			name: "NotFound",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.UserName, time.Minute)
+			},
+			buildStubs: closeTx(user1.UserName, db.Approval{}, sql.ErrNoRows),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/exchange/offer/%d", approval.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCounterTradeOfferAPI(t *testing.T) {
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+	from := randomAccount(user1.UserName)
+	to := randomAccount(user2.UserName)
+	approval := randomApproval(from, to)
+	gallery := randomGallery()
+
+	counter := db.TradeOffer{
+		Approval: randomApproval(to, from),
+		Items:    []db.TradeItem{randomTradeItem(db.TradeSideRequest, gallery)},
+	}
+	counter.Approval.ParentID = sql.NullInt64{Int64: approval.ID, Valid: true}
+	counter.Approval.ThreadID = sql.NullInt64{Int64: approval.ID, Valid: true}
+	counter.Approval.ToAmount = 30
+
+	body := gin.H{
+		"from_gallery_ids": []int64{gallery.ID},
+		"to_amount":        30,
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: body,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user2.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CounterTradeOfferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CounterTradeOfferTxParams) (db.TradeOffer, error) {
+						require.Equal(t, approval.ID, arg.ApprovalID)
+						require.Equal(t, user2.UserName, arg.Owner)
+						require.Equal(t, []int64{gallery.ID}, arg.FromGalleryIDs)
+						require.Empty(t, arg.ToGalleryIDs)
+						require.Equal(t, int64(30), arg.ToAmount)
+						require.WithinDuration(t, time.Now(), arg.Now, time.Second)
+						require.Equal(t, arg.Now.Add(defaultTradeOfferTTL), arg.ExpiresAt)
+						return counter, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.TradeOffer
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, counter.Approval.ID, got.Approval.ID)
+				require.Equal(t, approval.ID, got.Approval.ParentID.Int64)
+			},
+		},
+		{
+			name: "NotCounterparty",
+			body: body,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CounterTradeOfferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TradeOffer{}, db.ErrAccountNotOwned)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Expired",
+			body: body,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user2.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CounterTradeOfferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TradeOffer{}, db.ErrTradeExpired)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorCode(t, recorder, codeTradeExpired)
+			},
+		},
+		{
+			name: "TooManyItems",
+			body: gin.H{
+				"from_gallery_ids": []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user2.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CounterTradeOfferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/exchange/offer/%d/counter", approval.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestTradeHistoryAPI(t *testing.T) {
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+	from := randomAccount(user1.UserName)
+	to := randomAccount(user2.UserName)
+
+	// the counter-offer of the counterparty to a first offer
+	counter := randomApproval(to, from)
+	counter.ThreadID = sql.NullInt64{Int64: counter.ID + 1, Valid: true}
+	events := []db.TradeEvent{
+		{ID: 1, ApprovalID: counter.ThreadID.Int64, ThreadID: counter.ThreadID.Int64, Event: db.TradeEventCreated},
+		{ID: 2, ApprovalID: counter.ThreadID.Int64, ThreadID: counter.ThreadID.Int64, Event: db.TradeCountered},
+		{ID: 3, ApprovalID: counter.ID, ThreadID: counter.ThreadID.Int64, Event: db.TradeEventCreated},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetApproval(gomock.Any(), gomock.Eq(counter.ID)).
+		Times(1).
+		Return(counter, nil)
+	store.EXPECT().
+		GetAccount(gomock.Any(), gomock.Eq(to.ID)).
+		Times(1).
+		Return(to, nil)
+	store.EXPECT().
+		ListTradeEvents(gomock.Any(), gomock.Eq(counter.ThreadID.Int64)).
+		Times(1).
+		Return(events, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	url := fmt.Sprintf("/exchange/offer/%d/history", counter.ID)
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user2.UserName, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var got []db.TradeEvent
+	err = json.Unmarshal(recorder.Body.Bytes(), &got)
+	require.NoError(t, err)
+	require.Equal(t, events, got)
+}
+
+func TestListIncomingTradesAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.UserName)
+	approvals := []db.Approval{
+		randomApproval(randomAccount(user.UserName), account),
+		randomApproval(randomAccount(user.UserName), account),
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: fmt.Sprintf("account_id=%d&page_id=1&page_size=5", account.ID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				arg := db.ListIncomingApprovalsParams{
+					ToAccountID: account.ID,
+					Limit:       5,
+					Offset:      0,
+				}
+				store.EXPECT().
+					ListIncomingApprovals(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(approvals, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got []db.Approval
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, approvals, got)
+			},
+		},
+		{
+			name:  "NotOwned",
+			query: fmt.Sprintf("account_id=%d&page_id=1&page_size=5", account.ID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListIncomingApprovals(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidPageSize",
+			query: fmt.Sprintf("account_id=%d&page_id=1&page_size=100", account.ID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.UserName, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListIncomingApprovals(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/exchange/incoming?"+tc.query, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
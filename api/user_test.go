@@ -10,6 +10,7 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
@@ -151,6 +152,69 @@ func TestCreateUserAPI(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		{
+			name: "BirthdateAndRegion",
+			body: gin.H{
+				"user_name": user.UserName,
+				"hash_password": password,
+				"full_name": user.FullName,
+				"email":    user.Email,
+				"birthdate": "2010-04-01",
+				"region":   "JP",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateUserParams{
+					UserName: user.UserName,
+					FullName: user.FullName,
+					Email:    user.Email,
+					Birthdate: sql.NullTime{Time: time.Date(2010, 4, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					Region:   "JP",
+				}
+				store.EXPECT().
+					CreateUser(gomock.Any(), EqCreateUserParams(arg, password)).
+					Times(1).
+					Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidBirthdate",
+			body: gin.H{
+				"user_name": user.UserName,
+				"hash_password": password,
+				"full_name": user.FullName,
+				"email":    user.Email,
+				"birthdate": "01/04/2010",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BirthdateInFuture",
+			body: gin.H{
+				"user_name": user.UserName,
+				"hash_password": password,
+				"full_name": user.FullName,
+				"email":    user.Email,
+				"birthdate": time.Now().AddDate(1, 0, 0).Format("2006-01-02"),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "TooShortPassword",
 			body: gin.H{
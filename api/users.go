@@ -2,6 +2,7 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"time"
 
@@ -11,17 +12,26 @@ import (
 	"github.com/sRRRs-7/GachaPon/utils"
 )
 
+// dateLayout is the format of the dates of the requests, such as birthdates
+const dateLayout = "2006-01-02"
+
+var errBirthdateInFuture = errors.New("birthdate is in the future")
+
 type CreateUserRequest struct {
 	UserName     string `json:"user_name" binding:"required,alphanum"`
     HashPassword string `json:"hash_password" binding:"required,min=5"`
     FullName     string `json:"full_name" binding:"required"`
     Email        string `json:"email" binding:"required,email"`
+    // Birthdate and Region pick the monthly spending cap of the user
+    Birthdate    string `json:"birthdate" binding:"omitempty,datetime=2006-01-02"`
+    Region       string `json:"region" binding:"omitempty,iso3166_1_alpha2"`
 }
 
 type CreateUserResponse struct {
 	UserName     string `json:"user_name"`
     FullName     string `json:"full_name"`
     Email        string `json:"email"`
+    Region       string `json:"region"`
 }
 
 func (server *Server) CreateUserApi(ctx *gin.Context) {
@@ -42,6 +52,15 @@ func (server *Server) CreateUserApi(ctx *gin.Context) {
 		HashPassword: hashPassword,
 		FullName: req.FullName,
 		Email: req.Email,
+		Region: req.Region,
+	}
+	if req.Birthdate != "" {
+		birthdate, _ := time.Parse(dateLayout, req.Birthdate)
+		if birthdate.After(time.Now()) {
+			ctx.JSON(http.StatusBadRequest, errRes(errBirthdateInFuture))
+			return
+		}
+		arg.Birthdate = sql.NullTime{Time: birthdate, Valid: true}
 	}
 
 	user, err := server.store.CreateUser(ctx, arg)
@@ -61,6 +80,7 @@ func (server *Server) CreateUserApi(ctx *gin.Context) {
 		UserName: user.UserName,
 		FullName: user.FullName,
 		Email: user.Email,
+		Region: user.Region,
 	}
 
 	ctx.JSON(http.StatusOK, res)
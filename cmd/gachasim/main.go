@@ -0,0 +1,117 @@
+// Command gachasim runs simulated draws against the draw engine and checks the
+// observed frequencies against the configured rates with chi-square tests.
+//
+// The pool is read from a JSON file:
+//
+//	{
+//	  "rates": {"1": 4000, "7": 100},
+//	  "entries": [{"item_id": 1, "rating": 1, "weight": 1}, {"item_id": 2, "rating": 7, "weight": 1}]
+//	}
+//
+// Without -config the seeded rarity rates are used with one item per tier.
+// It exits with status 1 when a test falls below the significance level.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/sRRRs-7/GachaPon/gacha"
+)
+
+type config struct {
+	Rates   gacha.Rates   `json:"rates"`
+	Entries []gacha.Entry `json:"entries"`
+}
+
+func defaultConfig() config {
+	cfg := config{
+		Rates: gacha.Rates{1: 4000, 2: 2500, 3: 1500, 4: 1000, 5: 600, 6: 300, 7: 100},
+	}
+	for rating := int32(gacha.MinRating); rating <= gacha.MaxRating; rating++ {
+		cfg.Entries = append(cfg.Entries, gacha.Entry{ItemID: int64(rating), Rating: rating, Weight: 1})
+	}
+	return cfg
+}
+
+func loadConfig(path string) (config, error) {
+	if path == "" {
+		return defaultConfig(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config{}, err
+	}
+
+	var cfg config
+	err = json.Unmarshal(data, &cfg)
+	return cfg, err
+}
+
+func main() {
+	path := flag.String("config", "", "JSON file with the rates and entries of the pool")
+	draws := flag.Int("draws", 1000000, "number of simulated draws")
+	seed := flag.Int64("seed", 1, "seed of the simulation, the same seed replays the same draws")
+	fair := flag.Bool("fair", false, "draw from the HMAC rolls used for real pulls instead of math/rand")
+	alpha := flag.Float64("alpha", 0.001, "significance level below which the run fails")
+	flag.Parse()
+
+	cfg, err := loadConfig(*path)
+	if err != nil {
+		log.Fatal("cannot load config:", err)
+	}
+
+	pool, err := gacha.NewPool(cfg.Entries, cfg.Rates)
+	if err != nil {
+		log.Fatal("cannot build pool:", err)
+	}
+
+	var src gacha.Source = rand.New(rand.NewSource(*seed))
+	if *fair {
+		src = &gacha.FairSource{ServerSeed: "gachasim", ClientSeed: strconv.FormatInt(*seed, 10)}
+	}
+
+	counts := gacha.Simulate(pool, src, *draws)
+	probs := gacha.ConfiguredProbabilities(pool.Entries(), cfg.Rates)
+
+	ratings, tierCounts := gacha.TierCounts(pool, counts)
+	tierProbs := make([]float64, len(ratings))
+	for i, e := range pool.Entries() {
+		for j, rating := range ratings {
+			if rating == e.Rating {
+				tierProbs[j] += probs[i]
+			}
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RARITY\tEXPECTED\tOBSERVED\tDRAWS")
+	for i, rating := range ratings {
+		observed := float64(tierCounts[i]) / float64(*draws)
+		fmt.Fprintf(w, "%d\t%.4f%%\t%.4f%%\t%d\n", rating, tierProbs[i]*100, observed*100, tierCounts[i])
+	}
+	fmt.Fprintln(w, "\nITEM\tEXPECTED\tOBSERVED\tDRAWS")
+	for i, e := range pool.Entries() {
+		observed := float64(counts[i]) / float64(*draws)
+		fmt.Fprintf(w, "%d\t%.4f%%\t%.4f%%\t%d\n", e.ItemID, probs[i]*100, observed*100, counts[i])
+	}
+	w.Flush()
+
+	tiers := gacha.ChiSquare(tierCounts, tierProbs)
+	items := gacha.ChiSquare(counts, probs)
+	fmt.Printf("\ntiers: chi2=%.3f df=%d p=%.4f\n", tiers.Statistic, tiers.DF, tiers.PValue)
+	fmt.Printf("items: chi2=%.3f df=%d p=%.4f\n", items.Statistic, items.DF, items.PValue)
+
+	if tiers.PValue < *alpha || items.PValue < *alpha {
+		fmt.Printf("FAIL: observed frequencies drift from the configured rates (alpha=%g)\n", *alpha)
+		os.Exit(1)
+	}
+	fmt.Println("PASS")
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "rarity_rates";
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE "rarity_rates" (
+  "rating" int PRIMARY KEY,
+  "weight" bigint NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+INSERT INTO "rarity_rates" ("rating", "weight") VALUES
+  (1, 4000),
+  (2, 2500),
+  (3, 1500),
+  (4, 1000),
+  (5, 600),
+  (6, 300),
+  (7, 100);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "banner_rates";
+DROP TABLE IF EXISTS "banner_items";
+DROP TABLE IF EXISTS "banners";
@@ -0,0 +1,33 @@
+CREATE TABLE "banners" (
+  "id" bigserial PRIMARY KEY,
+  "banner_name" varchar NOT NULL,
+  "cost" bigint NOT NULL,
+  "is_active" boolean NOT NULL DEFAULT true,
+  "start_at" timestamptz NOT NULL,
+  "end_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE TABLE "banner_items" (
+  "id" bigserial PRIMARY KEY,
+  "banner_id" bigint NOT NULL,
+  "item_id" bigint NOT NULL,
+  "weight" bigint NOT NULL DEFAULT 1,
+  "is_featured" boolean NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE TABLE "banner_rates" (
+  "banner_id" bigint NOT NULL,
+  "rating" int NOT NULL,
+  "weight" bigint NOT NULL,
+  PRIMARY KEY ("banner_id", "rating")
+);
+
+CREATE UNIQUE INDEX ON "banner_items" USING BTREE ("banner_id", "item_id");
+
+ALTER TABLE "banner_items" ADD FOREIGN KEY ("banner_id") REFERENCES "banners" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "banner_items" ADD FOREIGN KEY ("item_id") REFERENCES "items" ("id");
+
+ALTER TABLE "banner_rates" ADD FOREIGN KEY ("banner_id") REFERENCES "banners" ("id") ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+ALTER TABLE "banners" DROP COLUMN IF EXISTS "guarantee_rating";
+ALTER TABLE "banners" DROP COLUMN IF EXISTS "multi_cost";
+ALTER TABLE "banners" DROP COLUMN IF EXISTS "multi_count";
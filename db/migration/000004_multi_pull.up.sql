@@ -0,0 +1,7 @@
+ALTER TABLE "banners" ADD COLUMN "multi_count" int NOT NULL DEFAULT 10;
+
+ALTER TABLE "banners" ADD COLUMN "multi_cost" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "banners" ADD COLUMN "guarantee_rating" int NOT NULL DEFAULT 0;
+
+UPDATE "banners" SET "multi_cost" = "cost" * 9;
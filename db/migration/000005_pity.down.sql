@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "pities";
+ALTER TABLE "banners" DROP COLUMN IF EXISTS "soft_pity_step";
+ALTER TABLE "banners" DROP COLUMN IF EXISTS "hard_pity";
+ALTER TABLE "banners" DROP COLUMN IF EXISTS "soft_pity";
@@ -0,0 +1,17 @@
+ALTER TABLE "banners" ADD COLUMN "soft_pity" int NOT NULL DEFAULT 0;
+
+ALTER TABLE "banners" ADD COLUMN "hard_pity" int NOT NULL DEFAULT 0;
+
+ALTER TABLE "banners" ADD COLUMN "soft_pity_step" bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE "pities" (
+  "account_id" bigint NOT NULL,
+  "banner_id" bigint NOT NULL,
+  "pulls" int NOT NULL DEFAULT 0,
+  "updated_at" timestamptz NOT NULL DEFAULT 'now()',
+  PRIMARY KEY ("account_id", "banner_id")
+);
+
+ALTER TABLE "pities" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "pities" ADD FOREIGN KEY ("banner_id") REFERENCES "banners" ("id") ON DELETE CASCADE;
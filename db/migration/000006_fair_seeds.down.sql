@@ -0,0 +1,4 @@
+ALTER TABLE "gachas" DROP COLUMN IF EXISTS "roll";
+ALTER TABLE "gachas" DROP COLUMN IF EXISTS "nonce";
+ALTER TABLE "gachas" DROP COLUMN IF EXISTS "seed_id";
+DROP TABLE IF EXISTS "seeds";
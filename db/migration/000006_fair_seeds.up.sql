@@ -0,0 +1,24 @@
+CREATE TABLE "seeds" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "server_seed" varchar NOT NULL,
+  "server_seed_hash" varchar NOT NULL,
+  "client_seed" varchar NOT NULL,
+  "nonce" bigint NOT NULL DEFAULT 0,
+  "revealed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX ON "seeds" ("account_id");
+
+CREATE UNIQUE INDEX ON "seeds" ("account_id") WHERE "revealed_at" IS NULL;
+
+ALTER TABLE "seeds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "gachas" ADD COLUMN "seed_id" bigint;
+
+ALTER TABLE "gachas" ADD COLUMN "nonce" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "gachas" ADD COLUMN "roll" double precision NOT NULL DEFAULT 0;
+
+ALTER TABLE "gachas" ADD FOREIGN KEY ("seed_id") REFERENCES "seeds" ("id");
@@ -0,0 +1,16 @@
+-- only succeeds while every item is owned at most once and every account owns at most one item
+ALTER TABLE "approval" DROP CONSTRAINT IF EXISTS "approval_to_item_id_fkey";
+ALTER TABLE "approval" DROP CONSTRAINT IF EXISTS "approval_from_item_id_fkey";
+ALTER TABLE "exchanges" DROP CONSTRAINT IF EXISTS "exchanges_item_id_fkey";
+ALTER TABLE "approval" DROP COLUMN IF EXISTS "to_gallery_id";
+ALTER TABLE "approval" DROP COLUMN IF EXISTS "from_gallery_id";
+ALTER TABLE "exchanges" DROP COLUMN IF EXISTS "gallery_id";
+ALTER TABLE "gachas" DROP COLUMN IF EXISTS "gallery_id";
+DROP INDEX IF EXISTS "galleries_item_id_idx";
+DROP INDEX IF EXISTS "galleries_owner_id_item_id_idx";
+ALTER TABLE "galleries" ADD CONSTRAINT "galleries_item_id_key" UNIQUE ("item_id");
+CREATE UNIQUE INDEX ON "galleries" USING BTREE ("owner_id");
+CREATE UNIQUE INDEX ON "galleries" USING BTREE ("item_id");
+ALTER TABLE "approval" ADD FOREIGN KEY ("from_item_id") REFERENCES "galleries" ("item_id");
+ALTER TABLE "approval" ADD FOREIGN KEY ("to_item_id") REFERENCES "galleries" ("item_id");
+ALTER TABLE "exchanges" ADD FOREIGN KEY ("item_id") REFERENCES "galleries" ("item_id");
@@ -0,0 +1,59 @@
+-- every gallery row becomes a single copy of an item, so an item can be owned
+-- many times and an account can own many items
+
+ALTER TABLE "approval" DROP CONSTRAINT IF EXISTS "approval_from_item_id_fkey";
+
+ALTER TABLE "approval" DROP CONSTRAINT IF EXISTS "approval_to_item_id_fkey";
+
+ALTER TABLE "exchanges" DROP CONSTRAINT IF EXISTS "exchanges_item_id_fkey";
+
+DROP INDEX IF EXISTS "galleries_owner_id_idx";
+
+DROP INDEX IF EXISTS "galleries_item_id_idx";
+
+ALTER TABLE "galleries" DROP CONSTRAINT IF EXISTS "galleries_item_id_key";
+
+CREATE INDEX ON "galleries" USING BTREE ("owner_id", "item_id");
+
+CREATE INDEX ON "galleries" USING BTREE ("item_id");
+
+ALTER TABLE "gachas" ADD COLUMN "gallery_id" bigint;
+
+ALTER TABLE "exchanges" ADD COLUMN "gallery_id" bigint;
+
+ALTER TABLE "approval" ADD COLUMN "from_gallery_id" bigint;
+
+ALTER TABLE "approval" ADD COLUMN "to_gallery_id" bigint;
+
+-- item ids were unique across galleries, so they still point at a single copy
+UPDATE "gachas" SET "gallery_id" = "galleries"."id"
+FROM "galleries" WHERE "galleries"."item_id" = "gachas"."item_id";
+
+UPDATE "exchanges" SET "gallery_id" = "galleries"."id"
+FROM "galleries" WHERE "galleries"."item_id" = "exchanges"."item_id";
+
+UPDATE "approval" SET "from_gallery_id" = "galleries"."id"
+FROM "galleries" WHERE "galleries"."item_id" = "approval"."from_item_id";
+
+UPDATE "approval" SET "to_gallery_id" = "galleries"."id"
+FROM "galleries" WHERE "galleries"."item_id" = "approval"."to_item_id";
+
+ALTER TABLE "exchanges" ALTER COLUMN "gallery_id" SET NOT NULL;
+
+ALTER TABLE "approval" ALTER COLUMN "from_gallery_id" SET NOT NULL;
+
+ALTER TABLE "approval" ALTER COLUMN "to_gallery_id" SET NOT NULL;
+
+ALTER TABLE "gachas" ADD FOREIGN KEY ("gallery_id") REFERENCES "galleries" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "exchanges" ADD FOREIGN KEY ("gallery_id") REFERENCES "galleries" ("id");
+
+ALTER TABLE "exchanges" ADD FOREIGN KEY ("item_id") REFERENCES "items" ("id");
+
+ALTER TABLE "approval" ADD FOREIGN KEY ("from_gallery_id") REFERENCES "galleries" ("id");
+
+ALTER TABLE "approval" ADD FOREIGN KEY ("to_gallery_id") REFERENCES "galleries" ("id");
+
+ALTER TABLE "approval" ADD FOREIGN KEY ("from_item_id") REFERENCES "items" ("id");
+
+ALTER TABLE "approval" ADD FOREIGN KEY ("to_item_id") REFERENCES "items" ("id");
@@ -0,0 +1,12 @@
+-- only succeeds while no traded copy has been converted into shards
+ALTER TABLE "approval" DROP CONSTRAINT IF EXISTS "approval_to_gallery_id_fkey";
+ALTER TABLE "approval" DROP CONSTRAINT IF EXISTS "approval_from_gallery_id_fkey";
+ALTER TABLE "exchanges" DROP CONSTRAINT IF EXISTS "exchanges_gallery_id_fkey";
+ALTER TABLE "approval" ALTER COLUMN "to_gallery_id" SET NOT NULL;
+ALTER TABLE "approval" ALTER COLUMN "from_gallery_id" SET NOT NULL;
+ALTER TABLE "exchanges" ALTER COLUMN "gallery_id" SET NOT NULL;
+ALTER TABLE "approval" ADD FOREIGN KEY ("to_gallery_id") REFERENCES "galleries" ("id");
+ALTER TABLE "approval" ADD FOREIGN KEY ("from_gallery_id") REFERENCES "galleries" ("id");
+ALTER TABLE "exchanges" ADD FOREIGN KEY ("gallery_id") REFERENCES "galleries" ("id");
+ALTER TABLE "banners" DROP COLUMN IF EXISTS "spark_cost";
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "shards";
@@ -0,0 +1,24 @@
+ALTER TABLE "accounts" ADD COLUMN "shards" bigint NOT NULL DEFAULT 0;
+
+-- a spark cost of 0 turns sparking off for the banner
+ALTER TABLE "banners" ADD COLUMN "spark_cost" bigint NOT NULL DEFAULT 0;
+
+-- converted copies are deleted, trade history keeps the item but loses the copy
+
+ALTER TABLE "exchanges" DROP CONSTRAINT IF EXISTS "exchanges_gallery_id_fkey";
+
+ALTER TABLE "approval" DROP CONSTRAINT IF EXISTS "approval_from_gallery_id_fkey";
+
+ALTER TABLE "approval" DROP CONSTRAINT IF EXISTS "approval_to_gallery_id_fkey";
+
+ALTER TABLE "exchanges" ALTER COLUMN "gallery_id" DROP NOT NULL;
+
+ALTER TABLE "approval" ALTER COLUMN "from_gallery_id" DROP NOT NULL;
+
+ALTER TABLE "approval" ALTER COLUMN "to_gallery_id" DROP NOT NULL;
+
+ALTER TABLE "exchanges" ADD FOREIGN KEY ("gallery_id") REFERENCES "galleries" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "approval" ADD FOREIGN KEY ("from_gallery_id") REFERENCES "galleries" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "approval" ADD FOREIGN KEY ("to_gallery_id") REFERENCES "galleries" ("id") ON DELETE SET NULL;
@@ -0,0 +1,43 @@
+-- a journal groups the balanced entries of a single currency movement,
+-- the amounts of its entries always sum up to 0
+CREATE TABLE "ledger_journals" (
+  "id" bigserial PRIMARY KEY,
+  "reason" varchar NOT NULL,
+  "reference_id" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+-- entries on the "accounts" book belong to a player account,
+-- entries on any other book to a system book such as revenue or payments
+CREATE TABLE "ledger_entries" (
+  "id" bigserial PRIMARY KEY,
+  "journal_id" bigint NOT NULL,
+  "account_id" bigint,
+  "book" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX ON "ledger_journals" ("reason", "reference_id");
+
+CREATE INDEX ON "ledger_entries" ("account_id", "id");
+
+CREATE INDEX ON "ledger_entries" ("journal_id");
+
+ALTER TABLE "ledger_entries" ADD FOREIGN KEY ("journal_id") REFERENCES "ledger_journals" ("id");
+
+ALTER TABLE "ledger_entries" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+-- existing balances are opened against the equity book
+INSERT INTO "ledger_journals" ("reason", "reference_id")
+SELECT 'opening_balance', "id"::varchar FROM "accounts" WHERE "balance" <> 0;
+
+INSERT INTO "ledger_entries" ("journal_id", "account_id", "book", "amount")
+SELECT "ledger_journals"."id", "accounts"."id", 'accounts', "accounts"."balance"
+FROM "ledger_journals" JOIN "accounts" ON "ledger_journals"."reference_id" = "accounts"."id"::varchar
+WHERE "ledger_journals"."reason" = 'opening_balance';
+
+INSERT INTO "ledger_entries" ("journal_id", "book", "amount")
+SELECT "ledger_journals"."id", 'equity', -"accounts"."balance"
+FROM "ledger_journals" JOIN "accounts" ON "ledger_journals"."reference_id" = "accounts"."id"::varchar
+WHERE "ledger_journals"."reason" = 'opening_balance';
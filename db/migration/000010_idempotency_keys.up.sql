@@ -0,0 +1,16 @@
+-- a key is scoped to the user sending it, the response is stored once the
+-- first request with the key has been handled
+CREATE TABLE "idempotency_keys" (
+  "username" varchar NOT NULL,
+  "idempotency_key" varchar NOT NULL,
+  "request_hash" varchar NOT NULL,
+  "response_status" int NOT NULL DEFAULT 0,
+  "response_body" bytea NOT NULL DEFAULT '',
+  "content_type" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  PRIMARY KEY ("username", "idempotency_key")
+);
+
+CREATE INDEX ON "idempotency_keys" ("created_at");
+
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("username") REFERENCES "users" ("user_name") ON DELETE CASCADE;
@@ -0,0 +1,19 @@
+-- a purchase order moves from pending to paid and credited, or ends failed,
+-- a credited order can later be refunded
+CREATE TABLE "orders" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "pack_id" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "price" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "provider" varchar NOT NULL,
+  "provider_ref" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  "updated_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX ON "orders" ("account_id", "id");
+
+ALTER TABLE "orders" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
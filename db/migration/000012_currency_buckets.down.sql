@@ -0,0 +1,15 @@
+ALTER TABLE "gachas" DROP COLUMN IF EXISTS "free_amount";
+ALTER TABLE "gachas" DROP COLUMN IF EXISTS "paid_amount";
+ALTER TABLE "banners" DROP COLUMN IF EXISTS "spend_order";
+ALTER TABLE "accounts" ADD COLUMN "total_balance" bigint NOT NULL DEFAULT 0;
+UPDATE "accounts" SET "total_balance" = "balance";
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "balance";
+ALTER TABLE "accounts" RENAME COLUMN "total_balance" TO "balance";
+ALTER TABLE "accounts" ALTER COLUMN "balance" SET DEFAULT 100;
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "free_balance";
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "paid_balance";
+DELETE FROM "ledger_entries" WHERE "journal_id" IN (
+  SELECT "id" FROM "ledger_journals" WHERE "reason" = 'bucket_transfer'
+);
+DELETE FROM "ledger_journals" WHERE "reason" = 'bucket_transfer';
+ALTER TABLE "ledger_entries" DROP COLUMN IF EXISTS "bucket";
@@ -0,0 +1,55 @@
+-- account legs of the ledger say which currency bucket they moved, "paid" or "free",
+-- legs on the system books leave it empty
+ALTER TABLE "ledger_entries" ADD COLUMN "bucket" varchar NOT NULL DEFAULT '';
+
+UPDATE "ledger_entries"
+SET "bucket" = CASE WHEN "ledger_journals"."reason" IN ('purchase', 'refund') THEN 'paid' ELSE 'free' END
+FROM "ledger_journals"
+WHERE "ledger_journals"."id" = "ledger_entries"."journal_id" AND "ledger_entries"."account_id" IS NOT NULL;
+
+ALTER TABLE "accounts" ADD COLUMN "paid_balance" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "accounts" ADD COLUMN "free_balance" bigint NOT NULL DEFAULT 0;
+
+-- pulls made before the split didn't record their bucket, so the paid balance is
+-- what was bought and not refunded, capped by what is left of the balance
+UPDATE "accounts"
+SET "paid_balance" = LEAST("balance", GREATEST(0, (
+  SELECT COALESCE(sum("amount"), 0) FROM "ledger_entries"
+  WHERE "account_id" = "accounts"."id" AND "bucket" = 'paid'
+)));
+
+UPDATE "accounts" SET "free_balance" = "balance" - "paid_balance";
+
+-- paid currency spent by those pulls is moved to the free bucket of the ledger,
+-- so both buckets reconcile with the account
+INSERT INTO "ledger_journals" ("reason", "reference_id")
+SELECT 'bucket_transfer', "accounts"."id"::varchar FROM "accounts"
+WHERE "paid_balance" <> (
+  SELECT COALESCE(sum("amount"), 0) FROM "ledger_entries"
+  WHERE "account_id" = "accounts"."id" AND "bucket" = 'paid'
+);
+
+INSERT INTO "ledger_entries" ("journal_id", "account_id", "book", "bucket", "amount")
+SELECT "ledger_journals"."id", "accounts"."id", 'accounts', "buckets"."bucket",
+  "buckets"."sign" * ("accounts"."paid_balance" - (
+    SELECT COALESCE(sum("amount"), 0) FROM "ledger_entries"
+    WHERE "account_id" = "accounts"."id" AND "bucket" = 'paid'
+  ))
+FROM "ledger_journals"
+JOIN "accounts" ON "ledger_journals"."reference_id" = "accounts"."id"::varchar
+CROSS JOIN (VALUES ('paid', 1), ('free', -1)) AS "buckets" ("bucket", "sign")
+WHERE "ledger_journals"."reason" = 'bucket_transfer';
+
+-- the balance is the total of both buckets
+ALTER TABLE "accounts" DROP COLUMN "balance";
+
+ALTER TABLE "accounts" ADD COLUMN "balance" bigint NOT NULL GENERATED ALWAYS AS ("paid_balance" + "free_balance") STORED;
+
+-- the order currency is spent in on the pulls of a banner
+ALTER TABLE "banners" ADD COLUMN "spend_order" varchar NOT NULL DEFAULT 'free_first';
+
+-- the part of the cost of the pull taken from each bucket
+ALTER TABLE "gachas" ADD COLUMN "paid_amount" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "gachas" ADD COLUMN "free_amount" bigint NOT NULL DEFAULT 0;
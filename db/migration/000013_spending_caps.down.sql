@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "gachas_account_id_created_at_idx";
+DROP INDEX IF EXISTS "orders_account_id_created_at_idx";
+DROP TABLE IF EXISTS "spending_caps";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "region";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "birthdate";
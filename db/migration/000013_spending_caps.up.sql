@@ -0,0 +1,27 @@
+-- a missing birthdate is treated as the youngest age band of the region
+ALTER TABLE "users" ADD COLUMN "birthdate" date;
+
+-- ISO 3166-1 alpha-2 code, empty when unknown
+ALTER TABLE "users" ADD COLUMN "region" varchar NOT NULL DEFAULT '';
+
+-- a band covers the ages from its min_age up to the min_age of the next band
+-- of the region, a null monthly_limit leaves the band uncapped
+CREATE TABLE "spending_caps" (
+  "id" bigserial PRIMARY KEY,
+  "region" varchar NOT NULL,
+  "min_age" int NOT NULL,
+  "monthly_limit" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE UNIQUE INDEX ON "spending_caps" ("region", "min_age");
+
+CREATE INDEX ON "orders" ("account_id", "created_at");
+
+CREATE INDEX ON "gachas" ("account_id", "created_at");
+
+-- Japanese industry guidelines for minors
+INSERT INTO "spending_caps" ("region", "min_age", "monthly_limit") VALUES
+  ('JP', 0, 5000),
+  ('JP', 16, 10000),
+  ('JP', 20, NULL);
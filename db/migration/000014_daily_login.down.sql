@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "daily_claims";
+DROP TABLE IF EXISTS "login_rewards";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "timezone";
@@ -0,0 +1,42 @@
+-- IANA time zone the calendar days of the user start in
+ALTER TABLE "users" ADD COLUMN "timezone" varchar NOT NULL DEFAULT 'UTC';
+
+-- the streak calendar, the days in order grant their amount of currency and
+-- item, and the calendar starts over after its last day
+CREATE TABLE "login_rewards" (
+  "day" int PRIMARY KEY,
+  "amount" bigint NOT NULL DEFAULT 0,
+  "item_id" bigint,
+  "updated_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+-- one claim per account and calendar day of the owner
+CREATE TABLE "daily_claims" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "claim_date" date NOT NULL,
+  "streak" int NOT NULL,
+  "amount" bigint NOT NULL,
+  "item_id" bigint,
+  "gallery_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE UNIQUE INDEX ON "daily_claims" ("account_id", "claim_date");
+
+ALTER TABLE "login_rewards" ADD FOREIGN KEY ("item_id") REFERENCES "items" ("id");
+
+ALTER TABLE "daily_claims" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "daily_claims" ADD FOREIGN KEY ("item_id") REFERENCES "items" ("id");
+
+ALTER TABLE "daily_claims" ADD FOREIGN KEY ("gallery_id") REFERENCES "galleries" ("id") ON DELETE SET NULL;
+
+INSERT INTO "login_rewards" ("day", "amount") VALUES
+  (1, 10),
+  (2, 10),
+  (3, 20),
+  (4, 20),
+  (5, 30),
+  (6, 30),
+  (7, 100);
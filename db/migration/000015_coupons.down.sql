@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "coupon_redemptions";
+DROP TABLE IF EXISTS "coupons";
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "pull_tickets";
@@ -0,0 +1,41 @@
+-- free pulls granted by coupons, a ticket pays for one draw of any banner
+ALTER TABLE "accounts" ADD COLUMN "pull_tickets" bigint NOT NULL DEFAULT 0;
+
+-- a code grants its bundle of currency, item and pull tickets between start_at
+-- and end_at, a null max_redemptions leaves the code unlimited
+CREATE TABLE "coupons" (
+  "id" bigserial PRIMARY KEY,
+  "code" varchar UNIQUE NOT NULL,
+  "amount" bigint NOT NULL DEFAULT 0,
+  "item_id" bigint,
+  "pull_tickets" bigint NOT NULL DEFAULT 0,
+  "max_redemptions" bigint,
+  "redemptions" bigint NOT NULL DEFAULT 0,
+  "per_user_limit" int NOT NULL DEFAULT 1,
+  "start_at" timestamptz NOT NULL,
+  "end_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+-- the per user limit counts the redemptions of the owner, which outlive the account
+CREATE TABLE "coupon_redemptions" (
+  "id" bigserial PRIMARY KEY,
+  "coupon_id" bigint NOT NULL,
+  "account_id" bigint,
+  "owner" varchar NOT NULL,
+  "gallery_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX ON "coupon_redemptions" ("coupon_id", "owner");
+
+ALTER TABLE "coupons" ADD FOREIGN KEY ("item_id") REFERENCES "items" ("id");
+
+-- redeemed coupons can't be deleted, ending them keeps the history
+ALTER TABLE "coupon_redemptions" ADD FOREIGN KEY ("coupon_id") REFERENCES "coupons" ("id");
+
+ALTER TABLE "coupon_redemptions" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "coupon_redemptions" ADD FOREIGN KEY ("owner") REFERENCES "users" ("user_name");
+
+ALTER TABLE "coupon_redemptions" ADD FOREIGN KEY ("gallery_id") REFERENCES "galleries" ("id") ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE "gachas" DROP COLUMN IF EXISTS "revoked_at";
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "flagged_at";
@@ -0,0 +1,7 @@
+-- set while the paid bucket of the account is negative after a refund or
+-- chargeback took back currency already spent, pulls are blocked until a
+-- credit settles the debt
+ALTER TABLE "accounts" ADD COLUMN "flagged_at" timestamptz;
+
+-- a revoked pull lost its copy to the reversal of the currency it was paid with
+ALTER TABLE "gachas" ADD COLUMN "revoked_at" timestamptz;
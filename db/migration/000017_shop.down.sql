@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "shop_purchases";
+DROP TABLE IF EXISTS "shop_offers";
+DROP TABLE IF EXISTS "shop_rotations";
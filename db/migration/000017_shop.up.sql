@@ -0,0 +1,53 @@
+-- a row per day the rotation generator ran, so a day is generated once
+CREATE TABLE "shop_rotations" (
+  "day" date PRIMARY KEY,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+-- an item sold for a fixed price between start_at and end_at, a null stock or
+-- per_user_limit leaves the offer unlimited
+CREATE TABLE "shop_offers" (
+  "id" bigserial PRIMARY KEY,
+  "item_id" bigint NOT NULL,
+  "price" bigint NOT NULL,
+  "spend_order" varchar NOT NULL DEFAULT 'free_first',
+  "stock" bigint,
+  "sold" bigint NOT NULL DEFAULT 0,
+  "per_user_limit" int,
+  "start_at" timestamptz NOT NULL,
+  "end_at" timestamptz NOT NULL,
+  "rotation_day" date,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE TABLE "shop_purchases" (
+  "id" bigserial PRIMARY KEY,
+  "offer_id" bigint NOT NULL,
+  "account_id" bigint,
+  "owner" varchar NOT NULL,
+  "gallery_id" bigint,
+  "paid_amount" bigint NOT NULL,
+  "free_amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX ON "shop_offers" ("start_at", "end_at");
+
+CREATE INDEX ON "shop_offers" ("rotation_day");
+
+CREATE INDEX ON "shop_purchases" ("offer_id", "owner");
+
+CREATE INDEX ON "shop_purchases" ("account_id", "created_at");
+
+ALTER TABLE "shop_offers" ADD FOREIGN KEY ("item_id") REFERENCES "items" ("id");
+
+ALTER TABLE "shop_offers" ADD FOREIGN KEY ("rotation_day") REFERENCES "shop_rotations" ("day") ON DELETE CASCADE;
+
+-- sold offers can't be deleted, ending them keeps the history
+ALTER TABLE "shop_purchases" ADD FOREIGN KEY ("offer_id") REFERENCES "shop_offers" ("id");
+
+ALTER TABLE "shop_purchases" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "shop_purchases" ADD FOREIGN KEY ("owner") REFERENCES "users" ("user_name");
+
+ALTER TABLE "shop_purchases" ADD FOREIGN KEY ("gallery_id") REFERENCES "galleries" ("id") ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "subscription_claims";
+DROP TABLE IF EXISTS "subscriptions";
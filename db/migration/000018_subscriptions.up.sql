@@ -0,0 +1,39 @@
+-- a pass bought by an account, a renewal bought before the last pass expires
+-- starts at its expiry. The expiry job moves passes past expires_at from
+-- active to expired
+CREATE TABLE "subscriptions" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "pass_id" varchar NOT NULL,
+  "daily_amount" bigint NOT NULL,
+  "paid_amount" bigint NOT NULL,
+  "free_amount" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "start_at" timestamptz NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+-- one claim per account and calendar day of the owner, however many passes are stacked
+CREATE TABLE "subscription_claims" (
+  "id" bigserial PRIMARY KEY,
+  "subscription_id" bigint NOT NULL,
+  "account_id" bigint NOT NULL,
+  "claim_date" date NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX ON "subscriptions" ("account_id", "expires_at");
+
+CREATE INDEX ON "subscriptions" ("status", "expires_at");
+
+CREATE INDEX ON "subscriptions" ("account_id", "created_at");
+
+CREATE UNIQUE INDEX ON "subscription_claims" ("account_id", "claim_date");
+
+ALTER TABLE "subscriptions" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "subscription_claims" ADD FOREIGN KEY ("subscription_id") REFERENCES "subscriptions" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "subscription_claims" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
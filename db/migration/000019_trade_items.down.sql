@@ -0,0 +1,18 @@
+-- only succeeds while every offer trades a single copy on each side
+ALTER TABLE "approval" ADD COLUMN "from_item_id" bigint;
+ALTER TABLE "approval" ADD COLUMN "to_item_id" bigint;
+ALTER TABLE "approval" ADD COLUMN "from_gallery_id" bigint;
+ALTER TABLE "approval" ADD COLUMN "to_gallery_id" bigint;
+UPDATE "approval" SET "from_item_id" = "trade_items"."item_id", "from_gallery_id" = "trade_items"."gallery_id"
+FROM "trade_items" WHERE "trade_items"."approval_id" = "approval"."id" AND "trade_items"."side" = 'request';
+UPDATE "approval" SET "to_item_id" = "trade_items"."item_id", "to_gallery_id" = "trade_items"."gallery_id"
+FROM "trade_items" WHERE "trade_items"."approval_id" = "approval"."id" AND "trade_items"."side" = 'response';
+ALTER TABLE "approval" ALTER COLUMN "from_item_id" SET NOT NULL;
+ALTER TABLE "approval" ALTER COLUMN "to_item_id" SET NOT NULL;
+ALTER TABLE "approval" ADD FOREIGN KEY ("from_item_id") REFERENCES "items" ("id");
+ALTER TABLE "approval" ADD FOREIGN KEY ("to_item_id") REFERENCES "items" ("id");
+ALTER TABLE "approval" ADD FOREIGN KEY ("from_gallery_id") REFERENCES "galleries" ("id") ON DELETE SET NULL;
+ALTER TABLE "approval" ADD FOREIGN KEY ("to_gallery_id") REFERENCES "galleries" ("id") ON DELETE SET NULL;
+ALTER TABLE "approval" DROP COLUMN IF EXISTS "to_amount";
+ALTER TABLE "approval" DROP COLUMN IF EXISTS "from_amount";
+DROP TABLE IF EXISTS "trade_items";
@@ -0,0 +1,40 @@
+-- the copies each side of a trade offer gives, the request side holding the
+-- copies of the proposer and the response side the ones of the counterparty.
+-- A converted copy leaves its line without a gallery, failing the settlement
+CREATE TABLE "trade_items" (
+  "id" bigserial PRIMARY KEY,
+  "approval_id" bigint NOT NULL,
+  "side" varchar NOT NULL,
+  "gallery_id" bigint,
+  "item_id" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE UNIQUE INDEX ON "trade_items" ("approval_id", "gallery_id");
+
+CREATE INDEX ON "trade_items" ("gallery_id");
+
+ALTER TABLE "trade_items" ADD FOREIGN KEY ("approval_id") REFERENCES "approval" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "trade_items" ADD FOREIGN KEY ("gallery_id") REFERENCES "galleries" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "trade_items" ADD FOREIGN KEY ("item_id") REFERENCES "items" ("id");
+
+-- free currency each side adds to its copies
+ALTER TABLE "approval" ADD COLUMN "from_amount" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "approval" ADD COLUMN "to_amount" bigint NOT NULL DEFAULT 0;
+
+INSERT INTO "trade_items" ("approval_id", "side", "gallery_id", "item_id")
+SELECT "id", 'request', "from_gallery_id", "from_item_id" FROM "approval";
+
+INSERT INTO "trade_items" ("approval_id", "side", "gallery_id", "item_id")
+SELECT "id", 'response', "to_gallery_id", "to_item_id" FROM "approval";
+
+ALTER TABLE "approval" DROP COLUMN "from_item_id";
+
+ALTER TABLE "approval" DROP COLUMN "to_item_id";
+
+ALTER TABLE "approval" DROP COLUMN "from_gallery_id";
+
+ALTER TABLE "approval" DROP COLUMN "to_gallery_id";
@@ -0,0 +1,7 @@
+-- closed offers were deleted before the history was kept
+DROP TABLE IF EXISTS "trade_events";
+ALTER TABLE "approval" DROP COLUMN IF EXISTS "thread_id";
+ALTER TABLE "approval" DROP COLUMN IF EXISTS "parent_id";
+DELETE FROM "approval" WHERE "status" != 'open';
+ALTER TABLE "approval" DROP COLUMN IF EXISTS "expires_at";
+ALTER TABLE "approval" DROP COLUMN IF EXISTS "status";
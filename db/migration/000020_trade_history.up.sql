@@ -0,0 +1,36 @@
+-- offers are kept once they are closed, an open offer past expires_at is
+-- expired by the sweeper. A counter-offer replaces its parent, every offer of
+-- a negotiation sharing the thread of the first one, whose thread_id is null
+ALTER TABLE "approval" ADD COLUMN "status" varchar NOT NULL DEFAULT 'open';
+
+ALTER TABLE "approval" ADD COLUMN "expires_at" timestamptz;
+
+ALTER TABLE "approval" ADD COLUMN "parent_id" bigint;
+
+ALTER TABLE "approval" ADD COLUMN "thread_id" bigint;
+
+UPDATE "approval" SET "expires_at" = "created_at" + interval '3 days';
+
+ALTER TABLE "approval" ALTER COLUMN "expires_at" SET NOT NULL;
+
+-- every change of an offer, account_id being null for the changes of the sweeper
+CREATE TABLE "trade_events" (
+  "id" bigserial PRIMARY KEY,
+  "approval_id" bigint NOT NULL,
+  "thread_id" bigint NOT NULL,
+  "account_id" bigint,
+  "event" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX ON "approval" ("status", "expires_at");
+
+CREATE INDEX ON "trade_events" ("thread_id");
+
+ALTER TABLE "approval" ADD FOREIGN KEY ("parent_id") REFERENCES "approval" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "approval" ADD FOREIGN KEY ("thread_id") REFERENCES "approval" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "trade_events" ADD FOREIGN KEY ("approval_id") REFERENCES "approval" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "trade_events" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE SET NULL;
@@ -0,0 +1,30 @@
+-- a copy put on sale by its owner for a price of free currency. An active
+-- listing holds its copy in escrow, which can't be traded or converted until
+-- the listing is sold or cancelled. The fee is the part of the price kept by
+-- the market on a sale
+CREATE TABLE "listings" (
+  "id" bigserial PRIMARY KEY,
+  "seller_id" bigint NOT NULL,
+  "gallery_id" bigint,
+  "item_id" bigint NOT NULL,
+  "price" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "buyer_id" bigint,
+  "fee" bigint NOT NULL DEFAULT 0,
+  "sold_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE UNIQUE INDEX ON "listings" ("gallery_id") WHERE "status" = 'active';
+
+CREATE INDEX ON "listings" ("status", "price");
+
+CREATE INDEX ON "listings" ("seller_id");
+
+ALTER TABLE "listings" ADD FOREIGN KEY ("seller_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "listings" ADD FOREIGN KEY ("gallery_id") REFERENCES "galleries" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "listings" ADD FOREIGN KEY ("item_id") REFERENCES "items" ("id");
+
+ALTER TABLE "listings" ADD FOREIGN KEY ("buyer_id") REFERENCES "accounts" ("id") ON DELETE SET NULL;
@@ -0,0 +1,3 @@
+-- admins manage the game data, rates, banners, shop, coupons and caps, and
+-- post admin entries to the ledger. They are only promoted in the database
+ALTER TABLE "users" ADD COLUMN "is_admin" boolean NOT NULL DEFAULT false;
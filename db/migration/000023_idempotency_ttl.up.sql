@@ -0,0 +1,7 @@
+-- a key expires after a while, then it is swept and the key can be used again.
+-- The keys stored so far expire a day after they were created
+ALTER TABLE "idempotency_keys" ADD COLUMN "expires_at" timestamptz NOT NULL DEFAULT (now() + interval '24 hours');
+
+UPDATE "idempotency_keys" SET "expires_at" = "created_at" + interval '24 hours';
+
+CREATE INDEX ON "idempotency_keys" ("expires_at");
@@ -0,0 +1,9 @@
+ALTER TABLE "gachas" DROP COLUMN IF EXISTS "min_rating";
+
+ALTER TABLE "gachas" DROP COLUMN IF EXISTS "pity_pulls";
+
+ALTER TABLE "gachas" DROP COLUMN IF EXISTS "pool_digest";
+
+ALTER TABLE "gachas" DROP COLUMN IF EXISTS "banner_id";
+
+DROP TABLE IF EXISTS "pool_snapshots";
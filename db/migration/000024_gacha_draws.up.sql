@@ -0,0 +1,20 @@
+-- the pool, rates and pity rules a pull was drawn with, stored once under the
+-- digest of their JSON so the draws can be replayed after the banner changed
+CREATE TABLE "pool_snapshots" (
+  "digest" varchar PRIMARY KEY,
+  "snapshot" jsonb NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+-- a gacha keeps its banner, the snapshot of its pool, the pity counter before
+-- the draw and the rating the draw was guaranteed, 0 when it wasn't.
+-- Gachas drawn before have no snapshot and their items can't be replayed
+ALTER TABLE "gachas" ADD COLUMN "banner_id" bigint;
+
+ALTER TABLE "gachas" ADD COLUMN "pool_digest" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "gachas" ADD COLUMN "pity_pulls" int NOT NULL DEFAULT 0;
+
+ALTER TABLE "gachas" ADD COLUMN "min_rating" int NOT NULL DEFAULT 0;
+
+ALTER TABLE "gachas" ADD FOREIGN KEY ("banner_id") REFERENCES "banners" ("id") ON DELETE SET NULL;
@@ -0,0 +1,3 @@
+-- a time zone change moves the calendar day of the daily claims, so it is only
+-- allowed once in a while. Users who never changed it have no change time
+ALTER TABLE "users" ADD COLUMN "timezone_updated_at" timestamptz;
@@ -6,7 +6,9 @@ package mockdb
 
 import (
 	context "context"
+	sql "database/sql"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	db "github.com/sRRRs-7/GachaPon/db/sqlc"
@@ -35,6 +37,170 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AddCouponRedemption mocks base method.
+func (m *MockStore) AddCouponRedemption(arg0 context.Context, arg1 int64) (db.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCouponRedemption", arg0, arg1)
+	ret0, _ := ret[0].(db.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCouponRedemption indicates an expected call of AddCouponRedemption.
+func (mr *MockStoreMockRecorder) AddCouponRedemption(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCouponRedemption", reflect.TypeOf((*MockStore)(nil).AddCouponRedemption), arg0, arg1)
+}
+
+// AddShopOfferSale mocks base method.
+func (m *MockStore) AddShopOfferSale(arg0 context.Context, arg1 int64) (db.ShopOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddShopOfferSale", arg0, arg1)
+	ret0, _ := ret[0].(db.ShopOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddShopOfferSale indicates an expected call of AddShopOfferSale.
+func (mr *MockStoreMockRecorder) AddShopOfferSale(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddShopOfferSale", reflect.TypeOf((*MockStore)(nil).AddShopOfferSale), arg0, arg1)
+}
+
+// ApproveTradeTx mocks base method.
+func (m *MockStore) ApproveTradeTx(arg0 context.Context, arg1 db.ApproveTradeTxParams) (db.ApproveTradeTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveTradeTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ApproveTradeTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveTradeTx indicates an expected call of ApproveTradeTx.
+func (mr *MockStoreMockRecorder) ApproveTradeTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveTradeTx", reflect.TypeOf((*MockStore)(nil).ApproveTradeTx), arg0, arg1)
+}
+
+// BuyListingTx mocks base method.
+func (m *MockStore) BuyListingTx(arg0 context.Context, arg1 db.BuyListingTxParams) (db.BuyListingTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuyListingTx", arg0, arg1)
+	ret0, _ := ret[0].(db.BuyListingTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuyListingTx indicates an expected call of BuyListingTx.
+func (mr *MockStoreMockRecorder) BuyListingTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyListingTx", reflect.TypeOf((*MockStore)(nil).BuyListingTx), arg0, arg1)
+}
+
+// CancelGalleryListing mocks base method.
+func (m *MockStore) CancelGalleryListing(arg0 context.Context, arg1 db.CancelGalleryListingParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelGalleryListing", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelGalleryListing indicates an expected call of CancelGalleryListing.
+func (mr *MockStoreMockRecorder) CancelGalleryListing(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelGalleryListing", reflect.TypeOf((*MockStore)(nil).CancelGalleryListing), arg0, arg1)
+}
+
+// CancelListingTx mocks base method.
+func (m *MockStore) CancelListingTx(arg0 context.Context, arg1 db.CancelListingTxParams) (db.Listing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelListingTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Listing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelListingTx indicates an expected call of CancelListingTx.
+func (mr *MockStoreMockRecorder) CancelListingTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelListingTx", reflect.TypeOf((*MockStore)(nil).CancelListingTx), arg0, arg1)
+}
+
+// CloseTradeOfferTx mocks base method.
+func (m *MockStore) CloseTradeOfferTx(arg0 context.Context, arg1 db.CloseTradeOfferTxParams) (db.Approval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseTradeOfferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Approval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseTradeOfferTx indicates an expected call of CloseTradeOfferTx.
+func (mr *MockStoreMockRecorder) CloseTradeOfferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseTradeOfferTx", reflect.TypeOf((*MockStore)(nil).CloseTradeOfferTx), arg0, arg1)
+}
+
+// ConvertTx mocks base method.
+func (m *MockStore) ConvertTx(arg0 context.Context, arg1 db.ConvertTxParams) (db.ConvertTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConvertTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ConvertTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConvertTx indicates an expected call of ConvertTx.
+func (mr *MockStoreMockRecorder) ConvertTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConvertTx", reflect.TypeOf((*MockStore)(nil).ConvertTx), arg0, arg1)
+}
+
+// CountCouponRedemptions mocks base method.
+func (m *MockStore) CountCouponRedemptions(arg0 context.Context, arg1 db.CountCouponRedemptionsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCouponRedemptions", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCouponRedemptions indicates an expected call of CountCouponRedemptions.
+func (mr *MockStoreMockRecorder) CountCouponRedemptions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCouponRedemptions", reflect.TypeOf((*MockStore)(nil).CountCouponRedemptions), arg0, arg1)
+}
+
+// CountShopPurchases mocks base method.
+func (m *MockStore) CountShopPurchases(arg0 context.Context, arg1 db.CountShopPurchasesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountShopPurchases", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountShopPurchases indicates an expected call of CountShopPurchases.
+func (mr *MockStoreMockRecorder) CountShopPurchases(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountShopPurchases", reflect.TypeOf((*MockStore)(nil).CountShopPurchases), arg0, arg1)
+}
+
+// CounterTradeOfferTx mocks base method.
+func (m *MockStore) CounterTradeOfferTx(arg0 context.Context, arg1 db.CounterTradeOfferTxParams) (db.TradeOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CounterTradeOfferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TradeOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CounterTradeOfferTx indicates an expected call of CounterTradeOfferTx.
+func (mr *MockStoreMockRecorder) CounterTradeOfferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CounterTradeOfferTx", reflect.TypeOf((*MockStore)(nil).CounterTradeOfferTx), arg0, arg1)
+}
+
 // CreateAccount mocks base method.
 func (m *MockStore) CreateAccount(arg0 context.Context, arg1 db.CreateAccountParams) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +216,21 @@ func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1)
 }
 
+// CreateAccountTx mocks base method.
+func (m *MockStore) CreateAccountTx(arg0 context.Context, arg1 db.CreateAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccountTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountTx indicates an expected call of CreateAccountTx.
+func (mr *MockStoreMockRecorder) CreateAccountTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountTx", reflect.TypeOf((*MockStore)(nil).CreateAccountTx), arg0, arg1)
+}
+
 // CreateApproval mocks base method.
 func (m *MockStore) CreateApproval(arg0 context.Context, arg1 db.CreateApprovalParams) (db.Approval, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +246,36 @@ func (mr *MockStoreMockRecorder) CreateApproval(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApproval", reflect.TypeOf((*MockStore)(nil).CreateApproval), arg0, arg1)
 }
 
+// CreateBanner mocks base method.
+func (m *MockStore) CreateBanner(arg0 context.Context, arg1 db.CreateBannerParams) (db.Banner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBanner", arg0, arg1)
+	ret0, _ := ret[0].(db.Banner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBanner indicates an expected call of CreateBanner.
+func (mr *MockStoreMockRecorder) CreateBanner(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBanner", reflect.TypeOf((*MockStore)(nil).CreateBanner), arg0, arg1)
+}
+
+// CreateBannerItem mocks base method.
+func (m *MockStore) CreateBannerItem(arg0 context.Context, arg1 db.CreateBannerItemParams) (db.BannerItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBannerItem", arg0, arg1)
+	ret0, _ := ret[0].(db.BannerItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBannerItem indicates an expected call of CreateBannerItem.
+func (mr *MockStoreMockRecorder) CreateBannerItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBannerItem", reflect.TypeOf((*MockStore)(nil).CreateBannerItem), arg0, arg1)
+}
+
 // CreateCategory mocks base method.
 func (m *MockStore) CreateCategory(arg0 context.Context, arg1 string) (db.Category, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +291,51 @@ func (mr *MockStoreMockRecorder) CreateCategory(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategory", reflect.TypeOf((*MockStore)(nil).CreateCategory), arg0, arg1)
 }
 
+// CreateCoupon mocks base method.
+func (m *MockStore) CreateCoupon(arg0 context.Context, arg1 db.CreateCouponParams) (db.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCoupon", arg0, arg1)
+	ret0, _ := ret[0].(db.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCoupon indicates an expected call of CreateCoupon.
+func (mr *MockStoreMockRecorder) CreateCoupon(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCoupon", reflect.TypeOf((*MockStore)(nil).CreateCoupon), arg0, arg1)
+}
+
+// CreateCouponRedemption mocks base method.
+func (m *MockStore) CreateCouponRedemption(arg0 context.Context, arg1 db.CreateCouponRedemptionParams) (db.CouponRedemption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCouponRedemption", arg0, arg1)
+	ret0, _ := ret[0].(db.CouponRedemption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCouponRedemption indicates an expected call of CreateCouponRedemption.
+func (mr *MockStoreMockRecorder) CreateCouponRedemption(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCouponRedemption", reflect.TypeOf((*MockStore)(nil).CreateCouponRedemption), arg0, arg1)
+}
+
+// CreateDailyClaim mocks base method.
+func (m *MockStore) CreateDailyClaim(arg0 context.Context, arg1 db.CreateDailyClaimParams) (db.DailyClaim, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDailyClaim", arg0, arg1)
+	ret0, _ := ret[0].(db.DailyClaim)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDailyClaim indicates an expected call of CreateDailyClaim.
+func (mr *MockStoreMockRecorder) CreateDailyClaim(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDailyClaim", reflect.TypeOf((*MockStore)(nil).CreateDailyClaim), arg0, arg1)
+}
+
 // CreateExchange mocks base method.
 func (m *MockStore) CreateExchange(arg0 context.Context, arg1 db.CreateExchangeParams) (db.Exchange, error) {
 	m.ctrl.T.Helper()
@@ -125,6 +381,21 @@ func (mr *MockStoreMockRecorder) CreateGallery(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGallery", reflect.TypeOf((*MockStore)(nil).CreateGallery), arg0, arg1)
 }
 
+// CreateIdempotencyKey mocks base method.
+func (m *MockStore) CreateIdempotencyKey(arg0 context.Context, arg1 db.CreateIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(db.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIdempotencyKey indicates an expected call of CreateIdempotencyKey.
+func (mr *MockStoreMockRecorder) CreateIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdempotencyKey", reflect.TypeOf((*MockStore)(nil).CreateIdempotencyKey), arg0, arg1)
+}
+
 // CreateItem mocks base method.
 func (m *MockStore) CreateItem(arg0 context.Context, arg1 db.CreateItemParams) (db.Item, error) {
 	m.ctrl.T.Helper()
@@ -140,6 +411,125 @@ func (mr *MockStoreMockRecorder) CreateItem(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItem", reflect.TypeOf((*MockStore)(nil).CreateItem), arg0, arg1)
 }
 
+// CreateLedgerEntry mocks base method.
+func (m *MockStore) CreateLedgerEntry(arg0 context.Context, arg1 db.CreateLedgerEntryParams) (db.LedgerEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLedgerEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.LedgerEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLedgerEntry indicates an expected call of CreateLedgerEntry.
+func (mr *MockStoreMockRecorder) CreateLedgerEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLedgerEntry", reflect.TypeOf((*MockStore)(nil).CreateLedgerEntry), arg0, arg1)
+}
+
+// CreateLedgerJournal mocks base method.
+func (m *MockStore) CreateLedgerJournal(arg0 context.Context, arg1 db.CreateLedgerJournalParams) (db.LedgerJournal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLedgerJournal", arg0, arg1)
+	ret0, _ := ret[0].(db.LedgerJournal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLedgerJournal indicates an expected call of CreateLedgerJournal.
+func (mr *MockStoreMockRecorder) CreateLedgerJournal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLedgerJournal", reflect.TypeOf((*MockStore)(nil).CreateLedgerJournal), arg0, arg1)
+}
+
+// CreateListing mocks base method.
+func (m *MockStore) CreateListing(arg0 context.Context, arg1 db.CreateListingParams) (db.Listing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateListing", arg0, arg1)
+	ret0, _ := ret[0].(db.Listing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateListing indicates an expected call of CreateListing.
+func (mr *MockStoreMockRecorder) CreateListing(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateListing", reflect.TypeOf((*MockStore)(nil).CreateListing), arg0, arg1)
+}
+
+// CreateListingTx mocks base method.
+func (m *MockStore) CreateListingTx(arg0 context.Context, arg1 db.CreateListingTxParams) (db.Listing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateListingTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Listing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateListingTx indicates an expected call of CreateListingTx.
+func (mr *MockStoreMockRecorder) CreateListingTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateListingTx", reflect.TypeOf((*MockStore)(nil).CreateListingTx), arg0, arg1)
+}
+
+// CreateOrder mocks base method.
+func (m *MockStore) CreateOrder(arg0 context.Context, arg1 db.CreateOrderParams) (db.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrder", arg0, arg1)
+	ret0, _ := ret[0].(db.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrder indicates an expected call of CreateOrder.
+func (mr *MockStoreMockRecorder) CreateOrder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockStore)(nil).CreateOrder), arg0, arg1)
+}
+
+// CreateOrderTx mocks base method.
+func (m *MockStore) CreateOrderTx(arg0 context.Context, arg1 db.CreateOrderParams) (db.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrderTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrderTx indicates an expected call of CreateOrderTx.
+func (mr *MockStoreMockRecorder) CreateOrderTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrderTx", reflect.TypeOf((*MockStore)(nil).CreateOrderTx), arg0, arg1)
+}
+
+// CreatePoolSnapshot mocks base method.
+func (m *MockStore) CreatePoolSnapshot(arg0 context.Context, arg1 db.CreatePoolSnapshotParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePoolSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePoolSnapshot indicates an expected call of CreatePoolSnapshot.
+func (mr *MockStoreMockRecorder) CreatePoolSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePoolSnapshot", reflect.TypeOf((*MockStore)(nil).CreatePoolSnapshot), arg0, arg1)
+}
+
+// CreateSeed mocks base method.
+func (m *MockStore) CreateSeed(arg0 context.Context, arg1 db.CreateSeedParams) (db.Seed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSeed", arg0, arg1)
+	ret0, _ := ret[0].(db.Seed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSeed indicates an expected call of CreateSeed.
+func (mr *MockStoreMockRecorder) CreateSeed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSeed", reflect.TypeOf((*MockStore)(nil).CreateSeed), arg0, arg1)
+}
+
 // CreateSession mocks base method.
 func (m *MockStore) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
 	m.ctrl.T.Helper()
@@ -155,494 +545,2257 @@ func (mr *MockStoreMockRecorder) CreateSession(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), arg0, arg1)
 }
 
-// CreateUser mocks base method.
-func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+// CreateShopOffer mocks base method.
+func (m *MockStore) CreateShopOffer(arg0 context.Context, arg1 db.CreateShopOfferParams) (db.ShopOffer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "CreateShopOffer", arg0, arg1)
+	ret0, _ := ret[0].(db.ShopOffer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+// CreateShopOffer indicates an expected call of CreateShopOffer.
+func (mr *MockStoreMockRecorder) CreateShopOffer(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShopOffer", reflect.TypeOf((*MockStore)(nil).CreateShopOffer), arg0, arg1)
 }
 
-// DeleteAccount mocks base method.
-func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 int64) error {
+// CreateShopPurchase mocks base method.
+func (m *MockStore) CreateShopPurchase(arg0 context.Context, arg1 db.CreateShopPurchaseParams) (db.ShopPurchase, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CreateShopPurchase", arg0, arg1)
+	ret0, _ := ret[0].(db.ShopPurchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteAccount indicates an expected call of DeleteAccount.
-func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
+// CreateShopPurchase indicates an expected call of CreateShopPurchase.
+func (mr *MockStoreMockRecorder) CreateShopPurchase(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShopPurchase", reflect.TypeOf((*MockStore)(nil).CreateShopPurchase), arg0, arg1)
 }
 
-// DeleteApproval mocks base method.
-func (m *MockStore) DeleteApproval(arg0 context.Context, arg1 int64) error {
+// CreateShopRotation mocks base method.
+func (m *MockStore) CreateShopRotation(arg0 context.Context, arg1 time.Time) (db.ShopRotation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteApproval", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CreateShopRotation", arg0, arg1)
+	ret0, _ := ret[0].(db.ShopRotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteApproval indicates an expected call of DeleteApproval.
-func (mr *MockStoreMockRecorder) DeleteApproval(arg0, arg1 interface{}) *gomock.Call {
+// CreateShopRotation indicates an expected call of CreateShopRotation.
+func (mr *MockStoreMockRecorder) CreateShopRotation(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApproval", reflect.TypeOf((*MockStore)(nil).DeleteApproval), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShopRotation", reflect.TypeOf((*MockStore)(nil).CreateShopRotation), arg0, arg1)
 }
 
-// DeleteItem mocks base method.
-func (m *MockStore) DeleteItem(arg0 context.Context, arg1 int64) error {
+// CreateSpendingCap mocks base method.
+func (m *MockStore) CreateSpendingCap(arg0 context.Context, arg1 db.CreateSpendingCapParams) (db.SpendingCap, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteItem", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CreateSpendingCap", arg0, arg1)
+	ret0, _ := ret[0].(db.SpendingCap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSpendingCap indicates an expected call of CreateSpendingCap.
+func (mr *MockStoreMockRecorder) CreateSpendingCap(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSpendingCap", reflect.TypeOf((*MockStore)(nil).CreateSpendingCap), arg0, arg1)
+}
+
+// CreateSubscription mocks base method.
+func (m *MockStore) CreateSubscription(arg0 context.Context, arg1 db.CreateSubscriptionParams) (db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscription", arg0, arg1)
+	ret0, _ := ret[0].(db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSubscription indicates an expected call of CreateSubscription.
+func (mr *MockStoreMockRecorder) CreateSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockStore)(nil).CreateSubscription), arg0, arg1)
+}
+
+// CreateSubscriptionClaim mocks base method.
+func (m *MockStore) CreateSubscriptionClaim(arg0 context.Context, arg1 db.CreateSubscriptionClaimParams) (db.SubscriptionClaim, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscriptionClaim", arg0, arg1)
+	ret0, _ := ret[0].(db.SubscriptionClaim)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSubscriptionClaim indicates an expected call of CreateSubscriptionClaim.
+func (mr *MockStoreMockRecorder) CreateSubscriptionClaim(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscriptionClaim", reflect.TypeOf((*MockStore)(nil).CreateSubscriptionClaim), arg0, arg1)
+}
+
+// CreateTradeEvent mocks base method.
+func (m *MockStore) CreateTradeEvent(arg0 context.Context, arg1 db.CreateTradeEventParams) (db.TradeEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTradeEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.TradeEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTradeEvent indicates an expected call of CreateTradeEvent.
+func (mr *MockStoreMockRecorder) CreateTradeEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTradeEvent", reflect.TypeOf((*MockStore)(nil).CreateTradeEvent), arg0, arg1)
+}
+
+// CreateTradeItem mocks base method.
+func (m *MockStore) CreateTradeItem(arg0 context.Context, arg1 db.CreateTradeItemParams) (db.TradeItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTradeItem", arg0, arg1)
+	ret0, _ := ret[0].(db.TradeItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTradeItem indicates an expected call of CreateTradeItem.
+func (mr *MockStoreMockRecorder) CreateTradeItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTradeItem", reflect.TypeOf((*MockStore)(nil).CreateTradeItem), arg0, arg1)
+}
+
+// CreateTradeOfferTx mocks base method.
+func (m *MockStore) CreateTradeOfferTx(arg0 context.Context, arg1 db.CreateTradeOfferTxParams) (db.TradeOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTradeOfferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TradeOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTradeOfferTx indicates an expected call of CreateTradeOfferTx.
+func (mr *MockStoreMockRecorder) CreateTradeOfferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTradeOfferTx", reflect.TypeOf((*MockStore)(nil).CreateTradeOfferTx), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+}
+
+// DailyClaimTx mocks base method.
+func (m *MockStore) DailyClaimTx(arg0 context.Context, arg1 db.DailyClaimTxParams) (db.DailyClaimTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DailyClaimTx", arg0, arg1)
+	ret0, _ := ret[0].(db.DailyClaimTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DailyClaimTx indicates an expected call of DailyClaimTx.
+func (mr *MockStoreMockRecorder) DailyClaimTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DailyClaimTx", reflect.TypeOf((*MockStore)(nil).DailyClaimTx), arg0, arg1)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
+}
+
+// DeleteApproval mocks base method.
+func (m *MockStore) DeleteApproval(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteApproval", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteApproval indicates an expected call of DeleteApproval.
+func (mr *MockStoreMockRecorder) DeleteApproval(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApproval", reflect.TypeOf((*MockStore)(nil).DeleteApproval), arg0, arg1)
+}
+
+// DeleteBanner mocks base method.
+func (m *MockStore) DeleteBanner(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBanner", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBanner indicates an expected call of DeleteBanner.
+func (mr *MockStoreMockRecorder) DeleteBanner(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBanner", reflect.TypeOf((*MockStore)(nil).DeleteBanner), arg0, arg1)
+}
+
+// DeleteBannerItem mocks base method.
+func (m *MockStore) DeleteBannerItem(arg0 context.Context, arg1 db.DeleteBannerItemParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBannerItem", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBannerItem indicates an expected call of DeleteBannerItem.
+func (mr *MockStoreMockRecorder) DeleteBannerItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBannerItem", reflect.TypeOf((*MockStore)(nil).DeleteBannerItem), arg0, arg1)
+}
+
+// DeleteCoupon mocks base method.
+func (m *MockStore) DeleteCoupon(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCoupon", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCoupon indicates an expected call of DeleteCoupon.
+func (mr *MockStoreMockRecorder) DeleteCoupon(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCoupon", reflect.TypeOf((*MockStore)(nil).DeleteCoupon), arg0, arg1)
+}
+
+// DeleteExpiredIdempotencyKeys mocks base method.
+func (m *MockStore) DeleteExpiredIdempotencyKeys(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredIdempotencyKeys", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredIdempotencyKeys indicates an expected call of DeleteExpiredIdempotencyKeys.
+func (mr *MockStoreMockRecorder) DeleteExpiredIdempotencyKeys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredIdempotencyKeys", reflect.TypeOf((*MockStore)(nil).DeleteExpiredIdempotencyKeys), arg0, arg1)
+}
+
+// DeleteGallery mocks base method.
+func (m *MockStore) DeleteGallery(arg0 context.Context, arg1 db.DeleteGalleryParams) (db.Gallery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGallery", arg0, arg1)
+	ret0, _ := ret[0].(db.Gallery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteGallery indicates an expected call of DeleteGallery.
+func (mr *MockStoreMockRecorder) DeleteGallery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGallery", reflect.TypeOf((*MockStore)(nil).DeleteGallery), arg0, arg1)
+}
+
+// DeleteIdempotencyKey mocks base method.
+func (m *MockStore) DeleteIdempotencyKey(arg0 context.Context, arg1 db.DeleteIdempotencyKeyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdempotencyKey indicates an expected call of DeleteIdempotencyKey.
+func (mr *MockStoreMockRecorder) DeleteIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotencyKey", reflect.TypeOf((*MockStore)(nil).DeleteIdempotencyKey), arg0, arg1)
+}
+
+// DeleteItem mocks base method.
+func (m *MockStore) DeleteItem(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteItem", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteItem indicates an expected call of DeleteItem.
+func (mr *MockStoreMockRecorder) DeleteItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItem", reflect.TypeOf((*MockStore)(nil).DeleteItem), arg0, arg1)
+}
+
+// DeleteLoginReward mocks base method.
+func (m *MockStore) DeleteLoginReward(arg0 context.Context, arg1 int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLoginReward", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLoginReward indicates an expected call of DeleteLoginReward.
+func (mr *MockStoreMockRecorder) DeleteLoginReward(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLoginReward", reflect.TypeOf((*MockStore)(nil).DeleteLoginReward), arg0, arg1)
+}
+
+// DeleteShopOffer mocks base method.
+func (m *MockStore) DeleteShopOffer(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteShopOffer", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteShopOffer indicates an expected call of DeleteShopOffer.
+func (mr *MockStoreMockRecorder) DeleteShopOffer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShopOffer", reflect.TypeOf((*MockStore)(nil).DeleteShopOffer), arg0, arg1)
+}
+
+// DeleteSpendingCap mocks base method.
+func (m *MockStore) DeleteSpendingCap(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSpendingCap", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSpendingCap indicates an expected call of DeleteSpendingCap.
+func (mr *MockStoreMockRecorder) DeleteSpendingCap(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSpendingCap", reflect.TypeOf((*MockStore)(nil).DeleteSpendingCap), arg0, arg1)
+}
+
+// ExchangeTx mocks base method.
+func (m *MockStore) ExchangeTx(arg0 context.Context, arg1 db.ExchangeTxParams) (db.ExchangeTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExchangeTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ExchangeTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExchangeTx indicates an expected call of ExchangeTx.
+func (mr *MockStoreMockRecorder) ExchangeTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExchangeTx", reflect.TypeOf((*MockStore)(nil).ExchangeTx), arg0, arg1)
+}
+
+// ExpireApprovals mocks base method.
+func (m *MockStore) ExpireApprovals(arg0 context.Context, arg1 time.Time) ([]db.Approval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireApprovals", arg0, arg1)
+	ret0, _ := ret[0].([]db.Approval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireApprovals indicates an expected call of ExpireApprovals.
+func (mr *MockStoreMockRecorder) ExpireApprovals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireApprovals", reflect.TypeOf((*MockStore)(nil).ExpireApprovals), arg0, arg1)
+}
+
+// ExpireSubscriptions mocks base method.
+func (m *MockStore) ExpireSubscriptions(arg0 context.Context, arg1 time.Time) ([]db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireSubscriptions", arg0, arg1)
+	ret0, _ := ret[0].([]db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireSubscriptions indicates an expected call of ExpireSubscriptions.
+func (mr *MockStoreMockRecorder) ExpireSubscriptions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireSubscriptions", reflect.TypeOf((*MockStore)(nil).ExpireSubscriptions), arg0, arg1)
+}
+
+// ExpireTradeOffersTx mocks base method.
+func (m *MockStore) ExpireTradeOffersTx(arg0 context.Context, arg1 time.Time) ([]db.Approval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireTradeOffersTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.Approval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireTradeOffersTx indicates an expected call of ExpireTradeOffersTx.
+func (mr *MockStoreMockRecorder) ExpireTradeOffersTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireTradeOffersTx", reflect.TypeOf((*MockStore)(nil).ExpireTradeOffersTx), arg0, arg1)
+}
+
+// GachaTx mocks base method.
+func (m *MockStore) GachaTx(arg0 context.Context, arg1 db.GachaTxParams) (db.GachaTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GachaTx", arg0, arg1)
+	ret0, _ := ret[0].(db.GachaTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GachaTx indicates an expected call of GachaTx.
+func (mr *MockStoreMockRecorder) GachaTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GachaTx", reflect.TypeOf((*MockStore)(nil).GachaTx), arg0, arg1)
+}
+
+// GetAccount mocks base method.
+func (m *MockStore) GetAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockStoreMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), arg0, arg1)
+}
+
+// GetAccountByOwner mocks base method.
+func (m *MockStore) GetAccountByOwner(arg0 context.Context, arg1 string) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByOwner", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByOwner indicates an expected call of GetAccountByOwner.
+func (mr *MockStoreMockRecorder) GetAccountByOwner(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByOwner", reflect.TypeOf((*MockStore)(nil).GetAccountByOwner), arg0, arg1)
+}
+
+// GetAccountForUpdate mocks base method.
+func (m *MockStore) GetAccountForUpdate(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountForUpdate indicates an expected call of GetAccountForUpdate.
+func (mr *MockStoreMockRecorder) GetAccountForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), arg0, arg1)
+}
+
+// GetActiveSeed mocks base method.
+func (m *MockStore) GetActiveSeed(arg0 context.Context, arg1 int64) (db.Seed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSeed", arg0, arg1)
+	ret0, _ := ret[0].(db.Seed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSeed indicates an expected call of GetActiveSeed.
+func (mr *MockStoreMockRecorder) GetActiveSeed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSeed", reflect.TypeOf((*MockStore)(nil).GetActiveSeed), arg0, arg1)
+}
+
+// GetActiveSeedForUpdate mocks base method.
+func (m *MockStore) GetActiveSeedForUpdate(arg0 context.Context, arg1 int64) (db.Seed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSeedForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Seed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSeedForUpdate indicates an expected call of GetActiveSeedForUpdate.
+func (mr *MockStoreMockRecorder) GetActiveSeedForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSeedForUpdate", reflect.TypeOf((*MockStore)(nil).GetActiveSeedForUpdate), arg0, arg1)
+}
+
+// GetApproval mocks base method.
+func (m *MockStore) GetApproval(arg0 context.Context, arg1 int64) (db.Approval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApproval", arg0, arg1)
+	ret0, _ := ret[0].(db.Approval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApproval indicates an expected call of GetApproval.
+func (mr *MockStoreMockRecorder) GetApproval(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApproval", reflect.TypeOf((*MockStore)(nil).GetApproval), arg0, arg1)
+}
+
+// GetApprovalForUpdate mocks base method.
+func (m *MockStore) GetApprovalForUpdate(arg0 context.Context, arg1 int64) (db.Approval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApprovalForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Approval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApprovalForUpdate indicates an expected call of GetApprovalForUpdate.
+func (mr *MockStoreMockRecorder) GetApprovalForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApprovalForUpdate", reflect.TypeOf((*MockStore)(nil).GetApprovalForUpdate), arg0, arg1)
+}
+
+// GetBanner mocks base method.
+func (m *MockStore) GetBanner(arg0 context.Context, arg1 int64) (db.Banner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBanner", arg0, arg1)
+	ret0, _ := ret[0].(db.Banner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBanner indicates an expected call of GetBanner.
+func (mr *MockStoreMockRecorder) GetBanner(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBanner", reflect.TypeOf((*MockStore)(nil).GetBanner), arg0, arg1)
+}
+
+// GetBannerItem mocks base method.
+func (m *MockStore) GetBannerItem(arg0 context.Context, arg1 db.GetBannerItemParams) (db.BannerItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBannerItem", arg0, arg1)
+	ret0, _ := ret[0].(db.BannerItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBannerItem indicates an expected call of GetBannerItem.
+func (mr *MockStoreMockRecorder) GetBannerItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBannerItem", reflect.TypeOf((*MockStore)(nil).GetBannerItem), arg0, arg1)
+}
+
+// GetCategory mocks base method.
+func (m *MockStore) GetCategory(arg0 context.Context, arg1 string) (db.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategory", arg0, arg1)
+	ret0, _ := ret[0].(db.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategory indicates an expected call of GetCategory.
+func (mr *MockStoreMockRecorder) GetCategory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategory", reflect.TypeOf((*MockStore)(nil).GetCategory), arg0, arg1)
+}
+
+// GetCoupon mocks base method.
+func (m *MockStore) GetCoupon(arg0 context.Context, arg1 int64) (db.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCoupon", arg0, arg1)
+	ret0, _ := ret[0].(db.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCoupon indicates an expected call of GetCoupon.
+func (mr *MockStoreMockRecorder) GetCoupon(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoupon", reflect.TypeOf((*MockStore)(nil).GetCoupon), arg0, arg1)
+}
+
+// GetCouponByCodeForUpdate mocks base method.
+func (m *MockStore) GetCouponByCodeForUpdate(arg0 context.Context, arg1 string) (db.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCouponByCodeForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCouponByCodeForUpdate indicates an expected call of GetCouponByCodeForUpdate.
+func (mr *MockStoreMockRecorder) GetCouponByCodeForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCouponByCodeForUpdate", reflect.TypeOf((*MockStore)(nil).GetCouponByCodeForUpdate), arg0, arg1)
+}
+
+// GetCurrentSubscription mocks base method.
+func (m *MockStore) GetCurrentSubscription(arg0 context.Context, arg1 db.GetCurrentSubscriptionParams) (db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentSubscription", arg0, arg1)
+	ret0, _ := ret[0].(db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentSubscription indicates an expected call of GetCurrentSubscription.
+func (mr *MockStoreMockRecorder) GetCurrentSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentSubscription", reflect.TypeOf((*MockStore)(nil).GetCurrentSubscription), arg0, arg1)
+}
+
+// GetExchange mocks base method.
+func (m *MockStore) GetExchange(arg0 context.Context, arg1 int64) (db.Exchange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExchange", arg0, arg1)
+	ret0, _ := ret[0].(db.Exchange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExchange indicates an expected call of GetExchange.
+func (mr *MockStoreMockRecorder) GetExchange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExchange", reflect.TypeOf((*MockStore)(nil).GetExchange), arg0, arg1)
+}
+
+// GetGacha mocks base method.
+func (m *MockStore) GetGacha(arg0 context.Context, arg1 int64) (db.Gacha, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGacha", arg0, arg1)
+	ret0, _ := ret[0].(db.Gacha)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGacha indicates an expected call of GetGacha.
+func (mr *MockStoreMockRecorder) GetGacha(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGacha", reflect.TypeOf((*MockStore)(nil).GetGacha), arg0, arg1)
+}
+
+// GetGallery mocks base method.
+func (m *MockStore) GetGallery(arg0 context.Context, arg1 int64) (db.Gallery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGallery", arg0, arg1)
+	ret0, _ := ret[0].(db.Gallery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGallery indicates an expected call of GetGallery.
+func (mr *MockStoreMockRecorder) GetGallery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGallery", reflect.TypeOf((*MockStore)(nil).GetGallery), arg0, arg1)
+}
+
+// GetGalleryForUpdate mocks base method.
+func (m *MockStore) GetGalleryForUpdate(arg0 context.Context, arg1 int64) (db.Gallery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGalleryForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Gallery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGalleryForUpdate indicates an expected call of GetGalleryForUpdate.
+func (mr *MockStoreMockRecorder) GetGalleryForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGalleryForUpdate", reflect.TypeOf((*MockStore)(nil).GetGalleryForUpdate), arg0, arg1)
+}
+
+// GetGalleryForUpdateNowait mocks base method.
+func (m *MockStore) GetGalleryForUpdateNowait(arg0 context.Context, arg1 int64) (db.Gallery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGalleryForUpdateNowait", arg0, arg1)
+	ret0, _ := ret[0].(db.Gallery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGalleryForUpdateNowait indicates an expected call of GetGalleryForUpdateNowait.
+func (mr *MockStoreMockRecorder) GetGalleryForUpdateNowait(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGalleryForUpdateNowait", reflect.TypeOf((*MockStore)(nil).GetGalleryForUpdateNowait), arg0, arg1)
+}
+
+// GetGalleryListing mocks base method.
+func (m *MockStore) GetGalleryListing(arg0 context.Context, arg1 sql.NullInt64) (db.Listing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGalleryListing", arg0, arg1)
+	ret0, _ := ret[0].(db.Listing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGalleryListing indicates an expected call of GetGalleryListing.
+func (mr *MockStoreMockRecorder) GetGalleryListing(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGalleryListing", reflect.TypeOf((*MockStore)(nil).GetGalleryListing), arg0, arg1)
+}
+
+// GetIdempotencyKey mocks base method.
+func (m *MockStore) GetIdempotencyKey(arg0 context.Context, arg1 db.GetIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(db.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyKey indicates an expected call of GetIdempotencyKey.
+func (mr *MockStoreMockRecorder) GetIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyKey", reflect.TypeOf((*MockStore)(nil).GetIdempotencyKey), arg0, arg1)
+}
+
+// GetItem mocks base method.
+func (m *MockStore) GetItem(arg0 context.Context, arg1 int64) (db.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItem", arg0, arg1)
+	ret0, _ := ret[0].(db.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItem indicates an expected call of GetItem.
+func (mr *MockStoreMockRecorder) GetItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockStore)(nil).GetItem), arg0, arg1)
+}
+
+// GetLastDailyClaim mocks base method.
+func (m *MockStore) GetLastDailyClaim(arg0 context.Context, arg1 int64) (db.DailyClaim, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastDailyClaim", arg0, arg1)
+	ret0, _ := ret[0].(db.DailyClaim)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastDailyClaim indicates an expected call of GetLastDailyClaim.
+func (mr *MockStoreMockRecorder) GetLastDailyClaim(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastDailyClaim", reflect.TypeOf((*MockStore)(nil).GetLastDailyClaim), arg0, arg1)
+}
+
+// GetLastSubscription mocks base method.
+func (m *MockStore) GetLastSubscription(arg0 context.Context, arg1 int64) (db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastSubscription", arg0, arg1)
+	ret0, _ := ret[0].(db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastSubscription indicates an expected call of GetLastSubscription.
+func (mr *MockStoreMockRecorder) GetLastSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastSubscription", reflect.TypeOf((*MockStore)(nil).GetLastSubscription), arg0, arg1)
+}
+
+// GetLedgerBalance mocks base method.
+func (m *MockStore) GetLedgerBalance(arg0 context.Context, arg1 sql.NullInt64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedgerBalance", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedgerBalance indicates an expected call of GetLedgerBalance.
+func (mr *MockStoreMockRecorder) GetLedgerBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedgerBalance", reflect.TypeOf((*MockStore)(nil).GetLedgerBalance), arg0, arg1)
+}
+
+// GetLedgerBucketBalance mocks base method.
+func (m *MockStore) GetLedgerBucketBalance(arg0 context.Context, arg1 db.GetLedgerBucketBalanceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedgerBucketBalance", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedgerBucketBalance indicates an expected call of GetLedgerBucketBalance.
+func (mr *MockStoreMockRecorder) GetLedgerBucketBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedgerBucketBalance", reflect.TypeOf((*MockStore)(nil).GetLedgerBucketBalance), arg0, arg1)
+}
+
+// GetLedgerJournalByReference mocks base method.
+func (m *MockStore) GetLedgerJournalByReference(arg0 context.Context, arg1 db.GetLedgerJournalByReferenceParams) (db.LedgerJournal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedgerJournalByReference", arg0, arg1)
+	ret0, _ := ret[0].(db.LedgerJournal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedgerJournalByReference indicates an expected call of GetLedgerJournalByReference.
+func (mr *MockStoreMockRecorder) GetLedgerJournalByReference(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedgerJournalByReference", reflect.TypeOf((*MockStore)(nil).GetLedgerJournalByReference), arg0, arg1)
+}
+
+// GetListing mocks base method.
+func (m *MockStore) GetListing(arg0 context.Context, arg1 int64) (db.Listing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListing", arg0, arg1)
+	ret0, _ := ret[0].(db.Listing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListing indicates an expected call of GetListing.
+func (mr *MockStoreMockRecorder) GetListing(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListing", reflect.TypeOf((*MockStore)(nil).GetListing), arg0, arg1)
+}
+
+// GetListingForUpdate mocks base method.
+func (m *MockStore) GetListingForUpdate(arg0 context.Context, arg1 int64) (db.Listing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListingForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Listing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListingForUpdate indicates an expected call of GetListingForUpdate.
+func (mr *MockStoreMockRecorder) GetListingForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListingForUpdate", reflect.TypeOf((*MockStore)(nil).GetListingForUpdate), arg0, arg1)
+}
+
+// GetMonthlyPaidSpend mocks base method.
+func (m *MockStore) GetMonthlyPaidSpend(arg0 context.Context, arg1 db.GetMonthlyPaidSpendParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMonthlyPaidSpend", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMonthlyPaidSpend indicates an expected call of GetMonthlyPaidSpend.
+func (mr *MockStoreMockRecorder) GetMonthlyPaidSpend(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMonthlyPaidSpend", reflect.TypeOf((*MockStore)(nil).GetMonthlyPaidSpend), arg0, arg1)
+}
+
+// GetMonthlyPurchases mocks base method.
+func (m *MockStore) GetMonthlyPurchases(arg0 context.Context, arg1 db.GetMonthlyPurchasesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMonthlyPurchases", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMonthlyPurchases indicates an expected call of GetMonthlyPurchases.
+func (mr *MockStoreMockRecorder) GetMonthlyPurchases(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMonthlyPurchases", reflect.TypeOf((*MockStore)(nil).GetMonthlyPurchases), arg0, arg1)
+}
+
+// GetMonthlyShopPaidSpend mocks base method.
+func (m *MockStore) GetMonthlyShopPaidSpend(arg0 context.Context, arg1 db.GetMonthlyShopPaidSpendParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMonthlyShopPaidSpend", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMonthlyShopPaidSpend indicates an expected call of GetMonthlyShopPaidSpend.
+func (mr *MockStoreMockRecorder) GetMonthlyShopPaidSpend(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMonthlyShopPaidSpend", reflect.TypeOf((*MockStore)(nil).GetMonthlyShopPaidSpend), arg0, arg1)
+}
+
+// GetMonthlySubscriptionPaidSpend mocks base method.
+func (m *MockStore) GetMonthlySubscriptionPaidSpend(arg0 context.Context, arg1 db.GetMonthlySubscriptionPaidSpendParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMonthlySubscriptionPaidSpend", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMonthlySubscriptionPaidSpend indicates an expected call of GetMonthlySubscriptionPaidSpend.
+func (mr *MockStoreMockRecorder) GetMonthlySubscriptionPaidSpend(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMonthlySubscriptionPaidSpend", reflect.TypeOf((*MockStore)(nil).GetMonthlySubscriptionPaidSpend), arg0, arg1)
+}
+
+// GetOrder mocks base method.
+func (m *MockStore) GetOrder(arg0 context.Context, arg1 int64) (db.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", arg0, arg1)
+	ret0, _ := ret[0].(db.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockStoreMockRecorder) GetOrder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockStore)(nil).GetOrder), arg0, arg1)
+}
+
+// GetOrderForUpdate mocks base method.
+func (m *MockStore) GetOrderForUpdate(arg0 context.Context, arg1 int64) (db.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderForUpdate indicates an expected call of GetOrderForUpdate.
+func (mr *MockStoreMockRecorder) GetOrderForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderForUpdate", reflect.TypeOf((*MockStore)(nil).GetOrderForUpdate), arg0, arg1)
+}
+
+// GetPity mocks base method.
+func (m *MockStore) GetPity(arg0 context.Context, arg1 db.GetPityParams) (db.Pity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPity", arg0, arg1)
+	ret0, _ := ret[0].(db.Pity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPity indicates an expected call of GetPity.
+func (mr *MockStoreMockRecorder) GetPity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPity", reflect.TypeOf((*MockStore)(nil).GetPity), arg0, arg1)
+}
+
+// GetPoolSnapshot mocks base method.
+func (m *MockStore) GetPoolSnapshot(arg0 context.Context, arg1 string) (db.PoolSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPoolSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(db.PoolSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPoolSnapshot indicates an expected call of GetPoolSnapshot.
+func (mr *MockStoreMockRecorder) GetPoolSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPoolSnapshot", reflect.TypeOf((*MockStore)(nil).GetPoolSnapshot), arg0, arg1)
+}
+
+// GetRarityRate mocks base method.
+func (m *MockStore) GetRarityRate(arg0 context.Context, arg1 int32) (db.RarityRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRarityRate", arg0, arg1)
+	ret0, _ := ret[0].(db.RarityRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRarityRate indicates an expected call of GetRarityRate.
+func (mr *MockStoreMockRecorder) GetRarityRate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRarityRate", reflect.TypeOf((*MockStore)(nil).GetRarityRate), arg0, arg1)
+}
+
+// GetSeed mocks base method.
+func (m *MockStore) GetSeed(arg0 context.Context, arg1 int64) (db.Seed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeed", arg0, arg1)
+	ret0, _ := ret[0].(db.Seed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeed indicates an expected call of GetSeed.
+func (mr *MockStoreMockRecorder) GetSeed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeed", reflect.TypeOf((*MockStore)(nil).GetSeed), arg0, arg1)
+}
+
+// GetSession mocks base method.
+func (m *MockStore) GetSession(arg0 context.Context, arg1 int64) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
+}
+
+// GetShopOffer mocks base method.
+func (m *MockStore) GetShopOffer(arg0 context.Context, arg1 int64) (db.ShopOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShopOffer", arg0, arg1)
+	ret0, _ := ret[0].(db.ShopOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShopOffer indicates an expected call of GetShopOffer.
+func (mr *MockStoreMockRecorder) GetShopOffer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShopOffer", reflect.TypeOf((*MockStore)(nil).GetShopOffer), arg0, arg1)
+}
+
+// GetShopOfferForUpdate mocks base method.
+func (m *MockStore) GetShopOfferForUpdate(arg0 context.Context, arg1 int64) (db.ShopOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShopOfferForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.ShopOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShopOfferForUpdate indicates an expected call of GetShopOfferForUpdate.
+func (mr *MockStoreMockRecorder) GetShopOfferForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShopOfferForUpdate", reflect.TypeOf((*MockStore)(nil).GetShopOfferForUpdate), arg0, arg1)
+}
+
+// GetSpendingCap mocks base method.
+func (m *MockStore) GetSpendingCap(arg0 context.Context, arg1 db.GetSpendingCapParams) (db.SpendingCap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpendingCap", arg0, arg1)
+	ret0, _ := ret[0].(db.SpendingCap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSpendingCap indicates an expected call of GetSpendingCap.
+func (mr *MockStoreMockRecorder) GetSpendingCap(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpendingCap", reflect.TypeOf((*MockStore)(nil).GetSpendingCap), arg0, arg1)
+}
+
+// GetSubscription mocks base method.
+func (m *MockStore) GetSubscription(arg0 context.Context, arg1 int64) (db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", arg0, arg1)
+	ret0, _ := ret[0].(db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockStoreMockRecorder) GetSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockStore)(nil).GetSubscription), arg0, arg1)
+}
+
+// GetSubscriptionClaim mocks base method.
+func (m *MockStore) GetSubscriptionClaim(arg0 context.Context, arg1 db.GetSubscriptionClaimParams) (db.SubscriptionClaim, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptionClaim", arg0, arg1)
+	ret0, _ := ret[0].(db.SubscriptionClaim)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptionClaim indicates an expected call of GetSubscriptionClaim.
+func (mr *MockStoreMockRecorder) GetSubscriptionClaim(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionClaim", reflect.TypeOf((*MockStore)(nil).GetSubscriptionClaim), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+}
+
+// LedgerTx mocks base method.
+func (m *MockStore) LedgerTx(arg0 context.Context, arg1 db.LedgerTxParams) (db.LedgerTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LedgerTx", arg0, arg1)
+	ret0, _ := ret[0].(db.LedgerTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LedgerTx indicates an expected call of LedgerTx.
+func (mr *MockStoreMockRecorder) LedgerTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LedgerTx", reflect.TypeOf((*MockStore)(nil).LedgerTx), arg0, arg1)
+}
+
+// ListAccountLedger mocks base method.
+func (m *MockStore) ListAccountLedger(arg0 context.Context, arg1 db.ListAccountLedgerParams) ([]db.ListAccountLedgerRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountLedger", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListAccountLedgerRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountLedger indicates an expected call of ListAccountLedger.
+func (mr *MockStoreMockRecorder) ListAccountLedger(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountLedger", reflect.TypeOf((*MockStore)(nil).ListAccountLedger), arg0, arg1)
+}
+
+// ListAccounts mocks base method.
+func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
+}
+
+// ListActiveBanners mocks base method.
+func (m *MockStore) ListActiveBanners(arg0 context.Context) ([]db.Banner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveBanners", arg0)
+	ret0, _ := ret[0].([]db.Banner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveBanners indicates an expected call of ListActiveBanners.
+func (mr *MockStoreMockRecorder) ListActiveBanners(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveBanners", reflect.TypeOf((*MockStore)(nil).ListActiveBanners), arg0)
+}
+
+// ListActiveShopOffers mocks base method.
+func (m *MockStore) ListActiveShopOffers(arg0 context.Context) ([]db.ListActiveShopOffersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveShopOffers", arg0)
+	ret0, _ := ret[0].([]db.ListActiveShopOffersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveShopOffers indicates an expected call of ListActiveShopOffers.
+func (mr *MockStoreMockRecorder) ListActiveShopOffers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveShopOffers", reflect.TypeOf((*MockStore)(nil).ListActiveShopOffers), arg0)
+}
+
+// ListApproval mocks base method.
+func (m *MockStore) ListApproval(arg0 context.Context, arg1 db.ListApprovalParams) ([]db.Approval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApproval", arg0, arg1)
+	ret0, _ := ret[0].([]db.Approval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApproval indicates an expected call of ListApproval.
+func (mr *MockStoreMockRecorder) ListApproval(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApproval", reflect.TypeOf((*MockStore)(nil).ListApproval), arg0, arg1)
+}
+
+// ListBannerItems mocks base method.
+func (m *MockStore) ListBannerItems(arg0 context.Context, arg1 int64) ([]db.BannerItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBannerItems", arg0, arg1)
+	ret0, _ := ret[0].([]db.BannerItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBannerItems indicates an expected call of ListBannerItems.
+func (mr *MockStoreMockRecorder) ListBannerItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBannerItems", reflect.TypeOf((*MockStore)(nil).ListBannerItems), arg0, arg1)
+}
+
+// ListBannerPool mocks base method.
+func (m *MockStore) ListBannerPool(arg0 context.Context, arg1 int64) ([]db.ListBannerPoolRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBannerPool", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListBannerPoolRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBannerPool indicates an expected call of ListBannerPool.
+func (mr *MockStoreMockRecorder) ListBannerPool(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBannerPool", reflect.TypeOf((*MockStore)(nil).ListBannerPool), arg0, arg1)
+}
+
+// ListBannerRates mocks base method.
+func (m *MockStore) ListBannerRates(arg0 context.Context, arg1 int64) ([]db.BannerRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBannerRates", arg0, arg1)
+	ret0, _ := ret[0].([]db.BannerRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBannerRates indicates an expected call of ListBannerRates.
+func (mr *MockStoreMockRecorder) ListBannerRates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBannerRates", reflect.TypeOf((*MockStore)(nil).ListBannerRates), arg0, arg1)
+}
+
+// ListBanners mocks base method.
+func (m *MockStore) ListBanners(arg0 context.Context, arg1 db.ListBannersParams) ([]db.Banner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBanners", arg0, arg1)
+	ret0, _ := ret[0].([]db.Banner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBanners indicates an expected call of ListBanners.
+func (mr *MockStoreMockRecorder) ListBanners(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBanners", reflect.TypeOf((*MockStore)(nil).ListBanners), arg0, arg1)
+}
+
+// ListCategories mocks base method.
+func (m *MockStore) ListCategories(arg0 context.Context, arg1 db.ListCategoriesParams) ([]db.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCategories", arg0, arg1)
+	ret0, _ := ret[0].([]db.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCategories indicates an expected call of ListCategories.
+func (mr *MockStoreMockRecorder) ListCategories(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockStore)(nil).ListCategories), arg0, arg1)
+}
+
+// ListCoupons mocks base method.
+func (m *MockStore) ListCoupons(arg0 context.Context, arg1 db.ListCouponsParams) ([]db.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCoupons", arg0, arg1)
+	ret0, _ := ret[0].([]db.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCoupons indicates an expected call of ListCoupons.
+func (mr *MockStoreMockRecorder) ListCoupons(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCoupons", reflect.TypeOf((*MockStore)(nil).ListCoupons), arg0, arg1)
+}
+
+// ListDailyClaims mocks base method.
+func (m *MockStore) ListDailyClaims(arg0 context.Context, arg1 db.ListDailyClaimsParams) ([]db.DailyClaim, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDailyClaims", arg0, arg1)
+	ret0, _ := ret[0].([]db.DailyClaim)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDailyClaims indicates an expected call of ListDailyClaims.
+func (mr *MockStoreMockRecorder) ListDailyClaims(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDailyClaims", reflect.TypeOf((*MockStore)(nil).ListDailyClaims), arg0, arg1)
+}
+
+// ListExchangeFromAccount mocks base method.
+func (m *MockStore) ListExchangeFromAccount(arg0 context.Context, arg1 db.ListExchangeFromAccountParams) ([]db.Exchange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExchangeFromAccount", arg0, arg1)
+	ret0, _ := ret[0].([]db.Exchange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExchangeFromAccount indicates an expected call of ListExchangeFromAccount.
+func (mr *MockStoreMockRecorder) ListExchangeFromAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExchangeFromAccount", reflect.TypeOf((*MockStore)(nil).ListExchangeFromAccount), arg0, arg1)
+}
+
+// ListExchangeToAccount mocks base method.
+func (m *MockStore) ListExchangeToAccount(arg0 context.Context, arg1 db.ListExchangeToAccountParams) ([]db.Exchange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExchangeToAccount", arg0, arg1)
+	ret0, _ := ret[0].([]db.Exchange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExchangeToAccount indicates an expected call of ListExchangeToAccount.
+func (mr *MockStoreMockRecorder) ListExchangeToAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExchangeToAccount", reflect.TypeOf((*MockStore)(nil).ListExchangeToAccount), arg0, arg1)
+}
+
+// ListGachas mocks base method.
+func (m *MockStore) ListGachas(arg0 context.Context, arg1 db.ListGachasParams) ([]db.Gacha, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGachas", arg0, arg1)
+	ret0, _ := ret[0].([]db.Gacha)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGachas indicates an expected call of ListGachas.
+func (mr *MockStoreMockRecorder) ListGachas(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGachas", reflect.TypeOf((*MockStore)(nil).ListGachas), arg0, arg1)
+}
+
+// ListGalleriesById mocks base method.
+func (m *MockStore) ListGalleriesById(arg0 context.Context, arg1 db.ListGalleriesByIdParams) ([]db.Gallery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGalleriesById", arg0, arg1)
+	ret0, _ := ret[0].([]db.Gallery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGalleriesById indicates an expected call of ListGalleriesById.
+func (mr *MockStoreMockRecorder) ListGalleriesById(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGalleriesById", reflect.TypeOf((*MockStore)(nil).ListGalleriesById), arg0, arg1)
+}
+
+// ListGalleriesByItemId mocks base method.
+func (m *MockStore) ListGalleriesByItemId(arg0 context.Context, arg1 db.ListGalleriesByItemIdParams) ([]db.Gallery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGalleriesByItemId", arg0, arg1)
+	ret0, _ := ret[0].([]db.Gallery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGalleriesByItemId indicates an expected call of ListGalleriesByItemId.
+func (mr *MockStoreMockRecorder) ListGalleriesByItemId(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGalleriesByItemId", reflect.TypeOf((*MockStore)(nil).ListGalleriesByItemId), arg0, arg1)
+}
+
+// ListGalleryQuantities mocks base method.
+func (m *MockStore) ListGalleryQuantities(arg0 context.Context, arg1 db.ListGalleryQuantitiesParams) ([]db.ListGalleryQuantitiesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGalleryQuantities", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListGalleryQuantitiesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGalleryQuantities indicates an expected call of ListGalleryQuantities.
+func (mr *MockStoreMockRecorder) ListGalleryQuantities(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGalleryQuantities", reflect.TypeOf((*MockStore)(nil).ListGalleryQuantities), arg0, arg1)
+}
+
+// ListIncomingApprovals mocks base method.
+func (m *MockStore) ListIncomingApprovals(arg0 context.Context, arg1 db.ListIncomingApprovalsParams) ([]db.Approval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncomingApprovals", arg0, arg1)
+	ret0, _ := ret[0].([]db.Approval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncomingApprovals indicates an expected call of ListIncomingApprovals.
+func (mr *MockStoreMockRecorder) ListIncomingApprovals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncomingApprovals", reflect.TypeOf((*MockStore)(nil).ListIncomingApprovals), arg0, arg1)
+}
+
+// ListItemByCategoryId mocks base method.
+func (m *MockStore) ListItemByCategoryId(arg0 context.Context, arg1 db.ListItemByCategoryIdParams) ([]db.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItemByCategoryId", arg0, arg1)
+	ret0, _ := ret[0].([]db.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItemByCategoryId indicates an expected call of ListItemByCategoryId.
+func (mr *MockStoreMockRecorder) ListItemByCategoryId(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItemByCategoryId", reflect.TypeOf((*MockStore)(nil).ListItemByCategoryId), arg0, arg1)
+}
+
+// ListItemsByCategoryId mocks base method.
+func (m *MockStore) ListItemsByCategoryId(arg0 context.Context, arg1 db.ListItemsByCategoryIdParams) ([]db.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItemsByCategoryId", arg0, arg1)
+	ret0, _ := ret[0].([]db.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItemsByCategoryId indicates an expected call of ListItemsByCategoryId.
+func (mr *MockStoreMockRecorder) ListItemsByCategoryId(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItemsByCategoryId", reflect.TypeOf((*MockStore)(nil).ListItemsByCategoryId), arg0, arg1)
+}
+
+// ListItemsById mocks base method.
+func (m *MockStore) ListItemsById(arg0 context.Context, arg1 db.ListItemsByIdParams) ([]db.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItemsById", arg0, arg1)
+	ret0, _ := ret[0].([]db.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItemsById indicates an expected call of ListItemsById.
+func (mr *MockStoreMockRecorder) ListItemsById(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItemsById", reflect.TypeOf((*MockStore)(nil).ListItemsById), arg0, arg1)
+}
+
+// ListItemsByItemName mocks base method.
+func (m *MockStore) ListItemsByItemName(arg0 context.Context, arg1 db.ListItemsByItemNameParams) ([]db.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItemsByItemName", arg0, arg1)
+	ret0, _ := ret[0].([]db.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItemsByItemName indicates an expected call of ListItemsByItemName.
+func (mr *MockStoreMockRecorder) ListItemsByItemName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItemsByItemName", reflect.TypeOf((*MockStore)(nil).ListItemsByItemName), arg0, arg1)
+}
+
+// ListItemsByRating mocks base method.
+func (m *MockStore) ListItemsByRating(arg0 context.Context, arg1 db.ListItemsByRatingParams) ([]db.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItemsByRating", arg0, arg1)
+	ret0, _ := ret[0].([]db.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItemsByRating indicates an expected call of ListItemsByRating.
+func (mr *MockStoreMockRecorder) ListItemsByRating(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItemsByRating", reflect.TypeOf((*MockStore)(nil).ListItemsByRating), arg0, arg1)
+}
+
+// ListLedgerEntriesByJournal mocks base method.
+func (m *MockStore) ListLedgerEntriesByJournal(arg0 context.Context, arg1 int64) ([]db.LedgerEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLedgerEntriesByJournal", arg0, arg1)
+	ret0, _ := ret[0].([]db.LedgerEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLedgerEntriesByJournal indicates an expected call of ListLedgerEntriesByJournal.
+func (mr *MockStoreMockRecorder) ListLedgerEntriesByJournal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLedgerEntriesByJournal", reflect.TypeOf((*MockStore)(nil).ListLedgerEntriesByJournal), arg0, arg1)
+}
+
+// ListLoginRewards mocks base method.
+func (m *MockStore) ListLoginRewards(arg0 context.Context) ([]db.LoginReward, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLoginRewards", arg0)
+	ret0, _ := ret[0].([]db.LoginReward)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLoginRewards indicates an expected call of ListLoginRewards.
+func (mr *MockStoreMockRecorder) ListLoginRewards(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoginRewards", reflect.TypeOf((*MockStore)(nil).ListLoginRewards), arg0)
+}
+
+// ListMarketListings mocks base method.
+func (m *MockStore) ListMarketListings(arg0 context.Context, arg1 db.ListMarketListingsParams) ([]db.ListMarketListingsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMarketListings", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListMarketListingsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMarketListings indicates an expected call of ListMarketListings.
+func (mr *MockStoreMockRecorder) ListMarketListings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMarketListings", reflect.TypeOf((*MockStore)(nil).ListMarketListings), arg0, arg1)
+}
+
+// ListOrders mocks base method.
+func (m *MockStore) ListOrders(arg0 context.Context, arg1 db.ListOrdersParams) ([]db.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", arg0, arg1)
+	ret0, _ := ret[0].([]db.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockStoreMockRecorder) ListOrders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockStore)(nil).ListOrders), arg0, arg1)
+}
+
+// ListOutgoingApprovals mocks base method.
+func (m *MockStore) ListOutgoingApprovals(arg0 context.Context, arg1 db.ListOutgoingApprovalsParams) ([]db.Approval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutgoingApprovals", arg0, arg1)
+	ret0, _ := ret[0].([]db.Approval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutgoingApprovals indicates an expected call of ListOutgoingApprovals.
+func (mr *MockStoreMockRecorder) ListOutgoingApprovals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutgoingApprovals", reflect.TypeOf((*MockStore)(nil).ListOutgoingApprovals), arg0, arg1)
+}
+
+// ListRarityRates mocks base method.
+func (m *MockStore) ListRarityRates(arg0 context.Context) ([]db.RarityRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRarityRates", arg0)
+	ret0, _ := ret[0].([]db.RarityRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRarityRates indicates an expected call of ListRarityRates.
+func (mr *MockStoreMockRecorder) ListRarityRates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRarityRates", reflect.TypeOf((*MockStore)(nil).ListRarityRates), arg0)
+}
+
+// ListRevocableGachas mocks base method.
+func (m *MockStore) ListRevocableGachas(arg0 context.Context, arg1 db.ListRevocableGachasParams) ([]db.Gacha, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevocableGachas", arg0, arg1)
+	ret0, _ := ret[0].([]db.Gacha)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevocableGachas indicates an expected call of ListRevocableGachas.
+func (mr *MockStoreMockRecorder) ListRevocableGachas(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevocableGachas", reflect.TypeOf((*MockStore)(nil).ListRevocableGachas), arg0, arg1)
+}
+
+// ListRotationCandidates mocks base method.
+func (m *MockStore) ListRotationCandidates(arg0 context.Context) ([]db.ListRotationCandidatesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRotationCandidates", arg0)
+	ret0, _ := ret[0].([]db.ListRotationCandidatesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRotationCandidates indicates an expected call of ListRotationCandidates.
+func (mr *MockStoreMockRecorder) ListRotationCandidates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRotationCandidates", reflect.TypeOf((*MockStore)(nil).ListRotationCandidates), arg0)
+}
+
+// ListRotationShopOffers mocks base method.
+func (m *MockStore) ListRotationShopOffers(arg0 context.Context, arg1 sql.NullTime) ([]db.ShopOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRotationShopOffers", arg0, arg1)
+	ret0, _ := ret[0].([]db.ShopOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRotationShopOffers indicates an expected call of ListRotationShopOffers.
+func (mr *MockStoreMockRecorder) ListRotationShopOffers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRotationShopOffers", reflect.TypeOf((*MockStore)(nil).ListRotationShopOffers), arg0, arg1)
+}
+
+// ListSellerListings mocks base method.
+func (m *MockStore) ListSellerListings(arg0 context.Context, arg1 db.ListSellerListingsParams) ([]db.Listing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSellerListings", arg0, arg1)
+	ret0, _ := ret[0].([]db.Listing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSellerListings indicates an expected call of ListSellerListings.
+func (mr *MockStoreMockRecorder) ListSellerListings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSellerListings", reflect.TypeOf((*MockStore)(nil).ListSellerListings), arg0, arg1)
+}
+
+// ListShopOffers mocks base method.
+func (m *MockStore) ListShopOffers(arg0 context.Context, arg1 db.ListShopOffersParams) ([]db.ShopOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShopOffers", arg0, arg1)
+	ret0, _ := ret[0].([]db.ShopOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShopOffers indicates an expected call of ListShopOffers.
+func (mr *MockStoreMockRecorder) ListShopOffers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShopOffers", reflect.TypeOf((*MockStore)(nil).ListShopOffers), arg0, arg1)
+}
+
+// ListSpendingCaps mocks base method.
+func (m *MockStore) ListSpendingCaps(arg0 context.Context, arg1 db.ListSpendingCapsParams) ([]db.SpendingCap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSpendingCaps", arg0, arg1)
+	ret0, _ := ret[0].([]db.SpendingCap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSpendingCaps indicates an expected call of ListSpendingCaps.
+func (mr *MockStoreMockRecorder) ListSpendingCaps(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSpendingCaps", reflect.TypeOf((*MockStore)(nil).ListSpendingCaps), arg0, arg1)
+}
+
+// ListSubscriptions mocks base method.
+func (m *MockStore) ListSubscriptions(arg0 context.Context, arg1 db.ListSubscriptionsParams) ([]db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptions", arg0, arg1)
+	ret0, _ := ret[0].([]db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptions indicates an expected call of ListSubscriptions.
+func (mr *MockStoreMockRecorder) ListSubscriptions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptions", reflect.TypeOf((*MockStore)(nil).ListSubscriptions), arg0, arg1)
+}
+
+// ListTradeEvents mocks base method.
+func (m *MockStore) ListTradeEvents(arg0 context.Context, arg1 int64) ([]db.TradeEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTradeEvents", arg0, arg1)
+	ret0, _ := ret[0].([]db.TradeEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTradeEvents indicates an expected call of ListTradeEvents.
+func (mr *MockStoreMockRecorder) ListTradeEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTradeEvents", reflect.TypeOf((*MockStore)(nil).ListTradeEvents), arg0, arg1)
+}
+
+// ListTradeItems mocks base method.
+func (m *MockStore) ListTradeItems(arg0 context.Context, arg1 int64) ([]db.TradeItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTradeItems", arg0, arg1)
+	ret0, _ := ret[0].([]db.TradeItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTradeItems indicates an expected call of ListTradeItems.
+func (mr *MockStoreMockRecorder) ListTradeItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTradeItems", reflect.TypeOf((*MockStore)(nil).ListTradeItems), arg0, arg1)
+}
+
+// MonthlySpending mocks base method.
+func (m *MockStore) MonthlySpending(arg0 context.Context, arg1 int64) (db.Spending, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MonthlySpending", arg0, arg1)
+	ret0, _ := ret[0].(db.Spending)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MonthlySpending indicates an expected call of MonthlySpending.
+func (mr *MockStoreMockRecorder) MonthlySpending(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MonthlySpending", reflect.TypeOf((*MockStore)(nil).MonthlySpending), arg0, arg1)
+}
+
+// PaymentEventTx mocks base method.
+func (m *MockStore) PaymentEventTx(arg0 context.Context, arg1 db.PaymentEventTxParams) (db.PaymentEventTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PaymentEventTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentEventTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PaymentEventTx indicates an expected call of PaymentEventTx.
+func (mr *MockStoreMockRecorder) PaymentEventTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PaymentEventTx", reflect.TypeOf((*MockStore)(nil).PaymentEventTx), arg0, arg1)
+}
+
+// ReconcileAccount mocks base method.
+func (m *MockStore) ReconcileAccount(arg0 context.Context, arg1 int64) (db.ReconcileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.ReconcileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileAccount indicates an expected call of ReconcileAccount.
+func (mr *MockStoreMockRecorder) ReconcileAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileAccount", reflect.TypeOf((*MockStore)(nil).ReconcileAccount), arg0, arg1)
+}
+
+// RedeemCouponTx mocks base method.
+func (m *MockStore) RedeemCouponTx(arg0 context.Context, arg1 db.RedeemCouponTxParams) (db.RedeemCouponTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeemCouponTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RedeemCouponTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeemCouponTx indicates an expected call of RedeemCouponTx.
+func (mr *MockStoreMockRecorder) RedeemCouponTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemCouponTx", reflect.TypeOf((*MockStore)(nil).RedeemCouponTx), arg0, arg1)
+}
+
+// RevealSeed mocks base method.
+func (m *MockStore) RevealSeed(arg0 context.Context, arg1 int64) (db.Seed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevealSeed", arg0, arg1)
+	ret0, _ := ret[0].(db.Seed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevealSeed indicates an expected call of RevealSeed.
+func (mr *MockStoreMockRecorder) RevealSeed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevealSeed", reflect.TypeOf((*MockStore)(nil).RevealSeed), arg0, arg1)
+}
+
+// RevokeGacha mocks base method.
+func (m *MockStore) RevokeGacha(arg0 context.Context, arg1 int64) (db.Gacha, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeGacha", arg0, arg1)
+	ret0, _ := ret[0].(db.Gacha)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeGacha indicates an expected call of RevokeGacha.
+func (mr *MockStoreMockRecorder) RevokeGacha(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeGacha", reflect.TypeOf((*MockStore)(nil).RevokeGacha), arg0, arg1)
+}
+
+// RotateSeedTx mocks base method.
+func (m *MockStore) RotateSeedTx(arg0 context.Context, arg1 db.RotateSeedTxParams) (db.RotateSeedTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSeedTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RotateSeedTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateSeedTx indicates an expected call of RotateSeedTx.
+func (mr *MockStoreMockRecorder) RotateSeedTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSeedTx", reflect.TypeOf((*MockStore)(nil).RotateSeedTx), arg0, arg1)
+}
+
+// RotateShopTx mocks base method.
+func (m *MockStore) RotateShopTx(arg0 context.Context, arg1 db.RotateShopTxParams) (db.RotateShopTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateShopTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RotateShopTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateShopTx indicates an expected call of RotateShopTx.
+func (mr *MockStoreMockRecorder) RotateShopTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateShopTx", reflect.TypeOf((*MockStore)(nil).RotateShopTx), arg0, arg1)
+}
+
+// SellListing mocks base method.
+func (m *MockStore) SellListing(arg0 context.Context, arg1 db.SellListingParams) (db.Listing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SellListing", arg0, arg1)
+	ret0, _ := ret[0].(db.Listing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteItem indicates an expected call of DeleteItem.
-func (mr *MockStoreMockRecorder) DeleteItem(arg0, arg1 interface{}) *gomock.Call {
+// SellListing indicates an expected call of SellListing.
+func (mr *MockStoreMockRecorder) SellListing(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItem", reflect.TypeOf((*MockStore)(nil).DeleteItem), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SellListing", reflect.TypeOf((*MockStore)(nil).SellListing), arg0, arg1)
 }
 
-// ExchangeTx mocks base method.
-func (m *MockStore) ExchangeTx(arg0 context.Context, arg1 db.ExchangeTxParams) (db.ExchangeTxResult, error) {
+// ShopPurchaseTx mocks base method.
+func (m *MockStore) ShopPurchaseTx(arg0 context.Context, arg1 db.ShopPurchaseTxParams) (db.ShopPurchaseTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExchangeTx", arg0, arg1)
-	ret0, _ := ret[0].(db.ExchangeTxResult)
+	ret := m.ctrl.Call(m, "ShopPurchaseTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ShopPurchaseTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ExchangeTx indicates an expected call of ExchangeTx.
-func (mr *MockStoreMockRecorder) ExchangeTx(arg0, arg1 interface{}) *gomock.Call {
+// ShopPurchaseTx indicates an expected call of ShopPurchaseTx.
+func (mr *MockStoreMockRecorder) ShopPurchaseTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExchangeTx", reflect.TypeOf((*MockStore)(nil).ExchangeTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShopPurchaseTx", reflect.TypeOf((*MockStore)(nil).ShopPurchaseTx), arg0, arg1)
 }
 
-// GetAccount mocks base method.
-func (m *MockStore) GetAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
+// SparkTx mocks base method.
+func (m *MockStore) SparkTx(arg0 context.Context, arg1 db.SparkTxParams) (db.SparkTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "SparkTx", arg0, arg1)
+	ret0, _ := ret[0].(db.SparkTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccount indicates an expected call of GetAccount.
-func (mr *MockStoreMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call {
+// SparkTx indicates an expected call of SparkTx.
+func (mr *MockStoreMockRecorder) SparkTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SparkTx", reflect.TypeOf((*MockStore)(nil).SparkTx), arg0, arg1)
 }
 
-// GetApproval mocks base method.
-func (m *MockStore) GetApproval(arg0 context.Context, arg1 int64) (db.Approval, error) {
+// SubscribeTx mocks base method.
+func (m *MockStore) SubscribeTx(arg0 context.Context, arg1 db.SubscribeTxParams) (db.SubscribeTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetApproval", arg0, arg1)
-	ret0, _ := ret[0].(db.Approval)
+	ret := m.ctrl.Call(m, "SubscribeTx", arg0, arg1)
+	ret0, _ := ret[0].(db.SubscribeTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetApproval indicates an expected call of GetApproval.
-func (mr *MockStoreMockRecorder) GetApproval(arg0, arg1 interface{}) *gomock.Call {
+// SubscribeTx indicates an expected call of SubscribeTx.
+func (mr *MockStoreMockRecorder) SubscribeTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApproval", reflect.TypeOf((*MockStore)(nil).GetApproval), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeTx", reflect.TypeOf((*MockStore)(nil).SubscribeTx), arg0, arg1)
 }
 
-// GetCategory mocks base method.
-func (m *MockStore) GetCategory(arg0 context.Context, arg1 string) (db.Category, error) {
+// SubscriptionClaimTx mocks base method.
+func (m *MockStore) SubscriptionClaimTx(arg0 context.Context, arg1 db.SubscriptionClaimTxParams) (db.SubscriptionClaimTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCategory", arg0, arg1)
-	ret0, _ := ret[0].(db.Category)
+	ret := m.ctrl.Call(m, "SubscriptionClaimTx", arg0, arg1)
+	ret0, _ := ret[0].(db.SubscriptionClaimTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetCategory indicates an expected call of GetCategory.
-func (mr *MockStoreMockRecorder) GetCategory(arg0, arg1 interface{}) *gomock.Call {
+// SubscriptionClaimTx indicates an expected call of SubscriptionClaimTx.
+func (mr *MockStoreMockRecorder) SubscriptionClaimTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategory", reflect.TypeOf((*MockStore)(nil).GetCategory), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionClaimTx", reflect.TypeOf((*MockStore)(nil).SubscriptionClaimTx), arg0, arg1)
 }
 
-// GetExchange mocks base method.
-func (m *MockStore) GetExchange(arg0 context.Context, arg1 int64) (db.Exchange, error) {
+// UpdateAccount mocks base method.
+func (m *MockStore) UpdateAccount(arg0 context.Context, arg1 db.UpdateAccountParams) (db.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetExchange", arg0, arg1)
-	ret0, _ := ret[0].(db.Exchange)
+	ret := m.ctrl.Call(m, "UpdateAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetExchange indicates an expected call of GetExchange.
-func (mr *MockStoreMockRecorder) GetExchange(arg0, arg1 interface{}) *gomock.Call {
+// UpdateAccount indicates an expected call of UpdateAccount.
+func (mr *MockStoreMockRecorder) UpdateAccount(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExchange", reflect.TypeOf((*MockStore)(nil).GetExchange), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockStore)(nil).UpdateAccount), arg0, arg1)
 }
 
-// GetGacha mocks base method.
-func (m *MockStore) GetGacha(arg0 context.Context, arg1 int64) (db.Gacha, error) {
+// UpdateAccountFlag mocks base method.
+func (m *MockStore) UpdateAccountFlag(arg0 context.Context, arg1 db.UpdateAccountFlagParams) (db.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetGacha", arg0, arg1)
-	ret0, _ := ret[0].(db.Gacha)
+	ret := m.ctrl.Call(m, "UpdateAccountFlag", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetGacha indicates an expected call of GetGacha.
-func (mr *MockStoreMockRecorder) GetGacha(arg0, arg1 interface{}) *gomock.Call {
+// UpdateAccountFlag indicates an expected call of UpdateAccountFlag.
+func (mr *MockStoreMockRecorder) UpdateAccountFlag(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGacha", reflect.TypeOf((*MockStore)(nil).GetGacha), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountFlag", reflect.TypeOf((*MockStore)(nil).UpdateAccountFlag), arg0, arg1)
 }
 
-// GetGallery mocks base method.
-func (m *MockStore) GetGallery(arg0 context.Context, arg1 int64) (db.Gallery, error) {
+// UpdateApprovalRequest mocks base method.
+func (m *MockStore) UpdateApprovalRequest(arg0 context.Context, arg1 db.UpdateApprovalRequestParams) (db.Approval, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetGallery", arg0, arg1)
-	ret0, _ := ret[0].(db.Gallery)
+	ret := m.ctrl.Call(m, "UpdateApprovalRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.Approval)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetGallery indicates an expected call of GetGallery.
-func (mr *MockStoreMockRecorder) GetGallery(arg0, arg1 interface{}) *gomock.Call {
+// UpdateApprovalRequest indicates an expected call of UpdateApprovalRequest.
+func (mr *MockStoreMockRecorder) UpdateApprovalRequest(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGallery", reflect.TypeOf((*MockStore)(nil).GetGallery), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApprovalRequest", reflect.TypeOf((*MockStore)(nil).UpdateApprovalRequest), arg0, arg1)
 }
 
-// GetItem mocks base method.
-func (m *MockStore) GetItem(arg0 context.Context, arg1 int64) (db.Item, error) {
+// UpdateApprovalResponse mocks base method.
+func (m *MockStore) UpdateApprovalResponse(arg0 context.Context, arg1 db.UpdateApprovalResponseParams) (db.Approval, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetItem", arg0, arg1)
-	ret0, _ := ret[0].(db.Item)
+	ret := m.ctrl.Call(m, "UpdateApprovalResponse", arg0, arg1)
+	ret0, _ := ret[0].(db.Approval)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetItem indicates an expected call of GetItem.
-func (mr *MockStoreMockRecorder) GetItem(arg0, arg1 interface{}) *gomock.Call {
+// UpdateApprovalResponse indicates an expected call of UpdateApprovalResponse.
+func (mr *MockStoreMockRecorder) UpdateApprovalResponse(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockStore)(nil).GetItem), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApprovalResponse", reflect.TypeOf((*MockStore)(nil).UpdateApprovalResponse), arg0, arg1)
 }
 
-// GetSession mocks base method.
-func (m *MockStore) GetSession(arg0 context.Context, arg1 int64) (db.Session, error) {
+// UpdateApprovalStatus mocks base method.
+func (m *MockStore) UpdateApprovalStatus(arg0 context.Context, arg1 db.UpdateApprovalStatusParams) (db.Approval, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
-	ret0, _ := ret[0].(db.Session)
+	ret := m.ctrl.Call(m, "UpdateApprovalStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.Approval)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSession indicates an expected call of GetSession.
-func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call {
+// UpdateApprovalStatus indicates an expected call of UpdateApprovalStatus.
+func (mr *MockStoreMockRecorder) UpdateApprovalStatus(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApprovalStatus", reflect.TypeOf((*MockStore)(nil).UpdateApprovalStatus), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+// UpdateBalance mocks base method.
+func (m *MockStore) UpdateBalance(arg0 context.Context, arg1 db.UpdateBalanceParams) (db.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "UpdateBalance", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+// UpdateBalance indicates an expected call of UpdateBalance.
+func (mr *MockStoreMockRecorder) UpdateBalance(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBalance", reflect.TypeOf((*MockStore)(nil).UpdateBalance), arg0, arg1)
 }
 
-// ListAccounts mocks base method.
-func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
+// UpdateBanner mocks base method.
+func (m *MockStore) UpdateBanner(arg0 context.Context, arg1 db.UpdateBannerParams) (db.Banner, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
-	ret0, _ := ret[0].([]db.Account)
+	ret := m.ctrl.Call(m, "UpdateBanner", arg0, arg1)
+	ret0, _ := ret[0].(db.Banner)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListAccounts indicates an expected call of ListAccounts.
-func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 interface{}) *gomock.Call {
+// UpdateBanner indicates an expected call of UpdateBanner.
+func (mr *MockStoreMockRecorder) UpdateBanner(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBanner", reflect.TypeOf((*MockStore)(nil).UpdateBanner), arg0, arg1)
 }
 
-// ListApproval mocks base method.
-func (m *MockStore) ListApproval(arg0 context.Context, arg1 db.ListApprovalParams) ([]db.Approval, error) {
+// UpdateBannerItem mocks base method.
+func (m *MockStore) UpdateBannerItem(arg0 context.Context, arg1 db.UpdateBannerItemParams) (db.BannerItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListApproval", arg0, arg1)
-	ret0, _ := ret[0].([]db.Approval)
+	ret := m.ctrl.Call(m, "UpdateBannerItem", arg0, arg1)
+	ret0, _ := ret[0].(db.BannerItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListApproval indicates an expected call of ListApproval.
-func (mr *MockStoreMockRecorder) ListApproval(arg0, arg1 interface{}) *gomock.Call {
+// UpdateBannerItem indicates an expected call of UpdateBannerItem.
+func (mr *MockStoreMockRecorder) UpdateBannerItem(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApproval", reflect.TypeOf((*MockStore)(nil).ListApproval), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBannerItem", reflect.TypeOf((*MockStore)(nil).UpdateBannerItem), arg0, arg1)
 }
 
-// ListCategories mocks base method.
-func (m *MockStore) ListCategories(arg0 context.Context, arg1 db.ListCategoriesParams) ([]db.Category, error) {
+// UpdateCoupon mocks base method.
+func (m *MockStore) UpdateCoupon(arg0 context.Context, arg1 db.UpdateCouponParams) (db.Coupon, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListCategories", arg0, arg1)
-	ret0, _ := ret[0].([]db.Category)
+	ret := m.ctrl.Call(m, "UpdateCoupon", arg0, arg1)
+	ret0, _ := ret[0].(db.Coupon)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListCategories indicates an expected call of ListCategories.
-func (mr *MockStoreMockRecorder) ListCategories(arg0, arg1 interface{}) *gomock.Call {
+// UpdateCoupon indicates an expected call of UpdateCoupon.
+func (mr *MockStoreMockRecorder) UpdateCoupon(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockStore)(nil).ListCategories), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCoupon", reflect.TypeOf((*MockStore)(nil).UpdateCoupon), arg0, arg1)
 }
 
-// ListExchangeFromAccount mocks base method.
-func (m *MockStore) ListExchangeFromAccount(arg0 context.Context, arg1 db.ListExchangeFromAccountParams) ([]db.Exchange, error) {
+// UpdateGallery mocks base method.
+func (m *MockStore) UpdateGallery(arg0 context.Context, arg1 db.UpdateGalleryParams) (db.Gallery, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListExchangeFromAccount", arg0, arg1)
-	ret0, _ := ret[0].([]db.Exchange)
+	ret := m.ctrl.Call(m, "UpdateGallery", arg0, arg1)
+	ret0, _ := ret[0].(db.Gallery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListExchangeFromAccount indicates an expected call of ListExchangeFromAccount.
-func (mr *MockStoreMockRecorder) ListExchangeFromAccount(arg0, arg1 interface{}) *gomock.Call {
+// UpdateGallery indicates an expected call of UpdateGallery.
+func (mr *MockStoreMockRecorder) UpdateGallery(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExchangeFromAccount", reflect.TypeOf((*MockStore)(nil).ListExchangeFromAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGallery", reflect.TypeOf((*MockStore)(nil).UpdateGallery), arg0, arg1)
 }
 
-// ListExchangeToAccount mocks base method.
-func (m *MockStore) ListExchangeToAccount(arg0 context.Context, arg1 db.ListExchangeToAccountParams) ([]db.Exchange, error) {
+// UpdateIdempotencyKeyResponse mocks base method.
+func (m *MockStore) UpdateIdempotencyKeyResponse(arg0 context.Context, arg1 db.UpdateIdempotencyKeyResponseParams) (db.IdempotencyKey, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListExchangeToAccount", arg0, arg1)
-	ret0, _ := ret[0].([]db.Exchange)
+	ret := m.ctrl.Call(m, "UpdateIdempotencyKeyResponse", arg0, arg1)
+	ret0, _ := ret[0].(db.IdempotencyKey)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListExchangeToAccount indicates an expected call of ListExchangeToAccount.
-func (mr *MockStoreMockRecorder) ListExchangeToAccount(arg0, arg1 interface{}) *gomock.Call {
+// UpdateIdempotencyKeyResponse indicates an expected call of UpdateIdempotencyKeyResponse.
+func (mr *MockStoreMockRecorder) UpdateIdempotencyKeyResponse(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExchangeToAccount", reflect.TypeOf((*MockStore)(nil).ListExchangeToAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIdempotencyKeyResponse", reflect.TypeOf((*MockStore)(nil).UpdateIdempotencyKeyResponse), arg0, arg1)
 }
 
-// ListGachas mocks base method.
-func (m *MockStore) ListGachas(arg0 context.Context, arg1 db.ListGachasParams) ([]db.Gacha, error) {
+// UpdateItem mocks base method.
+func (m *MockStore) UpdateItem(arg0 context.Context, arg1 db.UpdateItemParams) (db.Item, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListGachas", arg0, arg1)
-	ret0, _ := ret[0].([]db.Gacha)
+	ret := m.ctrl.Call(m, "UpdateItem", arg0, arg1)
+	ret0, _ := ret[0].(db.Item)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListGachas indicates an expected call of ListGachas.
-func (mr *MockStoreMockRecorder) ListGachas(arg0, arg1 interface{}) *gomock.Call {
+// UpdateItem indicates an expected call of UpdateItem.
+func (mr *MockStoreMockRecorder) UpdateItem(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGachas", reflect.TypeOf((*MockStore)(nil).ListGachas), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItem", reflect.TypeOf((*MockStore)(nil).UpdateItem), arg0, arg1)
 }
 
-// ListGalleriesById mocks base method.
-func (m *MockStore) ListGalleriesById(arg0 context.Context, arg1 db.ListGalleriesByIdParams) ([]db.Gallery, error) {
+// UpdateListingStatus mocks base method.
+func (m *MockStore) UpdateListingStatus(arg0 context.Context, arg1 db.UpdateListingStatusParams) (db.Listing, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListGalleriesById", arg0, arg1)
-	ret0, _ := ret[0].([]db.Gallery)
+	ret := m.ctrl.Call(m, "UpdateListingStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.Listing)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListGalleriesById indicates an expected call of ListGalleriesById.
-func (mr *MockStoreMockRecorder) ListGalleriesById(arg0, arg1 interface{}) *gomock.Call {
+// UpdateListingStatus indicates an expected call of UpdateListingStatus.
+func (mr *MockStoreMockRecorder) UpdateListingStatus(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGalleriesById", reflect.TypeOf((*MockStore)(nil).ListGalleriesById), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateListingStatus", reflect.TypeOf((*MockStore)(nil).UpdateListingStatus), arg0, arg1)
 }
 
-// ListGalleriesByItemId mocks base method.
-func (m *MockStore) ListGalleriesByItemId(arg0 context.Context, arg1 db.ListGalleriesByItemIdParams) ([]db.Gallery, error) {
+// UpdateOrderProviderRef mocks base method.
+func (m *MockStore) UpdateOrderProviderRef(arg0 context.Context, arg1 db.UpdateOrderProviderRefParams) (db.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListGalleriesByItemId", arg0, arg1)
-	ret0, _ := ret[0].([]db.Gallery)
+	ret := m.ctrl.Call(m, "UpdateOrderProviderRef", arg0, arg1)
+	ret0, _ := ret[0].(db.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListGalleriesByItemId indicates an expected call of ListGalleriesByItemId.
-func (mr *MockStoreMockRecorder) ListGalleriesByItemId(arg0, arg1 interface{}) *gomock.Call {
+// UpdateOrderProviderRef indicates an expected call of UpdateOrderProviderRef.
+func (mr *MockStoreMockRecorder) UpdateOrderProviderRef(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGalleriesByItemId", reflect.TypeOf((*MockStore)(nil).ListGalleriesByItemId), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderProviderRef", reflect.TypeOf((*MockStore)(nil).UpdateOrderProviderRef), arg0, arg1)
 }
 
-// ListItemByCategoryId mocks base method.
-func (m *MockStore) ListItemByCategoryId(arg0 context.Context, arg1 db.ListItemByCategoryIdParams) ([]db.Item, error) {
+// UpdateOrderStatus mocks base method.
+func (m *MockStore) UpdateOrderStatus(arg0 context.Context, arg1 db.UpdateOrderStatusParams) (db.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListItemByCategoryId", arg0, arg1)
-	ret0, _ := ret[0].([]db.Item)
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListItemByCategoryId indicates an expected call of ListItemByCategoryId.
-func (mr *MockStoreMockRecorder) ListItemByCategoryId(arg0, arg1 interface{}) *gomock.Call {
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockStoreMockRecorder) UpdateOrderStatus(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItemByCategoryId", reflect.TypeOf((*MockStore)(nil).ListItemByCategoryId), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockStore)(nil).UpdateOrderStatus), arg0, arg1)
 }
 
-// ListItemsByCategoryId mocks base method.
-func (m *MockStore) ListItemsByCategoryId(arg0 context.Context, arg1 db.ListItemsByCategoryIdParams) ([]db.Item, error) {
+// UpdatePullTickets mocks base method.
+func (m *MockStore) UpdatePullTickets(arg0 context.Context, arg1 db.UpdatePullTicketsParams) (db.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListItemsByCategoryId", arg0, arg1)
-	ret0, _ := ret[0].([]db.Item)
+	ret := m.ctrl.Call(m, "UpdatePullTickets", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListItemsByCategoryId indicates an expected call of ListItemsByCategoryId.
-func (mr *MockStoreMockRecorder) ListItemsByCategoryId(arg0, arg1 interface{}) *gomock.Call {
+// UpdatePullTickets indicates an expected call of UpdatePullTickets.
+func (mr *MockStoreMockRecorder) UpdatePullTickets(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItemsByCategoryId", reflect.TypeOf((*MockStore)(nil).ListItemsByCategoryId), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePullTickets", reflect.TypeOf((*MockStore)(nil).UpdatePullTickets), arg0, arg1)
 }
 
-// ListItemsById mocks base method.
-func (m *MockStore) ListItemsById(arg0 context.Context, arg1 db.ListItemsByIdParams) ([]db.Item, error) {
+// UpdateSeedNonce mocks base method.
+func (m *MockStore) UpdateSeedNonce(arg0 context.Context, arg1 db.UpdateSeedNonceParams) (db.Seed, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListItemsById", arg0, arg1)
-	ret0, _ := ret[0].([]db.Item)
+	ret := m.ctrl.Call(m, "UpdateSeedNonce", arg0, arg1)
+	ret0, _ := ret[0].(db.Seed)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListItemsById indicates an expected call of ListItemsById.
-func (mr *MockStoreMockRecorder) ListItemsById(arg0, arg1 interface{}) *gomock.Call {
+// UpdateSeedNonce indicates an expected call of UpdateSeedNonce.
+func (mr *MockStoreMockRecorder) UpdateSeedNonce(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItemsById", reflect.TypeOf((*MockStore)(nil).ListItemsById), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSeedNonce", reflect.TypeOf((*MockStore)(nil).UpdateSeedNonce), arg0, arg1)
 }
 
-// ListItemsByItemName mocks base method.
-func (m *MockStore) ListItemsByItemName(arg0 context.Context, arg1 db.ListItemsByItemNameParams) ([]db.Item, error) {
+// UpdateShards mocks base method.
+func (m *MockStore) UpdateShards(arg0 context.Context, arg1 db.UpdateShardsParams) (db.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListItemsByItemName", arg0, arg1)
-	ret0, _ := ret[0].([]db.Item)
+	ret := m.ctrl.Call(m, "UpdateShards", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListItemsByItemName indicates an expected call of ListItemsByItemName.
-func (mr *MockStoreMockRecorder) ListItemsByItemName(arg0, arg1 interface{}) *gomock.Call {
+// UpdateShards indicates an expected call of UpdateShards.
+func (mr *MockStoreMockRecorder) UpdateShards(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItemsByItemName", reflect.TypeOf((*MockStore)(nil).ListItemsByItemName), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateShards", reflect.TypeOf((*MockStore)(nil).UpdateShards), arg0, arg1)
 }
 
-// ListItemsByRating mocks base method.
-func (m *MockStore) ListItemsByRating(arg0 context.Context, arg1 db.ListItemsByRatingParams) ([]db.Item, error) {
+// UpdateShopOffer mocks base method.
+func (m *MockStore) UpdateShopOffer(arg0 context.Context, arg1 db.UpdateShopOfferParams) (db.ShopOffer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListItemsByRating", arg0, arg1)
-	ret0, _ := ret[0].([]db.Item)
+	ret := m.ctrl.Call(m, "UpdateShopOffer", arg0, arg1)
+	ret0, _ := ret[0].(db.ShopOffer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListItemsByRating indicates an expected call of ListItemsByRating.
-func (mr *MockStoreMockRecorder) ListItemsByRating(arg0, arg1 interface{}) *gomock.Call {
+// UpdateShopOffer indicates an expected call of UpdateShopOffer.
+func (mr *MockStoreMockRecorder) UpdateShopOffer(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItemsByRating", reflect.TypeOf((*MockStore)(nil).ListItemsByRating), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateShopOffer", reflect.TypeOf((*MockStore)(nil).UpdateShopOffer), arg0, arg1)
 }
 
-// UpdateAccount mocks base method.
-func (m *MockStore) UpdateAccount(arg0 context.Context, arg1 db.UpdateAccountParams) (db.Account, error) {
+// UpdateSpendingCap mocks base method.
+func (m *MockStore) UpdateSpendingCap(arg0 context.Context, arg1 db.UpdateSpendingCapParams) (db.SpendingCap, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAccount", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "UpdateSpendingCap", arg0, arg1)
+	ret0, _ := ret[0].(db.SpendingCap)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateAccount indicates an expected call of UpdateAccount.
-func (mr *MockStoreMockRecorder) UpdateAccount(arg0, arg1 interface{}) *gomock.Call {
+// UpdateSpendingCap indicates an expected call of UpdateSpendingCap.
+func (mr *MockStoreMockRecorder) UpdateSpendingCap(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockStore)(nil).UpdateAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSpendingCap", reflect.TypeOf((*MockStore)(nil).UpdateSpendingCap), arg0, arg1)
 }
 
-// UpdateApprovalRequest mocks base method.
-func (m *MockStore) UpdateApprovalRequest(arg0 context.Context, arg1 db.UpdateApprovalRequestParams) (db.Approval, error) {
+// UpdateUserTimezone mocks base method.
+func (m *MockStore) UpdateUserTimezone(arg0 context.Context, arg1 db.UpdateUserTimezoneParams) (db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateApprovalRequest", arg0, arg1)
-	ret0, _ := ret[0].(db.Approval)
+	ret := m.ctrl.Call(m, "UpdateUserTimezone", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateApprovalRequest indicates an expected call of UpdateApprovalRequest.
-func (mr *MockStoreMockRecorder) UpdateApprovalRequest(arg0, arg1 interface{}) *gomock.Call {
+// UpdateUserTimezone indicates an expected call of UpdateUserTimezone.
+func (mr *MockStoreMockRecorder) UpdateUserTimezone(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApprovalRequest", reflect.TypeOf((*MockStore)(nil).UpdateApprovalRequest), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserTimezone", reflect.TypeOf((*MockStore)(nil).UpdateUserTimezone), arg0, arg1)
 }
 
-// UpdateApprovalResponse mocks base method.
-func (m *MockStore) UpdateApprovalResponse(arg0 context.Context, arg1 db.UpdateApprovalResponseParams) (db.Approval, error) {
+// UpsertBannerRate mocks base method.
+func (m *MockStore) UpsertBannerRate(arg0 context.Context, arg1 db.UpsertBannerRateParams) (db.BannerRate, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateApprovalResponse", arg0, arg1)
-	ret0, _ := ret[0].(db.Approval)
+	ret := m.ctrl.Call(m, "UpsertBannerRate", arg0, arg1)
+	ret0, _ := ret[0].(db.BannerRate)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateApprovalResponse indicates an expected call of UpdateApprovalResponse.
-func (mr *MockStoreMockRecorder) UpdateApprovalResponse(arg0, arg1 interface{}) *gomock.Call {
+// UpsertBannerRate indicates an expected call of UpsertBannerRate.
+func (mr *MockStoreMockRecorder) UpsertBannerRate(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApprovalResponse", reflect.TypeOf((*MockStore)(nil).UpdateApprovalResponse), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertBannerRate", reflect.TypeOf((*MockStore)(nil).UpsertBannerRate), arg0, arg1)
 }
 
-// UpdateBalance mocks base method.
-func (m *MockStore) UpdateBalance(arg0 context.Context, arg1 db.UpdateBalanceParams) (db.Account, error) {
+// UpsertLoginReward mocks base method.
+func (m *MockStore) UpsertLoginReward(arg0 context.Context, arg1 db.UpsertLoginRewardParams) (db.LoginReward, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateBalance", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "UpsertLoginReward", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginReward)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateBalance indicates an expected call of UpdateBalance.
-func (mr *MockStoreMockRecorder) UpdateBalance(arg0, arg1 interface{}) *gomock.Call {
+// UpsertLoginReward indicates an expected call of UpsertLoginReward.
+func (mr *MockStoreMockRecorder) UpsertLoginReward(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBalance", reflect.TypeOf((*MockStore)(nil).UpdateBalance), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLoginReward", reflect.TypeOf((*MockStore)(nil).UpsertLoginReward), arg0, arg1)
 }
 
-// UpdateGallery mocks base method.
-func (m *MockStore) UpdateGallery(arg0 context.Context, arg1 db.UpdateGalleryParams) (db.Gallery, error) {
+// UpsertPity mocks base method.
+func (m *MockStore) UpsertPity(arg0 context.Context, arg1 db.UpsertPityParams) (db.Pity, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateGallery", arg0, arg1)
-	ret0, _ := ret[0].(db.Gallery)
+	ret := m.ctrl.Call(m, "UpsertPity", arg0, arg1)
+	ret0, _ := ret[0].(db.Pity)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateGallery indicates an expected call of UpdateGallery.
-func (mr *MockStoreMockRecorder) UpdateGallery(arg0, arg1 interface{}) *gomock.Call {
+// UpsertPity indicates an expected call of UpsertPity.
+func (mr *MockStoreMockRecorder) UpsertPity(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGallery", reflect.TypeOf((*MockStore)(nil).UpdateGallery), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertPity", reflect.TypeOf((*MockStore)(nil).UpsertPity), arg0, arg1)
 }
 
-// UpdateItem mocks base method.
-func (m *MockStore) UpdateItem(arg0 context.Context, arg1 db.UpdateItemParams) (db.Item, error) {
+// UpsertRarityRate mocks base method.
+func (m *MockStore) UpsertRarityRate(arg0 context.Context, arg1 db.UpsertRarityRateParams) (db.RarityRate, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateItem", arg0, arg1)
-	ret0, _ := ret[0].(db.Item)
+	ret := m.ctrl.Call(m, "UpsertRarityRate", arg0, arg1)
+	ret0, _ := ret[0].(db.RarityRate)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateItem indicates an expected call of UpdateItem.
-func (mr *MockStoreMockRecorder) UpdateItem(arg0, arg1 interface{}) *gomock.Call {
+// UpsertRarityRate indicates an expected call of UpsertRarityRate.
+func (mr *MockStoreMockRecorder) UpsertRarityRate(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItem", reflect.TypeOf((*MockStore)(nil).UpdateItem), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertRarityRate", reflect.TypeOf((*MockStore)(nil).UpsertRarityRate), arg0, arg1)
 }
@@ -1,6 +1,6 @@
 -- name: CreateAccount :one
 INSERT INTO accounts (
-    owner, balance
+    owner, free_balance
 ) VALUES (
     $1, $2
 ) RETURNING *;
@@ -9,6 +9,10 @@ INSERT INTO accounts (
 SELECT * FROM accounts
 WHERE id = $1 LIMIT 1;
 
+-- name: GetAccountByOwner :one
+SELECT * FROM accounts
+WHERE owner = $1 LIMIT 1;
+
 -- name: ListAccounts :many
 SELECT * FROM accounts
 WHERE owner = $1
@@ -18,16 +22,39 @@ OFFSET $3;
 
 -- name: UpdateAccount :one
 UPDATE accounts
-SET balance = $2
+SET paid_balance = $2, free_balance = $3
 where id = $1
 RETURNING *;
 
 -- name: UpdateBalance :one
 UPDATE accounts
-SET balance = balance + $2
+SET paid_balance = paid_balance + $2, free_balance = free_balance + $3
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateShards :one
+UPDATE accounts
+SET shards = shards + $2
 WHERE id = $1
 RETURNING *;
 
 -- name: DeleteAccount :exec
 DELETE FROM accounts
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: GetAccountForUpdate :one
+SELECT * FROM accounts
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: UpdatePullTickets :one
+UPDATE accounts
+SET pull_tickets = pull_tickets + $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateAccountFlag :one
+UPDATE accounts
+SET flagged_at = $2
+WHERE id = $1
+RETURNING *;
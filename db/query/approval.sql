@@ -1,13 +1,16 @@
 -- name: CreateApproval :one
 INSERT INTO approval (
     from_account_id,
-    from_item_id,
     from_A_approval,
+    from_amount,
     to_account_id,
-    to_item_id,
-    to_A_approval
+    to_A_approval,
+    to_amount,
+    expires_at,
+    parent_id,
+    thread_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 ) RETURNING *;
 
 -- name: GetApproval :one
@@ -34,4 +37,35 @@ RETURNING *;
 
 -- name: DeleteApproval :exec
 DELETE FROM approval
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: GetApprovalForUpdate :one
+SELECT * FROM approval
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListIncomingApprovals :many
+SELECT * FROM approval
+WHERE to_account_id = $1 AND status = 'open'
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ListOutgoingApprovals :many
+SELECT * FROM approval
+WHERE from_account_id = $1 AND status = 'open'
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdateApprovalStatus :one
+UPDATE approval
+SET status = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: ExpireApprovals :many
+UPDATE approval
+SET status = 'expired'
+WHERE status = 'open' AND expires_at <= $1
+RETURNING *;
@@ -0,0 +1,82 @@
+-- name: CreateBanner :one
+INSERT INTO banners (
+    banner_name, cost, is_active, start_at, end_at, multi_count, multi_cost, guarantee_rating,
+    soft_pity, hard_pity, soft_pity_step, spark_cost, spend_order
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+) RETURNING *;
+
+-- name: GetBanner :one
+SELECT * FROM banners
+WHERE id = $1 LIMIT 1;
+
+-- name: ListBanners :many
+SELECT * FROM banners
+ORDER BY id
+LIMIT $1
+OFFSET $2;
+
+-- name: ListActiveBanners :many
+SELECT * FROM banners
+WHERE is_active = true AND start_at <= now() AND end_at > now()
+ORDER BY end_at ASC;
+
+-- name: UpdateBanner :one
+UPDATE banners
+SET banner_name = $2, cost = $3, is_active = $4, start_at = $5, end_at = $6,
+    multi_count = $7, multi_cost = $8, guarantee_rating = $9,
+    soft_pity = $10, hard_pity = $11, soft_pity_step = $12, spark_cost = $13,
+    spend_order = $14
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteBanner :exec
+DELETE FROM banners
+WHERE id = $1;
+
+-- name: CreateBannerItem :one
+INSERT INTO banner_items (
+    banner_id, item_id, weight, is_featured
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetBannerItem :one
+SELECT * FROM banner_items
+WHERE banner_id = $1 AND item_id = $2 LIMIT 1;
+
+-- name: ListBannerItems :many
+SELECT * FROM banner_items
+WHERE banner_id = $1
+ORDER BY item_id;
+
+-- name: ListBannerPool :many
+SELECT banner_items.item_id, banner_items.weight, banner_items.is_featured, items.item_name, items.rating
+FROM banner_items
+JOIN items ON items.id = banner_items.item_id
+WHERE banner_items.banner_id = $1
+ORDER BY banner_items.item_id;
+
+-- name: UpdateBannerItem :one
+UPDATE banner_items
+SET weight = $3, is_featured = $4
+WHERE banner_id = $1 AND item_id = $2
+RETURNING *;
+
+-- name: DeleteBannerItem :exec
+DELETE FROM banner_items
+WHERE banner_id = $1 AND item_id = $2;
+
+-- name: ListBannerRates :many
+SELECT * FROM banner_rates
+WHERE banner_id = $1
+ORDER BY rating ASC;
+
+-- name: UpsertBannerRate :one
+INSERT INTO banner_rates (
+    banner_id, rating, weight
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (banner_id, rating) DO UPDATE
+SET weight = EXCLUDED.weight
+RETURNING *;
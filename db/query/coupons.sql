@@ -0,0 +1,48 @@
+-- name: CreateCoupon :one
+INSERT INTO coupons (
+    code, amount, item_id, pull_tickets, max_redemptions, per_user_limit, start_at, end_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetCoupon :one
+SELECT * FROM coupons
+WHERE id = $1 LIMIT 1;
+
+-- name: GetCouponByCodeForUpdate :one
+SELECT * FROM coupons
+WHERE code = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListCoupons :many
+SELECT * FROM coupons
+ORDER BY id
+LIMIT $1
+OFFSET $2;
+
+-- name: UpdateCoupon :one
+UPDATE coupons
+SET amount = $2, item_id = $3, pull_tickets = $4, max_redemptions = $5, per_user_limit = $6, start_at = $7, end_at = $8
+WHERE id = $1
+RETURNING *;
+
+-- name: AddCouponRedemption :one
+UPDATE coupons
+SET redemptions = redemptions + 1
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteCoupon :exec
+DELETE FROM coupons
+WHERE id = $1;
+
+-- name: CreateCouponRedemption :one
+INSERT INTO coupon_redemptions (
+    coupon_id, account_id, owner, gallery_id
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: CountCouponRedemptions :one
+SELECT count(*) FROM coupon_redemptions
+WHERE coupon_id = $1 AND owner = $2;
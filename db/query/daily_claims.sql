@@ -0,0 +1,19 @@
+-- name: CreateDailyClaim :one
+INSERT INTO daily_claims (
+    account_id, claim_date, streak, amount, item_id, gallery_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetLastDailyClaim :one
+SELECT * FROM daily_claims
+WHERE account_id = $1
+ORDER BY claim_date DESC
+LIMIT 1;
+
+-- name: ListDailyClaims :many
+SELECT * FROM daily_claims
+WHERE account_id = $1
+ORDER BY claim_date DESC
+LIMIT $2
+OFFSET $3;
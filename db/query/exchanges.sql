@@ -1,8 +1,8 @@
 -- name: CreateExchange :one
 INSERT INTO exchanges (
-    from_account_id, to_account_id, item_id
+    from_account_id, to_account_id, item_id, gallery_id
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4
 ) RETURNING *;
 
 -- name: GetExchange :one
@@ -1,8 +1,9 @@
 -- name: CreateGacha :one
 INSERT INTO gachas (
-    account_id, item_id
+    account_id, item_id, seed_id, nonce, roll, gallery_id, paid_amount, free_amount,
+    banner_id, pool_digest, pity_pulls, min_rating
 ) VALUES (
-    $1, $2
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 ) RETURNING *;
 
 -- name: GetGacha :one
@@ -14,4 +15,19 @@ LIMIT 1;
 SELECT * FROM gachas
 ORDER BY id ASC
 LIMIT $1
-OFFSET $2;
\ No newline at end of file
+OFFSET $2;
+-- name: GetMonthlyPaidSpend :one
+SELECT COALESCE(sum(paid_amount), 0)::bigint AS spent FROM gachas
+WHERE account_id = $1 AND created_at >= $2;
+
+-- name: ListRevocableGachas :many
+SELECT * FROM gachas
+WHERE account_id = $1 AND created_at >= $2
+    AND paid_amount > 0 AND revoked_at IS NULL
+ORDER BY id DESC;
+
+-- name: RevokeGacha :one
+UPDATE gachas
+SET revoked_at = now()
+WHERE id = $1
+RETURNING *;
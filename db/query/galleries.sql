@@ -9,6 +9,16 @@ INSERT INTO galleries (
 SELECT * FROM galleries
 WHERE id = $1 LIMIT 1;
 
+-- name: GetGalleryForUpdate :one
+SELECT * FROM galleries
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetGalleryForUpdateNowait :one
+SELECT * FROM galleries
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE NOWAIT;
+
 -- name: ListGalleriesById :many
 SELECT * FROM galleries
 WHERE owner_id = $1
@@ -23,8 +33,21 @@ ORDER BY item_id ASC
 LIMIT $2
 OFFSET $3;
 
+-- name: ListGalleryQuantities :many
+SELECT item_id, count(*) AS quantity FROM galleries
+WHERE owner_id = $1
+GROUP BY item_id
+ORDER BY item_id ASC
+LIMIT $2
+OFFSET $3;
+
 -- name: UpdateGallery :one
 UPDATE galleries
 SET owner_id = $3, exchange_at = $4
-WHERE owner_id = $1 AND item_id = $2
+WHERE id = $1 AND owner_id = $2
+RETURNING *;
+
+-- name: DeleteGallery :one
+DELETE FROM galleries
+WHERE id = $1 AND owner_id = $2
 RETURNING *;
\ No newline at end of file
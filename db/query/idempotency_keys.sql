@@ -0,0 +1,32 @@
+-- name: CreateIdempotencyKey :one
+INSERT INTO idempotency_keys (
+    username, idempotency_key, request_hash, expires_at
+) VALUES (
+    $1, $2, $3, $4
+) ON CONFLICT (username, idempotency_key) DO UPDATE
+SET request_hash = EXCLUDED.request_hash,
+    response_status = 0,
+    response_body = '',
+    content_type = '',
+    created_at = now(),
+    expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= now()
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE username = $1 AND idempotency_key = $2 LIMIT 1;
+
+-- name: UpdateIdempotencyKeyResponse :one
+UPDATE idempotency_keys
+SET response_status = $3, response_body = $4, content_type = $5
+WHERE username = $1 AND idempotency_key = $2
+RETURNING *;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE username = $1 AND idempotency_key = $2;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at <= $1;
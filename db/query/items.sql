@@ -48,4 +48,8 @@ RETURNING *;
 
 -- name: DeleteItem :exec
 DELETE FROM items
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: ListRotationCandidates :many
+SELECT id, rating FROM items
+ORDER BY id;
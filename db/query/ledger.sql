@@ -0,0 +1,42 @@
+-- name: CreateLedgerJournal :one
+INSERT INTO ledger_journals (
+    reason, reference_id
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: CreateLedgerEntry :one
+INSERT INTO ledger_entries (
+    journal_id, account_id, book, bucket, amount
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListLedgerEntriesByJournal :many
+SELECT * FROM ledger_entries
+WHERE journal_id = $1
+ORDER BY id;
+
+-- name: ListAccountLedger :many
+SELECT ledger_entries.id, ledger_entries.journal_id, ledger_entries.bucket, ledger_entries.amount,
+    ledger_journals.reason, ledger_journals.reference_id, ledger_entries.created_at
+FROM ledger_entries
+JOIN ledger_journals ON ledger_journals.id = ledger_entries.journal_id
+WHERE ledger_entries.account_id = $1
+ORDER BY ledger_entries.id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: GetLedgerBalance :one
+SELECT COALESCE(sum(amount), 0)::bigint AS balance FROM ledger_entries
+WHERE account_id = $1;
+
+-- name: GetLedgerBucketBalance :one
+SELECT COALESCE(sum(amount), 0)::bigint AS balance FROM ledger_entries
+WHERE account_id = $1 AND bucket = $2;
+
+-- name: GetLedgerJournalByReference :one
+SELECT * FROM ledger_journals
+WHERE reason = $1 AND reference_id = $2
+ORDER BY id DESC
+LIMIT 1;
@@ -0,0 +1,58 @@
+-- name: CreateListing :one
+INSERT INTO listings (
+    seller_id, gallery_id, item_id, price
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetListing :one
+SELECT * FROM listings
+WHERE id = $1 LIMIT 1;
+
+-- name: GetListingForUpdate :one
+SELECT * FROM listings
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetGalleryListing :one
+SELECT * FROM listings
+WHERE gallery_id = $1 AND status = 'active'
+LIMIT 1;
+
+-- name: ListMarketListings :many
+SELECT listings.id, listings.seller_id, listings.gallery_id, listings.item_id,
+    listings.price, listings.created_at,
+    items.item_name, items.rating, items.item_url, items.category_id
+FROM listings
+JOIN items ON items.id = listings.item_id
+WHERE listings.status = 'active'
+    AND sqlc.arg(category_id)::int IN (0, items.category_id)
+    AND items.rating >= sqlc.arg(min_rating) AND items.rating <= sqlc.arg(max_rating)
+    AND listings.price >= sqlc.arg(min_price) AND listings.price <= sqlc.arg(max_price)
+ORDER BY listings.price, listings.id
+LIMIT sqlc.arg(page_limit)::int
+OFFSET sqlc.arg(page_offset)::int;
+
+-- name: ListSellerListings :many
+SELECT * FROM listings
+WHERE seller_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: SellListing :one
+UPDATE listings
+SET status = 'sold', buyer_id = $2, fee = $3, sold_at = $4
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateListingStatus :one
+UPDATE listings
+SET status = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: CancelGalleryListing :exec
+UPDATE listings
+SET status = 'cancelled'
+WHERE gallery_id = $1 AND seller_id = $2 AND status = 'active';
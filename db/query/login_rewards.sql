@@ -0,0 +1,16 @@
+-- name: ListLoginRewards :many
+SELECT * FROM login_rewards
+ORDER BY day ASC;
+
+-- name: UpsertLoginReward :one
+INSERT INTO login_rewards (
+    day, amount, item_id
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (day) DO UPDATE
+SET amount = EXCLUDED.amount, item_id = EXCLUDED.item_id, updated_at = now()
+RETURNING *;
+
+-- name: DeleteLoginReward :exec
+DELETE FROM login_rewards
+WHERE day = $1;
@@ -0,0 +1,39 @@
+-- name: CreateOrder :one
+INSERT INTO orders (
+    account_id, pack_id, amount, price, currency, provider
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetOrder :one
+SELECT * FROM orders
+WHERE id = $1 LIMIT 1;
+
+-- name: GetOrderForUpdate :one
+SELECT * FROM orders
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListOrders :many
+SELECT * FROM orders
+WHERE account_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdateOrderProviderRef :one
+UPDATE orders
+SET provider_ref = $2, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateOrderStatus :one
+UPDATE orders
+SET status = $2, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetMonthlyPurchases :one
+SELECT COALESCE(sum(price), 0)::bigint AS purchased FROM orders
+WHERE account_id = $1 AND created_at >= $2
+    AND status IN ('pending', 'paid', 'credited');
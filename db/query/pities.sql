@@ -0,0 +1,12 @@
+-- name: GetPity :one
+SELECT * FROM pities
+WHERE account_id = $1 AND banner_id = $2 LIMIT 1;
+
+-- name: UpsertPity :one
+INSERT INTO pities (
+    account_id, banner_id, pulls
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (account_id, banner_id) DO UPDATE
+SET pulls = EXCLUDED.pulls, updated_at = now()
+RETURNING *;
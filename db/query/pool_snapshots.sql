@@ -0,0 +1,10 @@
+-- name: CreatePoolSnapshot :exec
+INSERT INTO pool_snapshots (
+    digest, snapshot
+) VALUES (
+    $1, $2
+) ON CONFLICT (digest) DO NOTHING;
+
+-- name: GetPoolSnapshot :one
+SELECT * FROM pool_snapshots
+WHERE digest = $1 LIMIT 1;
@@ -0,0 +1,16 @@
+-- name: GetRarityRate :one
+SELECT * FROM rarity_rates
+WHERE rating = $1 LIMIT 1;
+
+-- name: ListRarityRates :many
+SELECT * FROM rarity_rates
+ORDER BY rating ASC;
+
+-- name: UpsertRarityRate :one
+INSERT INTO rarity_rates (
+    rating, weight
+) VALUES (
+    $1, $2
+) ON CONFLICT (rating) DO UPDATE
+SET weight = EXCLUDED.weight, updated_at = now()
+RETURNING *;
@@ -0,0 +1,33 @@
+-- name: CreateSeed :one
+INSERT INTO seeds (
+    account_id, server_seed, server_seed_hash, client_seed
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetSeed :one
+SELECT * FROM seeds
+WHERE id = $1 LIMIT 1;
+
+-- name: GetActiveSeed :one
+SELECT * FROM seeds
+WHERE account_id = $1 AND revealed_at IS NULL
+LIMIT 1;
+
+-- name: GetActiveSeedForUpdate :one
+SELECT * FROM seeds
+WHERE account_id = $1 AND revealed_at IS NULL
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: UpdateSeedNonce :one
+UPDATE seeds
+SET nonce = nonce + $2
+WHERE id = $1
+RETURNING *;
+
+-- name: RevealSeed :one
+UPDATE seeds
+SET revealed_at = now()
+WHERE id = $1 AND revealed_at IS NULL
+RETURNING *;
@@ -0,0 +1,75 @@
+-- name: CreateShopOffer :one
+INSERT INTO shop_offers (
+    item_id, price, spend_order, stock, per_user_limit, start_at, end_at, rotation_day
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetShopOffer :one
+SELECT * FROM shop_offers
+WHERE id = $1 LIMIT 1;
+
+-- name: GetShopOfferForUpdate :one
+SELECT * FROM shop_offers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListShopOffers :many
+SELECT * FROM shop_offers
+ORDER BY id DESC
+LIMIT $1
+OFFSET $2;
+
+-- name: ListActiveShopOffers :many
+SELECT shop_offers.id, shop_offers.item_id, shop_offers.price, shop_offers.spend_order,
+    shop_offers.stock, shop_offers.sold, shop_offers.per_user_limit,
+    shop_offers.start_at, shop_offers.end_at,
+    items.item_name, items.rating, items.item_url
+FROM shop_offers
+JOIN items ON items.id = shop_offers.item_id
+WHERE shop_offers.start_at <= now() AND shop_offers.end_at > now()
+ORDER BY shop_offers.id;
+
+-- name: ListRotationShopOffers :many
+SELECT * FROM shop_offers
+WHERE rotation_day = $1
+ORDER BY id;
+
+-- name: UpdateShopOffer :one
+UPDATE shop_offers
+SET price = $2, spend_order = $3, stock = $4, per_user_limit = $5, start_at = $6, end_at = $7
+WHERE id = $1
+RETURNING *;
+
+-- name: AddShopOfferSale :one
+UPDATE shop_offers
+SET sold = sold + 1
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteShopOffer :exec
+DELETE FROM shop_offers
+WHERE id = $1;
+
+-- name: CreateShopRotation :one
+INSERT INTO shop_rotations (
+    day
+) VALUES (
+    $1
+) ON CONFLICT (day) DO NOTHING
+RETURNING *;
+
+-- name: CreateShopPurchase :one
+INSERT INTO shop_purchases (
+    offer_id, account_id, owner, gallery_id, paid_amount, free_amount
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: CountShopPurchases :one
+SELECT count(*) FROM shop_purchases
+WHERE offer_id = $1 AND owner = $2;
+
+-- name: GetMonthlyShopPaidSpend :one
+SELECT COALESCE(sum(paid_amount), 0)::bigint AS spent FROM shop_purchases
+WHERE account_id = $1 AND created_at >= $2;
@@ -0,0 +1,28 @@
+-- name: CreateSpendingCap :one
+INSERT INTO spending_caps (
+    region, min_age, monthly_limit
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetSpendingCap :one
+SELECT * FROM spending_caps
+WHERE region = sqlc.arg(region) AND min_age <= sqlc.arg(age)
+ORDER BY min_age DESC
+LIMIT 1;
+
+-- name: ListSpendingCaps :many
+SELECT * FROM spending_caps
+ORDER BY region, min_age
+LIMIT $1
+OFFSET $2;
+
+-- name: UpdateSpendingCap :one
+UPDATE spending_caps
+SET monthly_limit = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteSpendingCap :exec
+DELETE FROM spending_caps
+WHERE id = $1;
@@ -0,0 +1,51 @@
+-- name: CreateSubscription :one
+INSERT INTO subscriptions (
+    account_id, pass_id, daily_amount, paid_amount, free_amount, start_at, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetSubscription :one
+SELECT * FROM subscriptions
+WHERE id = $1 LIMIT 1;
+
+-- name: GetLastSubscription :one
+SELECT * FROM subscriptions
+WHERE account_id = $1 AND status = 'active'
+ORDER BY expires_at DESC
+LIMIT 1;
+
+-- name: GetCurrentSubscription :one
+SELECT * FROM subscriptions
+WHERE account_id = $1 AND status = 'active'
+AND start_at <= $2 AND expires_at > $2
+ORDER BY start_at
+LIMIT 1;
+
+-- name: ListSubscriptions :many
+SELECT * FROM subscriptions
+WHERE account_id = $1
+ORDER BY start_at DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ExpireSubscriptions :many
+UPDATE subscriptions
+SET status = 'expired'
+WHERE status = 'active' AND expires_at <= $1
+RETURNING *;
+
+-- name: GetMonthlySubscriptionPaidSpend :one
+SELECT COALESCE(sum(paid_amount), 0)::bigint AS spent FROM subscriptions
+WHERE account_id = $1 AND created_at >= $2;
+
+-- name: CreateSubscriptionClaim :one
+INSERT INTO subscription_claims (
+    subscription_id, account_id, claim_date, amount
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetSubscriptionClaim :one
+SELECT * FROM subscription_claims
+WHERE account_id = $1 AND claim_date = $2 LIMIT 1;
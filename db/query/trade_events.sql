@@ -0,0 +1,11 @@
+-- name: CreateTradeEvent :one
+INSERT INTO trade_events (
+    approval_id, thread_id, account_id, event
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListTradeEvents :many
+SELECT * FROM trade_events
+WHERE thread_id = $1
+ORDER BY id;
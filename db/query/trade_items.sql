@@ -0,0 +1,11 @@
+-- name: CreateTradeItem :one
+INSERT INTO trade_items (
+    approval_id, side, gallery_id, item_id
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListTradeItems :many
+SELECT * FROM trade_items
+WHERE approval_id = $1
+ORDER BY id;
@@ -1,10 +1,17 @@
 -- name: CreateUser :one
 INSERT INTO users (
-    user_name, hash_password, full_name, email
+    user_name, hash_password, full_name, email, birthdate, region
 ) VALUES (
-    $1, $2, $3, $4
+    $1, $2, $3, $4, $5, $6
 ) RETURNING *;
 
 -- name: GetUser :one
 SELECT * FROM users
-WHERE user_name = $1 LIMIT 1;
\ No newline at end of file
+WHERE user_name = $1 LIMIT 1;
+
+-- name: UpdateUserTimezone :one
+UPDATE users
+SET timezone = sqlc.arg(timezone), timezone_updated_at = now()
+WHERE user_name = sqlc.arg(user_name)
+    AND (timezone_updated_at IS NULL OR timezone_updated_at <= sqlc.arg(changed_before))
+RETURNING *;
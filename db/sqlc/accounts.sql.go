@@ -7,29 +7,35 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
-    owner, balance
+    owner, free_balance
 ) VALUES (
     $1, $2
-) RETURNING id, owner, balance, created_at
+) RETURNING id, owner, created_at, shards, paid_balance, free_balance, balance, pull_tickets, flagged_at
 `
 
 type CreateAccountParams struct {
-	Owner   string `json:"owner"`
-	Balance int64  `json:"balance"`
+	Owner       string `json:"owner"`
+	FreeBalance int64  `json:"free_balance"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, createAccount, arg.Owner, arg.Balance)
+	row := q.db.QueryRowContext(ctx, createAccount, arg.Owner, arg.FreeBalance)
 	var i Account
 	err := row.Scan(
 		&i.ID,
 		&i.Owner,
-		&i.Balance,
 		&i.CreatedAt,
+		&i.Shards,
+		&i.PaidBalance,
+		&i.FreeBalance,
+		&i.Balance,
+		&i.PullTickets,
+		&i.FlaggedAt,
 	)
 	return i, err
 }
@@ -45,7 +51,7 @@ func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, created_at FROM accounts
+SELECT id, owner, created_at, shards, paid_balance, free_balance, balance, pull_tickets, flagged_at FROM accounts
 WHERE id = $1 LIMIT 1
 `
 
@@ -55,14 +61,64 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 	err := row.Scan(
 		&i.ID,
 		&i.Owner,
+		&i.CreatedAt,
+		&i.Shards,
+		&i.PaidBalance,
+		&i.FreeBalance,
 		&i.Balance,
+		&i.PullTickets,
+		&i.FlaggedAt,
+	)
+	return i, err
+}
+
+const getAccountByOwner = `-- name: GetAccountByOwner :one
+SELECT id, owner, created_at, shards, paid_balance, free_balance, balance, pull_tickets, flagged_at FROM accounts
+WHERE owner = $1 LIMIT 1
+`
+
+func (q *Queries) GetAccountByOwner(ctx context.Context, owner string) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccountByOwner, owner)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
 		&i.CreatedAt,
+		&i.Shards,
+		&i.PaidBalance,
+		&i.FreeBalance,
+		&i.Balance,
+		&i.PullTickets,
+		&i.FlaggedAt,
+	)
+	return i, err
+}
+
+const getAccountForUpdate = `-- name: GetAccountForUpdate :one
+SELECT id, owner, created_at, shards, paid_balance, free_balance, balance, pull_tickets, flagged_at FROM accounts
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccountForUpdate, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.CreatedAt,
+		&i.Shards,
+		&i.PaidBalance,
+		&i.FreeBalance,
+		&i.Balance,
+		&i.PullTickets,
+		&i.FlaggedAt,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, created_at FROM accounts
+SELECT id, owner, created_at, shards, paid_balance, free_balance, balance, pull_tickets, flagged_at FROM accounts
 WHERE owner = $1
 ORDER BY id
 LIMIT $2
@@ -87,8 +143,13 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 		if err := rows.Scan(
 			&i.ID,
 			&i.Owner,
-			&i.Balance,
 			&i.CreatedAt,
+			&i.Shards,
+			&i.PaidBalance,
+			&i.FreeBalance,
+			&i.Balance,
+			&i.PullTickets,
+			&i.FlaggedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -105,48 +166,147 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 
 const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts
-SET balance = $2
+SET paid_balance = $2, free_balance = $3
 where id = $1
-RETURNING id, owner, balance, created_at
+RETURNING id, owner, created_at, shards, paid_balance, free_balance, balance, pull_tickets, flagged_at
 `
 
 type UpdateAccountParams struct {
-	ID      int64 `json:"id"`
-	Balance int64 `json:"balance"`
+	ID          int64 `json:"id"`
+	PaidBalance int64 `json:"paid_balance"`
+	FreeBalance int64 `json:"free_balance"`
 }
 
 func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, updateAccount, arg.ID, arg.Balance)
+	row := q.db.QueryRowContext(ctx, updateAccount, arg.ID, arg.PaidBalance, arg.FreeBalance)
 	var i Account
 	err := row.Scan(
 		&i.ID,
 		&i.Owner,
+		&i.CreatedAt,
+		&i.Shards,
+		&i.PaidBalance,
+		&i.FreeBalance,
 		&i.Balance,
+		&i.PullTickets,
+		&i.FlaggedAt,
+	)
+	return i, err
+}
+
+const updateAccountFlag = `-- name: UpdateAccountFlag :one
+UPDATE accounts
+SET flagged_at = $2
+WHERE id = $1
+RETURNING id, owner, created_at, shards, paid_balance, free_balance, balance, pull_tickets, flagged_at
+`
+
+type UpdateAccountFlagParams struct {
+	ID        int64        `json:"id"`
+	FlaggedAt sql.NullTime `json:"flagged_at"`
+}
+
+func (q *Queries) UpdateAccountFlag(ctx context.Context, arg UpdateAccountFlagParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountFlag, arg.ID, arg.FlaggedAt)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
 		&i.CreatedAt,
+		&i.Shards,
+		&i.PaidBalance,
+		&i.FreeBalance,
+		&i.Balance,
+		&i.PullTickets,
+		&i.FlaggedAt,
 	)
 	return i, err
 }
 
 const updateBalance = `-- name: UpdateBalance :one
 UPDATE accounts
-SET balance = balance + $2
+SET paid_balance = paid_balance + $2, free_balance = free_balance + $3
 WHERE id = $1
-RETURNING id, owner, balance, created_at
+RETURNING id, owner, created_at, shards, paid_balance, free_balance, balance, pull_tickets, flagged_at
 `
 
 type UpdateBalanceParams struct {
-	ID      int64 `json:"id"`
-	Balance int64 `json:"balance"`
+	ID          int64 `json:"id"`
+	PaidBalance int64 `json:"paid_balance"`
+	FreeBalance int64 `json:"free_balance"`
 }
 
 func (q *Queries) UpdateBalance(ctx context.Context, arg UpdateBalanceParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, updateBalance, arg.ID, arg.Balance)
+	row := q.db.QueryRowContext(ctx, updateBalance, arg.ID, arg.PaidBalance, arg.FreeBalance)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.CreatedAt,
+		&i.Shards,
+		&i.PaidBalance,
+		&i.FreeBalance,
+		&i.Balance,
+		&i.PullTickets,
+		&i.FlaggedAt,
+	)
+	return i, err
+}
+
+const updatePullTickets = `-- name: UpdatePullTickets :one
+UPDATE accounts
+SET pull_tickets = pull_tickets + $2
+WHERE id = $1
+RETURNING id, owner, created_at, shards, paid_balance, free_balance, balance, pull_tickets, flagged_at
+`
+
+type UpdatePullTicketsParams struct {
+	ID          int64 `json:"id"`
+	PullTickets int64 `json:"pull_tickets"`
+}
+
+func (q *Queries) UpdatePullTickets(ctx context.Context, arg UpdatePullTicketsParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updatePullTickets, arg.ID, arg.PullTickets)
 	var i Account
 	err := row.Scan(
 		&i.ID,
 		&i.Owner,
+		&i.CreatedAt,
+		&i.Shards,
+		&i.PaidBalance,
+		&i.FreeBalance,
 		&i.Balance,
+		&i.PullTickets,
+		&i.FlaggedAt,
+	)
+	return i, err
+}
+
+const updateShards = `-- name: UpdateShards :one
+UPDATE accounts
+SET shards = shards + $2
+WHERE id = $1
+RETURNING id, owner, created_at, shards, paid_balance, free_balance, balance, pull_tickets, flagged_at
+`
+
+type UpdateShardsParams struct {
+	ID     int64 `json:"id"`
+	Shards int64 `json:"shards"`
+}
+
+func (q *Queries) UpdateShards(ctx context.Context, arg UpdateShardsParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateShards, arg.ID, arg.Shards)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
 		&i.CreatedAt,
+		&i.Shards,
+		&i.PaidBalance,
+		&i.FreeBalance,
+		&i.Balance,
+		&i.PullTickets,
+		&i.FlaggedAt,
 	)
 	return i, err
 }
@@ -14,7 +14,7 @@ func RandomCreateAccount(t *testing.T) Account {
 
 	arg := CreateAccountParams{
 		Owner: user.UserName,
-		Balance: int64(100),
+		FreeBalance: int64(100),
 	}
 
 	account, err := testQueries.CreateAccount(context.Background(), arg)
@@ -23,6 +23,8 @@ func RandomCreateAccount(t *testing.T) Account {
 
 	require.Equal(t, user.UserName, account.Owner)
 	require.Equal(t, int64(100), account.Balance)
+	require.Equal(t, int64(100), account.FreeBalance)
+	require.Zero(t, account.PaidBalance)
 	require.NotZero(t, account.CreatedAt)
 
 	return account
@@ -72,7 +74,8 @@ func TestUpdateAccount(t *testing.T) {
 
 	arg := UpdateAccountParams{
 		ID: account1.ID,
-		Balance: int64(200),
+		PaidBalance: int64(50),
+		FreeBalance: int64(200),
 	}
 
 	account2, err := testQueries.UpdateAccount(context.Background(), arg)
@@ -81,7 +84,9 @@ func TestUpdateAccount(t *testing.T) {
 
 	require.Equal(t, account1.Owner, account2.Owner)
 	require.Equal(t, arg.ID, account2.ID)
-	require.Equal(t, arg.Balance, account2.Balance)
+	require.Equal(t, arg.PaidBalance, account2.PaidBalance)
+	require.Equal(t, arg.FreeBalance, account2.FreeBalance)
+	require.Equal(t, arg.PaidBalance+arg.FreeBalance, account2.Balance)
 	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
 }
 
@@ -90,7 +95,8 @@ func TestUpdateBalance(t *testing.T) {
 
 	arg := UpdateBalanceParams{
 		ID: account1.ID,
-		Balance: int64(300),
+		PaidBalance: int64(300),
+		FreeBalance: int64(-50),
 	}
 
 	account2, err := testQueries.UpdateBalance(context.Background(), arg)
@@ -99,7 +105,9 @@ func TestUpdateBalance(t *testing.T) {
 
 	require.Equal(t, account1.Owner, account2.Owner)
 	require.Equal(t, arg.ID, account2.ID)
-	require.Equal(t, (account1.Balance + arg.Balance), account2.Balance)
+	require.Equal(t, arg.PaidBalance, account2.PaidBalance)
+	require.Equal(t, account1.FreeBalance+arg.FreeBalance, account2.FreeBalance)
+	require.Equal(t, account1.Balance+arg.PaidBalance+arg.FreeBalance, account2.Balance)
 	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
 }
 
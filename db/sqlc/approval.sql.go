@@ -7,49 +7,64 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createApproval = `-- name: CreateApproval :one
 INSERT INTO approval (
     from_account_id,
-    from_item_id,
     from_A_approval,
+    from_amount,
     to_account_id,
-    to_item_id,
-    to_A_approval
+    to_A_approval,
+    to_amount,
+    expires_at,
+    parent_id,
+    thread_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
-) RETURNING id, from_account_id, from_item_id, from_a_approval, to_account_id, to_item_id, to_a_approval, created_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING id, from_account_id, from_a_approval, to_account_id, to_a_approval, created_at, from_amount, to_amount, status, expires_at, parent_id, thread_id
 `
 
 type CreateApprovalParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	FromItemID    int64 `json:"from_item_id"`
-	FromAApproval bool  `json:"from_a_approval"`
-	ToAccountID   int64 `json:"to_account_id"`
-	ToItemID      int64 `json:"to_item_id"`
-	ToAApproval   bool  `json:"to_a_approval"`
+	FromAccountID int64         `json:"from_account_id"`
+	FromAApproval bool          `json:"from_a_approval"`
+	FromAmount    int64         `json:"from_amount"`
+	ToAccountID   int64         `json:"to_account_id"`
+	ToAApproval   bool          `json:"to_a_approval"`
+	ToAmount      int64         `json:"to_amount"`
+	ExpiresAt     time.Time     `json:"expires_at"`
+	ParentID      sql.NullInt64 `json:"parent_id"`
+	ThreadID      sql.NullInt64 `json:"thread_id"`
 }
 
 func (q *Queries) CreateApproval(ctx context.Context, arg CreateApprovalParams) (Approval, error) {
 	row := q.db.QueryRowContext(ctx, createApproval,
 		arg.FromAccountID,
-		arg.FromItemID,
 		arg.FromAApproval,
+		arg.FromAmount,
 		arg.ToAccountID,
-		arg.ToItemID,
 		arg.ToAApproval,
+		arg.ToAmount,
+		arg.ExpiresAt,
+		arg.ParentID,
+		arg.ThreadID,
 	)
 	var i Approval
 	err := row.Scan(
 		&i.ID,
 		&i.FromAccountID,
-		&i.FromItemID,
 		&i.FromAApproval,
 		&i.ToAccountID,
-		&i.ToItemID,
 		&i.ToAApproval,
 		&i.CreatedAt,
+		&i.FromAmount,
+		&i.ToAmount,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.ParentID,
+		&i.ThreadID,
 	)
 	return i, err
 }
@@ -64,8 +79,51 @@ func (q *Queries) DeleteApproval(ctx context.Context, id int64) error {
 	return err
 }
 
+const expireApprovals = `-- name: ExpireApprovals :many
+UPDATE approval
+SET status = 'expired'
+WHERE status = 'open' AND expires_at <= $1
+RETURNING id, from_account_id, from_a_approval, to_account_id, to_a_approval, created_at, from_amount, to_amount, status, expires_at, parent_id, thread_id
+`
+
+func (q *Queries) ExpireApprovals(ctx context.Context, expiresAt time.Time) ([]Approval, error) {
+	rows, err := q.db.QueryContext(ctx, expireApprovals, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Approval{}
+	for rows.Next() {
+		var i Approval
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.FromAApproval,
+			&i.ToAccountID,
+			&i.ToAApproval,
+			&i.CreatedAt,
+			&i.FromAmount,
+			&i.ToAmount,
+			&i.Status,
+			&i.ExpiresAt,
+			&i.ParentID,
+			&i.ThreadID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getApproval = `-- name: GetApproval :one
-SELECT id, from_account_id, from_item_id, from_a_approval, to_account_id, to_item_id, to_a_approval, created_at FROM approval
+SELECT id, from_account_id, from_a_approval, to_account_id, to_a_approval, created_at, from_amount, to_amount, status, expires_at, parent_id, thread_id FROM approval
 WHERE id = $1 LIMIT 1
 `
 
@@ -75,18 +133,48 @@ func (q *Queries) GetApproval(ctx context.Context, id int64) (Approval, error) {
 	err := row.Scan(
 		&i.ID,
 		&i.FromAccountID,
-		&i.FromItemID,
 		&i.FromAApproval,
 		&i.ToAccountID,
-		&i.ToItemID,
 		&i.ToAApproval,
 		&i.CreatedAt,
+		&i.FromAmount,
+		&i.ToAmount,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.ParentID,
+		&i.ThreadID,
+	)
+	return i, err
+}
+
+const getApprovalForUpdate = `-- name: GetApprovalForUpdate :one
+SELECT id, from_account_id, from_a_approval, to_account_id, to_a_approval, created_at, from_amount, to_amount, status, expires_at, parent_id, thread_id FROM approval
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetApprovalForUpdate(ctx context.Context, id int64) (Approval, error) {
+	row := q.db.QueryRowContext(ctx, getApprovalForUpdate, id)
+	var i Approval
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.FromAApproval,
+		&i.ToAccountID,
+		&i.ToAApproval,
+		&i.CreatedAt,
+		&i.FromAmount,
+		&i.ToAmount,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.ParentID,
+		&i.ThreadID,
 	)
 	return i, err
 }
 
 const listApproval = `-- name: ListApproval :many
-SELECT id, from_account_id, from_item_id, from_a_approval, to_account_id, to_item_id, to_a_approval, created_at FROM approval
+SELECT id, from_account_id, from_a_approval, to_account_id, to_a_approval, created_at, from_amount, to_amount, status, expires_at, parent_id, thread_id FROM approval
 ORDER BY id
 LIMIT $1
 OFFSET $2
@@ -109,12 +197,116 @@ func (q *Queries) ListApproval(ctx context.Context, arg ListApprovalParams) ([]A
 		if err := rows.Scan(
 			&i.ID,
 			&i.FromAccountID,
-			&i.FromItemID,
 			&i.FromAApproval,
 			&i.ToAccountID,
-			&i.ToItemID,
 			&i.ToAApproval,
 			&i.CreatedAt,
+			&i.FromAmount,
+			&i.ToAmount,
+			&i.Status,
+			&i.ExpiresAt,
+			&i.ParentID,
+			&i.ThreadID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIncomingApprovals = `-- name: ListIncomingApprovals :many
+SELECT id, from_account_id, from_a_approval, to_account_id, to_a_approval, created_at, from_amount, to_amount, status, expires_at, parent_id, thread_id FROM approval
+WHERE to_account_id = $1 AND status = 'open'
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3
+`
+
+type ListIncomingApprovalsParams struct {
+	ToAccountID int64 `json:"to_account_id"`
+	Limit       int32 `json:"limit"`
+	Offset      int32 `json:"offset"`
+}
+
+func (q *Queries) ListIncomingApprovals(ctx context.Context, arg ListIncomingApprovalsParams) ([]Approval, error) {
+	rows, err := q.db.QueryContext(ctx, listIncomingApprovals, arg.ToAccountID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Approval{}
+	for rows.Next() {
+		var i Approval
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.FromAApproval,
+			&i.ToAccountID,
+			&i.ToAApproval,
+			&i.CreatedAt,
+			&i.FromAmount,
+			&i.ToAmount,
+			&i.Status,
+			&i.ExpiresAt,
+			&i.ParentID,
+			&i.ThreadID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOutgoingApprovals = `-- name: ListOutgoingApprovals :many
+SELECT id, from_account_id, from_a_approval, to_account_id, to_a_approval, created_at, from_amount, to_amount, status, expires_at, parent_id, thread_id FROM approval
+WHERE from_account_id = $1 AND status = 'open'
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3
+`
+
+type ListOutgoingApprovalsParams struct {
+	FromAccountID int64 `json:"from_account_id"`
+	Limit         int32 `json:"limit"`
+	Offset        int32 `json:"offset"`
+}
+
+func (q *Queries) ListOutgoingApprovals(ctx context.Context, arg ListOutgoingApprovalsParams) ([]Approval, error) {
+	rows, err := q.db.QueryContext(ctx, listOutgoingApprovals, arg.FromAccountID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Approval{}
+	for rows.Next() {
+		var i Approval
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.FromAApproval,
+			&i.ToAccountID,
+			&i.ToAApproval,
+			&i.CreatedAt,
+			&i.FromAmount,
+			&i.ToAmount,
+			&i.Status,
+			&i.ExpiresAt,
+			&i.ParentID,
+			&i.ThreadID,
 		); err != nil {
 			return nil, err
 		}
@@ -133,7 +325,7 @@ const updateApprovalRequest = `-- name: UpdateApprovalRequest :one
 UPDATE approval
 SET from_A_approval = $2
 where id = $1
-RETURNING id, from_account_id, from_item_id, from_a_approval, to_account_id, to_item_id, to_a_approval, created_at
+RETURNING id, from_account_id, from_a_approval, to_account_id, to_a_approval, created_at, from_amount, to_amount, status, expires_at, parent_id, thread_id
 `
 
 type UpdateApprovalRequestParams struct {
@@ -147,12 +339,16 @@ func (q *Queries) UpdateApprovalRequest(ctx context.Context, arg UpdateApprovalR
 	err := row.Scan(
 		&i.ID,
 		&i.FromAccountID,
-		&i.FromItemID,
 		&i.FromAApproval,
 		&i.ToAccountID,
-		&i.ToItemID,
 		&i.ToAApproval,
 		&i.CreatedAt,
+		&i.FromAmount,
+		&i.ToAmount,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.ParentID,
+		&i.ThreadID,
 	)
 	return i, err
 }
@@ -161,7 +357,7 @@ const updateApprovalResponse = `-- name: UpdateApprovalResponse :one
 UPDATE approval
 SET to_A_approval = $2
 where id = $1
-RETURNING id, from_account_id, from_item_id, from_a_approval, to_account_id, to_item_id, to_a_approval, created_at
+RETURNING id, from_account_id, from_a_approval, to_account_id, to_a_approval, created_at, from_amount, to_amount, status, expires_at, parent_id, thread_id
 `
 
 type UpdateApprovalResponseParams struct {
@@ -175,12 +371,48 @@ func (q *Queries) UpdateApprovalResponse(ctx context.Context, arg UpdateApproval
 	err := row.Scan(
 		&i.ID,
 		&i.FromAccountID,
-		&i.FromItemID,
 		&i.FromAApproval,
 		&i.ToAccountID,
-		&i.ToItemID,
 		&i.ToAApproval,
 		&i.CreatedAt,
+		&i.FromAmount,
+		&i.ToAmount,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.ParentID,
+		&i.ThreadID,
+	)
+	return i, err
+}
+
+const updateApprovalStatus = `-- name: UpdateApprovalStatus :one
+UPDATE approval
+SET status = $2
+WHERE id = $1
+RETURNING id, from_account_id, from_a_approval, to_account_id, to_a_approval, created_at, from_amount, to_amount, status, expires_at, parent_id, thread_id
+`
+
+type UpdateApprovalStatusParams struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+}
+
+func (q *Queries) UpdateApprovalStatus(ctx context.Context, arg UpdateApprovalStatusParams) (Approval, error) {
+	row := q.db.QueryRowContext(ctx, updateApprovalStatus, arg.ID, arg.Status)
+	var i Approval
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.FromAApproval,
+		&i.ToAccountID,
+		&i.ToAApproval,
+		&i.CreatedAt,
+		&i.FromAmount,
+		&i.ToAmount,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.ParentID,
+		&i.ThreadID,
 	)
 	return i, err
 }
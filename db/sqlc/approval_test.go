@@ -1,7 +1,553 @@
 package db
 
-import "testing"
+import (
+	"context"
+	"testing"
+	"time"
 
-func TestCreateApproval(t *testing.T) {
+	"github.com/stretchr/testify/require"
+)
 
-}
\ No newline at end of file
+// createOwnedGallery gives a copy of a new item to the account
+func createOwnedGallery(t *testing.T, account Account) Gallery {
+	gallery, err := testQueries.CreateGallery(context.Background(), CreateGalleryParams{
+		OwnerID: account.ID,
+		ItemID: RandomCreateItem(t).ID,
+	})
+	require.NoError(t, err)
+	return gallery
+}
+
+// createTestTradeOffer makes an offer, open for an hour unless arg sets its expiry
+func createTestTradeOffer(t *testing.T, arg CreateTradeOfferTxParams) TradeOffer {
+	if arg.ExpiresAt.IsZero() {
+		arg.ExpiresAt = time.Now().Add(time.Hour)
+	}
+
+	offer, err := NewStore(testDB).CreateTradeOfferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.Equal(t, arg.FromAccountID, offer.Approval.FromAccountID)
+	require.Equal(t, arg.ToAccountID, offer.Approval.ToAccountID)
+	require.Equal(t, arg.FromAmount, offer.Approval.FromAmount)
+	require.Equal(t, arg.ToAmount, offer.Approval.ToAmount)
+	require.True(t, offer.Approval.FromAApproval)
+	require.False(t, offer.Approval.ToAApproval)
+	require.Equal(t, TradeOpen, offer.Approval.Status)
+	require.WithinDuration(t, arg.ExpiresAt, offer.Approval.ExpiresAt, time.Second)
+	require.NotZero(t, offer.Approval.CreatedAt)
+
+	require.Equal(t, arg.FromGalleryIDs, tradeGalleryIDs(offer, TradeSideRequest))
+	require.Equal(t, arg.ToGalleryIDs, tradeGalleryIDs(offer, TradeSideResponse))
+	return offer
+}
+
+// RandomCreateTradeOffer offers a copy of a new account for a copy of another
+// new account, approved by its proposer
+func RandomCreateTradeOffer(t *testing.T) TradeOffer {
+	from := RandomCreateAccount(t)
+	to := RandomCreateAccount(t)
+
+	return createTestTradeOffer(t, CreateTradeOfferTxParams{
+		FromAccountID: from.ID,
+		Owner: from.Owner,
+		ToAccountID: to.ID,
+		FromGalleryIDs: []int64{createOwnedGallery(t, from).ID},
+		ToGalleryIDs: []int64{createOwnedGallery(t, to).ID},
+	})
+}
+
+// tradeGalleryIDs returns the copies a side of an offer gives
+func tradeGalleryIDs(offer TradeOffer, side string) []int64 {
+	var ids []int64
+	for _, item := range offer.Items {
+		if item.Side == side {
+			ids = append(ids, item.GalleryID.Int64)
+		}
+	}
+	return ids
+}
+
+// approvalOwner returns the owner of the account of a side of an offer
+func approvalOwner(t *testing.T, approval Approval, side string) string {
+	accountID := approval.FromAccountID
+	if side == TradeSideResponse {
+		accountID = approval.ToAccountID
+	}
+
+	account, err := testQueries.GetAccount(context.Background(), accountID)
+	require.NoError(t, err)
+	return account.Owner
+}
+
+func approveTrade(t *testing.T, approval Approval, side string, approved bool) (ApproveTradeTxResult, error) {
+	return NewStore(testDB).ApproveTradeTx(context.Background(), ApproveTradeTxParams{
+		ApprovalID: approval.ID,
+		Owner: approvalOwner(t, approval, side),
+		Side: side,
+		Approved: approved,
+		Now: time.Now(),
+	})
+}
+
+// requireTradeEvents compares the events of the thread of an offer with events
+func requireTradeEvents(t *testing.T, approval Approval, events ...string) {
+	history, err := testQueries.ListTradeEvents(context.Background(), TradeThread(approval))
+	require.NoError(t, err)
+
+	got := make([]string, len(history))
+	for i, event := range history {
+		got[i] = event.Event
+	}
+	require.Equal(t, events, got)
+}
+
+func TestCreateTradeOfferTx(t *testing.T) {
+	RandomCreateTradeOffer(t)
+}
+
+func TestCreateTradeOfferTxMultiItem(t *testing.T) {
+	from := createLedgerAccount(t, 1000)
+	to := createLedgerAccount(t, 1000)
+
+	// two copies and some currency for a copy, or a copy and currency for currency
+	createTestTradeOffer(t, CreateTradeOfferTxParams{
+		FromAccountID: from.ID,
+		Owner: from.Owner,
+		ToAccountID: to.ID,
+		FromGalleryIDs: []int64{createOwnedGallery(t, from).ID, createOwnedGallery(t, from).ID},
+		ToGalleryIDs: []int64{createOwnedGallery(t, to).ID},
+		FromAmount: 200,
+	})
+	createTestTradeOffer(t, CreateTradeOfferTxParams{
+		FromAccountID: from.ID,
+		Owner: from.Owner,
+		ToAccountID: to.ID,
+		FromGalleryIDs: []int64{createOwnedGallery(t, from).ID},
+		FromAmount: 100,
+		ToAmount: 500,
+	})
+}
+
+func TestCreateTradeOfferTxFails(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createLedgerAccount(t, 100)
+	to := createLedgerAccount(t, 100)
+	fromGallery := createOwnedGallery(t, from)
+	toGallery := createOwnedGallery(t, to)
+
+	testCases := []struct {
+		name string
+		arg  CreateTradeOfferTxParams
+		err  error
+	}{
+		{
+			name: "NotOwner",
+			arg: CreateTradeOfferTxParams{FromAccountID: from.ID, Owner: to.Owner, ToAccountID: to.ID, FromGalleryIDs: []int64{fromGallery.ID}, ToGalleryIDs: []int64{toGallery.ID}},
+			err: ErrAccountNotOwned,
+		},
+		{
+			name: "CopyNotHeld",
+			arg: CreateTradeOfferTxParams{FromAccountID: from.ID, Owner: from.Owner, ToAccountID: to.ID, FromGalleryIDs: []int64{fromGallery.ID, toGallery.ID}, ToAmount: 10},
+			err: ErrItemNotOwned,
+		},
+		{
+			name: "EmptySide",
+			arg: CreateTradeOfferTxParams{FromAccountID: from.ID, Owner: from.Owner, ToAccountID: to.ID, FromGalleryIDs: []int64{fromGallery.ID}},
+			err: ErrInvalidTrade,
+		},
+		{
+			name: "InsufficientBalance",
+			arg: CreateTradeOfferTxParams{FromAccountID: from.ID, Owner: from.Owner, ToAccountID: to.ID, FromAmount: 101, ToGalleryIDs: []int64{toGallery.ID}},
+			err: ErrInsufficientBalance,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := store.CreateTradeOfferTx(context.Background(), tc.arg)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+
+	// none of the failed offers was kept
+	outgoing, err := testQueries.ListOutgoingApprovals(context.Background(), ListOutgoingApprovalsParams{
+		FromAccountID: from.ID,
+		Limit: 5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Empty(t, outgoing)
+}
+
+func TestListIncomingOutgoingApprovals(t *testing.T) {
+	approval := RandomCreateTradeOffer(t).Approval
+
+	incoming, err := testQueries.ListIncomingApprovals(context.Background(), ListIncomingApprovalsParams{
+		ToAccountID: approval.ToAccountID,
+		Limit: 5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, incoming, 1)
+	require.Equal(t, approval.ID, incoming[0].ID)
+
+	outgoing, err := testQueries.ListOutgoingApprovals(context.Background(), ListOutgoingApprovalsParams{
+		FromAccountID: approval.FromAccountID,
+		Limit: 5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, outgoing, 1)
+	require.Equal(t, approval.ID, outgoing[0].ID)
+}
+
+func TestApproveTradeTx(t *testing.T) {
+	offer := RandomCreateTradeOffer(t)
+	approval := offer.Approval
+
+	result, err := approveTrade(t, approval, TradeSideResponse, true)
+	require.NoError(t, err)
+	require.True(t, result.Approval.ToAApproval)
+	require.NotNil(t, result.Exchange)
+
+	// the copies changed hands
+	require.Len(t, result.Exchange.Galleries, 2)
+	require.Equal(t, approval.ToAccountID, result.Exchange.Galleries[0].OwnerID)
+	require.Equal(t, approval.FromAccountID, result.Exchange.Galleries[1].OwnerID)
+	require.Equal(t, offer.Items[0].ItemID, result.Exchange.Exchanges[0].ItemID)
+	require.Equal(t, offer.Items[1].ItemID, result.Exchange.Exchanges[1].ItemID)
+
+	// and the offer is accepted, keeping its items
+	require.Equal(t, TradeAccepted, result.Approval.Status)
+
+	items, err := testQueries.ListTradeItems(context.Background(), approval.ID)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	requireTradeEvents(t, approval, TradeEventCreated, TradeEventApproved, TradeAccepted)
+
+	// an accepted offer is exchanged once
+	_, err = approveTrade(t, approval, TradeSideResponse, true)
+	require.ErrorIs(t, err, ErrTradeClosed)
+}
+
+func TestApproveTradeTxExpired(t *testing.T) {
+	from := RandomCreateAccount(t)
+	to := RandomCreateAccount(t)
+
+	// the offer expired but the sweeper hasn't closed it yet
+	offer := createTestTradeOffer(t, CreateTradeOfferTxParams{
+		FromAccountID: from.ID,
+		Owner: from.Owner,
+		ToAccountID: to.ID,
+		FromGalleryIDs: []int64{createOwnedGallery(t, from).ID},
+		ToGalleryIDs: []int64{createOwnedGallery(t, to).ID},
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+
+	_, err := approveTrade(t, offer.Approval, TradeSideResponse, true)
+	require.ErrorIs(t, err, ErrTradeExpired)
+}
+
+func TestCloseTradeOfferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	testCases := []struct {
+		name   string
+		side   string
+		status string
+	}{
+		{name: "Cancel", side: TradeSideRequest, status: TradeCancelled},
+		{name: "Reject", side: TradeSideResponse, status: TradeRejected},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			approval := RandomCreateTradeOffer(t).Approval
+			arg := CloseTradeOfferTxParams{
+				ApprovalID: approval.ID,
+				Owner: approvalOwner(t, approval, tc.side),
+				Now: time.Now(),
+			}
+
+			closed, err := store.CloseTradeOfferTx(context.Background(), arg)
+			require.NoError(t, err)
+			require.Equal(t, tc.status, closed.Status)
+			requireTradeEvents(t, approval, TradeEventCreated, tc.status)
+
+			// the closed offer is out of the open lists but kept in the history
+			_, err = store.CloseTradeOfferTx(context.Background(), arg)
+			require.ErrorIs(t, err, ErrTradeClosed)
+
+			incoming, err := testQueries.ListIncomingApprovals(context.Background(), ListIncomingApprovalsParams{
+				ToAccountID: approval.ToAccountID,
+				Limit: 5,
+				Offset: 0,
+			})
+			require.NoError(t, err)
+			require.Empty(t, incoming)
+		})
+	}
+}
+
+func TestCloseTradeOfferTxNotParty(t *testing.T) {
+	approval := RandomCreateTradeOffer(t).Approval
+
+	_, err := NewStore(testDB).CloseTradeOfferTx(context.Background(), CloseTradeOfferTxParams{
+		ApprovalID: approval.ID,
+		Owner: RandomCreateUser(t).UserName,
+		Now: time.Now(),
+	})
+	require.ErrorIs(t, err, ErrAccountNotOwned)
+}
+
+func TestCounterTradeOfferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createLedgerAccount(t, 1000)
+	to := createLedgerAccount(t, 1000)
+	offer := createTestTradeOffer(t, CreateTradeOfferTxParams{
+		FromAccountID: from.ID,
+		Owner: from.Owner,
+		ToAccountID: to.ID,
+		FromGalleryIDs: []int64{createOwnedGallery(t, from).ID},
+		ToGalleryIDs: []int64{createOwnedGallery(t, to).ID, createOwnedGallery(t, to).ID},
+	})
+	original := offer.Approval
+
+	arg := CounterTradeOfferTxParams{
+		ApprovalID: original.ID,
+		Owner: to.Owner,
+		// the counterparty keeps one of its copies and asks for currency on top
+		FromGalleryIDs: tradeGalleryIDs(offer, TradeSideResponse)[:1],
+		ToGalleryIDs: tradeGalleryIDs(offer, TradeSideRequest),
+		ToAmount: 100,
+		ExpiresAt: time.Now().Add(time.Hour),
+		Now: time.Now(),
+	}
+
+	// the proposer can't counter its own offer
+	_, err := store.CounterTradeOfferTx(context.Background(), CounterTradeOfferTxParams{
+		ApprovalID: original.ID,
+		Owner: from.Owner,
+		ToGalleryIDs: arg.FromGalleryIDs,
+		FromAmount: 10,
+		ExpiresAt: arg.ExpiresAt,
+		Now: arg.Now,
+	})
+	require.ErrorIs(t, err, ErrAccountNotOwned)
+
+	counter, err := store.CounterTradeOfferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, to.ID, counter.Approval.FromAccountID)
+	require.Equal(t, from.ID, counter.Approval.ToAccountID)
+	require.Equal(t, int64(100), counter.Approval.ToAmount)
+	require.Equal(t, original.ID, counter.Approval.ParentID.Int64)
+	require.Equal(t, original.ID, TradeThread(counter.Approval))
+	require.Equal(t, arg.FromGalleryIDs, tradeGalleryIDs(counter, TradeSideRequest))
+	require.Equal(t, arg.ToGalleryIDs, tradeGalleryIDs(counter, TradeSideResponse))
+
+	// the counter-offer replaces the original
+	original1, err := testQueries.GetApproval(context.Background(), original.ID)
+	require.NoError(t, err)
+	require.Equal(t, TradeCountered, original1.Status)
+
+	_, err = approveTrade(t, original, TradeSideResponse, true)
+	require.ErrorIs(t, err, ErrTradeClosed)
+
+	// the first proposer accepts the counter-offer
+	result, err := approveTrade(t, counter.Approval, TradeSideResponse, true)
+	require.NoError(t, err)
+	require.NotNil(t, result.Exchange)
+	require.Equal(t, from.FreeBalance-100, result.Exchange.ToAccount.FreeBalance)
+	requireReconciled(t, from)
+	requireReconciled(t, to)
+
+	// both offers share a single history
+	requireTradeEvents(t, counter.Approval,
+		TradeEventCreated, TradeCountered, TradeEventCreated, TradeEventApproved, TradeAccepted)
+}
+
+func TestExpireTradeOffersTx(t *testing.T) {
+	store := NewStore(testDB)
+	from := RandomCreateAccount(t)
+	to := RandomCreateAccount(t)
+
+	offer := createTestTradeOffer(t, CreateTradeOfferTxParams{
+		FromAccountID: from.ID,
+		Owner: from.Owner,
+		ToAccountID: to.ID,
+		FromGalleryIDs: []int64{createOwnedGallery(t, from).ID},
+		ToGalleryIDs: []int64{createOwnedGallery(t, to).ID},
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	open := RandomCreateTradeOffer(t)
+
+	expired, err := store.ExpireTradeOffersTx(context.Background(), time.Now().Add(2*time.Minute))
+	require.NoError(t, err)
+
+	ids := make(map[int64]bool)
+	for _, approval := range expired {
+		require.Equal(t, TradeExpired, approval.Status)
+		ids[approval.ID] = true
+	}
+	require.True(t, ids[offer.Approval.ID])
+	require.False(t, ids[open.Approval.ID])
+
+	history, err := testQueries.ListTradeEvents(context.Background(), offer.Approval.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, TradeExpired, history[1].Event)
+	require.False(t, history[1].AccountID.Valid)
+
+	_, err = approveTrade(t, offer.Approval, TradeSideResponse, true)
+	require.ErrorIs(t, err, ErrTradeClosed)
+}
+
+func TestApproveTradeTxMultiItemWithCurrency(t *testing.T) {
+	from := createLedgerAccount(t, 1000)
+	to := createLedgerAccount(t, 1000)
+
+	offer := createTestTradeOffer(t, CreateTradeOfferTxParams{
+		FromAccountID: from.ID,
+		Owner: from.Owner,
+		ToAccountID: to.ID,
+		FromGalleryIDs: []int64{createOwnedGallery(t, from).ID, createOwnedGallery(t, from).ID},
+		ToGalleryIDs: []int64{createOwnedGallery(t, to).ID},
+		FromAmount: 250,
+		ToAmount: 50,
+	})
+
+	result, err := approveTrade(t, offer.Approval, TradeSideResponse, true)
+	require.NoError(t, err)
+	require.NotNil(t, result.Exchange)
+
+	for _, id := range tradeGalleryIDs(offer, TradeSideRequest) {
+		gallery, err := testQueries.GetGallery(context.Background(), id)
+		require.NoError(t, err)
+		require.Equal(t, to.ID, gallery.OwnerID)
+	}
+	for _, id := range tradeGalleryIDs(offer, TradeSideResponse) {
+		gallery, err := testQueries.GetGallery(context.Background(), id)
+		require.NoError(t, err)
+		require.Equal(t, from.ID, gallery.OwnerID)
+	}
+
+	// both amounts moved, through the ledger
+	require.Equal(t, from.FreeBalance-200, result.Exchange.FromAccount.FreeBalance)
+	require.Equal(t, to.FreeBalance+200, result.Exchange.ToAccount.FreeBalance)
+	requireReconciled(t, from)
+	requireReconciled(t, to)
+}
+
+func TestApproveTradeTxCurrencySpent(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createLedgerAccount(t, 100)
+	to := createLedgerAccount(t, 100)
+	toGallery := createOwnedGallery(t, to)
+
+	offer := createTestTradeOffer(t, CreateTradeOfferTxParams{
+		FromAccountID: from.ID,
+		Owner: from.Owner,
+		ToAccountID: to.ID,
+		ToGalleryIDs: []int64{toGallery.ID},
+		FromAmount: 100,
+	})
+
+	// the proposer spends the currency of the offer before it is accepted
+	_, err := store.LedgerTx(context.Background(), LedgerTxParams{
+		AccountID: from.ID,
+		Amount: -50,
+		Reason: ReasonAdminAdjustment,
+		ReferenceID: "test",
+	})
+	require.NoError(t, err)
+
+	_, err = approveTrade(t, offer.Approval, TradeSideResponse, true)
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+
+	gallery, err := testQueries.GetGallery(context.Background(), toGallery.ID)
+	require.NoError(t, err)
+	require.Equal(t, to.ID, gallery.OwnerID)
+	requireReconciled(t, from)
+}
+
+func TestApproveTradeTxWithdrawn(t *testing.T) {
+	offer := RandomCreateTradeOffer(t)
+	approval := offer.Approval
+
+	result, err := approveTrade(t, approval, TradeSideRequest, false)
+	require.NoError(t, err)
+	require.False(t, result.Approval.FromAApproval)
+	require.Nil(t, result.Exchange)
+
+	// an acceptance waits for the proposer to approve again
+	result, err = approveTrade(t, approval, TradeSideResponse, true)
+	require.NoError(t, err)
+	require.True(t, result.Approval.ToAApproval)
+	require.Nil(t, result.Exchange)
+
+	gallery, err := testQueries.GetGallery(context.Background(), tradeGalleryIDs(offer, TradeSideRequest)[0])
+	require.NoError(t, err)
+	require.Equal(t, approval.FromAccountID, gallery.OwnerID)
+
+	result, err = approveTrade(t, approval, TradeSideRequest, true)
+	require.NoError(t, err)
+	require.NotNil(t, result.Exchange)
+}
+
+func TestApproveTradeTxWrongSide(t *testing.T) {
+	approval := RandomCreateTradeOffer(t).Approval
+
+	// the proposer can't accept for the counterparty
+	_, err := NewStore(testDB).ApproveTradeTx(context.Background(), ApproveTradeTxParams{
+		ApprovalID: approval.ID,
+		Owner: approvalOwner(t, approval, TradeSideRequest),
+		Side: TradeSideResponse,
+		Approved: true,
+	})
+	require.ErrorIs(t, err, ErrAccountNotOwned)
+
+	approval1, err := testQueries.GetApproval(context.Background(), approval.ID)
+	require.NoError(t, err)
+	require.False(t, approval1.ToAApproval)
+}
+
+func TestApproveTradeTxGalleryMoved(t *testing.T) {
+	offer := RandomCreateTradeOffer(t)
+	approval := offer.Approval
+
+	// the offered copy left the proposer after the offer was made
+	_, err := testQueries.UpdateGallery(context.Background(), UpdateGalleryParams{
+		ID: tradeGalleryIDs(offer, TradeSideRequest)[0],
+		OwnerID: approval.FromAccountID,
+		OwnerID_2: RandomCreateAccount(t).ID,
+	})
+	require.NoError(t, err)
+
+	_, err = approveTrade(t, approval, TradeSideResponse, true)
+	require.ErrorIs(t, err, ErrItemNotOwned)
+
+	// the failed exchange leaves the offer as it was
+	approval1, err := testQueries.GetApproval(context.Background(), approval.ID)
+	require.NoError(t, err)
+	require.False(t, approval1.ToAApproval)
+}
+
+func TestApproveTradeTxGalleryConverted(t *testing.T) {
+	offer := RandomCreateTradeOffer(t)
+	approval := offer.Approval
+
+	// the copy asked for was converted into shards
+	_, err := testQueries.DeleteGallery(context.Background(), DeleteGalleryParams{
+		ID: tradeGalleryIDs(offer, TradeSideResponse)[0],
+		OwnerID: approval.ToAccountID,
+	})
+	require.NoError(t, err)
+
+	_, err = approveTrade(t, approval, TradeSideResponse, true)
+	require.ErrorIs(t, err, ErrItemNotOwned)
+}
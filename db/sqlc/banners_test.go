@@ -0,0 +1,204 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/sRRRs-7/GachaPon/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func RandomCreateBanner(t *testing.T) Banner {
+	arg := CreateBannerParams{
+		BannerName: utils.RandomString(8),
+		Cost: utils.RandomInt(1, 100),
+		IsActive: true,
+		StartAt: time.Now().Add(-time.Hour),
+		EndAt: time.Now().Add(time.Hour),
+		MultiCount: 10,
+		MultiCost: utils.RandomInt(100, 900),
+		GuaranteeRating: 4,
+		SoftPity: 60,
+		HardPity: 80,
+		SoftPityStep: 600,
+		SparkCost: 300,
+		SpendOrder: SpendFreeFirst,
+	}
+
+	banner, err := testQueries.CreateBanner(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, banner)
+
+	require.Equal(t, arg.BannerName, banner.BannerName)
+	require.Equal(t, arg.Cost, banner.Cost)
+	require.Equal(t, arg.IsActive, banner.IsActive)
+	require.WithinDuration(t, arg.StartAt, banner.StartAt, time.Second)
+	require.WithinDuration(t, arg.EndAt, banner.EndAt, time.Second)
+	require.Equal(t, arg.MultiCount, banner.MultiCount)
+	require.Equal(t, arg.MultiCost, banner.MultiCost)
+	require.Equal(t, arg.GuaranteeRating, banner.GuaranteeRating)
+	require.Equal(t, arg.SpendOrder, banner.SpendOrder)
+	require.Equal(t, arg.SoftPity, banner.SoftPity)
+	require.Equal(t, arg.HardPity, banner.HardPity)
+	require.Equal(t, arg.SoftPityStep, banner.SoftPityStep)
+	require.Equal(t, arg.SparkCost, banner.SparkCost)
+	require.NotZero(t, banner.CreatedAt)
+
+	return banner
+}
+
+func RandomCreateBannerItem(t *testing.T, banner Banner) BannerItem {
+	item := RandomCreateItem(t)
+
+	arg := CreateBannerItemParams{
+		BannerID: banner.ID,
+		ItemID: item.ID,
+		Weight: utils.RandomInt(1, 10),
+		IsFeatured: false,
+	}
+
+	bannerItem, err := testQueries.CreateBannerItem(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, bannerItem)
+
+	require.Equal(t, arg.BannerID, bannerItem.BannerID)
+	require.Equal(t, arg.ItemID, bannerItem.ItemID)
+	require.Equal(t, arg.Weight, bannerItem.Weight)
+	require.Equal(t, arg.IsFeatured, bannerItem.IsFeatured)
+
+	return bannerItem
+}
+
+func TestCreateBanner(t *testing.T) {
+	RandomCreateBanner(t)
+}
+
+func TestGetBanner(t *testing.T) {
+	banner1 := RandomCreateBanner(t)
+
+	banner2, err := testQueries.GetBanner(context.Background(), banner1.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, banner2)
+
+	require.Equal(t, banner1.ID, banner2.ID)
+	require.Equal(t, banner1.BannerName, banner2.BannerName)
+	require.Equal(t, banner1.Cost, banner2.Cost)
+	require.WithinDuration(t, banner1.CreatedAt, banner2.CreatedAt, time.Second)
+}
+
+func TestListActiveBanners(t *testing.T) {
+	banner := RandomCreateBanner(t)
+
+	arg := UpdateBannerParams{
+		ID: banner.ID,
+		BannerName: banner.BannerName,
+		Cost: banner.Cost,
+		IsActive: true,
+		StartAt: time.Now().Add(-2 * time.Hour),
+		EndAt: time.Now().Add(-time.Hour),
+		MultiCount: banner.MultiCount,
+		MultiCost: banner.MultiCost,
+		GuaranteeRating: banner.GuaranteeRating,
+		SoftPity: banner.SoftPity,
+		HardPity: banner.HardPity,
+		SoftPityStep: banner.SoftPityStep,
+		SparkCost: banner.SparkCost,
+		SpendOrder: banner.SpendOrder,
+	}
+	expired, err := testQueries.UpdateBanner(context.Background(), arg)
+	require.NoError(t, err)
+
+	active := RandomCreateBanner(t)
+
+	banners, err := testQueries.ListActiveBanners(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, banners)
+
+	var found bool
+	for _, b := range banners {
+		require.NotEqual(t, expired.ID, b.ID)
+		if b.ID == active.ID {
+			found = true
+		}
+	}
+	require.True(t, found)
+}
+
+func TestListBannerPool(t *testing.T) {
+	banner := RandomCreateBanner(t)
+
+	var bannerItems []BannerItem
+	for i := 0; i < 3; i++ {
+		bannerItems = append(bannerItems, RandomCreateBannerItem(t, banner))
+	}
+
+	pool, err := testQueries.ListBannerPool(context.Background(), banner.ID)
+	require.NoError(t, err)
+	require.Len(t, pool, len(bannerItems))
+
+	for i, row := range pool {
+		require.Equal(t, bannerItems[i].ItemID, row.ItemID)
+		require.Equal(t, bannerItems[i].Weight, row.Weight)
+		require.Equal(t, int32(3), row.Rating)
+	}
+}
+
+func TestUpdateBannerItem(t *testing.T) {
+	banner := RandomCreateBanner(t)
+	bannerItem1 := RandomCreateBannerItem(t, banner)
+
+	arg := UpdateBannerItemParams{
+		BannerID: banner.ID,
+		ItemID: bannerItem1.ItemID,
+		Weight: bannerItem1.Weight + 1,
+		IsFeatured: true,
+	}
+
+	bannerItem2, err := testQueries.UpdateBannerItem(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, bannerItem1.ID, bannerItem2.ID)
+	require.Equal(t, arg.Weight, bannerItem2.Weight)
+	require.True(t, bannerItem2.IsFeatured)
+}
+
+func TestUpsertBannerRate(t *testing.T) {
+	banner := RandomCreateBanner(t)
+
+	arg := UpsertBannerRateParams{
+		BannerID: banner.ID,
+		Rating: 7,
+		Weight: 10,
+	}
+
+	rate1, err := testQueries.UpsertBannerRate(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Weight, rate1.Weight)
+
+	arg.Weight = 20
+	rate2, err := testQueries.UpsertBannerRate(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Weight, rate2.Weight)
+
+	rates, err := testQueries.ListBannerRates(context.Background(), banner.ID)
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+}
+
+func TestDeleteBanner(t *testing.T) {
+	banner1 := RandomCreateBanner(t)
+	RandomCreateBannerItem(t, banner1)
+
+	err := testQueries.DeleteBanner(context.Background(), banner1.ID)
+	require.NoError(t, err)
+
+	banner2, err := testQueries.GetBanner(context.Background(), banner1.ID)
+	require.Error(t, err)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+	require.Empty(t, banner2)
+
+	bannerItems, err := testQueries.ListBannerItems(context.Background(), banner1.ID)
+	require.NoError(t, err)
+	require.Empty(t, bannerItems)
+}
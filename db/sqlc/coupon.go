@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var ErrCouponInactive = errors.New("coupon is not active")
+var ErrCouponExhausted = errors.New("coupon has no redemptions left")
+var ErrCouponLimitReached = errors.New("coupon was already redeemed by the user")
+
+// CouponActive reports whether a coupon can be redeemed at now
+func CouponActive(coupon Coupon, now time.Time) bool {
+	return !now.Before(coupon.StartAt) && now.Before(coupon.EndAt)
+}
+
+// RedeemCouponTxParams contains the input parameters of the coupon redemption transaction
+type RedeemCouponTxParams struct {
+	AccountID int64     `json:"account_id"`
+	Owner     string    `json:"owner"`
+	Code      string    `json:"code"`
+	Now       time.Time `json:"now"`
+}
+
+type RedeemCouponTxResult struct {
+	Account    Account          `json:"account"`
+	Coupon     Coupon           `json:"coupon"`
+	Redemption CouponRedemption `json:"redemption"`
+	// Gallery is the copy of the item of the coupon, empty when it grants none
+	Gallery Gallery `json:"gallery"`
+}
+
+// RedeemCouponTx grants the bundle of a coupon to an account: the amount to
+// the free bucket through the ledger, a copy of the item and the pull tickets.
+// An unknown code fails with sql.ErrNoRows. The coupon row stays locked until
+// the transaction ends, so concurrent redemptions can't go past the global or
+// the per user limit.
+func (s *SQLStore) RedeemCouponTx(ctx context.Context, arg RedeemCouponTxParams) (RedeemCouponTxResult, error) {
+	var result RedeemCouponTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if account.Owner != arg.Owner {
+			return ErrAccountNotOwned
+		}
+		result.Account = account
+
+		coupon, err := q.GetCouponByCodeForUpdate(ctx, arg.Code)
+		if err != nil {
+			return err
+		}
+		if !CouponActive(coupon, arg.Now) {
+			return ErrCouponInactive
+		}
+		if coupon.MaxRedemptions.Valid && coupon.Redemptions >= coupon.MaxRedemptions.Int64 {
+			return ErrCouponExhausted
+		}
+
+		redeemed, err := q.CountCouponRedemptions(ctx, CountCouponRedemptionsParams{
+			CouponID: coupon.ID,
+			Owner:    account.Owner,
+		})
+		if err != nil {
+			return err
+		}
+		if redeemed >= int64(coupon.PerUserLimit) {
+			return ErrCouponLimitReached
+		}
+
+		result.Coupon, err = q.AddCouponRedemption(ctx, coupon.ID)
+		if err != nil {
+			return err
+		}
+
+		var galleryID sql.NullInt64
+		if coupon.ItemID.Valid {
+			result.Gallery, err = q.CreateGallery(ctx, CreateGalleryParams{
+				OwnerID: account.ID,
+				ItemID:  coupon.ItemID.Int64,
+			})
+			if err != nil {
+				return err
+			}
+			galleryID = sql.NullInt64{Int64: result.Gallery.ID, Valid: true}
+		}
+
+		result.Redemption, err = q.CreateCouponRedemption(ctx, CreateCouponRedemptionParams{
+			CouponID:  coupon.ID,
+			AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+			Owner:     account.Owner,
+			GalleryID: galleryID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if coupon.PullTickets > 0 {
+			result.Account, err = q.UpdatePullTickets(ctx, UpdatePullTicketsParams{
+				ID:          account.ID,
+				PullTickets: coupon.PullTickets,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if coupon.Amount == 0 {
+			return nil
+		}
+
+		posted, err := postLedger(ctx, q, postLedgerParams{
+			AccountID:   account.ID,
+			Free:        coupon.Amount,
+			Reason:      ReasonCoupon,
+			ReferenceID: ReferenceID(result.Redemption.ID),
+		})
+		result.Account = posted.Account
+		return err
+	})
+
+	return result, err
+}
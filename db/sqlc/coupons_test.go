@@ -0,0 +1,182 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/sRRRs-7/GachaPon/gacha"
+	"github.com/sRRRs-7/GachaPon/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func RandomCreateCoupon(t *testing.T, maxRedemptions int64, perUserLimit int32) Coupon {
+	item := RandomCreateItem(t)
+
+	arg := CreateCouponParams{
+		Code: utils.RandomString(12),
+		Amount: 300,
+		ItemID: sql.NullInt64{Int64: item.ID, Valid: true},
+		PullTickets: 2,
+		MaxRedemptions: sql.NullInt64{Int64: maxRedemptions, Valid: maxRedemptions > 0},
+		PerUserLimit: perUserLimit,
+		StartAt: time.Now().Add(-time.Hour),
+		EndAt: time.Now().Add(time.Hour),
+	}
+
+	coupon, err := testQueries.CreateCoupon(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, coupon)
+
+	require.Equal(t, arg.Code, coupon.Code)
+	require.Equal(t, arg.Amount, coupon.Amount)
+	require.Equal(t, arg.ItemID, coupon.ItemID)
+	require.Equal(t, arg.MaxRedemptions, coupon.MaxRedemptions)
+	require.Zero(t, coupon.Redemptions)
+
+	return coupon
+}
+
+func redeemCoupon(account Account, coupon Coupon) (RedeemCouponTxResult, error) {
+	return NewStore(testDB).RedeemCouponTx(context.Background(), RedeemCouponTxParams{
+		AccountID: account.ID,
+		Owner: account.Owner,
+		Code: coupon.Code,
+		Now: time.Now(),
+	})
+}
+
+func TestRedeemCouponTx(t *testing.T) {
+	coupon := RandomCreateCoupon(t, 0, 1)
+	account := createLedgerAccount(t, 0)
+
+	result, err := redeemCoupon(account, coupon)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), result.Coupon.Redemptions)
+	require.Equal(t, coupon.Amount, result.Account.FreeBalance)
+	require.Equal(t, coupon.PullTickets, result.Account.PullTickets)
+
+	require.Equal(t, account.ID, result.Gallery.OwnerID)
+	require.Equal(t, coupon.ItemID.Int64, result.Gallery.ItemID)
+	require.Equal(t, result.Gallery.ID, result.Redemption.GalleryID.Int64)
+	require.Equal(t, account.Owner, result.Redemption.Owner)
+	requireReconciled(t, account)
+
+	// the per user limit is spent
+	_, err = redeemCoupon(account, coupon)
+	require.ErrorIs(t, err, ErrCouponLimitReached)
+
+	account1, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Account.Balance, account1.Balance)
+}
+
+func TestRedeemCouponTxUnknownCode(t *testing.T) {
+	account := createLedgerAccount(t, 0)
+
+	_, err := redeemCoupon(account, Coupon{Code: utils.RandomString(12)})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestRedeemCouponTxInactive(t *testing.T) {
+	coupon := RandomCreateCoupon(t, 0, 1)
+	account := createLedgerAccount(t, 0)
+
+	_, err := testQueries.UpdateCoupon(context.Background(), UpdateCouponParams{
+		ID: coupon.ID,
+		Amount: coupon.Amount,
+		ItemID: coupon.ItemID,
+		PullTickets: coupon.PullTickets,
+		MaxRedemptions: coupon.MaxRedemptions,
+		PerUserLimit: coupon.PerUserLimit,
+		StartAt: time.Now().Add(-2 * time.Hour),
+		EndAt: time.Now().Add(-time.Hour),
+	})
+	require.NoError(t, err)
+
+	_, err = redeemCoupon(account, coupon)
+	require.ErrorIs(t, err, ErrCouponInactive)
+}
+
+func TestRedeemCouponTxExhausted(t *testing.T) {
+	coupon := RandomCreateCoupon(t, 1, 1)
+
+	_, err := redeemCoupon(createLedgerAccount(t, 0), coupon)
+	require.NoError(t, err)
+
+	_, err = redeemCoupon(createLedgerAccount(t, 0), coupon)
+	require.ErrorIs(t, err, ErrCouponExhausted)
+}
+
+func TestRedeemCouponTxConcurrent(t *testing.T) {
+	n := 10
+	limit := 3
+	coupon := RandomCreateCoupon(t, int64(limit), 2)
+
+	// half of the redemptions come twice from the same user
+	accounts := make([]Account, n/2)
+	for i := range accounts {
+		accounts[i] = createLedgerAccount(t, 0)
+	}
+
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		account := accounts[i%len(accounts)]
+		go func() {
+			_, err := redeemCoupon(account, coupon)
+			errs <- err
+		}()
+	}
+
+	redeemed := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			redeemed++
+			continue
+		}
+		require.ErrorIs(t, err, ErrCouponExhausted)
+	}
+	require.Equal(t, limit, redeemed)
+
+	coupon1, err := testQueries.GetCoupon(context.Background(), coupon.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(limit), coupon1.Redemptions)
+
+	for _, account := range accounts {
+		requireReconciled(t, account)
+	}
+}
+
+func TestGachaTxTickets(t *testing.T) {
+	store := NewStore(testDB)
+	coupon := RandomCreateCoupon(t, 0, 1)
+	account := createLedgerAccount(t, 0)
+	banner := RandomCreateBanner(t)
+	bannerItem := RandomCreateBannerItem(t, banner)
+
+	_, err := redeemCoupon(account, coupon)
+	require.NoError(t, err)
+
+	arg := GachaTxParams{
+		AccountID: account.ID,
+		BannerID: banner.ID,
+		Owner: account.Owner,
+		SpendOrder: SpendPaidOnly,
+		Tickets: coupon.PullTickets,
+		Pull: gacha.Pull{Count: int(coupon.PullTickets)},
+		Pool: randomBannerPool(t, bannerItem),
+	}
+
+	result, err := store.GachaTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, result.Gachas, 2)
+	require.Zero(t, result.Charge)
+	require.Zero(t, result.Account.PullTickets)
+	require.Equal(t, coupon.Amount, result.Account.Balance)
+	requireReconciled(t, account)
+
+	_, err = store.GachaTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInsufficientTickets)
+}
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var ErrNoLoginRewards = errors.New("login reward calendar is empty")
+
+// ClaimDate returns the calendar day of t in the time zone named tz,
+// as the midnight UTC of that day
+func ClaimDate(t time.Time, tz string) (time.Time, error) {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), nil
+}
+
+// StreakReward returns the reward of the calendar day a streak is on. The
+// calendar runs up to its last configured day and then starts over, and a day
+// left out of it grants nothing. rewards are sorted by day.
+func StreakReward(rewards []LoginReward, streak int32) LoginReward {
+	last := rewards[len(rewards)-1].Day
+	day := (streak-1)%last + 1
+
+	for _, reward := range rewards {
+		if reward.Day == day {
+			return reward
+		}
+	}
+	return LoginReward{Day: day}
+}
+
+// DailyClaimTxParams contains the input parameters of the daily claim transaction
+type DailyClaimTxParams struct {
+	AccountID int64  `json:"account_id"`
+	Owner     string `json:"owner"`
+	// Now is the time of the claim, read in the time zone of the owner
+	Now time.Time `json:"now"`
+}
+
+type DailyClaimTxResult struct {
+	Account Account    `json:"account"`
+	Claim   DailyClaim `json:"claim"`
+	// Claimed is false when the day was already claimed, Claim being the earlier claim
+	Claimed bool `json:"claimed"`
+}
+
+// DailyClaimTx grants the login reward of the day to an account.
+// Claims on consecutive days of the time zone of the owner grow the streak,
+// which walks the reward calendar and starts over after its last day, and a
+// missed day resets the streak to 1. Claiming a day twice returns the first
+// claim without granting anything.
+func (s *SQLStore) DailyClaimTx(ctx context.Context, arg DailyClaimTxParams) (DailyClaimTxResult, error) {
+	var result DailyClaimTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if account.Owner != arg.Owner {
+			return ErrAccountNotOwned
+		}
+		result.Account = account
+
+		user, err := q.GetUser(ctx, account.Owner)
+		if err != nil {
+			return err
+		}
+
+		today, err := ClaimDate(arg.Now, user.Timezone)
+		if err != nil {
+			return err
+		}
+
+		streak := int32(1)
+		last, err := q.GetLastDailyClaim(ctx, account.ID)
+		switch {
+		case err == nil:
+			// a later claim is left by a move to a time zone further east
+			if !last.ClaimDate.Before(today) {
+				result.Claim = last
+				return nil
+			}
+			if last.ClaimDate.Equal(today.AddDate(0, 0, -1)) {
+				streak = last.Streak + 1
+			}
+		case err != sql.ErrNoRows:
+			return err
+		}
+
+		rewards, err := q.ListLoginRewards(ctx)
+		if err != nil {
+			return err
+		}
+		if len(rewards) == 0 {
+			return ErrNoLoginRewards
+		}
+		reward := StreakReward(rewards, streak)
+
+		var galleryID sql.NullInt64
+		if reward.ItemID.Valid {
+			gallery, err := q.CreateGallery(ctx, CreateGalleryParams{
+				OwnerID: account.ID,
+				ItemID:  reward.ItemID.Int64,
+			})
+			if err != nil {
+				return err
+			}
+			galleryID = sql.NullInt64{Int64: gallery.ID, Valid: true}
+		}
+
+		result.Claim, err = q.CreateDailyClaim(ctx, CreateDailyClaimParams{
+			AccountID: account.ID,
+			ClaimDate: today,
+			Streak:    streak,
+			Amount:    reward.Amount,
+			ItemID:    reward.ItemID,
+			GalleryID: galleryID,
+		})
+		if err != nil {
+			return err
+		}
+		result.Claimed = true
+
+		if reward.Amount == 0 {
+			return nil
+		}
+
+		posted, err := postLedger(ctx, q, postLedgerParams{
+			AccountID:   account.ID,
+			Free:        reward.Amount,
+			Reason:      ReasonDailyLogin,
+			ReferenceID: ReferenceID(result.Claim.ID),
+		})
+		result.Account = posted.Account
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,186 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func claimDaily(t *testing.T, account Account, now time.Time) DailyClaimTxResult {
+	store := NewStore(testDB)
+
+	result, err := store.DailyClaimTx(context.Background(), DailyClaimTxParams{
+		AccountID: account.ID,
+		Owner:     account.Owner,
+		Now:       now,
+	})
+	require.NoError(t, err)
+	return result
+}
+
+func TestClaimDate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 16, 0, 0, 0, time.UTC)
+
+	day, err := ClaimDate(now, "UTC")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), day)
+
+	day, err = ClaimDate(now, "Asia/Tokyo")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), day)
+
+	_, err = ClaimDate(now, "Mars/Olympus")
+	require.Error(t, err)
+}
+
+func TestDailyClaimTxStreak(t *testing.T) {
+	account := createLedgerAccount(t, 0)
+	rewards, err := testQueries.ListLoginRewards(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, rewards)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var balance int64
+	for i := 0; i < len(rewards)+1; i++ {
+		result := claimDaily(t, account, now.AddDate(0, 0, i))
+		require.True(t, result.Claimed)
+		require.Equal(t, int32(i+1), result.Claim.Streak)
+
+		// the calendar starts over after its last day
+		reward := StreakReward(rewards, int32(i+1))
+		require.Equal(t, reward.Amount, result.Claim.Amount)
+		balance += reward.Amount
+		require.Equal(t, balance, result.Account.FreeBalance)
+	}
+
+	requireReconciled(t, account)
+}
+
+func TestStreakReward(t *testing.T) {
+	rewards := []LoginReward{
+		{Day: 1, Amount: 10},
+		{Day: 2, Amount: 20},
+		{Day: 5, Amount: 50},
+	}
+
+	require.Equal(t, rewards[1], StreakReward(rewards, 2))
+	require.Equal(t, rewards[2], StreakReward(rewards, 5))
+
+	// days left out of the calendar grant nothing
+	require.Equal(t, LoginReward{Day: 3}, StreakReward(rewards, 3))
+
+	// the calendar wraps on its last day, not on its number of rewards
+	require.Equal(t, rewards[0], StreakReward(rewards, 6))
+	require.Equal(t, LoginReward{Day: 4}, StreakReward(rewards, 9))
+}
+
+func TestDailyClaimTxSameDay(t *testing.T) {
+	account := createLedgerAccount(t, 0)
+	now := time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC)
+
+	first := claimDaily(t, account, now)
+	require.True(t, first.Claimed)
+
+	second := claimDaily(t, account, now.Add(23*time.Hour))
+	require.False(t, second.Claimed)
+	require.Equal(t, first.Claim.ID, second.Claim.ID)
+	require.Equal(t, first.Account.Balance, second.Account.Balance)
+
+	claims, err := testQueries.ListDailyClaims(context.Background(), ListDailyClaimsParams{
+		AccountID: account.ID,
+		Limit:     10,
+		Offset:    0,
+	})
+	require.NoError(t, err)
+	require.Len(t, claims, 1)
+
+	requireReconciled(t, account)
+}
+
+func TestDailyClaimTxMissedDay(t *testing.T) {
+	account := createLedgerAccount(t, 0)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	claimDaily(t, account, now)
+	result := claimDaily(t, account, now.AddDate(0, 0, 1))
+	require.Equal(t, int32(2), result.Claim.Streak)
+
+	result = claimDaily(t, account, now.AddDate(0, 0, 3))
+	require.True(t, result.Claimed)
+	require.Equal(t, int32(1), result.Claim.Streak)
+
+	requireReconciled(t, account)
+}
+
+func TestDailyClaimTxTimezone(t *testing.T) {
+	account := createLedgerAccount(t, 0)
+	_, err := testQueries.UpdateUserTimezone(context.Background(), UpdateUserTimezoneParams{
+		UserName: account.Owner,
+		Timezone: "Asia/Tokyo",
+	})
+	require.NoError(t, err)
+
+	// 2024-01-01 23:00 and 2024-01-02 08:00 in Tokyo
+	first := claimDaily(t, account, time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC))
+	require.True(t, first.Claimed)
+	require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), first.Claim.ClaimDate.UTC())
+
+	second := claimDaily(t, account, time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC))
+	require.True(t, second.Claimed)
+	require.Equal(t, int32(2), second.Claim.Streak)
+
+	// still 2024-01-02 in Tokyo
+	third := claimDaily(t, account, time.Date(2024, 1, 2, 14, 59, 0, 0, time.UTC))
+	require.False(t, third.Claimed)
+}
+
+func TestDailyClaimTxItem(t *testing.T) {
+	item := RandomCreateItem(t)
+	rewards, err := testQueries.ListLoginRewards(context.Background())
+	require.NoError(t, err)
+
+	// claims on the first day of a new streak grant the item while the test runs
+	first := rewards[0]
+	_, err = testQueries.UpsertLoginReward(context.Background(), UpsertLoginRewardParams{
+		Day:    first.Day,
+		Amount: first.Amount,
+		ItemID: sql.NullInt64{Int64: item.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	defer func() {
+		_, err := testQueries.UpsertLoginReward(context.Background(), UpsertLoginRewardParams{
+			Day:    first.Day,
+			Amount: first.Amount,
+			ItemID: first.ItemID,
+		})
+		require.NoError(t, err)
+	}()
+
+	account := createLedgerAccount(t, 0)
+	result := claimDaily(t, account, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	require.True(t, result.Claimed)
+	require.Equal(t, item.ID, result.Claim.ItemID.Int64)
+	require.True(t, result.Claim.GalleryID.Valid)
+
+	gallery, err := testQueries.GetGallery(context.Background(), result.Claim.GalleryID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, gallery.OwnerID)
+	require.Equal(t, item.ID, gallery.ItemID)
+
+	requireReconciled(t, account)
+}
+
+func TestDailyClaimTxNotOwned(t *testing.T) {
+	store := NewStore(testDB)
+	account := createLedgerAccount(t, 0)
+
+	_, err := store.DailyClaimTx(context.Background(), DailyClaimTxParams{
+		AccountID: account.ID,
+		Owner:     RandomCreateUser(t).UserName,
+		Now:       time.Now(),
+	})
+	require.ErrorIs(t, err, ErrAccountNotOwned)
+}
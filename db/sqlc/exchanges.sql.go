@@ -7,24 +7,31 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createExchange = `-- name: CreateExchange :one
 INSERT INTO exchanges (
-    from_account_id, to_account_id, item_id
+    from_account_id, to_account_id, item_id, gallery_id
 ) VALUES (
-    $1, $2, $3
-) RETURNING id, from_account_id, to_account_id, item_id, created_at
+    $1, $2, $3, $4
+) RETURNING id, from_account_id, to_account_id, item_id, created_at, gallery_id
 `
 
 type CreateExchangeParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	ItemID        int64 `json:"item_id"`
+	FromAccountID int64         `json:"from_account_id"`
+	ToAccountID   int64         `json:"to_account_id"`
+	ItemID        int64         `json:"item_id"`
+	GalleryID     sql.NullInt64 `json:"gallery_id"`
 }
 
 func (q *Queries) CreateExchange(ctx context.Context, arg CreateExchangeParams) (Exchange, error) {
-	row := q.db.QueryRowContext(ctx, createExchange, arg.FromAccountID, arg.ToAccountID, arg.ItemID)
+	row := q.db.QueryRowContext(ctx, createExchange,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.ItemID,
+		arg.GalleryID,
+	)
 	var i Exchange
 	err := row.Scan(
 		&i.ID,
@@ -32,12 +39,13 @@ func (q *Queries) CreateExchange(ctx context.Context, arg CreateExchangeParams)
 		&i.ToAccountID,
 		&i.ItemID,
 		&i.CreatedAt,
+		&i.GalleryID,
 	)
 	return i, err
 }
 
 const getExchange = `-- name: GetExchange :one
-SELECT id, from_account_id, to_account_id, item_id, created_at FROM exchanges
+SELECT id, from_account_id, to_account_id, item_id, created_at, gallery_id FROM exchanges
 WHERE id = $1 LIMIT 1
 `
 
@@ -50,12 +58,13 @@ func (q *Queries) GetExchange(ctx context.Context, id int64) (Exchange, error) {
 		&i.ToAccountID,
 		&i.ItemID,
 		&i.CreatedAt,
+		&i.GalleryID,
 	)
 	return i, err
 }
 
 const listExchangeFromAccount = `-- name: ListExchangeFromAccount :many
-SELECT id, from_account_id, to_account_id, item_id, created_at FROM exchanges
+SELECT id, from_account_id, to_account_id, item_id, created_at, gallery_id FROM exchanges
 WHERE from_account_id = $1
 ORDER BY id
 LIMIT $2
@@ -83,6 +92,7 @@ func (q *Queries) ListExchangeFromAccount(ctx context.Context, arg ListExchangeF
 			&i.ToAccountID,
 			&i.ItemID,
 			&i.CreatedAt,
+			&i.GalleryID,
 		); err != nil {
 			return nil, err
 		}
@@ -98,7 +108,7 @@ func (q *Queries) ListExchangeFromAccount(ctx context.Context, arg ListExchangeF
 }
 
 const listExchangeToAccount = `-- name: ListExchangeToAccount :many
-SELECT id, from_account_id, to_account_id, item_id, created_at FROM exchanges
+SELECT id, from_account_id, to_account_id, item_id, created_at, gallery_id FROM exchanges
 WHERE to_account_id = $1
 ORDER BY id
 LIMIT $2
@@ -126,6 +136,7 @@ func (q *Queries) ListExchangeToAccount(ctx context.Context, arg ListExchangeToA
 			&i.ToAccountID,
 			&i.ItemID,
 			&i.CreatedAt,
+			&i.GalleryID,
 		); err != nil {
 			return nil, err
 		}
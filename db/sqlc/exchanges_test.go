@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -20,11 +21,22 @@ func RandomExchange(t *testing.T) (e1, e2 Exchange) {
 	item_id2, err4 := testQueries.GetItem(context.Background(), 2)
 	require.NoError(t, err4)
 	require.NotEmpty(t, item_id2)
+	gallery1, err7 := testQueries.CreateGallery(context.Background(), CreateGalleryParams{
+		OwnerID: from_account.ID,
+		ItemID: item_id1.ID,
+	})
+	require.NoError(t, err7)
+	gallery2, err8 := testQueries.CreateGallery(context.Background(), CreateGalleryParams{
+		OwnerID: to_account.ID,
+		ItemID: item_id2.ID,
+	})
+	require.NoError(t, err8)
 
 	arg1 := CreateExchangeParams{
 		FromAccountID: from_account.ID,
 		ToAccountID: to_account.ID,
 		ItemID: item_id1.ID,
+		GalleryID: sql.NullInt64{Int64: gallery1.ID, Valid: true},
 	}
 
 	e1, err5 := testQueries.CreateExchange(context.Background(), arg1)
@@ -34,12 +46,14 @@ func RandomExchange(t *testing.T) (e1, e2 Exchange) {
 	require.Equal(t, arg1.FromAccountID, e1.FromAccountID)
 	require.Equal(t, arg1.ToAccountID, e1.ToAccountID)
 	require.Equal(t, arg1.ItemID, e1.ItemID)
+	require.Equal(t, arg1.GalleryID, e1.GalleryID)
 	require.NotZero(t, e1.CreatedAt)
 
 	arg2 := CreateExchangeParams{
 		FromAccountID: to_account.ID,
 		ToAccountID: from_account.ID,
 		ItemID: item_id2.ID,
+		GalleryID: sql.NullInt64{Int64: gallery2.ID, Valid: true},
 	}
 
 	e2, err6 := testQueries.CreateExchange(context.Background(), arg2)
@@ -49,6 +63,7 @@ func RandomExchange(t *testing.T) (e1, e2 Exchange) {
 	require.Equal(t, arg2.FromAccountID, e2.FromAccountID)
 	require.Equal(t, arg2.ToAccountID, e2.ToAccountID)
 	require.Equal(t, arg2.ItemID, e2.ItemID)
+	require.Equal(t, arg2.GalleryID, e2.GalleryID)
 	require.NotZero(t, e2.CreatedAt)
 
 	return e1, e2
@@ -7,35 +7,72 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createGacha = `-- name: CreateGacha :one
 INSERT INTO gachas (
-    account_id, item_id
+    account_id, item_id, seed_id, nonce, roll, gallery_id, paid_amount, free_amount,
+    banner_id, pool_digest, pity_pulls, min_rating
 ) VALUES (
-    $1, $2
-) RETURNING id, account_id, item_id, created_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) RETURNING id, account_id, item_id, created_at, seed_id, nonce, roll, gallery_id, paid_amount, free_amount, revoked_at, banner_id, pool_digest, pity_pulls, min_rating
 `
 
 type CreateGachaParams struct {
-	AccountID int64 `json:"account_id"`
-	ItemID    int64 `json:"item_id"`
+	AccountID  int64         `json:"account_id"`
+	ItemID     int64         `json:"item_id"`
+	SeedID     sql.NullInt64 `json:"seed_id"`
+	Nonce      int64         `json:"nonce"`
+	Roll       float64       `json:"roll"`
+	GalleryID  sql.NullInt64 `json:"gallery_id"`
+	PaidAmount int64         `json:"paid_amount"`
+	FreeAmount int64         `json:"free_amount"`
+	BannerID   sql.NullInt64 `json:"banner_id"`
+	PoolDigest string        `json:"pool_digest"`
+	PityPulls  int32         `json:"pity_pulls"`
+	MinRating  int32         `json:"min_rating"`
 }
 
 func (q *Queries) CreateGacha(ctx context.Context, arg CreateGachaParams) (Gacha, error) {
-	row := q.db.QueryRowContext(ctx, createGacha, arg.AccountID, arg.ItemID)
+	row := q.db.QueryRowContext(ctx, createGacha,
+		arg.AccountID,
+		arg.ItemID,
+		arg.SeedID,
+		arg.Nonce,
+		arg.Roll,
+		arg.GalleryID,
+		arg.PaidAmount,
+		arg.FreeAmount,
+		arg.BannerID,
+		arg.PoolDigest,
+		arg.PityPulls,
+		arg.MinRating,
+	)
 	var i Gacha
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.ItemID,
 		&i.CreatedAt,
+		&i.SeedID,
+		&i.Nonce,
+		&i.Roll,
+		&i.GalleryID,
+		&i.PaidAmount,
+		&i.FreeAmount,
+		&i.RevokedAt,
+		&i.BannerID,
+		&i.PoolDigest,
+		&i.PityPulls,
+		&i.MinRating,
 	)
 	return i, err
 }
 
 const getGacha = `-- name: GetGacha :one
-SELECT id, account_id, item_id, created_at FROM gachas
+SELECT id, account_id, item_id, created_at, seed_id, nonce, roll, gallery_id, paid_amount, free_amount, revoked_at, banner_id, pool_digest, pity_pulls, min_rating FROM gachas
 WHERE id = $1
 LIMIT 1
 `
@@ -48,12 +85,40 @@ func (q *Queries) GetGacha(ctx context.Context, id int64) (Gacha, error) {
 		&i.AccountID,
 		&i.ItemID,
 		&i.CreatedAt,
+		&i.SeedID,
+		&i.Nonce,
+		&i.Roll,
+		&i.GalleryID,
+		&i.PaidAmount,
+		&i.FreeAmount,
+		&i.RevokedAt,
+		&i.BannerID,
+		&i.PoolDigest,
+		&i.PityPulls,
+		&i.MinRating,
 	)
 	return i, err
 }
 
+const getMonthlyPaidSpend = `-- name: GetMonthlyPaidSpend :one
+SELECT COALESCE(sum(paid_amount), 0)::bigint AS spent FROM gachas
+WHERE account_id = $1 AND created_at >= $2
+`
+
+type GetMonthlyPaidSpendParams struct {
+	AccountID int64     `json:"account_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) GetMonthlyPaidSpend(ctx context.Context, arg GetMonthlyPaidSpendParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getMonthlyPaidSpend, arg.AccountID, arg.CreatedAt)
+	var spent int64
+	err := row.Scan(&spent)
+	return spent, err
+}
+
 const listGachas = `-- name: ListGachas :many
-SELECT id, account_id, item_id, created_at FROM gachas
+SELECT id, account_id, item_id, created_at, seed_id, nonce, roll, gallery_id, paid_amount, free_amount, revoked_at, banner_id, pool_digest, pity_pulls, min_rating FROM gachas
 ORDER BY id ASC
 LIMIT $1
 OFFSET $2
@@ -78,6 +143,68 @@ func (q *Queries) ListGachas(ctx context.Context, arg ListGachasParams) ([]Gacha
 			&i.AccountID,
 			&i.ItemID,
 			&i.CreatedAt,
+			&i.SeedID,
+			&i.Nonce,
+			&i.Roll,
+			&i.GalleryID,
+			&i.PaidAmount,
+			&i.FreeAmount,
+			&i.RevokedAt,
+			&i.BannerID,
+			&i.PoolDigest,
+			&i.PityPulls,
+			&i.MinRating,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRevocableGachas = `-- name: ListRevocableGachas :many
+SELECT id, account_id, item_id, created_at, seed_id, nonce, roll, gallery_id, paid_amount, free_amount, revoked_at, banner_id, pool_digest, pity_pulls, min_rating FROM gachas
+WHERE account_id = $1 AND created_at >= $2
+    AND paid_amount > 0 AND revoked_at IS NULL
+ORDER BY id DESC
+`
+
+type ListRevocableGachasParams struct {
+	AccountID int64     `json:"account_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) ListRevocableGachas(ctx context.Context, arg ListRevocableGachasParams) ([]Gacha, error) {
+	rows, err := q.db.QueryContext(ctx, listRevocableGachas, arg.AccountID, arg.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Gacha{}
+	for rows.Next() {
+		var i Gacha
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.ItemID,
+			&i.CreatedAt,
+			&i.SeedID,
+			&i.Nonce,
+			&i.Roll,
+			&i.GalleryID,
+			&i.PaidAmount,
+			&i.FreeAmount,
+			&i.RevokedAt,
+			&i.BannerID,
+			&i.PoolDigest,
+			&i.PityPulls,
+			&i.MinRating,
 		); err != nil {
 			return nil, err
 		}
@@ -91,3 +218,33 @@ func (q *Queries) ListGachas(ctx context.Context, arg ListGachasParams) ([]Gacha
 	}
 	return items, nil
 }
+
+const revokeGacha = `-- name: RevokeGacha :one
+UPDATE gachas
+SET revoked_at = now()
+WHERE id = $1
+RETURNING id, account_id, item_id, created_at, seed_id, nonce, roll, gallery_id, paid_amount, free_amount, revoked_at, banner_id, pool_digest, pity_pulls, min_rating
+`
+
+func (q *Queries) RevokeGacha(ctx context.Context, id int64) (Gacha, error) {
+	row := q.db.QueryRowContext(ctx, revokeGacha, id)
+	var i Gacha
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ItemID,
+		&i.CreatedAt,
+		&i.SeedID,
+		&i.Nonce,
+		&i.Roll,
+		&i.GalleryID,
+		&i.PaidAmount,
+		&i.FreeAmount,
+		&i.RevokedAt,
+		&i.BannerID,
+		&i.PoolDigest,
+		&i.PityPulls,
+		&i.MinRating,
+	)
+	return i, err
+}
@@ -36,6 +36,30 @@ func (q *Queries) CreateGallery(ctx context.Context, arg CreateGalleryParams) (G
 	return i, err
 }
 
+const deleteGallery = `-- name: DeleteGallery :one
+DELETE FROM galleries
+WHERE id = $1 AND owner_id = $2
+RETURNING id, owner_id, item_id, exchange_at, created_at
+`
+
+type DeleteGalleryParams struct {
+	ID      int64 `json:"id"`
+	OwnerID int64 `json:"owner_id"`
+}
+
+func (q *Queries) DeleteGallery(ctx context.Context, arg DeleteGalleryParams) (Gallery, error) {
+	row := q.db.QueryRowContext(ctx, deleteGallery, arg.ID, arg.OwnerID)
+	var i Gallery
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.ItemID,
+		&i.ExchangeAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getGallery = `-- name: GetGallery :one
 SELECT id, owner_id, item_id, exchange_at, created_at FROM galleries
 WHERE id = $1 LIMIT 1
@@ -54,6 +78,44 @@ func (q *Queries) GetGallery(ctx context.Context, id int64) (Gallery, error) {
 	return i, err
 }
 
+const getGalleryForUpdate = `-- name: GetGalleryForUpdate :one
+SELECT id, owner_id, item_id, exchange_at, created_at FROM galleries
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetGalleryForUpdate(ctx context.Context, id int64) (Gallery, error) {
+	row := q.db.QueryRowContext(ctx, getGalleryForUpdate, id)
+	var i Gallery
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.ItemID,
+		&i.ExchangeAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getGalleryForUpdateNowait = `-- name: GetGalleryForUpdateNowait :one
+SELECT id, owner_id, item_id, exchange_at, created_at FROM galleries
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE NOWAIT
+`
+
+func (q *Queries) GetGalleryForUpdateNowait(ctx context.Context, id int64) (Gallery, error) {
+	row := q.db.QueryRowContext(ctx, getGalleryForUpdateNowait, id)
+	var i Gallery
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.ItemID,
+		&i.ExchangeAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const listGalleriesById = `-- name: ListGalleriesById :many
 SELECT id, owner_id, item_id, exchange_at, created_at FROM galleries
 WHERE owner_id = $1
@@ -140,24 +202,67 @@ func (q *Queries) ListGalleriesByItemId(ctx context.Context, arg ListGalleriesBy
 	return items, nil
 }
 
+const listGalleryQuantities = `-- name: ListGalleryQuantities :many
+SELECT item_id, count(*) AS quantity FROM galleries
+WHERE owner_id = $1
+GROUP BY item_id
+ORDER BY item_id ASC
+LIMIT $2
+OFFSET $3
+`
+
+type ListGalleryQuantitiesParams struct {
+	OwnerID int64 `json:"owner_id"`
+	Limit   int32 `json:"limit"`
+	Offset  int32 `json:"offset"`
+}
+
+type ListGalleryQuantitiesRow struct {
+	ItemID   int64 `json:"item_id"`
+	Quantity int64 `json:"quantity"`
+}
+
+func (q *Queries) ListGalleryQuantities(ctx context.Context, arg ListGalleryQuantitiesParams) ([]ListGalleryQuantitiesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listGalleryQuantities, arg.OwnerID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListGalleryQuantitiesRow{}
+	for rows.Next() {
+		var i ListGalleryQuantitiesRow
+		if err := rows.Scan(&i.ItemID, &i.Quantity); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateGallery = `-- name: UpdateGallery :one
 UPDATE galleries
 SET owner_id = $3, exchange_at = $4
-WHERE owner_id = $1 AND item_id = $2
+WHERE id = $1 AND owner_id = $2
 RETURNING id, owner_id, item_id, exchange_at, created_at
 `
 
 type UpdateGalleryParams struct {
+	ID         int64     `json:"id"`
 	OwnerID    int64     `json:"owner_id"`
-	ItemID     int64     `json:"item_id"`
 	OwnerID_2  int64     `json:"owner_id_2"`
 	ExchangeAt time.Time `json:"exchange_at"`
 }
 
 func (q *Queries) UpdateGallery(ctx context.Context, arg UpdateGalleryParams) (Gallery, error) {
 	row := q.db.QueryRowContext(ctx, updateGallery,
+		arg.ID,
 		arg.OwnerID,
-		arg.ItemID,
 		arg.OwnerID_2,
 		arg.ExchangeAt,
 	)
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
@@ -49,6 +50,50 @@ func TestGetGallery(t *testing.T) {
 	require.WithinDuration(t, gallery1.CreatedAt, gallery2.CreatedAt, time.Second)
 }
 
+func TestUpdateGalleryNotOwned(t *testing.T) {
+	gallery1 := RandomCreateGallery(t)
+
+	arg := UpdateGalleryParams{
+		ID: gallery1.ID,
+		OwnerID: 2,
+		OwnerID_2: 1,
+		ExchangeAt: time.Now(),
+	}
+
+	_, err := testQueries.UpdateGallery(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	gallery2, err := testQueries.GetGallery(context.Background(), gallery1.ID)
+	require.NoError(t, err)
+	require.Equal(t, gallery1.OwnerID, gallery2.OwnerID)
+}
+
+func TestListGalleryQuantities(t *testing.T) {
+	account := RandomCreateAccount(t)
+	item := RandomCreateItem(t)
+
+	// the same item can be held several times
+	for i := 0; i < 3; i++ {
+		_, err := testQueries.CreateGallery(context.Background(), CreateGalleryParams{
+			OwnerID: account.ID,
+			ItemID: item.ID,
+		})
+		require.NoError(t, err)
+	}
+
+	arg := ListGalleryQuantitiesParams{
+		OwnerID: account.ID,
+		Limit: 5,
+		Offset: 0,
+	}
+
+	quantities, err := testQueries.ListGalleryQuantities(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, quantities, 1)
+	require.Equal(t, item.ID, quantities[0].ItemID)
+	require.Equal(t, int64(3), quantities[0].Quantity)
+}
+
 func TestListGalleriesById(t *testing.T) {
 	var gallery Gallery
 	for i := 0; i < 5; i++ {
@@ -105,8 +150,8 @@ func TestUpdateGallery(t *testing.T) {
 	gallery1 := RandomCreateGallery(t)
 
 	arg := UpdateGalleryParams{
-		OwnerID: gallery1.ID,
-		ItemID: gallery1.ItemID,
+		ID: gallery1.ID,
+		OwnerID: gallery1.OwnerID,
 		OwnerID_2: 2,
 		ExchangeAt: gallery1.ExchangeAt,
 	}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sRRRs-7/GachaPon/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	user := RandomCreateUser(t)
+
+	arg := CreateIdempotencyKeyParams{
+		Username: user.UserName,
+		IdempotencyKey: utils.RandomString(16),
+		RequestHash: utils.RandomString(32),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	key1, err := testQueries.CreateIdempotencyKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.RequestHash, key1.RequestHash)
+	require.Zero(t, key1.ResponseStatus)
+
+	// a used key is not inserted again
+	_, err = testQueries.CreateIdempotencyKey(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	key2, err := testQueries.UpdateIdempotencyKeyResponse(context.Background(), UpdateIdempotencyKeyResponseParams{
+		Username: arg.Username,
+		IdempotencyKey: arg.IdempotencyKey,
+		ResponseStatus: http.StatusOK,
+		ResponseBody: []byte(`{"id":1}`),
+		ContentType: "application/json",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(http.StatusOK), key2.ResponseStatus)
+
+	key3, err := testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		Username: arg.Username,
+		IdempotencyKey: arg.IdempotencyKey,
+	})
+	require.NoError(t, err)
+	require.Equal(t, key2.ResponseBody, key3.ResponseBody)
+
+	err = testQueries.DeleteIdempotencyKey(context.Background(), DeleteIdempotencyKeyParams{
+		Username: arg.Username,
+		IdempotencyKey: arg.IdempotencyKey,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateIdempotencyKey(context.Background(), arg)
+	require.NoError(t, err)
+}
+
+func TestIdempotencyKeyExpiry(t *testing.T) {
+	user := RandomCreateUser(t)
+
+	arg := CreateIdempotencyKeyParams{
+		Username: user.UserName,
+		IdempotencyKey: utils.RandomString(16),
+		RequestHash: utils.RandomString(32),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}
+
+	_, err := testQueries.CreateIdempotencyKey(context.Background(), arg)
+	require.NoError(t, err)
+
+	// an expired key is taken over by the next request using it
+	arg.RequestHash = utils.RandomString(32)
+	arg.ExpiresAt = time.Now().Add(time.Hour)
+	key, err := testQueries.CreateIdempotencyKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.RequestHash, key.RequestHash)
+	require.WithinDuration(t, arg.ExpiresAt, key.ExpiresAt, time.Second)
+
+	// the sweep deletes the keys past their expiry only
+	deleted, err := testQueries.DeleteExpiredIdempotencyKeys(context.Background(), time.Now())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, deleted, int64(0))
+
+	_, err = testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		Username: arg.Username,
+		IdempotencyKey: arg.IdempotencyKey,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.DeleteExpiredIdempotencyKeys(context.Background(), arg.ExpiresAt.Add(time.Second))
+	require.NoError(t, err)
+
+	_, err = testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		Username: arg.Username,
+		IdempotencyKey: arg.IdempotencyKey,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
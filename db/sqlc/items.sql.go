@@ -284,6 +284,39 @@ func (q *Queries) ListItemsByRating(ctx context.Context, arg ListItemsByRatingPa
 	return items, nil
 }
 
+const listRotationCandidates = `-- name: ListRotationCandidates :many
+SELECT id, rating FROM items
+ORDER BY id
+`
+
+type ListRotationCandidatesRow struct {
+	ID     int64 `json:"id"`
+	Rating int32 `json:"rating"`
+}
+
+func (q *Queries) ListRotationCandidates(ctx context.Context) ([]ListRotationCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRotationCandidates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRotationCandidatesRow{}
+	for rows.Next() {
+		var i ListRotationCandidatesRow
+		if err := rows.Scan(&i.ID, &i.Rating); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateItem = `-- name: UpdateItem :one
 UPDATE items
 SET item_name = $2, rating = $3, item_url = $4, category_id = $5
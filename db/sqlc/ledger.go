@@ -0,0 +1,315 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+)
+
+// reasons of the ledger journals
+const (
+	ReasonOpeningBalance  = "opening_balance"
+	ReasonGachaPull       = "gacha_pull"
+	ReasonPurchase        = "purchase"
+	ReasonRefund          = "refund"
+	ReasonAdminGrant      = "admin_grant"
+	ReasonAdminAdjustment = "admin_adjustment"
+	ReasonDailyLogin      = "daily_login"
+	ReasonCoupon          = "coupon"
+	ReasonChargeback      = "chargeback"
+	ReasonShopPurchase    = "shop_purchase"
+	ReasonPassPurchase    = "pass_purchase"
+	ReasonPassClaim       = "pass_claim"
+	// ReasonTrade moves the free currency added to a trade between its two accounts
+	ReasonTrade = "trade"
+	// ReasonMarketPurchase takes the price of a market listing from its buyer,
+	// ReasonMarketSale gives it to the seller less the fee
+	ReasonMarketPurchase = "market_purchase"
+	ReasonMarketSale     = "market_sale"
+	// ReasonRevocation gives back the cost of pulls whose copies a reversal took back
+	ReasonRevocation = "revocation"
+	// ReasonBucketTransfer moves currency between the buckets of an account,
+	// it was only posted when the balances were split into paid and free
+	ReasonBucketTransfer = "bucket_transfer"
+)
+
+// books of the ledger entries
+const (
+	// BookAccounts holds the balances of the player accounts
+	BookAccounts = "accounts"
+	BookEquity   = "equity"
+	BookRevenue  = "revenue"
+	BookPayments = "payments"
+	BookGrants   = "grants"
+	// BookTrades passes the currency of a trade from one account to the other,
+	// its balance is back to 0 after every trade
+	BookTrades = "trades"
+	// BookMarket passes the price of a listing from its buyer to its seller,
+	// keeping the fees of the sales
+	BookMarket = "market"
+)
+
+var ErrUnknownReason = errors.New("unknown ledger reason")
+
+// reasonBooks is the system book every reason moves currency to or from
+var reasonBooks = map[string]string{
+	ReasonOpeningBalance:  BookEquity,
+	ReasonGachaPull:       BookRevenue,
+	ReasonPurchase:        BookPayments,
+	ReasonRefund:          BookPayments,
+	ReasonAdminGrant:      BookGrants,
+	ReasonAdminAdjustment: BookGrants,
+	ReasonDailyLogin:      BookGrants,
+	ReasonCoupon:          BookGrants,
+	ReasonChargeback:      BookPayments,
+	ReasonRevocation:      BookRevenue,
+	ReasonShopPurchase:    BookRevenue,
+	ReasonPassPurchase:    BookRevenue,
+	ReasonPassClaim:       BookGrants,
+	ReasonTrade:           BookTrades,
+	ReasonMarketPurchase:  BookMarket,
+	ReasonMarketSale:      BookMarket,
+}
+
+// reasonBuckets is the bucket of the account a reason moves currency in,
+// pulls and purchases of shop offers and passes spend both buckets in a spend order
+var reasonBuckets = map[string]string{
+	ReasonOpeningBalance:  BucketFree,
+	ReasonPurchase:        BucketPaid,
+	ReasonRefund:          BucketPaid,
+	ReasonAdminGrant:      BucketFree,
+	ReasonAdminAdjustment: BucketFree,
+	ReasonDailyLogin:      BucketFree,
+	ReasonCoupon:          BucketFree,
+	ReasonChargeback:      BucketPaid,
+	ReasonPassClaim:       BucketFree,
+}
+
+// ReferenceID formats the id of the row a journal refers to
+func ReferenceID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+type postLedgerParams struct {
+	AccountID int64
+	// Paid and Free are the amounts moved in each bucket of the account
+	Paid        int64
+	Free        int64
+	Reason      string
+	ReferenceID string
+}
+
+type postLedgerResult struct {
+	Account Account
+	Journal LedgerJournal
+	Entries []LedgerEntry
+}
+
+// postLedger moves the paid and free amounts into the account from the system
+// book of the reason, negative amounts move them out of the account.
+// It writes a journal with an entry for every bucket moved and one for the
+// system book, and applies the amounts to the cached balances of the account,
+// so it must run inside a transaction. A credit bringing the paid bucket of a
+// flagged account back to 0 settles its debt and lifts the flag.
+func postLedger(ctx context.Context, q *Queries, arg postLedgerParams) (postLedgerResult, error) {
+	var result postLedgerResult
+
+	book, ok := reasonBooks[arg.Reason]
+	if !ok {
+		return result, ErrUnknownReason
+	}
+
+	var err error
+	result.Journal, err = q.CreateLedgerJournal(ctx, CreateLedgerJournalParams{
+		Reason:      arg.Reason,
+		ReferenceID: arg.ReferenceID,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	var legs []CreateLedgerEntryParams
+	for _, bucket := range []struct {
+		name   string
+		amount int64
+	}{{BucketPaid, arg.Paid}, {BucketFree, arg.Free}} {
+		if bucket.amount == 0 {
+			continue
+		}
+		legs = append(legs, CreateLedgerEntryParams{
+			JournalID: result.Journal.ID,
+			AccountID: sql.NullInt64{Int64: arg.AccountID, Valid: true},
+			Book:      BookAccounts,
+			Bucket:    bucket.name,
+			Amount:    bucket.amount,
+		})
+	}
+	legs = append(legs, CreateLedgerEntryParams{
+		JournalID: result.Journal.ID,
+		Book:      book,
+		Amount:    -(arg.Paid + arg.Free),
+	})
+	for _, leg := range legs {
+		entry, err := q.CreateLedgerEntry(ctx, leg)
+		if err != nil {
+			return result, err
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+
+	result.Account, err = q.UpdateBalance(ctx, UpdateBalanceParams{
+		ID:          arg.AccountID,
+		PaidBalance: arg.Paid,
+		FreeBalance: arg.Free,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if result.Account.FlaggedAt.Valid && result.Account.PaidBalance >= 0 {
+		result.Account, err = q.UpdateAccountFlag(ctx, UpdateAccountFlagParams{
+			ID: arg.AccountID,
+		})
+	}
+	return result, err
+}
+
+// LedgerTxParams contains the input parameters of the ledger transaction
+type LedgerTxParams struct {
+	AccountID   int64  `json:"account_id"`
+	Amount      int64  `json:"amount"`
+	Reason      string `json:"reason"`
+	ReferenceID string `json:"reference_id"`
+	// SetBalance turns Amount into the target balance of the account,
+	// the difference to the current balance being posted to the free bucket
+	SetBalance bool `json:"set_balance"`
+}
+
+type LedgerTxResult struct {
+	Account Account       `json:"account"`
+	Journal LedgerJournal `json:"journal"`
+	Entries []LedgerEntry `json:"entries"`
+}
+
+// LedgerTx posts a single credit or debit to the bucket the reason moves.
+// A debit larger than the balance of the bucket fails with ErrInsufficientBalance.
+func (s *SQLStore) LedgerTx(ctx context.Context, arg LedgerTxParams) (LedgerTxResult, error) {
+	var result LedgerTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		bucket, ok := reasonBuckets[arg.Reason]
+		if !ok {
+			return ErrUnknownReason
+		}
+
+		amount := arg.Amount
+		if arg.SetBalance {
+			bucket = BucketFree
+			amount = arg.Amount - account.Balance
+		}
+
+		params := postLedgerParams{
+			AccountID:   account.ID,
+			Reason:      arg.Reason,
+			ReferenceID: arg.ReferenceID,
+		}
+		balance := account.FreeBalance
+		if bucket == BucketPaid {
+			balance = account.PaidBalance
+			params.Paid = amount
+		} else {
+			params.Free = amount
+		}
+		if balance+amount < 0 {
+			return ErrInsufficientBalance
+		}
+
+		posted, err := postLedger(ctx, q, params)
+		if err != nil {
+			return err
+		}
+
+		result = LedgerTxResult(posted)
+		return nil
+	})
+
+	return result, err
+}
+
+// CreateAccountTx opens an account, posting its initial free balance to the ledger
+func (s *SQLStore) CreateAccountTx(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	var result Account
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.CreateAccount(ctx, CreateAccountParams{
+			Owner:       arg.Owner,
+			FreeBalance: 0,
+		})
+		if err != nil || arg.FreeBalance == 0 {
+			return err
+		}
+
+		posted, err := postLedger(ctx, q, postLedgerParams{
+			AccountID:   result.ID,
+			Free:        arg.FreeBalance,
+			Reason:      ReasonOpeningBalance,
+			ReferenceID: ReferenceID(result.ID),
+		})
+		result = posted.Account
+		return err
+	})
+
+	return result, err
+}
+
+type ReconcileResult struct {
+	Account           Account `json:"account"`
+	LedgerBalance     int64   `json:"ledger_balance"`
+	LedgerPaidBalance int64   `json:"ledger_paid_balance"`
+	// Difference is the cached balance minus the ledger balance, 0 when both agree
+	Difference int64 `json:"difference"`
+	// PaidDifference is the same for the paid bucket, the free bucket being the rest
+	PaidDifference int64 `json:"paid_difference"`
+}
+
+// ReconcileAccount compares the cached balances of an account with the sums of its ledger entries
+func (s *SQLStore) ReconcileAccount(ctx context.Context, accountID int64) (ReconcileResult, error) {
+	var result ReconcileResult
+
+	// read both sides in one snapshot, so a concurrent posting can't show up on one side only
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	q := New(tx)
+	result.Account, err = q.GetAccount(ctx, accountID)
+	if err != nil {
+		return result, err
+	}
+
+	result.LedgerBalance, err = q.GetLedgerBalance(ctx, sql.NullInt64{Int64: accountID, Valid: true})
+	if err != nil {
+		return result, err
+	}
+
+	result.LedgerPaidBalance, err = q.GetLedgerBucketBalance(ctx, GetLedgerBucketBalanceParams{
+		AccountID: sql.NullInt64{Int64: accountID, Valid: true},
+		Bucket:    BucketPaid,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Difference = result.Account.Balance - result.LedgerBalance
+	result.PaidDifference = result.Account.PaidBalance - result.LedgerPaidBalance
+	return result, tx.Commit()
+}
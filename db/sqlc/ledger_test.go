@@ -0,0 +1,212 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/sRRRs-7/GachaPon/gacha"
+	"github.com/sRRRs-7/GachaPon/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// createLedgerAccount opens an account whose balance is backed by the ledger
+func createLedgerAccount(t *testing.T, balance int64) Account {
+	store := NewStore(testDB)
+
+	user := RandomCreateUser(t)
+	account, err := store.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner: user.UserName,
+		FreeBalance: balance,
+	})
+	require.NoError(t, err)
+	require.Equal(t, balance, account.Balance)
+	return account
+}
+
+func requireReconciled(t *testing.T, account Account) {
+	store := NewStore(testDB)
+
+	result, err := store.ReconcileAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Zero(t, result.Difference)
+	require.Equal(t, result.Account.Balance, result.LedgerBalance)
+	require.Zero(t, result.PaidDifference)
+	require.Equal(t, result.Account.PaidBalance, result.LedgerPaidBalance)
+}
+
+func TestCreateAccountTx(t *testing.T) {
+	account := createLedgerAccount(t, utils.RandomInt(1, 1000))
+	requireReconciled(t, account)
+}
+
+func TestLedgerTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createLedgerAccount(t, 100)
+
+	arg := LedgerTxParams{
+		AccountID: account.ID,
+		Amount: 50,
+		Reason: ReasonAdminGrant,
+		ReferenceID: "ticket-1",
+	}
+
+	result, err := store.LedgerTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(150), result.Account.Balance)
+	require.Equal(t, ReasonAdminGrant, result.Journal.Reason)
+	require.Equal(t, arg.ReferenceID, result.Journal.ReferenceID)
+
+	// the entries of a journal are balanced
+	entries, err := testQueries.ListLedgerEntriesByJournal(context.Background(), result.Journal.ID)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	var sum int64
+	for _, entry := range entries {
+		sum += entry.Amount
+	}
+	require.Zero(t, sum)
+	require.Equal(t, BookAccounts, entries[0].Book)
+	require.Equal(t, BucketFree, entries[0].Bucket)
+	require.Equal(t, BookGrants, entries[1].Book)
+	require.Empty(t, entries[1].Bucket)
+	require.False(t, entries[1].AccountID.Valid)
+
+	requireReconciled(t, account)
+}
+
+func TestLedgerTxSetBalance(t *testing.T) {
+	store := NewStore(testDB)
+	account := createLedgerAccount(t, 100)
+
+	arg := LedgerTxParams{
+		AccountID: account.ID,
+		Amount: 30,
+		Reason: ReasonAdminAdjustment,
+		SetBalance: true,
+	}
+
+	result, err := store.LedgerTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(30), result.Account.Balance)
+	require.Equal(t, int64(-70), result.Entries[0].Amount)
+
+	requireReconciled(t, account)
+}
+
+func TestLedgerTxInsufficientBalance(t *testing.T) {
+	store := NewStore(testDB)
+	account := createLedgerAccount(t, 100)
+
+	arg := LedgerTxParams{
+		AccountID: account.ID,
+		Amount: -101,
+		Reason: ReasonRefund,
+	}
+
+	_, err := store.LedgerTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+
+	arg.Amount = 10
+	arg.Reason = "unknown"
+	_, err = store.LedgerTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrUnknownReason)
+
+	requireReconciled(t, account)
+}
+
+func TestGachaTxLedger(t *testing.T) {
+	store := NewStore(testDB)
+	account := createLedgerAccount(t, 100)
+	banner := RandomCreateBanner(t)
+	bannerItem := RandomCreateBannerItem(t, banner)
+
+	arg := GachaTxParams{
+		AccountID: account.ID,
+		BannerID: banner.ID,
+		Owner: account.Owner,
+		Cost: 30,
+		SpendOrder: SpendFreeFirst,
+		Pull: gacha.Pull{Count: 1},
+		Pool: randomBannerPool(t, bannerItem),
+	}
+
+	result, err := store.GachaTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(70), result.Account.Balance)
+
+	ledger, err := testQueries.ListAccountLedger(context.Background(), ListAccountLedgerParams{
+		AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+		Limit: 5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, ledger, 2)
+	require.Equal(t, ReasonGachaPull, ledger[0].Reason)
+	require.Equal(t, -arg.Cost, ledger[0].Amount)
+	require.Equal(t, ReferenceID(result.Gachas[0].ID), ledger[0].ReferenceID)
+	require.Equal(t, ReasonOpeningBalance, ledger[1].Reason)
+
+	requireReconciled(t, account)
+}
+
+func TestGachaTxSpendOrder(t *testing.T) {
+	store := NewStore(testDB)
+	account := createLedgerAccount(t, 20)
+	banner := RandomCreateBanner(t)
+	bannerItem := RandomCreateBannerItem(t, banner)
+
+	_, err := store.LedgerTx(context.Background(), LedgerTxParams{
+		AccountID: account.ID,
+		Amount: 100,
+		Reason: ReasonPurchase,
+		ReferenceID: "order-1",
+	})
+	require.NoError(t, err)
+
+	arg := GachaTxParams{
+		AccountID: account.ID,
+		BannerID: banner.ID,
+		Owner: account.Owner,
+		Cost: 30,
+		SpendOrder: SpendFreeFirst,
+		Pull: gacha.Pull{Count: 3},
+		Pool: randomBannerPool(t, bannerItem),
+	}
+
+	// free currency runs out during the second pull
+	result, err := store.GachaTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, Charge{Paid: 10, Free: 20}, result.Charge)
+	require.Equal(t, int64(90), result.Account.PaidBalance)
+	require.Zero(t, result.Account.FreeBalance)
+
+	require.Len(t, result.Gachas, 3)
+	require.Equal(t, int64(10), result.Gachas[0].FreeAmount)
+	require.Equal(t, int64(10), result.Gachas[1].FreeAmount)
+	require.Equal(t, int64(10), result.Gachas[2].PaidAmount)
+	require.Zero(t, result.Gachas[2].FreeAmount)
+	requireReconciled(t, account)
+
+	// a paid only banner can't be pulled with free currency
+	_, err = store.LedgerTx(context.Background(), LedgerTxParams{
+		AccountID: account.ID,
+		Amount: 500,
+		Reason: ReasonAdminGrant,
+	})
+	require.NoError(t, err)
+
+	arg.SpendOrder = SpendPaidOnly
+	arg.Cost = 100
+	_, err = store.GachaTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+
+	arg.SpendOrder = SpendPaidFirst
+	result, err = store.GachaTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, Charge{Paid: 90, Free: 10}, result.Charge)
+	require.Zero(t, result.Account.PaidBalance)
+	require.Equal(t, int64(490), result.Account.FreeBalance)
+	requireReconciled(t, account)
+}
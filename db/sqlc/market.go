@@ -0,0 +1,266 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/sRRRs-7/GachaPon/market"
+)
+
+// statuses of the market listings, only an active listing holds its copy in escrow
+const (
+	ListingActive    = "active"
+	ListingSold      = "sold"
+	ListingCancelled = "cancelled"
+)
+
+// errors of the market
+var (
+	ErrItemListed    = errors.New("item copy is listed on the market")
+	ErrListingClosed = errors.New("listing is no longer on sale")
+	ErrOwnListing    = errors.New("listings can't be bought by their seller")
+)
+
+// checkNotListed fails with ErrItemListed while a copy is held by an active listing.
+// The copy must be locked, so no listing can be created until the transaction ends.
+func checkNotListed(ctx context.Context, q *Queries, galleryID int64) error {
+	_, err := q.GetGalleryListing(ctx, sql.NullInt64{Int64: galleryID, Valid: true})
+	switch err {
+	case nil:
+		return ErrItemListed
+	case sql.ErrNoRows:
+		return nil
+	}
+	return err
+}
+
+// CreateListingTxParams contains the input parameters of the listing transaction
+type CreateListingTxParams struct {
+	AccountID int64  `json:"account_id"`
+	Owner     string `json:"owner"`
+	GalleryID int64  `json:"gallery_id"`
+	Price     int64  `json:"price"`
+}
+
+// CreateListingTx puts a copy of the account on sale, holding it in escrow
+// until the listing is sold or cancelled
+func (s *SQLStore) CreateListingTx(ctx context.Context, arg CreateListingTxParams) (Listing, error) {
+	var result Listing
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if account.Owner != arg.Owner {
+			return ErrAccountNotOwned
+		}
+
+		gallery, err := lockGallery(ctx, q, arg.GalleryID)
+		if err != nil {
+			return err
+		}
+		if gallery.OwnerID != account.ID {
+			return ErrItemNotOwned
+		}
+		if err := checkNotListed(ctx, q, gallery.ID); err != nil {
+			return err
+		}
+
+		result, err = q.CreateListing(ctx, CreateListingParams{
+			SellerID:  account.ID,
+			GalleryID: sql.NullInt64{Int64: gallery.ID, Valid: true},
+			ItemID:    gallery.ItemID,
+			Price:     arg.Price,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// CancelListingTxParams contains the input parameters of the listing cancellation transaction
+type CancelListingTxParams struct {
+	ListingID int64 `json:"listing_id"`
+	// Owner must own the account of the seller
+	Owner string `json:"owner"`
+}
+
+// CancelListingTx takes an active listing off the market, giving its copy back to the seller.
+// The seller is locked before the listing like in every market transaction, the
+// reversals of payments locking the account before cancelling its listings.
+func (s *SQLStore) CancelListingTx(ctx context.Context, arg CancelListingTxParams) (Listing, error) {
+	var result Listing
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// the seller of a listing never changes, so it is read before the locks
+		listing, err := q.GetListing(ctx, arg.ListingID)
+		if err != nil {
+			return err
+		}
+
+		seller, err := q.GetAccountForUpdate(ctx, listing.SellerID)
+		if err != nil {
+			return err
+		}
+		if seller.Owner != arg.Owner {
+			return ErrAccountNotOwned
+		}
+
+		listing, err = q.GetListingForUpdate(ctx, listing.ID)
+		if err != nil {
+			return err
+		}
+		if listing.Status != ListingActive {
+			return ErrListingClosed
+		}
+
+		result, err = q.UpdateListingStatus(ctx, UpdateListingStatusParams{
+			ID:     listing.ID,
+			Status: ListingCancelled,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// BuyListingTxParams contains the input parameters of the market purchase transaction
+type BuyListingTxParams struct {
+	ListingID int64  `json:"listing_id"`
+	AccountID int64  `json:"account_id"`
+	Owner     string `json:"owner"`
+	// FeePercent is the part of the price the market keeps
+	FeePercent int64     `json:"fee_percent"`
+	Now        time.Time `json:"now"`
+}
+
+type BuyListingTxResult struct {
+	Listing  Listing  `json:"listing"`
+	Buyer    Account  `json:"buyer"`
+	Seller   Account  `json:"seller"`
+	Gallery  Gallery  `json:"gallery"`
+	Exchange Exchange `json:"exchange"`
+}
+
+// BuyListingTx sells the copy of an active listing to the account. The price is
+// taken from the free bucket of the buyer and given to the seller less the fee,
+// which stays in the market book, and the copy changes hands in the same
+// transaction. The accounts are locked first in the order of their ids, then
+// the listing and the copy, so concurrent purchases sell it once and a reversal
+// revoking the copy meanwhile can't deadlock against the purchase.
+func (s *SQLStore) BuyListingTx(ctx context.Context, arg BuyListingTxParams) (BuyListingTxResult, error) {
+	var result BuyListingTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// the seller of a listing never changes, so it is read before the locks
+		listing, err := q.GetListing(ctx, arg.ListingID)
+		if err != nil {
+			return err
+		}
+		if listing.SellerID == arg.AccountID {
+			return ErrOwnListing
+		}
+
+		accounts := map[int64]*Account{
+			arg.AccountID:    &result.Buyer,
+			listing.SellerID: &result.Seller,
+		}
+		for _, id := range sortedIDs([]int64{arg.AccountID, listing.SellerID}) {
+			*accounts[id], err = q.GetAccountForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+		}
+		if result.Buyer.Owner != arg.Owner {
+			return ErrAccountNotOwned
+		}
+
+		listing, err = q.GetListingForUpdate(ctx, listing.ID)
+		if err != nil {
+			return err
+		}
+		if listing.Status != ListingActive {
+			return ErrListingClosed
+		}
+		if err := checkTradeAmount(result.Buyer, listing.Price); err != nil {
+			return err
+		}
+
+		// the escrow keeps the copy with the seller, a copy revoked meanwhile
+		// cancelled its listing
+		if !listing.GalleryID.Valid {
+			return ErrItemNotOwned
+		}
+		gallery, err := lockGallery(ctx, q, listing.GalleryID.Int64)
+		if err != nil {
+			return err
+		}
+		if gallery.OwnerID != listing.SellerID {
+			return ErrItemNotOwned
+		}
+
+		result.Gallery, err = q.UpdateGallery(ctx, UpdateGalleryParams{
+			ID:         gallery.ID,
+			OwnerID:    listing.SellerID,
+			OwnerID_2:  result.Buyer.ID,
+			ExchangeAt: arg.Now,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Exchange, err = q.CreateExchange(ctx, CreateExchangeParams{
+			FromAccountID: listing.SellerID,
+			ToAccountID:   result.Buyer.ID,
+			ItemID:        gallery.ItemID,
+			GalleryID:     sql.NullInt64{Int64: gallery.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		fee := market.Fee(listing.Price, arg.FeePercent)
+		result.Listing, err = q.SellListing(ctx, SellListingParams{
+			ID:      listing.ID,
+			BuyerID: sql.NullInt64{Int64: result.Buyer.ID, Valid: true},
+			Fee:     fee,
+			SoldAt:  sql.NullTime{Time: arg.Now, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		if listing.Price == 0 {
+			return nil
+		}
+
+		posted, err := postLedger(ctx, q, postLedgerParams{
+			AccountID:   result.Buyer.ID,
+			Free:        -listing.Price,
+			Reason:      ReasonMarketPurchase,
+			ReferenceID: ReferenceID(listing.ID),
+		})
+		if err != nil {
+			return err
+		}
+		result.Buyer = posted.Account
+
+		if listing.Price == fee {
+			return nil
+		}
+
+		posted, err = postLedger(ctx, q, postLedgerParams{
+			AccountID:   result.Seller.ID,
+			Free:        listing.Price - fee,
+			Reason:      ReasonMarketSale,
+			ReferenceID: ReferenceID(listing.ID),
+		})
+		result.Seller = posted.Account
+		return err
+	})
+
+	return result, err
+}
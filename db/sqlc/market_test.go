@@ -0,0 +1,378 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sRRRs-7/GachaPon/payments"
+	"github.com/sRRRs-7/GachaPon/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestListing(t *testing.T, seller Account, price int64) Listing {
+	gallery := createOwnedGallery(t, seller)
+
+	listing, err := NewStore(testDB).CreateListingTx(context.Background(), CreateListingTxParams{
+		AccountID: seller.ID,
+		Owner: seller.Owner,
+		GalleryID: gallery.ID,
+		Price: price,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, seller.ID, listing.SellerID)
+	require.Equal(t, gallery.ID, listing.GalleryID.Int64)
+	require.Equal(t, gallery.ItemID, listing.ItemID)
+	require.Equal(t, price, listing.Price)
+	require.Equal(t, ListingActive, listing.Status)
+	require.False(t, listing.BuyerID.Valid)
+	require.NotZero(t, listing.CreatedAt)
+	return listing
+}
+
+func TestCreateListingTx(t *testing.T) {
+	store := NewStore(testDB)
+	seller := RandomCreateAccount(t)
+	listing := createTestListing(t, seller, 100)
+
+	other := RandomCreateAccount(t)
+	testCases := []struct {
+		name string
+		arg  CreateListingTxParams
+		err  error
+	}{
+		{
+			name: "AlreadyListed",
+			arg: CreateListingTxParams{AccountID: seller.ID, Owner: seller.Owner, GalleryID: listing.GalleryID.Int64, Price: 50},
+			err: ErrItemListed,
+		},
+		{
+			name: "NotOwner",
+			arg: CreateListingTxParams{AccountID: seller.ID, Owner: other.Owner, GalleryID: createOwnedGallery(t, seller).ID, Price: 50},
+			err: ErrAccountNotOwned,
+		},
+		{
+			name: "CopyNotHeld",
+			arg: CreateListingTxParams{AccountID: other.ID, Owner: other.Owner, GalleryID: listing.GalleryID.Int64, Price: 50},
+			err: ErrItemNotOwned,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := store.CreateListingTx(context.Background(), tc.arg)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestListedCopyEscrowed(t *testing.T) {
+	store := NewStore(testDB)
+	seller := RandomCreateAccount(t)
+	other := RandomCreateAccount(t)
+	listing := createTestListing(t, seller, 100)
+	galleryID := listing.GalleryID.Int64
+
+	// the copy can neither be converted nor traded while it is listed
+	_, err := store.ConvertTx(context.Background(), ConvertTxParams{
+		AccountID: seller.ID,
+		Owner: seller.Owner,
+		GalleryIDs: []int64{galleryID},
+	})
+	require.ErrorIs(t, err, ErrItemListed)
+
+	_, err = store.ExchangeTx(context.Background(), ExchangeTxParams{
+		FromAccountID: seller.ID,
+		ToAccountID: other.ID,
+		FromGalleryIDs: []int64{galleryID},
+		ToGalleryIDs: []int64{createOwnedGallery(t, other).ID},
+	})
+	require.ErrorIs(t, err, ErrItemListed)
+
+	_, err = store.CreateTradeOfferTx(context.Background(), CreateTradeOfferTxParams{
+		FromAccountID: seller.ID,
+		Owner: seller.Owner,
+		ToAccountID: other.ID,
+		FromGalleryIDs: []int64{galleryID},
+		ToGalleryIDs: []int64{createOwnedGallery(t, other).ID},
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.ErrorIs(t, err, ErrItemListed)
+
+	// a cancelled listing gives the copy back
+	_, err = store.CancelListingTx(context.Background(), CancelListingTxParams{
+		ListingID: listing.ID,
+		Owner: other.Owner,
+	})
+	require.ErrorIs(t, err, ErrAccountNotOwned)
+
+	cancelled, err := store.CancelListingTx(context.Background(), CancelListingTxParams{
+		ListingID: listing.ID,
+		Owner: seller.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ListingCancelled, cancelled.Status)
+
+	_, err = store.CancelListingTx(context.Background(), CancelListingTxParams{
+		ListingID: listing.ID,
+		Owner: seller.Owner,
+	})
+	require.ErrorIs(t, err, ErrListingClosed)
+
+	result, err := store.ConvertTx(context.Background(), ConvertTxParams{
+		AccountID: seller.ID,
+		Owner: seller.Owner,
+		GalleryIDs: []int64{galleryID},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Galleries, 1)
+}
+
+func TestBuyListingTx(t *testing.T) {
+	store := NewStore(testDB)
+	seller := createLedgerAccount(t, 0)
+	buyer := createLedgerAccount(t, 1000)
+	listing := createTestListing(t, seller, 200)
+
+	arg := BuyListingTxParams{
+		ListingID: listing.ID,
+		AccountID: buyer.ID,
+		Owner: buyer.Owner,
+		FeePercent: 5,
+		Now: time.Now(),
+	}
+
+	result, err := store.BuyListingTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	// the copy changed hands
+	require.Equal(t, buyer.ID, result.Gallery.OwnerID)
+	require.Equal(t, listing.GalleryID.Int64, result.Gallery.ID)
+	require.Equal(t, seller.ID, result.Exchange.FromAccountID)
+	require.Equal(t, buyer.ID, result.Exchange.ToAccountID)
+
+	// the seller got the price less the fee
+	require.Equal(t, ListingSold, result.Listing.Status)
+	require.Equal(t, buyer.ID, result.Listing.BuyerID.Int64)
+	require.Equal(t, int64(10), result.Listing.Fee)
+	require.True(t, result.Listing.SoldAt.Valid)
+	require.Equal(t, int64(800), result.Buyer.FreeBalance)
+	require.Equal(t, int64(190), result.Seller.FreeBalance)
+	requireReconciled(t, buyer)
+	requireReconciled(t, seller)
+
+	// a sold listing is sold once, and its copy is free to trade for the buyer
+	_, err = store.BuyListingTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrListingClosed)
+
+	_, err = store.ConvertTx(context.Background(), ConvertTxParams{
+		AccountID: buyer.ID,
+		Owner: buyer.Owner,
+		GalleryIDs: []int64{result.Gallery.ID},
+	})
+	require.NoError(t, err)
+}
+
+func TestBuyListingTxFails(t *testing.T) {
+	store := NewStore(testDB)
+	seller := createLedgerAccount(t, 0)
+	buyer := createLedgerAccount(t, 100)
+	listing := createTestListing(t, seller, 101)
+
+	testCases := []struct {
+		name string
+		arg  BuyListingTxParams
+		err  error
+	}{
+		{
+			name: "OwnListing",
+			arg: BuyListingTxParams{ListingID: listing.ID, AccountID: seller.ID, Owner: seller.Owner, Now: time.Now()},
+			err: ErrOwnListing,
+		},
+		{
+			name: "NotOwner",
+			arg: BuyListingTxParams{ListingID: listing.ID, AccountID: buyer.ID, Owner: seller.Owner, Now: time.Now()},
+			err: ErrAccountNotOwned,
+		},
+		{
+			name: "InsufficientBalance",
+			arg: BuyListingTxParams{ListingID: listing.ID, AccountID: buyer.ID, Owner: buyer.Owner, Now: time.Now()},
+			err: ErrInsufficientBalance,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := store.BuyListingTx(context.Background(), tc.arg)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+
+	// the listing is still on sale
+	listing1, err := testQueries.GetListing(context.Background(), listing.ID)
+	require.NoError(t, err)
+	require.Equal(t, ListingActive, listing1.Status)
+}
+
+func TestBuyListingTxConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+	seller := createLedgerAccount(t, 0)
+	listing := createTestListing(t, seller, 100)
+
+	n := 5
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		buyer := createLedgerAccount(t, 100)
+		go func() {
+			_, err := store.BuyListingTx(context.Background(), BuyListingTxParams{
+				ListingID: listing.ID,
+				AccountID: buyer.ID,
+				Owner: buyer.Owner,
+				Now: time.Now(),
+			})
+			errs <- err
+		}()
+	}
+
+	// a single buyer gets the copy
+	sold := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			sold++
+			continue
+		}
+		require.ErrorIs(t, err, ErrListingClosed)
+	}
+	require.Equal(t, 1, sold)
+
+	seller, err := testQueries.GetAccount(context.Background(), seller.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), seller.FreeBalance)
+	requireReconciled(t, seller)
+}
+
+func TestBuyListingTxConcurrentReversal(t *testing.T) {
+	store := NewStore(testDB)
+	n := 5
+
+	// each seller lists a copy pulled with a pack that is charged back while the
+	// copy is bought, the chargeback revoking the copy and cancelling its listing.
+	// Both lock the account of the seller before the listing, or they would deadlock.
+	type sale struct {
+		order   Order
+		listing Listing
+		buyer   Account
+	}
+	sales := make([]sale, n)
+	for i := range sales {
+		seller := createLedgerAccount(t, 0)
+		order := creditTestOrder(t, seller, "small")
+		pulled := paidPulls(t, seller, 1, 100)
+
+		listing, err := store.CreateListingTx(context.Background(), CreateListingTxParams{
+			AccountID: seller.ID,
+			Owner: seller.Owner,
+			GalleryID: pulled.Galleries[0].ID,
+			Price: 50,
+		})
+		require.NoError(t, err)
+		sales[i] = sale{order: order, listing: listing, buyer: createLedgerAccount(t, 100)}
+	}
+
+	errs := make(chan error)
+	for _, sale := range sales {
+		sale := sale
+		go func() {
+			_, err := store.BuyListingTx(context.Background(), BuyListingTxParams{
+				ListingID: sale.listing.ID,
+				AccountID: sale.buyer.ID,
+				Owner: sale.buyer.Owner,
+				Now: time.Now(),
+			})
+			errs <- err
+		}()
+		go func() {
+			_, err := store.PaymentEventTx(context.Background(), PaymentEventTxParams{
+				OrderID: sale.order.ID,
+				Event: payments.EventChargeback,
+				ProviderRef: sale.order.ProviderRef,
+				RevokeItems: true,
+			})
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < 2*n; i++ {
+		err := <-errs
+		// a purchase coming after the chargeback finds its listing cancelled
+		if err != nil {
+			require.ErrorIs(t, err, ErrListingClosed)
+		}
+	}
+
+	for _, sale := range sales {
+		listing, err := testQueries.GetListing(context.Background(), sale.listing.ID)
+		require.NoError(t, err)
+		require.Contains(t, []string{ListingSold, ListingCancelled}, listing.Status)
+
+		order, err := testQueries.GetOrder(context.Background(), sale.order.ID)
+		require.NoError(t, err)
+		require.Equal(t, payments.StatusChargedBack, order.Status)
+		requireReconciled(t, sale.buyer)
+	}
+}
+
+func TestListMarketListings(t *testing.T) {
+	seller := RandomCreateAccount(t)
+
+	// prices no other test lists at
+	price := utils.RandomInt(1000000, 1000000000)
+	listing1 := createTestListing(t, seller, price+1)
+	listing2 := createTestListing(t, seller, price)
+
+	item, err := testQueries.GetItem(context.Background(), listing1.ItemID)
+	require.NoError(t, err)
+
+	arg := ListMarketListingsParams{
+		CategoryID: item.CategoryID,
+		MinRating: item.Rating,
+		MaxRating: item.Rating,
+		MinPrice: price,
+		MaxPrice: price + 1,
+		PageLimit: 5,
+		PageOffset: 0,
+	}
+
+	// cheapest first
+	listings, err := testQueries.ListMarketListings(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, listings, 2)
+	require.Equal(t, listing2.ID, listings[0].ID)
+	require.Equal(t, listing1.ID, listings[1].ID)
+	require.Equal(t, item.ItemName, listings[1].ItemName)
+
+	arg.MinPrice = price + 1
+	listings, err = testQueries.ListMarketListings(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, listings, 1)
+	require.Equal(t, listing1.ID, listings[0].ID)
+
+	arg.MaxRating = item.Rating - 1
+	listings, err = testQueries.ListMarketListings(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, listings)
+
+	// a cancelled listing leaves the market
+	arg.MaxRating = item.Rating
+	_, err = NewStore(testDB).CancelListingTx(context.Background(), CancelListingTxParams{
+		ListingID: listing1.ID,
+		Owner: seller.Owner,
+	})
+	require.NoError(t, err)
+
+	listings, err = testQueries.ListMarketListings(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, listings)
+}
@@ -5,25 +5,69 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 )
 
 type Account struct {
-	ID        int64     `json:"id"`
-	Owner     string    `json:"owner"`
-	Balance   int64     `json:"balance"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          int64        `json:"id"`
+	Owner       string       `json:"owner"`
+	CreatedAt   time.Time    `json:"created_at"`
+	Shards      int64        `json:"shards"`
+	PaidBalance int64        `json:"paid_balance"`
+	FreeBalance int64        `json:"free_balance"`
+	Balance     int64        `json:"balance"`
+	PullTickets int64        `json:"pull_tickets"`
+	FlaggedAt   sql.NullTime `json:"flagged_at"`
 }
 
 type Approval struct {
-	ID            int64     `json:"id"`
-	FromAccountID int64     `json:"from_account_id"`
-	FromItemID    int64     `json:"from_item_id"`
-	FromAApproval bool      `json:"from_a_approval"`
-	ToAccountID   int64     `json:"to_account_id"`
-	ToItemID      int64     `json:"to_item_id"`
-	ToAApproval   bool      `json:"to_a_approval"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID            int64         `json:"id"`
+	FromAccountID int64         `json:"from_account_id"`
+	FromAApproval bool          `json:"from_a_approval"`
+	ToAccountID   int64         `json:"to_account_id"`
+	ToAApproval   bool          `json:"to_a_approval"`
+	CreatedAt     time.Time     `json:"created_at"`
+	FromAmount    int64         `json:"from_amount"`
+	ToAmount      int64         `json:"to_amount"`
+	Status        string        `json:"status"`
+	ExpiresAt     time.Time     `json:"expires_at"`
+	ParentID      sql.NullInt64 `json:"parent_id"`
+	ThreadID      sql.NullInt64 `json:"thread_id"`
+}
+
+type Banner struct {
+	ID              int64     `json:"id"`
+	BannerName      string    `json:"banner_name"`
+	Cost            int64     `json:"cost"`
+	IsActive        bool      `json:"is_active"`
+	StartAt         time.Time `json:"start_at"`
+	EndAt           time.Time `json:"end_at"`
+	CreatedAt       time.Time `json:"created_at"`
+	MultiCount      int32     `json:"multi_count"`
+	MultiCost       int64     `json:"multi_cost"`
+	GuaranteeRating int32     `json:"guarantee_rating"`
+	SoftPity        int32     `json:"soft_pity"`
+	HardPity        int32     `json:"hard_pity"`
+	SoftPityStep    int64     `json:"soft_pity_step"`
+	SparkCost       int64     `json:"spark_cost"`
+	SpendOrder      string    `json:"spend_order"`
+}
+
+type BannerItem struct {
+	ID         int64     `json:"id"`
+	BannerID   int64     `json:"banner_id"`
+	ItemID     int64     `json:"item_id"`
+	Weight     int64     `json:"weight"`
+	IsFeatured bool      `json:"is_featured"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type BannerRate struct {
+	BannerID int64 `json:"banner_id"`
+	Rating   int32 `json:"rating"`
+	Weight   int64 `json:"weight"`
 }
 
 type Category struct {
@@ -32,19 +76,65 @@ type Category struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type Coupon struct {
+	ID             int64         `json:"id"`
+	Code           string        `json:"code"`
+	Amount         int64         `json:"amount"`
+	ItemID         sql.NullInt64 `json:"item_id"`
+	PullTickets    int64         `json:"pull_tickets"`
+	MaxRedemptions sql.NullInt64 `json:"max_redemptions"`
+	Redemptions    int64         `json:"redemptions"`
+	PerUserLimit   int32         `json:"per_user_limit"`
+	StartAt        time.Time     `json:"start_at"`
+	EndAt          time.Time     `json:"end_at"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+type CouponRedemption struct {
+	ID        int64         `json:"id"`
+	CouponID  int64         `json:"coupon_id"`
+	AccountID sql.NullInt64 `json:"account_id"`
+	Owner     string        `json:"owner"`
+	GalleryID sql.NullInt64 `json:"gallery_id"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type DailyClaim struct {
+	ID        int64         `json:"id"`
+	AccountID int64         `json:"account_id"`
+	ClaimDate time.Time     `json:"claim_date"`
+	Streak    int32         `json:"streak"`
+	Amount    int64         `json:"amount"`
+	ItemID    sql.NullInt64 `json:"item_id"`
+	GalleryID sql.NullInt64 `json:"gallery_id"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
 type Exchange struct {
-	ID            int64     `json:"id"`
-	FromAccountID int64     `json:"from_account_id"`
-	ToAccountID   int64     `json:"to_account_id"`
-	ItemID        int64     `json:"item_id"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID            int64         `json:"id"`
+	FromAccountID int64         `json:"from_account_id"`
+	ToAccountID   int64         `json:"to_account_id"`
+	ItemID        int64         `json:"item_id"`
+	CreatedAt     time.Time     `json:"created_at"`
+	GalleryID     sql.NullInt64 `json:"gallery_id"`
 }
 
 type Gacha struct {
-	ID        int64     `json:"id"`
-	AccountID int64     `json:"account_id"`
-	ItemID    int64     `json:"item_id"`
-	CreatedAt time.Time `json:"created_at"`
+	ID         int64         `json:"id"`
+	AccountID  int64         `json:"account_id"`
+	ItemID     int64         `json:"item_id"`
+	CreatedAt  time.Time     `json:"created_at"`
+	SeedID     sql.NullInt64 `json:"seed_id"`
+	Nonce      int64         `json:"nonce"`
+	Roll       float64       `json:"roll"`
+	GalleryID  sql.NullInt64 `json:"gallery_id"`
+	PaidAmount int64         `json:"paid_amount"`
+	FreeAmount int64         `json:"free_amount"`
+	RevokedAt  sql.NullTime  `json:"revoked_at"`
+	BannerID   sql.NullInt64 `json:"banner_id"`
+	PoolDigest string        `json:"pool_digest"`
+	PityPulls  int32         `json:"pity_pulls"`
+	MinRating  int32         `json:"min_rating"`
 }
 
 type Gallery struct {
@@ -55,6 +145,17 @@ type Gallery struct {
 	CreatedAt  time.Time `json:"created_at"`
 }
 
+type IdempotencyKey struct {
+	Username       string    `json:"username"`
+	IdempotencyKey string    `json:"idempotency_key"`
+	RequestHash    string    `json:"request_hash"`
+	ResponseStatus int32     `json:"response_status"`
+	ResponseBody   []byte    `json:"response_body"`
+	ContentType    string    `json:"content_type"`
+	CreatedAt      time.Time `json:"created_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
 type Item struct {
 	ID         int64     `json:"id"`
 	ItemName   string    `json:"item_name"`
@@ -64,6 +165,87 @@ type Item struct {
 	CreatedAt  time.Time `json:"created_at"`
 }
 
+type LedgerEntry struct {
+	ID        int64         `json:"id"`
+	JournalID int64         `json:"journal_id"`
+	AccountID sql.NullInt64 `json:"account_id"`
+	Book      string        `json:"book"`
+	Amount    int64         `json:"amount"`
+	CreatedAt time.Time     `json:"created_at"`
+	Bucket    string        `json:"bucket"`
+}
+
+type LedgerJournal struct {
+	ID          int64     `json:"id"`
+	Reason      string    `json:"reason"`
+	ReferenceID string    `json:"reference_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Listing struct {
+	ID        int64         `json:"id"`
+	SellerID  int64         `json:"seller_id"`
+	GalleryID sql.NullInt64 `json:"gallery_id"`
+	ItemID    int64         `json:"item_id"`
+	Price     int64         `json:"price"`
+	Status    string        `json:"status"`
+	BuyerID   sql.NullInt64 `json:"buyer_id"`
+	Fee       int64         `json:"fee"`
+	SoldAt    sql.NullTime  `json:"sold_at"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type LoginReward struct {
+	Day       int32         `json:"day"`
+	Amount    int64         `json:"amount"`
+	ItemID    sql.NullInt64 `json:"item_id"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+type Order struct {
+	ID          int64     `json:"id"`
+	AccountID   int64     `json:"account_id"`
+	PackID      string    `json:"pack_id"`
+	Amount      int64     `json:"amount"`
+	Price       int64     `json:"price"`
+	Currency    string    `json:"currency"`
+	Status      string    `json:"status"`
+	Provider    string    `json:"provider"`
+	ProviderRef string    `json:"provider_ref"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type Pity struct {
+	AccountID int64     `json:"account_id"`
+	BannerID  int64     `json:"banner_id"`
+	Pulls     int32     `json:"pulls"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PoolSnapshot struct {
+	Digest    string          `json:"digest"`
+	Snapshot  json.RawMessage `json:"snapshot"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type RarityRate struct {
+	Rating    int32     `json:"rating"`
+	Weight    int64     `json:"weight"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Seed struct {
+	ID             int64        `json:"id"`
+	AccountID      int64        `json:"account_id"`
+	ServerSeed     string       `json:"server_seed"`
+	ServerSeedHash string       `json:"server_seed_hash"`
+	ClientSeed     string       `json:"client_seed"`
+	Nonce          int64        `json:"nonce"`
+	RevealedAt     sql.NullTime `json:"revealed_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
 type Session struct {
 	ID        int64     `json:"id"`
 	UserName  string    `json:"user_name"`
@@ -73,11 +255,94 @@ type Session struct {
 	ExpiredAt time.Time `json:"expired_at"`
 }
 
+type ShopOffer struct {
+	ID           int64         `json:"id"`
+	ItemID       int64         `json:"item_id"`
+	Price        int64         `json:"price"`
+	SpendOrder   string        `json:"spend_order"`
+	Stock        sql.NullInt64 `json:"stock"`
+	Sold         int64         `json:"sold"`
+	PerUserLimit sql.NullInt32 `json:"per_user_limit"`
+	StartAt      time.Time     `json:"start_at"`
+	EndAt        time.Time     `json:"end_at"`
+	RotationDay  sql.NullTime  `json:"rotation_day"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+type ShopPurchase struct {
+	ID         int64         `json:"id"`
+	OfferID    int64         `json:"offer_id"`
+	AccountID  sql.NullInt64 `json:"account_id"`
+	Owner      string        `json:"owner"`
+	GalleryID  sql.NullInt64 `json:"gallery_id"`
+	PaidAmount int64         `json:"paid_amount"`
+	FreeAmount int64         `json:"free_amount"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type ShopRotation struct {
+	Day       time.Time `json:"day"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SpendingCap struct {
+	ID           int64         `json:"id"`
+	Region       string        `json:"region"`
+	MinAge       int32         `json:"min_age"`
+	MonthlyLimit sql.NullInt64 `json:"monthly_limit"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+type Subscription struct {
+	ID          int64     `json:"id"`
+	AccountID   int64     `json:"account_id"`
+	PassID      string    `json:"pass_id"`
+	DailyAmount int64     `json:"daily_amount"`
+	PaidAmount  int64     `json:"paid_amount"`
+	FreeAmount  int64     `json:"free_amount"`
+	Status      string    `json:"status"`
+	StartAt     time.Time `json:"start_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type SubscriptionClaim struct {
+	ID             int64     `json:"id"`
+	SubscriptionID int64     `json:"subscription_id"`
+	AccountID      int64     `json:"account_id"`
+	ClaimDate      time.Time `json:"claim_date"`
+	Amount         int64     `json:"amount"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type TradeEvent struct {
+	ID         int64         `json:"id"`
+	ApprovalID int64         `json:"approval_id"`
+	ThreadID   int64         `json:"thread_id"`
+	AccountID  sql.NullInt64 `json:"account_id"`
+	Event      string        `json:"event"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type TradeItem struct {
+	ID         int64         `json:"id"`
+	ApprovalID int64         `json:"approval_id"`
+	Side       string        `json:"side"`
+	GalleryID  sql.NullInt64 `json:"gallery_id"`
+	ItemID     int64         `json:"item_id"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
 type User struct {
-	ID           int64     `json:"id"`
-	UserName     string    `json:"user_name"`
-	HashPassword string    `json:"hash_password"`
-	FullName     string    `json:"full_name"`
-	Email        string    `json:"email"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID                int64        `json:"id"`
+	UserName          string       `json:"user_name"`
+	HashPassword      string       `json:"hash_password"`
+	FullName          string       `json:"full_name"`
+	Email             string       `json:"email"`
+	CreatedAt         time.Time    `json:"created_at"`
+	Birthdate         sql.NullTime `json:"birthdate"`
+	Region            string       `json:"region"`
+	Timezone          string       `json:"timezone"`
+	IsAdmin           bool         `json:"is_admin"`
+	TimezoneUpdatedAt sql.NullTime `json:"timezone_updated_at"`
 }
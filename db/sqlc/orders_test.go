@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/sRRRs-7/GachaPon/payments"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestOrder(t *testing.T, account Account, packID string) Order {
+	pack, ok := payments.FindPack(packID)
+	require.True(t, ok)
+
+	arg := CreateOrderParams{
+		AccountID: account.ID,
+		PackID: pack.ID,
+		Amount: pack.Amount,
+		Price: pack.Price,
+		Currency: pack.Currency,
+		Provider: payments.FakeProviderName,
+	}
+
+	order, err := testQueries.CreateOrder(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, order.ID)
+	require.Equal(t, arg.AccountID, order.AccountID)
+	require.Equal(t, arg.Amount, order.Amount)
+	require.Equal(t, payments.StatusPending, order.Status)
+	require.Empty(t, order.ProviderRef)
+
+	order, err = testQueries.UpdateOrderProviderRef(context.Background(), UpdateOrderProviderRefParams{
+		ID: order.ID,
+		ProviderRef: fmt.Sprintf("fake_%d", order.ID),
+	})
+	require.NoError(t, err)
+	return order
+}
+
+func TestCreateOrder(t *testing.T) {
+	account := createLedgerAccount(t, 0)
+	order1 := createTestOrder(t, account, "small")
+
+	order2, err := testQueries.GetOrder(context.Background(), order1.ID)
+	require.NoError(t, err)
+	require.Equal(t, order1.ProviderRef, order2.ProviderRef)
+	require.Equal(t, order1.Status, order2.Status)
+}
+
+func TestListOrders(t *testing.T) {
+	account := createLedgerAccount(t, 0)
+	for i := 0; i < 3; i++ {
+		createTestOrder(t, account, "small")
+	}
+
+	orders, err := testQueries.ListOrders(context.Background(), ListOrdersParams{
+		AccountID: account.ID,
+		Limit: 5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, orders, 3)
+	require.Greater(t, orders[0].ID, orders[1].ID)
+}
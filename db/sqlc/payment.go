@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sRRRs-7/GachaPon/payments"
+)
+
+var ErrProviderRefMismatch = errors.New("payment event doesn't match the order")
+
+// CreateOrderTx opens an order for a pack. An order whose price takes the month
+// to date purchases of the account past the cap of its owner fails with
+// ErrSpendingCapExceeded.
+func (s *SQLStore) CreateOrderTx(ctx context.Context, arg CreateOrderParams) (Order, error) {
+	var result Order
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// concurrent orders of the account queue up behind the lock,
+		// each reading the purchases of the ones before it
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		spending, err := monthlySpending(ctx, q, account, time.Now())
+		if err != nil {
+			return err
+		}
+		if !spending.AllowsPurchase(arg.Price) {
+			return ErrSpendingCapExceeded
+		}
+
+		result, err = q.CreateOrder(ctx, arg)
+		return err
+	})
+
+	return result, err
+}
+
+// PaymentEventTxParams contains the input parameters of the payment event transaction
+type PaymentEventTxParams struct {
+	OrderID     int64              `json:"order_id"`
+	Event       payments.EventType `json:"event"`
+	ProviderRef string             `json:"provider_ref"`
+	// RevokeItems lets refunds and chargebacks take back the copies pulled
+	// with the currency they reverse
+	RevokeItems bool `json:"revoke_items"`
+}
+
+type PaymentEventTxResult struct {
+	Order Order `json:"order"`
+	// Applied is false when the order had already reached the status of the event
+	Applied bool `json:"applied"`
+	// Reversal is set when a refund or chargeback took the pack back
+	Reversal *Reversal `json:"reversal,omitempty"`
+}
+
+// PaymentEventTx moves an order along its status machine.
+// A paid order credits its pack to the paid bucket of the account, and a
+// refunded or charged back order takes it back by reversing the credit, both
+// through the ledger. The order row stays locked until the transaction ends
+// and an event already applied is a no-op, so a pack is credited exactly once
+// however many times the provider delivers the event.
+func (s *SQLStore) PaymentEventTx(ctx context.Context, arg PaymentEventTxParams) (PaymentEventTxResult, error) {
+	var result PaymentEventTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		order, err := q.GetOrderForUpdate(ctx, arg.OrderID)
+		if err != nil {
+			return err
+		}
+		if arg.ProviderRef != order.ProviderRef {
+			return ErrProviderRefMismatch
+		}
+
+		result.Order = order
+		status := payments.EventStatus(arg.Event)
+		if order.Status == status {
+			return nil
+		}
+
+		var amount int64
+		var reason string
+		switch arg.Event {
+		case payments.EventPaid:
+			if err := payments.Transition(order.Status, payments.StatusPaid); err != nil {
+				return err
+			}
+			if err := payments.Transition(payments.StatusPaid, status); err != nil {
+				return err
+			}
+			amount, reason = order.Amount, ReasonPurchase
+		case payments.EventRefunded, payments.EventChargeback:
+			if err := payments.Transition(order.Status, status); err != nil {
+				return err
+			}
+			reason = ReasonRefund
+			if arg.Event == payments.EventChargeback {
+				reason = ReasonChargeback
+			}
+			reversal, err := reverseOrder(ctx, q, order, reason, arg.RevokeItems)
+			if err != nil {
+				return err
+			}
+			result.Reversal = &reversal
+		default:
+			if err := payments.Transition(order.Status, status); err != nil {
+				return err
+			}
+		}
+
+		if amount != 0 {
+			_, err = postLedger(ctx, q, postLedgerParams{
+				AccountID:   order.AccountID,
+				Paid:        amount,
+				Reason:      reason,
+				ReferenceID: ReferenceID(order.ID),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Order, err = q.UpdateOrderStatus(ctx, UpdateOrderStatusParams{
+			ID:     order.ID,
+			Status: status,
+		})
+		result.Applied = true
+		return err
+	})
+
+	return result, err
+}
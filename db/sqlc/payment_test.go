@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sRRRs-7/GachaPon/payments"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentEventTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createLedgerAccount(t, 10)
+	order := createTestOrder(t, account, "small")
+
+	arg := PaymentEventTxParams{
+		OrderID: order.ID,
+		Event: payments.EventPaid,
+		ProviderRef: order.ProviderRef,
+	}
+
+	// a redelivered event must not credit the pack twice
+	for i := 0; i < 3; i++ {
+		result, err := store.PaymentEventTx(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, i == 0, result.Applied)
+		require.Equal(t, payments.StatusCredited, result.Order.Status)
+	}
+
+	account1, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+order.Amount, account1.Balance)
+	require.Equal(t, order.Amount, account1.PaidBalance)
+	requireReconciled(t, account1)
+
+	arg.Event = payments.EventRefunded
+	result, err := store.PaymentEventTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.Applied)
+	require.Equal(t, payments.StatusRefunded, result.Order.Status)
+
+	account2, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, account2.Balance)
+	requireReconciled(t, account2)
+
+	// a refunded order can't be paid again
+	arg.Event = payments.EventPaid
+	_, err = store.PaymentEventTx(context.Background(), arg)
+	require.ErrorIs(t, err, payments.ErrInvalidTransition)
+}
+
+func TestPaymentEventTxConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+	account := createLedgerAccount(t, 0)
+	order := createTestOrder(t, account, "medium")
+
+	n := 5
+	errs := make(chan error)
+	applied := make(chan bool)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			result, err := store.PaymentEventTx(context.Background(), PaymentEventTxParams{
+				OrderID: order.ID,
+				Event: payments.EventPaid,
+				ProviderRef: order.ProviderRef,
+			})
+			errs <- err
+			applied <- result.Applied
+		}()
+	}
+
+	count := 0
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+		if <-applied {
+			count++
+		}
+	}
+	require.Equal(t, 1, count)
+
+	account1, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, order.Amount, account1.Balance)
+	requireReconciled(t, account1)
+}
+
+func TestPaymentEventTxFailed(t *testing.T) {
+	store := NewStore(testDB)
+	account := createLedgerAccount(t, 0)
+	order := createTestOrder(t, account, "small")
+
+	_, err := store.PaymentEventTx(context.Background(), PaymentEventTxParams{
+		OrderID: order.ID,
+		Event: payments.EventPaid,
+		ProviderRef: "fake_other",
+	})
+	require.ErrorIs(t, err, ErrProviderRefMismatch)
+
+	result, err := store.PaymentEventTx(context.Background(), PaymentEventTxParams{
+		OrderID: order.ID,
+		Event: payments.EventFailed,
+		ProviderRef: order.ProviderRef,
+	})
+	require.NoError(t, err)
+	require.Equal(t, payments.StatusFailed, result.Order.Status)
+
+	// a failed payment credits nothing and can't be refunded
+	_, err = store.PaymentEventTx(context.Background(), PaymentEventTxParams{
+		OrderID: order.ID,
+		Event: payments.EventRefunded,
+		ProviderRef: order.ProviderRef,
+	})
+	require.ErrorIs(t, err, payments.ErrInvalidTransition)
+
+	account1, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Zero(t, account1.Balance)
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsertPity(t *testing.T) {
+	account := RandomCreateAccount(t)
+	banner := RandomCreateBanner(t)
+
+	arg := UpsertPityParams{
+		AccountID: account.ID,
+		BannerID: banner.ID,
+		Pulls: 3,
+	}
+
+	pity1, err := testQueries.UpsertPity(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Pulls, pity1.Pulls)
+
+	arg.Pulls = 0
+	pity2, err := testQueries.UpsertPity(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Pulls, pity2.Pulls)
+	require.False(t, pity2.UpdatedAt.Before(pity1.UpdatedAt))
+
+	pity3, err := testQueries.GetPity(context.Background(), GetPityParams{
+		AccountID: account.ID,
+		BannerID: banner.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, pity2, pity3)
+}
+
+func TestGetPityNotFound(t *testing.T) {
+	account := RandomCreateAccount(t)
+	banner := RandomCreateBanner(t)
+
+	pity, err := testQueries.GetPity(context.Background(), GetPityParams{
+		AccountID: account.ID,
+		BannerID: banner.ID,
+	})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+	require.Empty(t, pity)
+}
@@ -6,49 +6,171 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 type Querier interface {
+	AddCouponRedemption(ctx context.Context, id int64) (Coupon, error)
+	AddShopOfferSale(ctx context.Context, id int64) (ShopOffer, error)
+	CancelGalleryListing(ctx context.Context, arg CancelGalleryListingParams) error
+	CountCouponRedemptions(ctx context.Context, arg CountCouponRedemptionsParams) (int64, error)
+	CountShopPurchases(ctx context.Context, arg CountShopPurchasesParams) (int64, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
 	CreateApproval(ctx context.Context, arg CreateApprovalParams) (Approval, error)
+	CreateBanner(ctx context.Context, arg CreateBannerParams) (Banner, error)
+	CreateBannerItem(ctx context.Context, arg CreateBannerItemParams) (BannerItem, error)
 	CreateCategory(ctx context.Context, category string) (Category, error)
+	CreateCoupon(ctx context.Context, arg CreateCouponParams) (Coupon, error)
+	CreateCouponRedemption(ctx context.Context, arg CreateCouponRedemptionParams) (CouponRedemption, error)
+	CreateDailyClaim(ctx context.Context, arg CreateDailyClaimParams) (DailyClaim, error)
 	CreateExchange(ctx context.Context, arg CreateExchangeParams) (Exchange, error)
 	CreateGacha(ctx context.Context, arg CreateGachaParams) (Gacha, error)
 	CreateGallery(ctx context.Context, arg CreateGalleryParams) (Gallery, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
 	CreateItem(ctx context.Context, arg CreateItemParams) (Item, error)
+	CreateLedgerEntry(ctx context.Context, arg CreateLedgerEntryParams) (LedgerEntry, error)
+	CreateLedgerJournal(ctx context.Context, arg CreateLedgerJournalParams) (LedgerJournal, error)
+	CreateListing(ctx context.Context, arg CreateListingParams) (Listing, error)
+	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
+	CreatePoolSnapshot(ctx context.Context, arg CreatePoolSnapshotParams) error
+	CreateSeed(ctx context.Context, arg CreateSeedParams) (Seed, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateShopOffer(ctx context.Context, arg CreateShopOfferParams) (ShopOffer, error)
+	CreateShopPurchase(ctx context.Context, arg CreateShopPurchaseParams) (ShopPurchase, error)
+	CreateShopRotation(ctx context.Context, day time.Time) (ShopRotation, error)
+	CreateSpendingCap(ctx context.Context, arg CreateSpendingCapParams) (SpendingCap, error)
+	CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) (Subscription, error)
+	CreateSubscriptionClaim(ctx context.Context, arg CreateSubscriptionClaimParams) (SubscriptionClaim, error)
+	CreateTradeEvent(ctx context.Context, arg CreateTradeEventParams) (TradeEvent, error)
+	CreateTradeItem(ctx context.Context, arg CreateTradeItemParams) (TradeItem, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	DeleteAccount(ctx context.Context, id int64) error
 	DeleteApproval(ctx context.Context, id int64) error
+	DeleteBanner(ctx context.Context, id int64) error
+	DeleteBannerItem(ctx context.Context, arg DeleteBannerItemParams) error
+	DeleteCoupon(ctx context.Context, id int64) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context, expiresAt time.Time) (int64, error)
+	DeleteGallery(ctx context.Context, arg DeleteGalleryParams) (Gallery, error)
+	DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error
 	DeleteItem(ctx context.Context, id int64) error
+	DeleteLoginReward(ctx context.Context, day int32) error
+	DeleteShopOffer(ctx context.Context, id int64) error
+	DeleteSpendingCap(ctx context.Context, id int64) error
+	ExpireApprovals(ctx context.Context, expiresAt time.Time) ([]Approval, error)
+	ExpireSubscriptions(ctx context.Context, expiresAt time.Time) ([]Subscription, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
+	GetAccountByOwner(ctx context.Context, owner string) (Account, error)
+	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetActiveSeed(ctx context.Context, accountID int64) (Seed, error)
+	GetActiveSeedForUpdate(ctx context.Context, accountID int64) (Seed, error)
 	GetApproval(ctx context.Context, id int64) (Approval, error)
+	GetApprovalForUpdate(ctx context.Context, id int64) (Approval, error)
+	GetBanner(ctx context.Context, id int64) (Banner, error)
+	GetBannerItem(ctx context.Context, arg GetBannerItemParams) (BannerItem, error)
 	GetCategory(ctx context.Context, category string) (Category, error)
+	GetCoupon(ctx context.Context, id int64) (Coupon, error)
+	GetCouponByCodeForUpdate(ctx context.Context, code string) (Coupon, error)
+	GetCurrentSubscription(ctx context.Context, arg GetCurrentSubscriptionParams) (Subscription, error)
 	GetExchange(ctx context.Context, id int64) (Exchange, error)
 	GetGacha(ctx context.Context, id int64) (Gacha, error)
 	GetGallery(ctx context.Context, id int64) (Gallery, error)
+	GetGalleryForUpdate(ctx context.Context, id int64) (Gallery, error)
+	GetGalleryForUpdateNowait(ctx context.Context, id int64) (Gallery, error)
+	GetGalleryListing(ctx context.Context, galleryID sql.NullInt64) (Listing, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
 	GetItem(ctx context.Context, id int64) (Item, error)
+	GetLastDailyClaim(ctx context.Context, accountID int64) (DailyClaim, error)
+	GetLastSubscription(ctx context.Context, accountID int64) (Subscription, error)
+	GetLedgerBalance(ctx context.Context, accountID sql.NullInt64) (int64, error)
+	GetLedgerBucketBalance(ctx context.Context, arg GetLedgerBucketBalanceParams) (int64, error)
+	GetLedgerJournalByReference(ctx context.Context, arg GetLedgerJournalByReferenceParams) (LedgerJournal, error)
+	GetListing(ctx context.Context, id int64) (Listing, error)
+	GetListingForUpdate(ctx context.Context, id int64) (Listing, error)
+	GetMonthlyPaidSpend(ctx context.Context, arg GetMonthlyPaidSpendParams) (int64, error)
+	GetMonthlyPurchases(ctx context.Context, arg GetMonthlyPurchasesParams) (int64, error)
+	GetMonthlyShopPaidSpend(ctx context.Context, arg GetMonthlyShopPaidSpendParams) (int64, error)
+	GetMonthlySubscriptionPaidSpend(ctx context.Context, arg GetMonthlySubscriptionPaidSpendParams) (int64, error)
+	GetOrder(ctx context.Context, id int64) (Order, error)
+	GetOrderForUpdate(ctx context.Context, id int64) (Order, error)
+	GetPity(ctx context.Context, arg GetPityParams) (Pity, error)
+	GetPoolSnapshot(ctx context.Context, digest string) (PoolSnapshot, error)
+	GetRarityRate(ctx context.Context, rating int32) (RarityRate, error)
+	GetSeed(ctx context.Context, id int64) (Seed, error)
 	GetSession(ctx context.Context, id int64) (Session, error)
+	GetShopOffer(ctx context.Context, id int64) (ShopOffer, error)
+	GetShopOfferForUpdate(ctx context.Context, id int64) (ShopOffer, error)
+	GetSpendingCap(ctx context.Context, arg GetSpendingCapParams) (SpendingCap, error)
+	GetSubscription(ctx context.Context, id int64) (Subscription, error)
+	GetSubscriptionClaim(ctx context.Context, arg GetSubscriptionClaimParams) (SubscriptionClaim, error)
 	GetUser(ctx context.Context, userName string) (User, error)
+	ListAccountLedger(ctx context.Context, arg ListAccountLedgerParams) ([]ListAccountLedgerRow, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	ListActiveBanners(ctx context.Context) ([]Banner, error)
+	ListActiveShopOffers(ctx context.Context) ([]ListActiveShopOffersRow, error)
 	ListApproval(ctx context.Context, arg ListApprovalParams) ([]Approval, error)
+	ListBannerItems(ctx context.Context, bannerID int64) ([]BannerItem, error)
+	ListBannerPool(ctx context.Context, bannerID int64) ([]ListBannerPoolRow, error)
+	ListBannerRates(ctx context.Context, bannerID int64) ([]BannerRate, error)
+	ListBanners(ctx context.Context, arg ListBannersParams) ([]Banner, error)
 	ListCategories(ctx context.Context, arg ListCategoriesParams) ([]Category, error)
+	ListCoupons(ctx context.Context, arg ListCouponsParams) ([]Coupon, error)
+	ListDailyClaims(ctx context.Context, arg ListDailyClaimsParams) ([]DailyClaim, error)
 	ListExchangeFromAccount(ctx context.Context, arg ListExchangeFromAccountParams) ([]Exchange, error)
 	ListExchangeToAccount(ctx context.Context, arg ListExchangeToAccountParams) ([]Exchange, error)
 	ListGachas(ctx context.Context, arg ListGachasParams) ([]Gacha, error)
 	ListGalleriesById(ctx context.Context, arg ListGalleriesByIdParams) ([]Gallery, error)
 	ListGalleriesByItemId(ctx context.Context, arg ListGalleriesByItemIdParams) ([]Gallery, error)
+	ListGalleryQuantities(ctx context.Context, arg ListGalleryQuantitiesParams) ([]ListGalleryQuantitiesRow, error)
+	ListIncomingApprovals(ctx context.Context, arg ListIncomingApprovalsParams) ([]Approval, error)
 	ListItemByCategoryId(ctx context.Context, arg ListItemByCategoryIdParams) ([]Item, error)
 	ListItemsByCategoryId(ctx context.Context, arg ListItemsByCategoryIdParams) ([]Item, error)
 	ListItemsById(ctx context.Context, arg ListItemsByIdParams) ([]Item, error)
 	ListItemsByItemName(ctx context.Context, arg ListItemsByItemNameParams) ([]Item, error)
 	ListItemsByRating(ctx context.Context, arg ListItemsByRatingParams) ([]Item, error)
+	ListLedgerEntriesByJournal(ctx context.Context, journalID int64) ([]LedgerEntry, error)
+	ListLoginRewards(ctx context.Context) ([]LoginReward, error)
+	ListMarketListings(ctx context.Context, arg ListMarketListingsParams) ([]ListMarketListingsRow, error)
+	ListOrders(ctx context.Context, arg ListOrdersParams) ([]Order, error)
+	ListOutgoingApprovals(ctx context.Context, arg ListOutgoingApprovalsParams) ([]Approval, error)
+	ListRarityRates(ctx context.Context) ([]RarityRate, error)
+	ListRevocableGachas(ctx context.Context, arg ListRevocableGachasParams) ([]Gacha, error)
+	ListRotationCandidates(ctx context.Context) ([]ListRotationCandidatesRow, error)
+	ListRotationShopOffers(ctx context.Context, rotationDay sql.NullTime) ([]ShopOffer, error)
+	ListSellerListings(ctx context.Context, arg ListSellerListingsParams) ([]Listing, error)
+	ListShopOffers(ctx context.Context, arg ListShopOffersParams) ([]ShopOffer, error)
+	ListSpendingCaps(ctx context.Context, arg ListSpendingCapsParams) ([]SpendingCap, error)
+	ListSubscriptions(ctx context.Context, arg ListSubscriptionsParams) ([]Subscription, error)
+	ListTradeEvents(ctx context.Context, threadID int64) ([]TradeEvent, error)
+	ListTradeItems(ctx context.Context, approvalID int64) ([]TradeItem, error)
+	RevealSeed(ctx context.Context, id int64) (Seed, error)
+	RevokeGacha(ctx context.Context, id int64) (Gacha, error)
+	SellListing(ctx context.Context, arg SellListingParams) (Listing, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdateAccountFlag(ctx context.Context, arg UpdateAccountFlagParams) (Account, error)
 	UpdateApprovalRequest(ctx context.Context, arg UpdateApprovalRequestParams) (Approval, error)
 	UpdateApprovalResponse(ctx context.Context, arg UpdateApprovalResponseParams) (Approval, error)
+	UpdateApprovalStatus(ctx context.Context, arg UpdateApprovalStatusParams) (Approval, error)
 	UpdateBalance(ctx context.Context, arg UpdateBalanceParams) (Account, error)
+	UpdateBanner(ctx context.Context, arg UpdateBannerParams) (Banner, error)
+	UpdateBannerItem(ctx context.Context, arg UpdateBannerItemParams) (BannerItem, error)
+	UpdateCoupon(ctx context.Context, arg UpdateCouponParams) (Coupon, error)
 	UpdateGallery(ctx context.Context, arg UpdateGalleryParams) (Gallery, error)
+	UpdateIdempotencyKeyResponse(ctx context.Context, arg UpdateIdempotencyKeyResponseParams) (IdempotencyKey, error)
 	UpdateItem(ctx context.Context, arg UpdateItemParams) (Item, error)
+	UpdateListingStatus(ctx context.Context, arg UpdateListingStatusParams) (Listing, error)
+	UpdateOrderProviderRef(ctx context.Context, arg UpdateOrderProviderRefParams) (Order, error)
+	UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (Order, error)
+	UpdatePullTickets(ctx context.Context, arg UpdatePullTicketsParams) (Account, error)
+	UpdateSeedNonce(ctx context.Context, arg UpdateSeedNonceParams) (Seed, error)
+	UpdateShards(ctx context.Context, arg UpdateShardsParams) (Account, error)
+	UpdateShopOffer(ctx context.Context, arg UpdateShopOfferParams) (ShopOffer, error)
+	UpdateSpendingCap(ctx context.Context, arg UpdateSpendingCapParams) (SpendingCap, error)
+	UpdateUserTimezone(ctx context.Context, arg UpdateUserTimezoneParams) (User, error)
+	UpsertBannerRate(ctx context.Context, arg UpsertBannerRateParams) (BannerRate, error)
+	UpsertLoginReward(ctx context.Context, arg UpsertLoginRewardParams) (LoginReward, error)
+	UpsertPity(ctx context.Context, arg UpsertPityParams) (Pity, error)
+	UpsertRarityRate(ctx context.Context, arg UpsertRarityRateParams) (RarityRate, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sRRRs-7/GachaPon/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsertRarityRate(t *testing.T) {
+	rate1, err := testQueries.GetRarityRate(context.Background(), 7)
+	require.NoError(t, err)
+	require.NotEmpty(t, rate1)
+
+	arg := UpsertRarityRateParams{
+		Rating: rate1.Rating,
+		Weight: utils.RandomInt(1, 1000),
+	}
+
+	rate2, err := testQueries.UpsertRarityRate(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, rate2)
+
+	require.Equal(t, arg.Rating, rate2.Rating)
+	require.Equal(t, arg.Weight, rate2.Weight)
+	require.NotZero(t, rate2.UpdatedAt)
+}
+
+func TestListRarityRates(t *testing.T) {
+	rates, err := testQueries.ListRarityRates(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, rates)
+
+	for i := 1; i < len(rates); i++ {
+		require.Less(t, rates[i-1].Rating, rates[i].Rating)
+	}
+}
@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var ErrAccountFlagged = errors.New("account is blocked until its debt is settled")
+
+type Reversal struct {
+	Account Account       `json:"account"`
+	Journal LedgerJournal `json:"journal"`
+	// Revoked are the pulls whose copies were taken back, latest first
+	Revoked []Gacha `json:"revoked"`
+	// Debt is the currency the account still owes, the negative paid balance
+	Debt int64 `json:"debt"`
+}
+
+// reverseOrder walks back the journal that credited an order, posting its
+// entries negated under the given reason. Currency already spent leaves the
+// paid bucket negative and flags the account, which blocks its pulls until a
+// later credit settles the debt.
+// With revoke set, the latest pulls paid since the credit lose their copies
+// and get their cost back first, until the debt is covered. Copies the account
+// no longer holds are skipped.
+func reverseOrder(ctx context.Context, q *Queries, order Order, reason string, revoke bool) (Reversal, error) {
+	var result Reversal
+
+	account, err := q.GetAccountForUpdate(ctx, order.AccountID)
+	if err != nil {
+		return result, err
+	}
+
+	credit, err := q.GetLedgerJournalByReference(ctx, GetLedgerJournalByReferenceParams{
+		Reason:      ReasonPurchase,
+		ReferenceID: ReferenceID(order.ID),
+	})
+	if err != nil {
+		return result, err
+	}
+	entries, err := q.ListLedgerEntriesByJournal(ctx, credit.ID)
+	if err != nil {
+		return result, err
+	}
+
+	var reversal postLedgerParams
+	for _, entry := range entries {
+		if entry.Book != BookAccounts {
+			continue
+		}
+		if entry.Bucket == BucketPaid {
+			reversal.Paid -= entry.Amount
+		} else {
+			reversal.Free -= entry.Amount
+		}
+	}
+
+	if revoke {
+		debt := -(account.PaidBalance + reversal.Paid)
+		if debt > 0 {
+			account, result.Revoked, err = revokePulls(ctx, q, account, order, credit.CreatedAt, debt)
+			if err != nil {
+				return result, err
+			}
+		}
+	}
+
+	reversal.AccountID = account.ID
+	reversal.Reason = reason
+	reversal.ReferenceID = ReferenceID(order.ID)
+	posted, err := postLedger(ctx, q, reversal)
+	if err != nil {
+		return result, err
+	}
+	result.Account = posted.Account
+	result.Journal = posted.Journal
+
+	if result.Account.PaidBalance < 0 {
+		result.Debt = -result.Account.PaidBalance
+		if !result.Account.FlaggedAt.Valid {
+			result.Account, err = q.UpdateAccountFlag(ctx, UpdateAccountFlagParams{
+				ID:        account.ID,
+				FlaggedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			})
+		}
+	}
+	return result, err
+}
+
+// revokePulls takes back the copies of the latest paid pulls of the account
+// since a time, until the paid amounts given back cover the debt
+func revokePulls(ctx context.Context, q *Queries, account Account, order Order, since time.Time, debt int64) (Account, []Gacha, error) {
+	gachas, err := q.ListRevocableGachas(ctx, ListRevocableGachasParams{
+		AccountID: account.ID,
+		CreatedAt: since,
+	})
+	if err != nil {
+		return account, nil, err
+	}
+
+	var revoked []Gacha
+	var refund Charge
+	for _, record := range gachas {
+		if refund.Paid >= debt {
+			break
+		}
+		if !record.GalleryID.Valid {
+			continue
+		}
+
+		// a revoked copy is taken out of the escrow of its listing, the listing
+		// of a copy traded away belonging to another seller. The account is
+		// locked already, the market locking it before its listings too.
+		err := q.CancelGalleryListing(ctx, CancelGalleryListingParams{
+			GalleryID: record.GalleryID,
+			SellerID:  account.ID,
+		})
+		if err != nil {
+			return account, nil, err
+		}
+
+		_, err = q.DeleteGallery(ctx, DeleteGalleryParams{
+			ID:      record.GalleryID.Int64,
+			OwnerID: account.ID,
+		})
+		if err == sql.ErrNoRows {
+			// traded away or converted into shards
+			continue
+		}
+		if err != nil {
+			return account, nil, err
+		}
+
+		record, err = q.RevokeGacha(ctx, record.ID)
+		if err != nil {
+			return account, nil, err
+		}
+		revoked = append(revoked, record)
+		refund.Paid += record.PaidAmount
+		refund.Free += record.FreeAmount
+	}
+	if len(revoked) == 0 {
+		return account, nil, nil
+	}
+
+	posted, err := postLedger(ctx, q, postLedgerParams{
+		AccountID:   account.ID,
+		Paid:        refund.Paid,
+		Free:        refund.Free,
+		Reason:      ReasonRevocation,
+		ReferenceID: ReferenceID(order.ID),
+	})
+	return posted.Account, revoked, err
+}
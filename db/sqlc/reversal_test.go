@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sRRRs-7/GachaPon/gacha"
+	"github.com/sRRRs-7/GachaPon/payments"
+	"github.com/stretchr/testify/require"
+)
+
+// creditTestOrder pays an order of a pack, crediting it to the account
+func creditTestOrder(t *testing.T, account Account, packID string) Order {
+	order := createTestOrder(t, account, packID)
+
+	result, err := NewStore(testDB).PaymentEventTx(context.Background(), PaymentEventTxParams{
+		OrderID: order.ID,
+		Event: payments.EventPaid,
+		ProviderRef: order.ProviderRef,
+	})
+	require.NoError(t, err)
+	require.Equal(t, payments.StatusCredited, result.Order.Status)
+	return result.Order
+}
+
+func chargeBack(t *testing.T, order Order, revoke bool) Reversal {
+	result, err := NewStore(testDB).PaymentEventTx(context.Background(), PaymentEventTxParams{
+		OrderID: order.ID,
+		Event: payments.EventChargeback,
+		ProviderRef: order.ProviderRef,
+		RevokeItems: revoke,
+	})
+	require.NoError(t, err)
+	require.True(t, result.Applied)
+	require.Equal(t, payments.StatusChargedBack, result.Order.Status)
+	require.NotNil(t, result.Reversal)
+	require.Equal(t, ReasonChargeback, result.Reversal.Journal.Reason)
+	return *result.Reversal
+}
+
+// paidPulls pulls count times from a new banner with paid currency only
+func paidPulls(t *testing.T, account Account, count int, cost int64) GachaTxResult {
+	banner := RandomCreateBanner(t)
+	bannerItem := RandomCreateBannerItem(t, banner)
+
+	result, err := NewStore(testDB).GachaTx(context.Background(), GachaTxParams{
+		AccountID: account.ID,
+		BannerID: banner.ID,
+		Owner: account.Owner,
+		Cost: cost,
+		SpendOrder: SpendPaidOnly,
+		Pull: gacha.Pull{Count: count},
+		Pool: randomBannerPool(t, bannerItem),
+	})
+	require.NoError(t, err)
+	return result
+}
+
+func TestReversalUnspent(t *testing.T) {
+	account := createLedgerAccount(t, 10)
+	order := creditTestOrder(t, account, "small")
+
+	reversal := chargeBack(t, order, false)
+	require.Equal(t, account.Balance, reversal.Account.Balance)
+	require.Zero(t, reversal.Account.PaidBalance)
+	require.Zero(t, reversal.Debt)
+	require.False(t, reversal.Account.FlaggedAt.Valid)
+	requireReconciled(t, account)
+
+	// the chargeback is applied once
+	_, err := NewStore(testDB).PaymentEventTx(context.Background(), PaymentEventTxParams{
+		OrderID: order.ID,
+		Event: payments.EventRefunded,
+		ProviderRef: order.ProviderRef,
+	})
+	require.ErrorIs(t, err, payments.ErrInvalidTransition)
+}
+
+func TestReversalDebt(t *testing.T) {
+	store := NewStore(testDB)
+	account := createLedgerAccount(t, 10)
+	order := creditTestOrder(t, account, "small")
+	paidPulls(t, account, 1, 80)
+
+	reversal := chargeBack(t, order, false)
+	require.Equal(t, int64(80), reversal.Debt)
+	require.Equal(t, int64(-80), reversal.Account.PaidBalance)
+	require.Equal(t, int64(10), reversal.Account.FreeBalance)
+	require.True(t, reversal.Account.FlaggedAt.Valid)
+	require.Empty(t, reversal.Revoked)
+	requireReconciled(t, account)
+
+	// a flagged account can't pull, even with free currency
+	banner := RandomCreateBanner(t)
+	_, err := store.GachaTx(context.Background(), GachaTxParams{
+		AccountID: account.ID,
+		BannerID: banner.ID,
+		Owner: account.Owner,
+		Cost: 10,
+		SpendOrder: SpendFreeFirst,
+		Pull: gacha.Pull{Count: 1},
+		Pool: randomBannerPool(t, RandomCreateBannerItem(t, banner)),
+	})
+	require.ErrorIs(t, err, ErrAccountFlagged)
+
+	// a purchase settles the debt
+	creditTestOrder(t, account, "small")
+	account1, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(20), account1.PaidBalance)
+	require.False(t, account1.FlaggedAt.Valid)
+	requireReconciled(t, account1)
+}
+
+func TestReversalRevokeItems(t *testing.T) {
+	account := createLedgerAccount(t, 0)
+	order := creditTestOrder(t, account, "small")
+	pulled := paidPulls(t, account, 3, 90)
+
+	reversal := chargeBack(t, order, true)
+
+	// the latest pulls are revoked until their cost covers the debt of 90
+	require.Len(t, reversal.Revoked, 3)
+	for i, record := range reversal.Revoked {
+		require.Equal(t, pulled.Gachas[2-i].ID, record.ID)
+		require.True(t, record.RevokedAt.Valid)
+
+		_, err := testQueries.GetGallery(context.Background(), pulled.Galleries[2-i].ID)
+		require.Error(t, err)
+	}
+	require.Zero(t, reversal.Debt)
+	require.Zero(t, reversal.Account.Balance)
+	require.False(t, reversal.Account.FlaggedAt.Valid)
+	requireReconciled(t, account)
+}
+
+func TestReversalRevokeTradedItem(t *testing.T) {
+	account := createLedgerAccount(t, 0)
+	order := creditTestOrder(t, account, "small")
+	pulled := paidPulls(t, account, 2, 60)
+
+	// the latest copy is gone, so the earlier one is revoked instead
+	_, err := testQueries.DeleteGallery(context.Background(), DeleteGalleryParams{
+		ID: pulled.Galleries[1].ID,
+		OwnerID: account.ID,
+	})
+	require.NoError(t, err)
+
+	reversal := chargeBack(t, order, true)
+	require.Len(t, reversal.Revoked, 1)
+	require.Equal(t, pulled.Gachas[0].ID, reversal.Revoked[0].ID)
+	require.Equal(t, int64(30), reversal.Debt)
+	require.True(t, reversal.Account.FlaggedAt.Valid)
+	requireReconciled(t, account)
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSeed(t *testing.T) {
+	account := RandomCreateAccount(t)
+	seed1 := createTestSeed(t, account, func(float64) bool { return true })
+
+	seed2, err := testQueries.GetSeed(context.Background(), seed1.ID)
+	require.NoError(t, err)
+	require.Equal(t, seed1, seed2)
+}
+
+func TestUpdateSeedNonce(t *testing.T) {
+	account := RandomCreateAccount(t)
+	seed1 := createTestSeed(t, account, func(float64) bool { return true })
+
+	arg := UpdateSeedNonceParams{
+		ID: seed1.ID,
+		Nonce: 10,
+	}
+
+	seed2, err := testQueries.UpdateSeedNonce(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, seed1.Nonce+arg.Nonce, seed2.Nonce)
+}
+
+func TestRevealSeed(t *testing.T) {
+	account := RandomCreateAccount(t)
+	seed1 := createTestSeed(t, account, func(float64) bool { return true })
+
+	seed2, err := testQueries.RevealSeed(context.Background(), seed1.ID)
+	require.NoError(t, err)
+	require.True(t, seed2.RevealedAt.Valid)
+
+	_, err = testQueries.GetActiveSeed(context.Background(), account.ID)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	// a seed is revealed only once
+	_, err = testQueries.RevealSeed(context.Background(), seed1.ID)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
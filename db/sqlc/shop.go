@@ -0,0 +1,201 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/sRRRs-7/GachaPon/gacha"
+	"github.com/sRRRs-7/GachaPon/shop"
+)
+
+var ErrOfferInactive = errors.New("shop offer is not on sale")
+var ErrOutOfStock = errors.New("shop offer is out of stock")
+var ErrOfferLimitReached = errors.New("shop offer was already bought up to its limit")
+
+// OfferActive reports whether an offer is on sale at now
+func OfferActive(offer ShopOffer, now time.Time) bool {
+	return !now.Before(offer.StartAt) && now.Before(offer.EndAt)
+}
+
+// ShopPurchaseTxParams contains the input parameters of the shop purchase transaction
+type ShopPurchaseTxParams struct {
+	AccountID int64     `json:"account_id"`
+	Owner     string    `json:"owner"`
+	OfferID   int64     `json:"offer_id"`
+	Now       time.Time `json:"now"`
+}
+
+type ShopPurchaseTxResult struct {
+	Account  Account      `json:"account"`
+	Offer    ShopOffer    `json:"offer"`
+	Purchase ShopPurchase `json:"purchase"`
+	Gallery  Gallery      `json:"gallery"`
+	Charge   Charge       `json:"charge"`
+}
+
+// ShopPurchaseTx sells a copy of the item of an offer to an account, charging
+// its price through the ledger in the spend order of the offer like a pull.
+// The account and offer rows stay locked until the transaction ends, so
+// concurrent purchases can neither spend the same balance twice nor sell past
+// the stock or the per user limit of the offer.
+func (s *SQLStore) ShopPurchaseTx(ctx context.Context, arg ShopPurchaseTxParams) (ShopPurchaseTxResult, error) {
+	var result ShopPurchaseTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if account.Owner != arg.Owner {
+			return ErrAccountNotOwned
+		}
+		if account.FlaggedAt.Valid {
+			return ErrAccountFlagged
+		}
+		result.Account = account
+
+		offer, err := q.GetShopOfferForUpdate(ctx, arg.OfferID)
+		if err != nil {
+			return err
+		}
+		if !OfferActive(offer, arg.Now) {
+			return ErrOfferInactive
+		}
+		if offer.Stock.Valid && offer.Sold >= offer.Stock.Int64 {
+			return ErrOutOfStock
+		}
+		if offer.PerUserLimit.Valid {
+			bought, err := q.CountShopPurchases(ctx, CountShopPurchasesParams{
+				OfferID: offer.ID,
+				Owner:   account.Owner,
+			})
+			if err != nil {
+				return err
+			}
+			if bought >= int64(offer.PerUserLimit.Int32) {
+				return ErrOfferLimitReached
+			}
+		}
+
+		result.Charge, err = splitCost(account, offer.SpendOrder, offer.Price)
+		if err != nil {
+			return err
+		}
+		if result.Charge.Paid > 0 {
+			spending, err := monthlySpending(ctx, q, account, arg.Now)
+			if err != nil {
+				return err
+			}
+			if !spending.AllowsSpend(result.Charge.Paid) {
+				return ErrSpendingCapExceeded
+			}
+		}
+
+		result.Offer, err = q.AddShopOfferSale(ctx, offer.ID)
+		if err != nil {
+			return err
+		}
+
+		result.Gallery, err = q.CreateGallery(ctx, CreateGalleryParams{
+			OwnerID: account.ID,
+			ItemID:  offer.ItemID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Purchase, err = q.CreateShopPurchase(ctx, CreateShopPurchaseParams{
+			OfferID:    offer.ID,
+			AccountID:  sql.NullInt64{Int64: account.ID, Valid: true},
+			Owner:      account.Owner,
+			GalleryID:  sql.NullInt64{Int64: result.Gallery.ID, Valid: true},
+			PaidAmount: result.Charge.Paid,
+			FreeAmount: result.Charge.Free,
+		})
+		if err != nil {
+			return err
+		}
+
+		if offer.Price == 0 {
+			return nil
+		}
+
+		posted, err := postLedger(ctx, q, postLedgerParams{
+			AccountID:   account.ID,
+			Paid:        -result.Charge.Paid,
+			Free:        -result.Charge.Free,
+			Reason:      ReasonShopPurchase,
+			ReferenceID: ReferenceID(result.Purchase.ID),
+		})
+		result.Account = posted.Account
+		return err
+	})
+
+	return result, err
+}
+
+// RotateShopTxParams contains the input parameters of the shop rotation transaction
+type RotateShopTxParams struct {
+	// Day is the day of the rotation, its offers are on sale from its midnight UTC for a day
+	Day    time.Time    `json:"day"`
+	Size   int          `json:"size"`
+	Source gacha.Source `json:"-"`
+}
+
+type RotateShopTxResult struct {
+	Offers []ShopOffer `json:"offers"`
+	// Created is false when the day was already rotated, Offers being its offers
+	Created bool `json:"created"`
+}
+
+// RotateShopTx picks the offers of a day from every item that can be sold.
+// A day is rotated once, concurrent rotations of a day wait for the first one
+// and return its offers.
+func (s *SQLStore) RotateShopTx(ctx context.Context, arg RotateShopTxParams) (RotateShopTxResult, error) {
+	var result RotateShopTxResult
+
+	day := shop.Day(arg.Day)
+	rotationDay := sql.NullTime{Time: day, Valid: true}
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		_, err := q.CreateShopRotation(ctx, day)
+		if err == sql.ErrNoRows {
+			result.Offers, err = q.ListRotationShopOffers(ctx, rotationDay)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		result.Created = true
+
+		rows, err := q.ListRotationCandidates(ctx)
+		if err != nil {
+			return err
+		}
+		candidates := make([]shop.Candidate, len(rows))
+		for i, row := range rows {
+			candidates[i] = shop.Candidate{ItemID: row.ID, Rating: row.Rating}
+		}
+
+		for _, picked := range shop.Rotate(candidates, arg.Size, arg.Source) {
+			offer, err := q.CreateShopOffer(ctx, CreateShopOfferParams{
+				ItemID:       picked.ItemID,
+				Price:        picked.Price,
+				SpendOrder:   SpendFreeFirst,
+				PerUserLimit: sql.NullInt32{Int32: shop.RotationPerUserLimit, Valid: true},
+				StartAt:      day,
+				EndAt:        day.AddDate(0, 0, 1),
+				RotationDay:  rotationDay,
+			})
+			if err != nil {
+				return err
+			}
+			result.Offers = append(result.Offers, offer)
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,211 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/sRRRs-7/GachaPon/shop"
+	"github.com/sRRRs-7/GachaPon/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func RandomCreateShopOffer(t *testing.T, price int64, stock int64, perUserLimit int32) ShopOffer {
+	item := RandomCreateItem(t)
+
+	arg := CreateShopOfferParams{
+		ItemID: item.ID,
+		Price: price,
+		SpendOrder: SpendFreeFirst,
+		Stock: sql.NullInt64{Int64: stock, Valid: stock > 0},
+		PerUserLimit: sql.NullInt32{Int32: perUserLimit, Valid: perUserLimit > 0},
+		StartAt: time.Now().Add(-time.Hour),
+		EndAt: time.Now().Add(time.Hour),
+	}
+
+	offer, err := testQueries.CreateShopOffer(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, offer)
+
+	require.Equal(t, arg.ItemID, offer.ItemID)
+	require.Equal(t, arg.Price, offer.Price)
+	require.Equal(t, arg.Stock, offer.Stock)
+	require.Equal(t, arg.PerUserLimit, offer.PerUserLimit)
+	require.Zero(t, offer.Sold)
+
+	return offer
+}
+
+func buyOffer(account Account, offer ShopOffer) (ShopPurchaseTxResult, error) {
+	return NewStore(testDB).ShopPurchaseTx(context.Background(), ShopPurchaseTxParams{
+		AccountID: account.ID,
+		Owner: account.Owner,
+		OfferID: offer.ID,
+		Now: time.Now(),
+	})
+}
+
+func TestShopPurchaseTx(t *testing.T) {
+	offer := RandomCreateShopOffer(t, 300, 0, 0)
+	account := createLedgerAccount(t, 1000)
+
+	result, err := buyOffer(account, offer)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), result.Offer.Sold)
+	require.Equal(t, int64(700), result.Account.Balance)
+	require.Equal(t, Charge{Free: 300}, result.Charge)
+
+	require.Equal(t, account.ID, result.Gallery.OwnerID)
+	require.Equal(t, offer.ItemID, result.Gallery.ItemID)
+	require.Equal(t, result.Gallery.ID, result.Purchase.GalleryID.Int64)
+	require.Equal(t, account.Owner, result.Purchase.Owner)
+	require.Equal(t, int64(300), result.Purchase.FreeAmount)
+	requireReconciled(t, account)
+
+	journal, err := testQueries.GetLedgerJournalByReference(context.Background(), GetLedgerJournalByReferenceParams{
+		Reason: ReasonShopPurchase,
+		ReferenceID: ReferenceID(result.Purchase.ID),
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, journal)
+}
+
+func TestShopPurchaseTxInsufficientBalance(t *testing.T) {
+	offer := RandomCreateShopOffer(t, 300, 0, 0)
+	account := createLedgerAccount(t, 100)
+
+	_, err := buyOffer(account, offer)
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+
+	offer1, err := testQueries.GetShopOffer(context.Background(), offer.ID)
+	require.NoError(t, err)
+	require.Zero(t, offer1.Sold)
+}
+
+func TestShopPurchaseTxInactive(t *testing.T) {
+	offer := RandomCreateShopOffer(t, 100, 0, 0)
+	account := createLedgerAccount(t, 1000)
+
+	_, err := testQueries.UpdateShopOffer(context.Background(), UpdateShopOfferParams{
+		ID: offer.ID,
+		Price: offer.Price,
+		SpendOrder: offer.SpendOrder,
+		StartAt: time.Now().Add(-2 * time.Hour),
+		EndAt: time.Now().Add(-time.Hour),
+	})
+	require.NoError(t, err)
+
+	_, err = buyOffer(account, offer)
+	require.ErrorIs(t, err, ErrOfferInactive)
+}
+
+func TestShopPurchaseTxPerUserLimit(t *testing.T) {
+	offer := RandomCreateShopOffer(t, 100, 0, 1)
+	account := createLedgerAccount(t, 1000)
+
+	_, err := buyOffer(account, offer)
+	require.NoError(t, err)
+
+	_, err = buyOffer(account, offer)
+	require.ErrorIs(t, err, ErrOfferLimitReached)
+
+	// the limit holds for every account of the user, other users can still buy
+	_, err = buyOffer(createLedgerAccount(t, 1000), offer)
+	require.NoError(t, err)
+}
+
+func TestShopPurchaseTxSpendingCap(t *testing.T) {
+	account := createCappedAccount(t, 15, 50)
+	creditTestOrder(t, account, "small")
+
+	offer := RandomCreateShopOffer(t, 100, 0, 0)
+	_, err := testQueries.UpdateShopOffer(context.Background(), UpdateShopOfferParams{
+		ID: offer.ID,
+		Price: offer.Price,
+		SpendOrder: SpendPaidOnly,
+		StartAt: offer.StartAt,
+		EndAt: offer.EndAt,
+	})
+	require.NoError(t, err)
+
+	_, err = buyOffer(account, offer)
+	require.ErrorIs(t, err, ErrSpendingCapExceeded)
+}
+
+func TestShopPurchaseTxFlagged(t *testing.T) {
+	account := createLedgerAccount(t, 1000)
+	_, err := testQueries.UpdateAccountFlag(context.Background(), UpdateAccountFlagParams{
+		ID: account.ID,
+		FlaggedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	require.NoError(t, err)
+
+	_, err = buyOffer(account, RandomCreateShopOffer(t, 100, 0, 0))
+	require.ErrorIs(t, err, ErrAccountFlagged)
+}
+
+func TestShopPurchaseTxConcurrent(t *testing.T) {
+	n := 10
+	stock := 3
+	offer := RandomCreateShopOffer(t, 100, int64(stock), 0)
+
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		account := createLedgerAccount(t, 1000)
+		go func() {
+			_, err := buyOffer(account, offer)
+			errs <- err
+		}()
+	}
+
+	sold := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			sold++
+			continue
+		}
+		require.ErrorIs(t, err, ErrOutOfStock)
+	}
+	require.Equal(t, stock, sold)
+
+	offer1, err := testQueries.GetShopOffer(context.Background(), offer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(stock), offer1.Sold)
+}
+
+func TestRotateShopTx(t *testing.T) {
+	store := NewStore(testDB)
+	for i := 0; i < shop.RotationSize; i++ {
+		RandomCreateItem(t)
+	}
+
+	// a day of its own, far from the days other runs rotated
+	day := time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(utils.RandomInt(0, 100000)))
+	arg := RotateShopTxParams{
+		Day: day.Add(13 * time.Hour),
+		Size: shop.RotationSize,
+		Source: rand.New(rand.NewSource(1)),
+	}
+
+	result, err := store.RotateShopTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.Created)
+	require.Len(t, result.Offers, shop.RotationSize)
+
+	for _, offer := range result.Offers {
+		require.True(t, day.Equal(offer.StartAt))
+		require.True(t, day.AddDate(0, 0, 1).Equal(offer.EndAt))
+		require.Equal(t, int32(shop.RotationPerUserLimit), offer.PerUserLimit.Int32)
+		require.False(t, offer.Stock.Valid)
+		require.True(t, offer.RotationDay.Valid)
+	}
+
+	// the day is generated once
+	result1, err := store.RotateShopTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, result1.Created)
+	require.ElementsMatch(t, result.Offers, result1.Offers)
+}
@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var ErrSpendingCapExceeded = errors.New("monthly spending cap exceeded")
+
+// Spending is the money an account paid for packs and the paid currency it spent
+// since the start of the month, against the monthly cap of the age band of its
+// owner. The cap is set in the currency of the pack prices (JPY) and checked
+// against the orders, and is read as the same number of paid currency units for
+// the spending, a paid unit costing about one yen. The two are separate limits,
+// a minor can both buy and spend up to the cap in a month.
+type Spending struct {
+	AccountID int64     `json:"account_id"`
+	Region    string    `json:"region"`
+	Age       int32     `json:"age"`
+	Since     time.Time `json:"since"`
+	// Capped is false when no cap applies to the owner, Limit is 0 then
+	Capped bool  `json:"capped"`
+	Limit  int64 `json:"limit"`
+	// Purchased is the price of the orders of the month that didn't fail and
+	// weren't refunded, in the currency of the packs
+	Purchased int64 `json:"purchased"`
+	// Spent is the paid currency units taken by pulls, shop purchases and passes
+	// during the month
+	Spent int64 `json:"spent"`
+}
+
+// AllowsPurchase reports whether an order of price keeps the purchases under the cap
+func (s Spending) AllowsPurchase(price int64) bool {
+	return !s.Capped || s.Purchased+price <= s.Limit
+}
+
+// AllowsSpend reports whether spending amount of paid currency keeps the
+// spending under the cap, read as a limit in currency units
+func (s Spending) AllowsSpend(amount int64) bool {
+	return !s.Capped || s.Spent+amount <= s.Limit
+}
+
+// MonthStart returns the start of the calendar month of t in UTC
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Age returns the age in full years at t of someone born on birthdate,
+// an unknown birthdate is age 0 so that it falls in the youngest band
+func Age(birthdate sql.NullTime, t time.Time) int32 {
+	if !birthdate.Valid {
+		return 0
+	}
+
+	born := birthdate.Time
+	age := t.Year() - born.Year()
+	if t.Month() < born.Month() || (t.Month() == born.Month() && t.Day() < born.Day()) {
+		age--
+	}
+	if age < 0 {
+		return 0
+	}
+	return int32(age)
+}
+
+// monthlySpending reads the spending of the account at now.
+// Callers about to add to it lock the account row first, so the totals can't
+// move before their own order or pull is written.
+func monthlySpending(ctx context.Context, q *Queries, account Account, now time.Time) (Spending, error) {
+	user, err := q.GetUser(ctx, account.Owner)
+	if err != nil {
+		return Spending{}, err
+	}
+
+	spending := Spending{
+		AccountID: account.ID,
+		Region:    user.Region,
+		Age:       Age(user.Birthdate, now),
+		Since:     MonthStart(now),
+	}
+
+	band, err := q.GetSpendingCap(ctx, GetSpendingCapParams{
+		Region: spending.Region,
+		Age:    spending.Age,
+	})
+	switch {
+	case err == nil:
+		spending.Capped = band.MonthlyLimit.Valid
+		spending.Limit = band.MonthlyLimit.Int64
+	case err != sql.ErrNoRows:
+		return spending, err
+	}
+
+	spending.Purchased, err = q.GetMonthlyPurchases(ctx, GetMonthlyPurchasesParams{
+		AccountID: account.ID,
+		CreatedAt: spending.Since,
+	})
+	if err != nil {
+		return spending, err
+	}
+
+	spending.Spent, err = q.GetMonthlyPaidSpend(ctx, GetMonthlyPaidSpendParams{
+		AccountID: account.ID,
+		CreatedAt: spending.Since,
+	})
+	if err != nil {
+		return spending, err
+	}
+
+	bought, err := q.GetMonthlyShopPaidSpend(ctx, GetMonthlyShopPaidSpendParams{
+		AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+		CreatedAt: spending.Since,
+	})
+	if err != nil {
+		return spending, err
+	}
+	spending.Spent += bought
+
+	passes, err := q.GetMonthlySubscriptionPaidSpend(ctx, GetMonthlySubscriptionPaidSpendParams{
+		AccountID: account.ID,
+		CreatedAt: spending.Since,
+	})
+	spending.Spent += passes
+	return spending, err
+}
+
+// MonthlySpending returns the month to date spending of an account
+func (s *SQLStore) MonthlySpending(ctx context.Context, accountID int64) (Spending, error) {
+	account, err := s.GetAccount(ctx, accountID)
+	if err != nil {
+		return Spending{}, err
+	}
+	return monthlySpending(ctx, s.Queries, account, time.Now())
+}
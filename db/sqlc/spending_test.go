@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/sRRRs-7/GachaPon/gacha"
+	"github.com/sRRRs-7/GachaPon/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// createCappedAccount opens an account for a user of the given age in a region
+// of its own, capped at limit for minors and uncapped from 20
+func createCappedAccount(t *testing.T, age int, limit int64) Account {
+	region := utils.RandomString(8)
+	for _, band := range []CreateSpendingCapParams{
+		{Region: region, MinAge: 0, MonthlyLimit: sql.NullInt64{Int64: limit, Valid: true}},
+		{Region: region, MinAge: 20},
+	} {
+		_, err := testQueries.CreateSpendingCap(context.Background(), band)
+		require.NoError(t, err)
+	}
+
+	hashPassword, err := utils.HashedPassword(utils.RandomString(6))
+	require.NoError(t, err)
+
+	user, err := testQueries.CreateUser(context.Background(), CreateUserParams{
+		UserName: utils.RandomString(6),
+		HashPassword: hashPassword,
+		FullName: utils.RandomString(4),
+		Email: utils.RandomEmail(),
+		Birthdate: sql.NullTime{Time: time.Now().AddDate(-age, 0, -1), Valid: true},
+		Region: region,
+	})
+	require.NoError(t, err)
+
+	account, err := NewStore(testDB).CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner: user.UserName,
+		FreeBalance: 1000,
+	})
+	require.NoError(t, err)
+	return account
+}
+
+func TestAge(t *testing.T) {
+	now := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	born := func(y int, m time.Month, d int) sql.NullTime {
+		return sql.NullTime{Time: time.Date(y, m, d, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+
+	require.Equal(t, int32(16), Age(born(2008, 4, 1), now))
+	require.Equal(t, int32(15), Age(born(2008, 4, 2), now))
+	require.Equal(t, int32(15), Age(born(2008, 12, 31), now))
+	require.Zero(t, Age(sql.NullTime{}, now))
+	require.Zero(t, Age(born(2030, 1, 1), now))
+}
+
+func TestMonthStart(t *testing.T) {
+	now := time.Date(2024, 2, 29, 23, 59, 0, 0, time.FixedZone("JST", 9*60*60))
+	require.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), MonthStart(now))
+}
+
+func TestCreateOrderTxSpendingCap(t *testing.T) {
+	store := NewStore(testDB)
+	account := createCappedAccount(t, 15, 1000)
+
+	arg := CreateOrderParams{
+		AccountID: account.ID,
+		PackID: "medium",
+		Amount: 550,
+		Price: 610,
+		Currency: "JPY",
+		Provider: "fake",
+	}
+
+	order, err := store.CreateOrderTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	_, err = store.CreateOrderTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrSpendingCapExceeded)
+
+	spending, err := store.MonthlySpending(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.True(t, spending.Capped)
+	require.Equal(t, int32(15), spending.Age)
+	require.Equal(t, int64(1000), spending.Limit)
+	require.Equal(t, int64(610), spending.Purchased)
+
+	// a failed order gives its share of the cap back
+	_, err = testQueries.UpdateOrderStatus(context.Background(), UpdateOrderStatusParams{
+		ID: order.ID,
+		Status: "failed",
+	})
+	require.NoError(t, err)
+
+	_, err = store.CreateOrderTx(context.Background(), arg)
+	require.NoError(t, err)
+}
+
+func TestCreateOrderTxUncapped(t *testing.T) {
+	store := NewStore(testDB)
+	account := createCappedAccount(t, 30, 1000)
+
+	for i := 0; i < 3; i++ {
+		_, err := store.CreateOrderTx(context.Background(), CreateOrderParams{
+			AccountID: account.ID,
+			PackID: "medium",
+			Amount: 550,
+			Price: 610,
+			Currency: "JPY",
+			Provider: "fake",
+		})
+		require.NoError(t, err)
+	}
+
+	spending, err := store.MonthlySpending(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.False(t, spending.Capped)
+	require.Equal(t, int64(1830), spending.Purchased)
+}
+
+func TestGachaTxSpendingCap(t *testing.T) {
+	store := NewStore(testDB)
+	account := createCappedAccount(t, 12, 50)
+	banner := RandomCreateBanner(t)
+	bannerItem := RandomCreateBannerItem(t, banner)
+
+	_, err := store.LedgerTx(context.Background(), LedgerTxParams{
+		AccountID: account.ID,
+		Amount: 100,
+		Reason: ReasonPurchase,
+	})
+	require.NoError(t, err)
+
+	arg := GachaTxParams{
+		AccountID: account.ID,
+		BannerID: banner.ID,
+		Owner: account.Owner,
+		Cost: 40,
+		SpendOrder: SpendPaidOnly,
+		Pull: gacha.Pull{Count: 1},
+		Pool: randomBannerPool(t, bannerItem),
+	}
+
+	_, err = store.GachaTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	_, err = store.GachaTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrSpendingCapExceeded)
+
+	// free currency isn't capped
+	arg.SpendOrder = SpendFreeFirst
+	_, err = store.GachaTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	spending, err := store.MonthlySpending(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(40), spending.Spent)
+}
@@ -3,13 +3,55 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
+
+	"github.com/lib/pq"
+	"github.com/sRRRs-7/GachaPon/gacha"
+)
+
+var ErrAccountNotOwned = errors.New("account doesn't belong to the authenticated user")
+var ErrInsufficientBalance = errors.New("insufficient balance")
+var ErrInsufficientShards = errors.New("insufficient shards")
+var ErrInsufficientTickets = errors.New("insufficient pull tickets")
+
+// errors of the exchanges
+var (
+	ErrItemNotOwned = errors.New("item copy doesn't belong to its sender")
+	ErrItemLocked   = errors.New("item copy is locked by another trade")
+	ErrSameAccount  = errors.New("item copies can't be exchanged within the same account")
+	ErrInvalidTrade = errors.New("each side of a trade has to give distinct copies or currency")
 )
 
 type Store interface {
 	Querier
 	ExchangeTx(ctx context.Context, arg ExchangeTxParams) (ExchangeTxResult, error)
+	GachaTx(ctx context.Context, arg GachaTxParams) (GachaTxResult, error)
+	RotateSeedTx(ctx context.Context, arg RotateSeedTxParams) (RotateSeedTxResult, error)
+	ConvertTx(ctx context.Context, arg ConvertTxParams) (ConvertTxResult, error)
+	SparkTx(ctx context.Context, arg SparkTxParams) (SparkTxResult, error)
+	LedgerTx(ctx context.Context, arg LedgerTxParams) (LedgerTxResult, error)
+	CreateAccountTx(ctx context.Context, arg CreateAccountParams) (Account, error)
+	ReconcileAccount(ctx context.Context, accountID int64) (ReconcileResult, error)
+	PaymentEventTx(ctx context.Context, arg PaymentEventTxParams) (PaymentEventTxResult, error)
+	CreateOrderTx(ctx context.Context, arg CreateOrderParams) (Order, error)
+	MonthlySpending(ctx context.Context, accountID int64) (Spending, error)
+	DailyClaimTx(ctx context.Context, arg DailyClaimTxParams) (DailyClaimTxResult, error)
+	RedeemCouponTx(ctx context.Context, arg RedeemCouponTxParams) (RedeemCouponTxResult, error)
+	ShopPurchaseTx(ctx context.Context, arg ShopPurchaseTxParams) (ShopPurchaseTxResult, error)
+	RotateShopTx(ctx context.Context, arg RotateShopTxParams) (RotateShopTxResult, error)
+	SubscribeTx(ctx context.Context, arg SubscribeTxParams) (SubscribeTxResult, error)
+	SubscriptionClaimTx(ctx context.Context, arg SubscriptionClaimTxParams) (SubscriptionClaimTxResult, error)
+	ApproveTradeTx(ctx context.Context, arg ApproveTradeTxParams) (ApproveTradeTxResult, error)
+	CreateTradeOfferTx(ctx context.Context, arg CreateTradeOfferTxParams) (TradeOffer, error)
+	CloseTradeOfferTx(ctx context.Context, arg CloseTradeOfferTxParams) (Approval, error)
+	CounterTradeOfferTx(ctx context.Context, arg CounterTradeOfferTxParams) (TradeOffer, error)
+	ExpireTradeOffersTx(ctx context.Context, now time.Time) ([]Approval, error)
+	CreateListingTx(ctx context.Context, arg CreateListingTxParams) (Listing, error)
+	CancelListingTx(ctx context.Context, arg CancelListingTxParams) (Listing, error)
+	BuyListingTx(ctx context.Context, arg BuyListingTxParams) (BuyListingTxResult, error)
 }
 
 type SQLStore struct {
@@ -47,64 +89,592 @@ func (s *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
 type ExchangeTxParams struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
-	ItemID1       int64 `json:"item_id_1"`
-	ItemID2       int64 `json:"item_id_2"`
+	// FromGalleryIDs are the copies the from account gives, ToGalleryIDs the ones it gets
+	FromGalleryIDs []int64 `json:"from_gallery_ids"`
+	ToGalleryIDs   []int64 `json:"to_gallery_ids"`
+	// FromAmount and ToAmount are the free currency each account adds to its copies
+	FromAmount int64 `json:"from_amount"`
+	ToAmount   int64 `json:"to_amount"`
+	// ReferenceID is the id the ledger journals of the currency refer to
+	ReferenceID string `json:"reference_id"`
 }
 
 type ExchangeTxResult struct {
-	Exchange1    Exchange `json:"exchange_1"`
-	Exchange2    Exchange `json:"exchange_2"`
-	Gallery1	 Gallery  `json:"gallery_1"`
-	Gallery2	 Gallery  `json:"gallery_2"`
+	FromAccount Account `json:"from_account"`
+	ToAccount   Account `json:"to_account"`
+	// Galleries and Exchanges follow the copies of the from account, then the ones of the to account
+	Galleries []Gallery  `json:"galleries"`
+	Exchanges []Exchange `json:"exchanges"`
 }
 
+// ExchangeTx swaps item copies and currency between accounts.
+// A copy that doesn't belong to its sender fails the transaction with ErrItemNotOwned,
+// a copy another trade holds the lock of fails it with ErrItemLocked and a copy
+// held in escrow by a market listing fails it with ErrItemListed.
 func (s *SQLStore) ExchangeTx(ctx context.Context, arg ExchangeTxParams) (ExchangeTxResult, error) {
 	var result ExchangeTxResult
 
 	err := s.execTx(ctx, func(q *Queries) error {
 		var err error
+		result, err = exchange(ctx, q, arg)
+		return err
+	})
 
-		result.Exchange1, err = q.CreateExchange(ctx, CreateExchangeParams{
-			FromAccountID: arg.FromAccountID,
-			ToAccountID:   arg.ToAccountID,
-			ItemID:        arg.ItemID1,
+	return result, err
+}
+
+// exchange swaps item copies and currency inside the transaction of q
+func exchange(ctx context.Context, q *Queries, arg ExchangeTxParams) (ExchangeTxResult, error) {
+	var result ExchangeTxResult
+
+	owners, err := tradeOwners(arg)
+	if err != nil {
+		return result, err
+	}
+
+	// lock the accounts, then the copies, in the order of their ids, so opposite
+	// trades can't deadlock, and check everything before anything is written
+	accounts := map[int64]*Account{
+		arg.FromAccountID: &result.FromAccount,
+		arg.ToAccountID:   &result.ToAccount,
+	}
+	for _, id := range sortedIDs([]int64{arg.FromAccountID, arg.ToAccountID}) {
+		*accounts[id], err = q.GetAccountForUpdate(ctx, id)
+		if err != nil {
+			return result, err
+		}
+	}
+	if err := checkTradeAmount(result.FromAccount, arg.FromAmount); err != nil {
+		return result, err
+	}
+	if err := checkTradeAmount(result.ToAccount, arg.ToAmount); err != nil {
+		return result, err
+	}
+
+	ids := make([]int64, 0, len(owners))
+	for id := range owners {
+		ids = append(ids, id)
+	}
+	for _, id := range sortedIDs(ids) {
+		gallery, err := lockGallery(ctx, q, id)
+		if err != nil {
+			return result, err
+		}
+		if gallery.OwnerID != owners[id] {
+			return result, ErrItemNotOwned
+		}
+		if err := checkNotListed(ctx, q, id); err != nil {
+			return result, err
+		}
+	}
+
+	now := time.Now()
+	for _, side := range []struct {
+		from, to   int64
+		galleryIDs []int64
+	}{
+		{arg.FromAccountID, arg.ToAccountID, arg.FromGalleryIDs},
+		{arg.ToAccountID, arg.FromAccountID, arg.ToGalleryIDs},
+	} {
+		for _, id := range side.galleryIDs {
+			gallery, err := q.UpdateGallery(ctx, UpdateGalleryParams{
+				ID:         id,
+				OwnerID:    side.from,
+				OwnerID_2:  side.to,
+				ExchangeAt: now,
+			})
+			if err != nil {
+				return result, err
+			}
+			result.Galleries = append(result.Galleries, gallery)
+
+			exchanged, err := q.CreateExchange(ctx, CreateExchangeParams{
+				FromAccountID: side.from,
+				ToAccountID:   side.to,
+				ItemID:        gallery.ItemID,
+				GalleryID:     sql.NullInt64{Int64: gallery.ID, Valid: true},
+			})
+			if err != nil {
+				return result, err
+			}
+			result.Exchanges = append(result.Exchanges, exchanged)
+		}
+	}
+
+	// the currency passes through the trades book, which every exchange leaves at 0
+	for _, leg := range []struct {
+		from, to *Account
+		amount   int64
+	}{
+		{&result.FromAccount, &result.ToAccount, arg.FromAmount},
+		{&result.ToAccount, &result.FromAccount, arg.ToAmount},
+	} {
+		if leg.amount == 0 {
+			continue
+		}
+
+		posted, err := postLedger(ctx, q, postLedgerParams{
+			AccountID:   leg.from.ID,
+			Free:        -leg.amount,
+			Reason:      ReasonTrade,
+			ReferenceID: arg.ReferenceID,
 		})
+		if err != nil {
+			return result, err
+		}
+		*leg.from = posted.Account
+
+		posted, err = postLedger(ctx, q, postLedgerParams{
+			AccountID:   leg.to.ID,
+			Free:        leg.amount,
+			Reason:      ReasonTrade,
+			ReferenceID: arg.ReferenceID,
+		})
+		if err != nil {
+			return result, err
+		}
+		*leg.to = posted.Account
+	}
+
+	return result, nil
+}
+
+// tradeOwners maps every copy of a trade to the account giving it. Each side
+// has to give copies or currency, and a copy can only be given once.
+func tradeOwners(arg ExchangeTxParams) (map[int64]int64, error) {
+	if arg.FromAccountID == arg.ToAccountID {
+		return nil, ErrSameAccount
+	}
+	if arg.FromAmount < 0 || arg.ToAmount < 0 {
+		return nil, ErrInvalidTrade
+	}
+	if len(arg.FromGalleryIDs) == 0 && arg.FromAmount == 0 || len(arg.ToGalleryIDs) == 0 && arg.ToAmount == 0 {
+		return nil, ErrInvalidTrade
+	}
+
+	owners := make(map[int64]int64)
+	for _, side := range []struct {
+		accountID  int64
+		galleryIDs []int64
+	}{
+		{arg.FromAccountID, arg.FromGalleryIDs},
+		{arg.ToAccountID, arg.ToGalleryIDs},
+	} {
+		for _, id := range side.galleryIDs {
+			if _, ok := owners[id]; ok {
+				return nil, ErrInvalidTrade
+			}
+			owners[id] = side.accountID
+		}
+	}
+	return owners, nil
+}
+
+// checkTradeAmount checks the account can give an amount of free currency in a trade,
+// a flagged account keeping its currency until its debt is settled
+func checkTradeAmount(account Account, amount int64) error {
+	if amount == 0 {
+		return nil
+	}
+	if account.FlaggedAt.Valid {
+		return ErrAccountFlagged
+	}
+	if account.FreeBalance < amount {
+		return ErrInsufficientBalance
+	}
+	return nil
+}
+
+func sortedIDs(ids []int64) []int64 {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// lockGallery locks a copy for a trade without waiting for the lock of another one
+func lockGallery(ctx context.Context, q *Queries, id int64) (Gallery, error) {
+	gallery, err := q.GetGalleryForUpdateNowait(ctx, id)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "lock_not_available" {
+		return gallery, ErrItemLocked
+	}
+	return gallery, err
+}
+
+// GachaTxParams contains the input parameters of the gacha transaction
+type GachaTxParams struct {
+	AccountID  int64        `json:"account_id"`
+	BannerID   int64        `json:"banner_id"`
+	Owner      string       `json:"owner"`
+	Cost       int64        `json:"cost"`
+	// SpendOrder is the order the buckets of the account pay the cost in
+	SpendOrder string       `json:"spend_order"`
+	// Tickets is the number of pull tickets spent on the pull besides the cost
+	Tickets    int64        `json:"tickets"`
+	Pull       gacha.Pull   `json:"pull"`
+	Pool       *gacha.Pool  `json:"-"`
+}
+
+type GachaTxResult struct {
+	Account   Account   `json:"account"`
+	Charge    Charge    `json:"charge"`
+	Gachas    []Gacha   `json:"gachas"`
+	Galleries []Gallery `json:"galleries"`
+	Pity      Pity      `json:"pity"`
+}
+
+// GachaTx charges the account for the pull through the ledger, stores the drawn
+// items and moves the pity counter of the account on the banner.
+// The cost is taken from the paid and free buckets in the spend order, and every
+// gacha row records the part of the cost each bucket paid for it. Paid currency
+// past the monthly cap of the owner fails with ErrSpendingCapExceeded.
+// Pulls paid with tickets take them from the account and post nothing to the ledger.
+// A flagged account can't pull until its debt is settled.
+// Draws read the rolls of the active seed of the account, and every gacha row
+// keeps the seed, nonce and roll it was decided by, with the banner, the
+// snapshot of the pool, the pity counter and the guaranteed rating it was drawn
+// with, so its item can be drawn again from its roll.
+// The account row stays locked until the transaction ends, so concurrent pulls
+// can neither spend the same balance twice nor read a stale pity counter.
+func (s *SQLStore) GachaTx(ctx context.Context, arg GachaTxParams) (GachaTxResult, error) {
+	var result GachaTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
 		if err != nil {
 			return err
 		}
+		if account.Owner != arg.Owner {
+			return ErrAccountNotOwned
+		}
+		if account.FlaggedAt.Valid {
+			return ErrAccountFlagged
+		}
+		if account.PullTickets < arg.Tickets {
+			return ErrInsufficientTickets
+		}
+		result.Charge, err = splitCost(account, arg.SpendOrder, arg.Cost)
+		if err != nil {
+			return err
+		}
+		if result.Charge.Paid > 0 {
+			spending, err := monthlySpending(ctx, q, account, time.Now())
+			if err != nil {
+				return err
+			}
+			if !spending.AllowsSpend(result.Charge.Paid) {
+				return ErrSpendingCapExceeded
+			}
+		}
 
-		result.Exchange2, err = q.CreateExchange(ctx, CreateExchangeParams{
-			FromAccountID: arg.ToAccountID,
-			ToAccountID:   arg.FromAccountID,
-			ItemID:        arg.ItemID2,
+		var pulls int32
+		pity, err := q.GetPity(ctx, GetPityParams{
+			AccountID: account.ID,
+			BannerID:  arg.BannerID,
 		})
+		switch {
+		case err == nil:
+			pulls = pity.Pulls
+		case err != sql.ErrNoRows:
+			return err
+		}
+
+		seed, err := activeSeed(ctx, q, account.ID)
 		if err != nil {
 			return err
 		}
 
-		result.Gallery1, err = q.UpdateGallery(ctx, UpdateGalleryParams{
-			OwnerID: arg.FromAccountID,
-			ItemID: arg.ItemID1,
-			OwnerID_2: arg.ToAccountID,
-			ExchangeAt: time.Now(),
+		src := &gacha.FairSource{
+			ServerSeed: seed.ServerSeed,
+			ClientSeed: seed.ClientSeed,
+			Nonce:      seed.Nonce,
+		}
+		draws, pulls, err := gacha.DrawPull(arg.Pool, src, arg.Pull, pulls)
+		if err != nil {
+			return err
+		}
+
+		snapshot, digest, err := gacha.NewSnapshot(arg.Pool, arg.Pull.Pity).Encode()
+		if err != nil {
+			return err
+		}
+		err = q.CreatePoolSnapshot(ctx, CreatePoolSnapshotParams{
+			Digest:   digest,
+			Snapshot: snapshot,
 		})
 		if err != nil {
 			return err
 		}
 
-		result.Gallery2, err = q.UpdateGallery(ctx, UpdateGalleryParams{
-			OwnerID: arg.ToAccountID,
-			ItemID: arg.ItemID2,
-			OwnerID_2: arg.FromAccountID,
-			ExchangeAt: time.Now(),
+		charges := splitPulls(result.Charge, arg.SpendOrder, len(draws))
+		for i, draw := range draws {
+			entry := draw.Entry
+			// every draw adds a new copy of the item to the gallery
+			gallery, err := q.CreateGallery(ctx, CreateGalleryParams{
+				OwnerID: account.ID,
+				ItemID:  entry.ItemID,
+			})
+			if err != nil {
+				return err
+			}
+			result.Galleries = append(result.Galleries, gallery)
+
+			nonce := seed.Nonce + int64(i)
+			record, err := q.CreateGacha(ctx, CreateGachaParams{
+				AccountID:  account.ID,
+				ItemID:     entry.ItemID,
+				SeedID:     sql.NullInt64{Int64: seed.ID, Valid: true},
+				Nonce:      nonce,
+				Roll:       gacha.Roll(seed.ServerSeed, seed.ClientSeed, nonce),
+				GalleryID:  sql.NullInt64{Int64: gallery.ID, Valid: true},
+				PaidAmount: charges[i].Paid,
+				FreeAmount: charges[i].Free,
+				BannerID:   sql.NullInt64{Int64: arg.BannerID, Valid: true},
+				PoolDigest: digest,
+				PityPulls:  draw.Pulls,
+				MinRating:  draw.MinRating,
+			})
+			if err != nil {
+				return err
+			}
+			result.Gachas = append(result.Gachas, record)
+		}
+
+		_, err = q.UpdateSeedNonce(ctx, UpdateSeedNonceParams{
+			ID:    seed.ID,
+			Nonce: src.Nonce - seed.Nonce,
 		})
 		if err != nil {
 			return err
 		}
 
-		return nil
+		result.Pity, err = q.UpsertPity(ctx, UpsertPityParams{
+			AccountID: account.ID,
+			BannerID:  arg.BannerID,
+			Pulls:     pulls,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Account = account
+		if arg.Tickets > 0 {
+			result.Account, err = q.UpdatePullTickets(ctx, UpdatePullTicketsParams{
+				ID:          account.ID,
+				PullTickets: -arg.Tickets,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if arg.Cost == 0 {
+			return nil
+		}
+
+		// the journal refers to the first gacha of the pull
+		posted, err := postLedger(ctx, q, postLedgerParams{
+			AccountID:   account.ID,
+			Paid:        -result.Charge.Paid,
+			Free:        -result.Charge.Free,
+			Reason:      ReasonGachaPull,
+			ReferenceID: ReferenceID(result.Gachas[0].ID),
+		})
+		result.Account = posted.Account
+		return err
 	})
 
 	return result, err
 }
 
+// activeSeed returns the unrevealed seed of an account, committing to a new one
+// with a random client seed when the account has none
+func activeSeed(ctx context.Context, q *Queries, accountID int64) (Seed, error) {
+	seed, err := q.GetActiveSeedForUpdate(ctx, accountID)
+	if err != sql.ErrNoRows {
+		return seed, err
+	}
+	return newSeed(ctx, q, accountID, "")
+}
+
+// newSeed commits to a new server seed for an account.
+// An empty client seed is replaced by a random one.
+func newSeed(ctx context.Context, q *Queries, accountID int64, clientSeed string) (Seed, error) {
+	serverSeed, err := gacha.NewSeed()
+	if err != nil {
+		return Seed{}, err
+	}
+
+	if clientSeed == "" {
+		clientSeed, err = gacha.NewSeed()
+		if err != nil {
+			return Seed{}, err
+		}
+		clientSeed = clientSeed[:16]
+	}
+
+	return q.CreateSeed(ctx, CreateSeedParams{
+		AccountID:      accountID,
+		ServerSeed:     serverSeed,
+		ServerSeedHash: gacha.HashSeed(serverSeed),
+		ClientSeed:     clientSeed,
+	})
+}
+
+// RotateSeedTxParams contains the input parameters of the seed rotation transaction
+type RotateSeedTxParams struct {
+	AccountID  int64  `json:"account_id"`
+	Owner      string `json:"owner"`
+	ClientSeed string `json:"client_seed"`
+}
+
+type RotateSeedTxResult struct {
+	// Revealed is the previous seed, left empty when the account had none
+	Revealed Seed `json:"revealed"`
+	Active   Seed `json:"active"`
+}
+
+// RotateSeedTx reveals the active seed of the account, so its past draws can be
+// verified, and commits to a new server seed paired with the given client seed.
+// It locks the account like GachaTx, so no pull can read a seed being rotated.
+func (s *SQLStore) RotateSeedTx(ctx context.Context, arg RotateSeedTxParams) (RotateSeedTxResult, error) {
+	var result RotateSeedTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if account.Owner != arg.Owner {
+			return ErrAccountNotOwned
+		}
+
+		seed, err := q.GetActiveSeedForUpdate(ctx, account.ID)
+		switch {
+		case err == nil:
+			result.Revealed, err = q.RevealSeed(ctx, seed.ID)
+			if err != nil {
+				return err
+			}
+		case err != sql.ErrNoRows:
+			return err
+		}
+
+		result.Active, err = newSeed(ctx, q, account.ID, arg.ClientSeed)
+		return err
+	})
+
+	return result, err
+}
+
+// ConvertTxParams contains the input parameters of the shard conversion transaction
+type ConvertTxParams struct {
+	AccountID  int64   `json:"account_id"`
+	Owner      string  `json:"owner"`
+	GalleryIDs []int64 `json:"gallery_ids"`
+}
+
+type ConvertTxResult struct {
+	Account   Account   `json:"account"`
+	Galleries []Gallery `json:"galleries"`
+	Shards    int64     `json:"shards"`
+}
+
+// ConvertTx removes copies from the gallery of the account and credits shards
+// by the rating of every removed item.
+// A copy the account doesn't hold fails the whole conversion with sql.ErrNoRows,
+// a copy listed on the market fails it with ErrItemListed.
+func (s *SQLStore) ConvertTx(ctx context.Context, arg ConvertTxParams) (ConvertTxResult, error) {
+	var result ConvertTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if account.Owner != arg.Owner {
+			return ErrAccountNotOwned
+		}
+
+		for _, id := range arg.GalleryIDs {
+			// lock the copy first, a listing of it could be created meanwhile
+			locked, err := q.GetGalleryForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if locked.OwnerID != account.ID {
+				return sql.ErrNoRows
+			}
+			if err := checkNotListed(ctx, q, id); err != nil {
+				return err
+			}
+
+			gallery, err := q.DeleteGallery(ctx, DeleteGalleryParams{
+				ID:      id,
+				OwnerID: account.ID,
+			})
+			if err != nil {
+				return err
+			}
+			result.Galleries = append(result.Galleries, gallery)
+
+			item, err := q.GetItem(ctx, gallery.ItemID)
+			if err != nil {
+				return err
+			}
+			result.Shards += gacha.ShardValue(item.Rating)
+		}
+
+		result.Account, err = q.UpdateShards(ctx, UpdateShardsParams{
+			ID:     account.ID,
+			Shards: result.Shards,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// SparkTxParams contains the input parameters of the spark transaction
+type SparkTxParams struct {
+	AccountID int64  `json:"account_id"`
+	Owner     string `json:"owner"`
+	ItemID    int64  `json:"item_id"`
+	Cost      int64  `json:"cost"`
+}
+
+type SparkTxResult struct {
+	Account Account `json:"account"`
+	Gallery Gallery `json:"gallery"`
+}
+
+// SparkTx spends shards of the account on a new copy of the chosen item
+func (s *SQLStore) SparkTx(ctx context.Context, arg SparkTxParams) (SparkTxResult, error) {
+	var result SparkTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if account.Owner != arg.Owner {
+			return ErrAccountNotOwned
+		}
+		if account.Shards < arg.Cost {
+			return ErrInsufficientShards
+		}
+
+		result.Gallery, err = q.CreateGallery(ctx, CreateGalleryParams{
+			OwnerID: account.ID,
+			ItemID:  arg.ItemID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.UpdateShards(ctx, UpdateShardsParams{
+			ID:     account.ID,
+			Shards: -arg.Cost,
+		})
+		return err
+	})
+
+	return result, err
+}
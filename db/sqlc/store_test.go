@@ -0,0 +1,722 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sRRRs-7/GachaPon/gacha"
+	"github.com/stretchr/testify/require"
+)
+
+func randomBannerPool(t *testing.T, bannerItem BannerItem) *gacha.Pool {
+	item, err := testQueries.GetItem(context.Background(), bannerItem.ItemID)
+	require.NoError(t, err)
+
+	entries := []gacha.Entry{{
+		ItemID: item.ID,
+		Rating: item.Rating,
+		Weight: bannerItem.Weight,
+	}}
+
+	pool, err := gacha.NewPool(entries, gacha.Rates{item.Rating: 1})
+	require.NoError(t, err)
+	return pool
+}
+
+func TestGachaTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := RandomCreateAccount(t)
+	banner := RandomCreateBanner(t)
+	bannerItem := RandomCreateBannerItem(t, banner)
+
+	arg := GachaTxParams{
+		AccountID: account1.ID,
+		BannerID: banner.ID,
+		Owner: account1.Owner,
+		Cost: 30,
+		SpendOrder: SpendFreeFirst,
+		Pull: gacha.Pull{Count: 1},
+		Pool: randomBannerPool(t, bannerItem),
+	}
+
+	result, err := store.GachaTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, result)
+
+	require.Equal(t, account1.ID, result.Account.ID)
+	require.Equal(t, account1.Balance-arg.Cost, result.Account.Balance)
+
+	require.Len(t, result.Gachas, 1)
+	require.Equal(t, account1.ID, result.Gachas[0].AccountID)
+	require.Equal(t, bannerItem.ItemID, result.Gachas[0].ItemID)
+
+	require.Len(t, result.Galleries, 1)
+	require.Equal(t, account1.ID, result.Galleries[0].OwnerID)
+	require.Equal(t, bannerItem.ItemID, result.Galleries[0].ItemID)
+
+	// a single tier pool always draws its top rarity
+	require.Equal(t, account1.ID, result.Pity.AccountID)
+	require.Equal(t, banner.ID, result.Pity.BannerID)
+	require.Zero(t, result.Pity.Pulls)
+
+	account2, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Account.Balance, account2.Balance)
+}
+
+func TestGachaTxInsufficientBalance(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := RandomCreateAccount(t)
+	banner := RandomCreateBanner(t)
+	bannerItem := RandomCreateBannerItem(t, banner)
+
+	arg := GachaTxParams{
+		AccountID: account1.ID,
+		BannerID: banner.ID,
+		Owner: account1.Owner,
+		Cost: account1.Balance + 1,
+		SpendOrder: SpendFreeFirst,
+		Pull: gacha.Pull{Count: 1},
+		Pool: randomBannerPool(t, bannerItem),
+	}
+
+	_, err := store.GachaTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+
+	account2, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, account2.Balance)
+}
+
+func TestGachaTxAccountNotOwned(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := RandomCreateAccount(t)
+	account2 := RandomCreateAccount(t)
+	banner := RandomCreateBanner(t)
+	bannerItem := RandomCreateBannerItem(t, banner)
+
+	arg := GachaTxParams{
+		AccountID: account1.ID,
+		BannerID: banner.ID,
+		Owner: account2.Owner,
+		Cost: 10,
+		SpendOrder: SpendFreeFirst,
+		Pull: gacha.Pull{Count: 1},
+		Pool: randomBannerPool(t, bannerItem),
+	}
+
+	_, err := store.GachaTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrAccountNotOwned)
+}
+
+func TestGachaTxMultiDuplicates(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := RandomCreateAccount(t)
+	banner := RandomCreateBanner(t)
+	bannerItem := RandomCreateBannerItem(t, banner)
+
+	arg := GachaTxParams{
+		AccountID: account1.ID,
+		BannerID: banner.ID,
+		Owner: account1.Owner,
+		Cost: 90,
+		SpendOrder: SpendFreeFirst,
+		Pull: gacha.Pull{Count: 3},
+		Pool: randomBannerPool(t, bannerItem),
+	}
+
+	// every pull draws the same item, each into a gallery row of its own
+	result, err := store.GachaTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, result.Gachas, arg.Pull.Count)
+	require.Len(t, result.Galleries, arg.Pull.Count)
+
+	for i, gallery := range result.Galleries {
+		require.Equal(t, bannerItem.ItemID, gallery.ItemID)
+		require.Equal(t, gallery.ID, result.Gachas[i].GalleryID.Int64)
+	}
+
+	quantities, err := testQueries.ListGalleryQuantities(context.Background(), ListGalleryQuantitiesParams{
+		OwnerID: account1.ID,
+		Limit: 5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, quantities, 1)
+	require.Equal(t, int64(arg.Pull.Count), quantities[0].Quantity)
+}
+
+func TestGachaTxRollback(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := RandomCreateAccount(t)
+	banner := RandomCreateBanner(t)
+	bannerItem := RandomCreateBannerItem(t, banner)
+
+	// the second entry points to a missing item, so its gallery insert fails
+	entries := []gacha.Entry{
+		{ItemID: bannerItem.ItemID, Rating: 1, Weight: 1},
+		{ItemID: -1, Rating: 7, Weight: 1},
+	}
+	pool, err := gacha.NewPool(entries, gacha.Rates{1: 1, 7: 1})
+	require.NoError(t, err)
+
+	arg := GachaTxParams{
+		AccountID: account1.ID,
+		BannerID: banner.ID,
+		Owner: account1.Owner,
+		Cost: 10,
+		SpendOrder: SpendFreeFirst,
+		Pull: gacha.Pull{Count: 2, MinRating: 7},
+		Pool: pool,
+	}
+
+	_, err = store.GachaTx(context.Background(), arg)
+	require.Error(t, err)
+
+	account2, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, account2.Balance)
+
+	galleries, err := testQueries.ListGalleriesById(context.Background(), ListGalleriesByIdParams{
+		OwnerID: account1.ID,
+		Limit: 5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Empty(t, galleries)
+}
+
+// createTestSeed commits the account to a seed whose first roll satisfies want
+func createTestSeed(t *testing.T, account Account, want func(roll float64) bool) Seed {
+	serverSeed, err := gacha.NewSeed()
+	require.NoError(t, err)
+
+	var clientSeed string
+	for i := 0; ; i++ {
+		clientSeed = fmt.Sprintf("test%d", i)
+		if want(gacha.Roll(serverSeed, clientSeed, 0)) {
+			break
+		}
+	}
+
+	seed, err := testQueries.CreateSeed(context.Background(), CreateSeedParams{
+		AccountID: account.ID,
+		ServerSeed: serverSeed,
+		ServerSeedHash: gacha.HashSeed(serverSeed),
+		ClientSeed: clientSeed,
+	})
+	require.NoError(t, err)
+	return seed
+}
+
+// pityTestPool puts the items of two banner items in a common and a top tier
+func pityTestPool(t *testing.T, common, top BannerItem) *gacha.Pool {
+	entries := []gacha.Entry{
+		{ItemID: common.ItemID, Rating: 1, Weight: 1},
+		{ItemID: top.ItemID, Rating: 7, Weight: 1},
+	}
+
+	pool, err := gacha.NewPool(entries, gacha.Rates{1: 99, 7: 1})
+	require.NoError(t, err)
+	return pool
+}
+
+func TestGachaTxPity(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := RandomCreateAccount(t)
+	banner := RandomCreateBanner(t)
+	common := RandomCreateBannerItem(t, banner)
+	top := RandomCreateBannerItem(t, banner)
+
+	_, err := testQueries.UpsertPity(context.Background(), UpsertPityParams{
+		AccountID: account1.ID,
+		BannerID: banner.ID,
+		Pulls: 4,
+	})
+	require.NoError(t, err)
+
+	// the common tier covers the first 99% of the rolls
+	createTestSeed(t, account1, func(roll float64) bool {
+		return roll < 0.9
+	})
+
+	arg := GachaTxParams{
+		AccountID: account1.ID,
+		BannerID: banner.ID,
+		Owner: account1.Owner,
+		Cost: 10,
+		SpendOrder: SpendFreeFirst,
+		Pull: gacha.Pull{Count: 1, Pity: gacha.Pity{Hard: 10}},
+		Pool: pityTestPool(t, common, top),
+	}
+
+	result, err := store.GachaTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, common.ItemID, result.Gachas[0].ItemID)
+	require.Equal(t, int32(5), result.Pity.Pulls)
+
+	pity, err := testQueries.GetPity(context.Background(), GetPityParams{
+		AccountID: account1.ID,
+		BannerID: banner.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, result.Pity.Pulls, pity.Pulls)
+}
+
+func TestGachaTxHardPity(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := RandomCreateAccount(t)
+	banner := RandomCreateBanner(t)
+	common := RandomCreateBannerItem(t, banner)
+	top := RandomCreateBannerItem(t, banner)
+
+	_, err := testQueries.UpsertPity(context.Background(), UpsertPityParams{
+		AccountID: account1.ID,
+		BannerID: banner.ID,
+		Pulls: 9,
+	})
+	require.NoError(t, err)
+
+	arg := GachaTxParams{
+		AccountID: account1.ID,
+		BannerID: banner.ID,
+		Owner: account1.Owner,
+		Cost: 10,
+		SpendOrder: SpendFreeFirst,
+		Pull: gacha.Pull{Count: 1, Pity: gacha.Pity{Hard: 10}},
+		Pool: pityTestPool(t, common, top),
+	}
+
+	result, err := store.GachaTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, top.ItemID, result.Gachas[0].ItemID)
+	require.Zero(t, result.Pity.Pulls)
+
+	// the record keeps what the item was drawn with
+	record := result.Gachas[0]
+	require.Equal(t, sql.NullInt64{Int64: banner.ID, Valid: true}, record.BannerID)
+	require.Equal(t, int32(9), record.PityPulls)
+	require.NotEmpty(t, record.PoolDigest)
+
+	stored, err := testQueries.GetPoolSnapshot(context.Background(), record.PoolDigest)
+	require.NoError(t, err)
+
+	var snapshot gacha.Snapshot
+	require.NoError(t, json.Unmarshal(stored.Snapshot, &snapshot))
+	entry, err := snapshot.Replay(record.Roll, record.PityPulls, record.MinRating)
+	require.NoError(t, err)
+	require.Equal(t, record.ItemID, entry.ItemID)
+}
+
+func TestRotateSeedTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := RandomCreateAccount(t)
+	seed1 := createTestSeed(t, account1, func(float64) bool { return true })
+
+	arg := RotateSeedTxParams{
+		AccountID: account1.ID,
+		Owner: account1.Owner,
+		ClientSeed: "lucky",
+	}
+
+	result, err := store.RotateSeedTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.Equal(t, seed1.ID, result.Revealed.ID)
+	require.True(t, result.Revealed.RevealedAt.Valid)
+	require.Equal(t, gacha.HashSeed(result.Revealed.ServerSeed), result.Revealed.ServerSeedHash)
+
+	require.NotEqual(t, seed1.ID, result.Active.ID)
+	require.False(t, result.Active.RevealedAt.Valid)
+	require.Equal(t, arg.ClientSeed, result.Active.ClientSeed)
+	require.Equal(t, gacha.HashSeed(result.Active.ServerSeed), result.Active.ServerSeedHash)
+	require.Zero(t, result.Active.Nonce)
+
+	active, err := testQueries.GetActiveSeed(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Active.ID, active.ID)
+}
+
+func TestRotateSeedTxWithoutSeed(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := RandomCreateAccount(t)
+
+	arg := RotateSeedTxParams{
+		AccountID: account1.ID,
+		Owner: account1.Owner,
+	}
+
+	result, err := store.RotateSeedTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, result.Revealed.ID)
+	require.NotEmpty(t, result.Active.ClientSeed)
+}
+
+// createTestGallery gives the account a new copy of an item
+func createTestGallery(t *testing.T, account Account, item Item) Gallery {
+	gallery, err := testQueries.CreateGallery(context.Background(), CreateGalleryParams{
+		OwnerID: account.ID,
+		ItemID: item.ID,
+	})
+	require.NoError(t, err)
+	return gallery
+}
+
+func TestExchangeTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := RandomCreateAccount(t)
+	account2 := RandomCreateAccount(t)
+	item := RandomCreateItem(t)
+
+	// both accounts hold a copy of the same item
+	gallery1 := createTestGallery(t, account1, item)
+	gallery2 := createTestGallery(t, account2, item)
+
+	arg := ExchangeTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		FromGalleryIDs: []int64{gallery1.ID},
+		ToGalleryIDs: []int64{gallery2.ID},
+	}
+
+	result, err := store.ExchangeTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.Len(t, result.Galleries, 2)
+	require.Equal(t, gallery1.ID, result.Galleries[0].ID)
+	require.Equal(t, account2.ID, result.Galleries[0].OwnerID)
+	require.Equal(t, gallery2.ID, result.Galleries[1].ID)
+	require.Equal(t, account1.ID, result.Galleries[1].OwnerID)
+
+	require.Len(t, result.Exchanges, 2)
+	require.Equal(t, gallery1.ID, result.Exchanges[0].GalleryID.Int64)
+	require.Equal(t, item.ID, result.Exchanges[0].ItemID)
+	require.Equal(t, gallery2.ID, result.Exchanges[1].GalleryID.Int64)
+	require.Equal(t, item.ID, result.Exchanges[1].ItemID)
+
+	// no currency was added, the balances are untouched
+	require.Equal(t, account1.Balance, result.FromAccount.Balance)
+	require.Equal(t, account2.Balance, result.ToAccount.Balance)
+}
+
+func TestExchangeTxMultiItemWithCurrency(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createLedgerAccount(t, 1000)
+	account2 := createLedgerAccount(t, 1000)
+	item := RandomCreateItem(t)
+
+	// two copies and some currency for a single copy
+	arg := ExchangeTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		FromGalleryIDs: []int64{createTestGallery(t, account1, item).ID, createTestGallery(t, account1, item).ID},
+		ToGalleryIDs: []int64{createTestGallery(t, account2, item).ID},
+		FromAmount: 300,
+		ReferenceID: "test",
+	}
+
+	result, err := store.ExchangeTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, result.Galleries, 3)
+	require.Len(t, result.Exchanges, 3)
+	require.Equal(t, account2.ID, result.Galleries[0].OwnerID)
+	require.Equal(t, account2.ID, result.Galleries[1].OwnerID)
+	require.Equal(t, account1.ID, result.Galleries[2].OwnerID)
+
+	require.Equal(t, account1.FreeBalance-300, result.FromAccount.FreeBalance)
+	require.Equal(t, account2.FreeBalance+300, result.ToAccount.FreeBalance)
+	requireReconciled(t, account1)
+	requireReconciled(t, account2)
+}
+
+func TestExchangeTxInsufficientBalance(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createLedgerAccount(t, 100)
+	account2 := createLedgerAccount(t, 100)
+	item := RandomCreateItem(t)
+	gallery1 := createTestGallery(t, account1, item)
+
+	arg := ExchangeTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		FromGalleryIDs: []int64{gallery1.ID},
+		ToAmount: 101,
+	}
+
+	_, err := store.ExchangeTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+
+	gallery, err := testQueries.GetGallery(context.Background(), gallery1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.ID, gallery.OwnerID)
+}
+
+func TestExchangeTxInvalid(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := RandomCreateAccount(t)
+	account2 := RandomCreateAccount(t)
+	item := RandomCreateItem(t)
+	gallery1 := createTestGallery(t, account1, item)
+
+	testCases := []struct {
+		name string
+		arg  ExchangeTxParams
+	}{
+		{
+			name: "EmptySide",
+			arg: ExchangeTxParams{FromAccountID: account1.ID, ToAccountID: account2.ID, FromGalleryIDs: []int64{gallery1.ID}},
+		},
+		{
+			name: "DuplicateCopy",
+			arg: ExchangeTxParams{FromAccountID: account1.ID, ToAccountID: account2.ID, FromGalleryIDs: []int64{gallery1.ID, gallery1.ID}, ToAmount: 10},
+		},
+		{
+			name: "NegativeAmount",
+			arg: ExchangeTxParams{FromAccountID: account1.ID, ToAccountID: account2.ID, FromGalleryIDs: []int64{gallery1.ID}, ToAmount: -10},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := store.ExchangeTx(context.Background(), tc.arg)
+			require.ErrorIs(t, err, ErrInvalidTrade)
+		})
+	}
+}
+
+func TestExchangeTxGalleryNotOwned(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := RandomCreateAccount(t)
+	account2 := RandomCreateAccount(t)
+	item := RandomCreateItem(t)
+
+	gallery1 := createTestGallery(t, account1, item)
+	// the second copy also belongs to the sender
+	gallery2 := createTestGallery(t, account1, item)
+
+	arg := ExchangeTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		FromGalleryIDs: []int64{gallery1.ID},
+		ToGalleryIDs: []int64{gallery2.ID},
+	}
+
+	_, err := store.ExchangeTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrItemNotOwned)
+
+	gallery, err := testQueries.GetGallery(context.Background(), gallery1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.ID, gallery.OwnerID)
+}
+
+func TestExchangeTxSameAccount(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := RandomCreateAccount(t)
+	item := RandomCreateItem(t)
+
+	arg := ExchangeTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account1.ID,
+		FromGalleryIDs: []int64{createTestGallery(t, account1, item).ID},
+		ToGalleryIDs: []int64{createTestGallery(t, account1, item).ID},
+	}
+
+	_, err := store.ExchangeTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrSameAccount)
+}
+
+func TestExchangeTxConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+	n := 10
+
+	account1 := RandomCreateAccount(t)
+	item := RandomCreateItem(t)
+	gallery1 := createTestGallery(t, account1, item)
+
+	// every counterparty asks for the same copy of account1
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		account2 := RandomCreateAccount(t)
+		arg := ExchangeTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID: account2.ID,
+			FromGalleryIDs: []int64{gallery1.ID},
+			ToGalleryIDs: []int64{createTestGallery(t, account2, item).ID},
+		}
+		go func() {
+			_, err := store.ExchangeTx(context.Background(), arg)
+			errs <- err
+		}()
+	}
+
+	traded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			traded++
+			continue
+		}
+		// the loser either met the lock or came after the copy had moved
+		require.True(t, errors.Is(err, ErrItemLocked) || errors.Is(err, ErrItemNotOwned), err)
+	}
+	require.Equal(t, 1, traded)
+
+	gallery, err := testQueries.GetGallery(context.Background(), gallery1.ID)
+	require.NoError(t, err)
+	require.NotEqual(t, account1.ID, gallery.OwnerID)
+}
+
+func TestExchangeTxConcurrentOppositeSides(t *testing.T) {
+	store := NewStore(testDB)
+	n := 10
+
+	account1 := RandomCreateAccount(t)
+	account2 := RandomCreateAccount(t)
+	item := RandomCreateItem(t)
+	gallery1 := createTestGallery(t, account1, item)
+	gallery2 := createTestGallery(t, account2, item)
+
+	// both accounts send the same swap at once, half of them from each side,
+	// which would deadlock if the copies were locked in the order of the sides
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		arg := ExchangeTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID: account2.ID,
+			FromGalleryIDs: []int64{gallery1.ID},
+			ToGalleryIDs: []int64{gallery2.ID},
+		}
+		if i%2 == 1 {
+			arg = ExchangeTxParams{
+				FromAccountID: account2.ID,
+				ToAccountID: account1.ID,
+				FromGalleryIDs: []int64{gallery2.ID},
+				ToGalleryIDs: []int64{gallery1.ID},
+			}
+		}
+		go func() {
+			_, err := store.ExchangeTx(context.Background(), arg)
+			errs <- err
+		}()
+	}
+
+	traded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			traded++
+			continue
+		}
+		require.True(t, errors.Is(err, ErrItemLocked) || errors.Is(err, ErrItemNotOwned), err)
+	}
+	require.Equal(t, 1, traded)
+
+	gallery, err := testQueries.GetGallery(context.Background(), gallery1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.ID, gallery.OwnerID)
+
+	gallery, err = testQueries.GetGallery(context.Background(), gallery2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.ID, gallery.OwnerID)
+}
+
+func TestConvertTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := RandomCreateAccount(t)
+	item := RandomCreateItem(t)
+
+	gallery1 := createTestGallery(t, account1, item)
+	gallery2 := createTestGallery(t, account1, item)
+
+	arg := ConvertTxParams{
+		AccountID: account1.ID,
+		Owner: account1.Owner,
+		GalleryIDs: []int64{gallery1.ID, gallery2.ID},
+	}
+
+	result, err := store.ConvertTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, result.Galleries, 2)
+	require.Equal(t, 2*gacha.ShardValue(item.Rating), result.Shards)
+	require.Equal(t, account1.Shards+result.Shards, result.Account.Shards)
+
+	_, err = testQueries.GetGallery(context.Background(), gallery1.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestConvertTxGalleryNotOwned(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := RandomCreateAccount(t)
+	account2 := RandomCreateAccount(t)
+	item := RandomCreateItem(t)
+
+	gallery1 := createTestGallery(t, account1, item)
+	gallery2 := createTestGallery(t, account2, item)
+
+	arg := ConvertTxParams{
+		AccountID: account1.ID,
+		Owner: account1.Owner,
+		GalleryIDs: []int64{gallery1.ID, gallery2.ID},
+	}
+
+	_, err := store.ConvertTx(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// the copy of the account is kept when the conversion fails
+	_, err = testQueries.GetGallery(context.Background(), gallery1.ID)
+	require.NoError(t, err)
+
+	account, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Shards, account.Shards)
+}
+
+func TestSparkTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := RandomCreateAccount(t)
+	item := RandomCreateItem(t)
+
+	_, err := testQueries.UpdateShards(context.Background(), UpdateShardsParams{
+		ID: account1.ID,
+		Shards: 300,
+	})
+	require.NoError(t, err)
+
+	arg := SparkTxParams{
+		AccountID: account1.ID,
+		Owner: account1.Owner,
+		ItemID: item.ID,
+		Cost: 300,
+	}
+
+	result, err := store.SparkTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, result.Account.Shards)
+	require.Equal(t, account1.ID, result.Gallery.OwnerID)
+	require.Equal(t, item.ID, result.Gallery.ItemID)
+
+	_, err = store.SparkTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInsufficientShards)
+}
@@ -0,0 +1,200 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/sRRRs-7/GachaPon/pass"
+)
+
+// statuses of the subscriptions
+const (
+	SubscriptionActive  = "active"
+	SubscriptionExpired = "expired"
+)
+
+var ErrNoActivePass = errors.New("account has no active pass")
+
+// SubscribeTxParams contains the input parameters of the subscribe transaction
+type SubscribeTxParams struct {
+	AccountID  int64     `json:"account_id"`
+	Owner      string    `json:"owner"`
+	Pass       pass.Pass `json:"pass"`
+	SpendOrder string    `json:"spend_order"`
+	Now        time.Time `json:"now"`
+}
+
+type SubscribeTxResult struct {
+	Account      Account      `json:"account"`
+	Subscription Subscription `json:"subscription"`
+	Charge       Charge       `json:"charge"`
+}
+
+// SubscribeTx sells a pass to an account, charging its price through the
+// ledger in the spend order like a pull. A pass bought while another one
+// lasts stacks after it, up to pass.MaxDays ahead.
+func (s *SQLStore) SubscribeTx(ctx context.Context, arg SubscribeTxParams) (SubscribeTxResult, error) {
+	var result SubscribeTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if account.Owner != arg.Owner {
+			return ErrAccountNotOwned
+		}
+		if account.FlaggedAt.Valid {
+			return ErrAccountFlagged
+		}
+		result.Account = account
+
+		// the account lock keeps concurrent renewals from stacking on the same expiry
+		var expiresAt time.Time
+		last, err := q.GetLastSubscription(ctx, account.ID)
+		switch {
+		case err == nil:
+			expiresAt = last.ExpiresAt
+		case err != sql.ErrNoRows:
+			return err
+		}
+
+		start, end, err := pass.Stack(arg.Now, expiresAt, arg.Pass.Days)
+		if err != nil {
+			return err
+		}
+
+		result.Charge, err = splitCost(account, arg.SpendOrder, arg.Pass.Price)
+		if err != nil {
+			return err
+		}
+		if result.Charge.Paid > 0 {
+			spending, err := monthlySpending(ctx, q, account, arg.Now)
+			if err != nil {
+				return err
+			}
+			if !spending.AllowsSpend(result.Charge.Paid) {
+				return ErrSpendingCapExceeded
+			}
+		}
+
+		result.Subscription, err = q.CreateSubscription(ctx, CreateSubscriptionParams{
+			AccountID:   account.ID,
+			PassID:      arg.Pass.ID,
+			DailyAmount: arg.Pass.DailyAmount,
+			PaidAmount:  result.Charge.Paid,
+			FreeAmount:  result.Charge.Free,
+			StartAt:     start,
+			ExpiresAt:   end,
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.Pass.Price == 0 {
+			return nil
+		}
+
+		posted, err := postLedger(ctx, q, postLedgerParams{
+			AccountID:   account.ID,
+			Paid:        -result.Charge.Paid,
+			Free:        -result.Charge.Free,
+			Reason:      ReasonPassPurchase,
+			ReferenceID: ReferenceID(result.Subscription.ID),
+		})
+		result.Account = posted.Account
+		return err
+	})
+
+	return result, err
+}
+
+// SubscriptionClaimTxParams contains the input parameters of the pass claim transaction
+type SubscriptionClaimTxParams struct {
+	AccountID int64  `json:"account_id"`
+	Owner     string `json:"owner"`
+	// Now is the time of the claim, read in the time zone of the owner
+	Now time.Time `json:"now"`
+}
+
+type SubscriptionClaimTxResult struct {
+	Account      Account           `json:"account"`
+	Subscription Subscription      `json:"subscription"`
+	Claim        SubscriptionClaim `json:"claim"`
+	// Claimed is false when the day was already claimed, Claim being the earlier claim
+	Claimed bool `json:"claimed"`
+}
+
+// SubscriptionClaimTx grants the daily amount of the pass lasting at now to
+// an account, once per calendar day of the time zone of the owner. Claiming
+// a day twice returns the first claim without granting anything.
+func (s *SQLStore) SubscriptionClaimTx(ctx context.Context, arg SubscriptionClaimTxParams) (SubscriptionClaimTxResult, error) {
+	var result SubscriptionClaimTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if account.Owner != arg.Owner {
+			return ErrAccountNotOwned
+		}
+		result.Account = account
+
+		result.Subscription, err = q.GetCurrentSubscription(ctx, GetCurrentSubscriptionParams{
+			AccountID: account.ID,
+			StartAt:   arg.Now,
+		})
+		if err == sql.ErrNoRows {
+			return ErrNoActivePass
+		}
+		if err != nil {
+			return err
+		}
+
+		user, err := q.GetUser(ctx, account.Owner)
+		if err != nil {
+			return err
+		}
+
+		today, err := ClaimDate(arg.Now, user.Timezone)
+		if err != nil {
+			return err
+		}
+
+		result.Claim, err = q.GetSubscriptionClaim(ctx, GetSubscriptionClaimParams{
+			AccountID: account.ID,
+			ClaimDate: today,
+		})
+		if err == nil {
+			return nil
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		result.Claim, err = q.CreateSubscriptionClaim(ctx, CreateSubscriptionClaimParams{
+			SubscriptionID: result.Subscription.ID,
+			AccountID:      account.ID,
+			ClaimDate:      today,
+			Amount:         result.Subscription.DailyAmount,
+		})
+		if err != nil {
+			return err
+		}
+		result.Claimed = true
+
+		posted, err := postLedger(ctx, q, postLedgerParams{
+			AccountID:   account.ID,
+			Free:        result.Claim.Amount,
+			Reason:      ReasonPassClaim,
+			ReferenceID: ReferenceID(result.Claim.ID),
+		})
+		result.Account = posted.Account
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,163 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sRRRs-7/GachaPon/pass"
+	"github.com/stretchr/testify/require"
+)
+
+func monthlyPass(t *testing.T) pass.Pass {
+	p, ok := pass.Find("monthly")
+	require.True(t, ok)
+	return p
+}
+
+func subscribe(account Account, p pass.Pass, now time.Time) (SubscribeTxResult, error) {
+	return NewStore(testDB).SubscribeTx(context.Background(), SubscribeTxParams{
+		AccountID: account.ID,
+		Owner: account.Owner,
+		Pass: p,
+		SpendOrder: SpendPaidOnly,
+		Now: now,
+	})
+}
+
+func claimPass(account Account, now time.Time) (SubscriptionClaimTxResult, error) {
+	return NewStore(testDB).SubscriptionClaimTx(context.Background(), SubscriptionClaimTxParams{
+		AccountID: account.ID,
+		Owner: account.Owner,
+		Now: now,
+	})
+}
+
+func TestSubscribeTx(t *testing.T) {
+	p := monthlyPass(t)
+	account := createLedgerAccount(t, 0)
+	creditTestOrder(t, account, "medium")
+	now := time.Now()
+
+	result, err := subscribe(account, p, now)
+	require.NoError(t, err)
+	require.Equal(t, int64(550)-p.Price, result.Account.PaidBalance)
+	require.Equal(t, Charge{Paid: p.Price}, result.Charge)
+
+	subscription := result.Subscription
+	require.Equal(t, account.ID, subscription.AccountID)
+	require.Equal(t, p.ID, subscription.PassID)
+	require.Equal(t, p.DailyAmount, subscription.DailyAmount)
+	require.Equal(t, SubscriptionActive, subscription.Status)
+	require.WithinDuration(t, now, subscription.StartAt, time.Second)
+	require.WithinDuration(t, now.AddDate(0, 0, p.Days), subscription.ExpiresAt, time.Second)
+	requireReconciled(t, account)
+}
+
+func TestSubscribeTxInsufficientBalance(t *testing.T) {
+	// passes are bought with paid currency only
+	account := createLedgerAccount(t, 1000)
+
+	_, err := subscribe(account, monthlyPass(t), time.Now())
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+}
+
+func TestSubscribeTxStacking(t *testing.T) {
+	p := monthlyPass(t)
+	account := createLedgerAccount(t, 0)
+	creditTestOrder(t, account, "mega")
+	now := time.Now()
+
+	var last Subscription
+	for i := 0; i < pass.MaxDays/p.Days; i++ {
+		result, err := subscribe(account, p, now)
+		require.NoError(t, err)
+		if i > 0 {
+			require.True(t, last.ExpiresAt.Equal(result.Subscription.StartAt))
+		}
+		last = result.Subscription
+	}
+
+	_, err := subscribe(account, p, now)
+	require.ErrorIs(t, err, pass.ErrStackFull)
+	requireReconciled(t, account)
+}
+
+func TestSubscriptionClaimTx(t *testing.T) {
+	p := monthlyPass(t)
+	account := createLedgerAccount(t, 0)
+	creditTestOrder(t, account, "medium")
+	now := time.Now()
+
+	subscribed, err := subscribe(account, p, now)
+	require.NoError(t, err)
+
+	result, err := claimPass(account, now)
+	require.NoError(t, err)
+	require.True(t, result.Claimed)
+	require.Equal(t, subscribed.Subscription.ID, result.Subscription.ID)
+	require.Equal(t, p.DailyAmount, result.Claim.Amount)
+	require.Equal(t, p.DailyAmount, result.Account.FreeBalance)
+
+	// a day is claimed once
+	result1, err := claimPass(account, now)
+	require.NoError(t, err)
+	require.False(t, result1.Claimed)
+	require.Equal(t, result.Claim.ID, result1.Claim.ID)
+	require.Equal(t, p.DailyAmount, result1.Account.FreeBalance)
+
+	result2, err := claimPass(account, now.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.True(t, result2.Claimed)
+	require.Equal(t, 2*p.DailyAmount, result2.Account.FreeBalance)
+	requireReconciled(t, account)
+
+	// nothing is left to claim once the pass expires
+	_, err = claimPass(account, now.AddDate(0, 0, p.Days))
+	require.ErrorIs(t, err, ErrNoActivePass)
+}
+
+func TestSubscriptionClaimTxNoPass(t *testing.T) {
+	account := createLedgerAccount(t, 0)
+
+	_, err := claimPass(account, time.Now())
+	require.ErrorIs(t, err, ErrNoActivePass)
+}
+
+func TestSubscriptionClaimTxNotOwned(t *testing.T) {
+	account := createLedgerAccount(t, 0)
+	account.Owner = RandomCreateUser(t).UserName
+
+	_, err := claimPass(account, time.Now())
+	require.ErrorIs(t, err, ErrAccountNotOwned)
+}
+
+func TestExpireSubscriptions(t *testing.T) {
+	p := monthlyPass(t)
+	account := createLedgerAccount(t, 0)
+	creditTestOrder(t, account, "medium")
+	now := time.Now()
+
+	subscribed, err := subscribe(account, p, now)
+	require.NoError(t, err)
+
+	expired, err := testQueries.ExpireSubscriptions(context.Background(), now)
+	require.NoError(t, err)
+	for _, subscription := range expired {
+		require.NotEqual(t, subscribed.Subscription.ID, subscription.ID)
+	}
+
+	expired, err = testQueries.ExpireSubscriptions(context.Background(), now.AddDate(0, 0, p.Days))
+	require.NoError(t, err)
+
+	var found bool
+	for _, subscription := range expired {
+		require.Equal(t, SubscriptionExpired, subscription.Status)
+		found = found || subscription.ID == subscribed.Subscription.ID
+	}
+	require.True(t, found)
+
+	// an expired pass can't be claimed even within its period
+	_, err = claimPass(account, now)
+	require.ErrorIs(t, err, ErrNoActivePass)
+}
@@ -0,0 +1,434 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// sides of a trade offer, the proposer approves the request and the counterparty the response
+const (
+	TradeSideRequest  = "request"
+	TradeSideResponse = "response"
+)
+
+// statuses of the trade offers, every offer but an open one is closed
+const (
+	TradeOpen      = "open"
+	TradeAccepted  = "accepted"
+	TradeCancelled = "cancelled"
+	TradeRejected  = "rejected"
+	// TradeCountered is the status of an offer replaced by a counter-offer
+	TradeCountered = "countered"
+	TradeExpired   = "expired"
+)
+
+// events of the history of a trade offer, besides the statuses closing it
+const (
+	TradeEventCreated   = "created"
+	TradeEventApproved  = "approved"
+	TradeEventWithdrawn = "withdrawn"
+)
+
+var ErrUnknownTradeSide = errors.New("unknown trade side")
+var ErrTradeClosed = errors.New("trade offer is no longer open")
+var ErrTradeExpired = errors.New("trade offer has expired")
+
+// TradeOffer is an offer with the copies each of its sides gives
+type TradeOffer struct {
+	Approval Approval    `json:"approval"`
+	Items    []TradeItem `json:"items"`
+}
+
+// CreateTradeOfferTxParams contains the input parameters of the trade offer transaction
+type CreateTradeOfferTxParams struct {
+	FromAccountID int64 `json:"from_account_id"`
+	// Owner must own the from account, the proposer of the offer
+	Owner          string    `json:"owner"`
+	ToAccountID    int64     `json:"to_account_id"`
+	FromGalleryIDs []int64   `json:"from_gallery_ids"`
+	ToGalleryIDs   []int64   `json:"to_gallery_ids"`
+	FromAmount     int64     `json:"from_amount"`
+	ToAmount       int64     `json:"to_amount"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// CreateTradeOfferTx offers copies and currency of the from account for copies
+// and currency of the to account, approved by its proposer. The copies and
+// balances are checked again when the offer is settled, so they aren't locked.
+func (s *SQLStore) CreateTradeOfferTx(ctx context.Context, arg CreateTradeOfferTxParams) (TradeOffer, error) {
+	var result TradeOffer
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = createTradeOffer(ctx, q, arg, nil)
+		return err
+	})
+
+	return result, err
+}
+
+// createTradeOffer makes an offer inside the transaction of q, a counter-offer
+// joining the thread of the offer it replaces
+func createTradeOffer(ctx context.Context, q *Queries, arg CreateTradeOfferTxParams, parent *Approval) (TradeOffer, error) {
+	var result TradeOffer
+
+	owners, err := tradeOwners(ExchangeTxParams{
+		FromAccountID:  arg.FromAccountID,
+		ToAccountID:    arg.ToAccountID,
+		FromGalleryIDs: arg.FromGalleryIDs,
+		ToGalleryIDs:   arg.ToGalleryIDs,
+		FromAmount:     arg.FromAmount,
+		ToAmount:       arg.ToAmount,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	account, err := q.GetAccount(ctx, arg.FromAccountID)
+	if err != nil {
+		return result, err
+	}
+	if account.Owner != arg.Owner {
+		return result, ErrAccountNotOwned
+	}
+	if err := checkTradeAmount(account, arg.FromAmount); err != nil {
+		return result, err
+	}
+
+	if _, err := q.GetAccount(ctx, arg.ToAccountID); err != nil {
+		return result, err
+	}
+
+	params := CreateApprovalParams{
+		FromAccountID: arg.FromAccountID,
+		FromAApproval: true,
+		FromAmount:    arg.FromAmount,
+		ToAccountID:   arg.ToAccountID,
+		ToAApproval:   false,
+		ToAmount:      arg.ToAmount,
+		ExpiresAt:     arg.ExpiresAt,
+	}
+	if parent != nil {
+		params.ParentID = sql.NullInt64{Int64: parent.ID, Valid: true}
+		params.ThreadID = sql.NullInt64{Int64: TradeThread(*parent), Valid: true}
+	}
+	result.Approval, err = q.CreateApproval(ctx, params)
+	if err != nil {
+		return result, err
+	}
+
+	for _, side := range []struct {
+		name       string
+		galleryIDs []int64
+	}{
+		{TradeSideRequest, arg.FromGalleryIDs},
+		{TradeSideResponse, arg.ToGalleryIDs},
+	} {
+		for _, id := range side.galleryIDs {
+			gallery, err := q.GetGallery(ctx, id)
+			if err != nil {
+				return result, err
+			}
+			if gallery.OwnerID != owners[id] {
+				return result, ErrItemNotOwned
+			}
+			if err := checkNotListed(ctx, q, id); err != nil {
+				return result, err
+			}
+
+			item, err := q.CreateTradeItem(ctx, CreateTradeItemParams{
+				ApprovalID: result.Approval.ID,
+				Side:       side.name,
+				GalleryID:  sql.NullInt64{Int64: gallery.ID, Valid: true},
+				ItemID:     gallery.ItemID,
+			})
+			if err != nil {
+				return result, err
+			}
+			result.Items = append(result.Items, item)
+		}
+	}
+
+	return result, recordTradeEvent(ctx, q, result.Approval, arg.FromAccountID, TradeEventCreated)
+}
+
+// TradeThread is the id of the first offer of the negotiation an offer belongs to
+func TradeThread(approval Approval) int64 {
+	if approval.ThreadID.Valid {
+		return approval.ThreadID.Int64
+	}
+	return approval.ID
+}
+
+// recordTradeEvent adds an event to the history of an offer, an accountID of 0
+// recording a change made by the sweeper
+func recordTradeEvent(ctx context.Context, q *Queries, approval Approval, accountID int64, event string) error {
+	_, err := q.CreateTradeEvent(ctx, CreateTradeEventParams{
+		ApprovalID: approval.ID,
+		ThreadID:   TradeThread(approval),
+		AccountID:  sql.NullInt64{Int64: accountID, Valid: accountID != 0},
+		Event:      event,
+	})
+	return err
+}
+
+// openTradeOffer locks an offer that can still change, failing with
+// ErrTradeClosed once it's closed and ErrTradeExpired past its expiry,
+// even before the sweeper expired it
+func openTradeOffer(ctx context.Context, q *Queries, id int64, now time.Time) (Approval, error) {
+	approval, err := q.GetApprovalForUpdate(ctx, id)
+	if err != nil {
+		return approval, err
+	}
+	if approval.Status != TradeOpen {
+		return approval, ErrTradeClosed
+	}
+	if !now.Before(approval.ExpiresAt) {
+		return approval, ErrTradeExpired
+	}
+	return approval, nil
+}
+
+// ApproveTradeTxParams contains the input parameters of the trade approval transaction
+type ApproveTradeTxParams struct {
+	ApprovalID int64 `json:"approval_id"`
+	// Owner must own the account of Side
+	Owner    string    `json:"owner"`
+	Side     string    `json:"side"`
+	Approved bool      `json:"approved"`
+	Now      time.Time `json:"now"`
+}
+
+type ApproveTradeTxResult struct {
+	Approval Approval `json:"approval"`
+	// Exchange is set when both sides approved and the copies were swapped
+	Exchange *ExchangeTxResult `json:"exchange,omitempty"`
+}
+
+// ApproveTradeTx sets the approval of one side of an open trade offer. Once both
+// sides approved, the copies and currency are exchanged and the offer is accepted
+// in the same transaction, so an offer is exchanged at most once. A copy that
+// left its account since the offer was made fails the transaction with ErrItemNotOwned.
+func (s *SQLStore) ApproveTradeTx(ctx context.Context, arg ApproveTradeTxParams) (ApproveTradeTxResult, error) {
+	var result ApproveTradeTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		approval, err := openTradeOffer(ctx, q, arg.ApprovalID, arg.Now)
+		if err != nil {
+			return err
+		}
+
+		var accountID int64
+		switch arg.Side {
+		case TradeSideRequest:
+			accountID = approval.FromAccountID
+		case TradeSideResponse:
+			accountID = approval.ToAccountID
+		default:
+			return ErrUnknownTradeSide
+		}
+
+		account, err := q.GetAccount(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		if account.Owner != arg.Owner {
+			return ErrAccountNotOwned
+		}
+
+		if arg.Side == TradeSideRequest {
+			result.Approval, err = q.UpdateApprovalRequest(ctx, UpdateApprovalRequestParams{
+				ID:            approval.ID,
+				FromAApproval: arg.Approved,
+			})
+		} else {
+			result.Approval, err = q.UpdateApprovalResponse(ctx, UpdateApprovalResponseParams{
+				ID:          approval.ID,
+				ToAApproval: arg.Approved,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		event := TradeEventWithdrawn
+		if arg.Approved {
+			event = TradeEventApproved
+		}
+		if err := recordTradeEvent(ctx, q, approval, accountID, event); err != nil {
+			return err
+		}
+
+		if !result.Approval.FromAApproval || !result.Approval.ToAApproval {
+			return nil
+		}
+
+		items, err := q.ListTradeItems(ctx, approval.ID)
+		if err != nil {
+			return err
+		}
+
+		trade := ExchangeTxParams{
+			FromAccountID: approval.FromAccountID,
+			ToAccountID:   approval.ToAccountID,
+			FromAmount:    approval.FromAmount,
+			ToAmount:      approval.ToAmount,
+			ReferenceID:   ReferenceID(approval.ID),
+		}
+		for _, item := range items {
+			// the copy was converted since the offer was made
+			if !item.GalleryID.Valid {
+				return ErrItemNotOwned
+			}
+			if item.Side == TradeSideRequest {
+				trade.FromGalleryIDs = append(trade.FromGalleryIDs, item.GalleryID.Int64)
+			} else {
+				trade.ToGalleryIDs = append(trade.ToGalleryIDs, item.GalleryID.Int64)
+			}
+		}
+
+		exchanged, err := exchange(ctx, q, trade)
+		if err != nil {
+			return err
+		}
+		result.Exchange = &exchanged
+
+		result.Approval, err = q.UpdateApprovalStatus(ctx, UpdateApprovalStatusParams{
+			ID:     approval.ID,
+			Status: TradeAccepted,
+		})
+		if err != nil {
+			return err
+		}
+		return recordTradeEvent(ctx, q, approval, accountID, TradeAccepted)
+	})
+
+	return result, err
+}
+
+// CloseTradeOfferTxParams contains the input parameters of the close trade offer transaction
+type CloseTradeOfferTxParams struct {
+	ApprovalID int64 `json:"approval_id"`
+	// Owner must own one of the accounts of the offer
+	Owner string    `json:"owner"`
+	Now   time.Time `json:"now"`
+}
+
+// CloseTradeOfferTx closes an open offer, its proposer cancels it and its
+// counterparty rejects it
+func (s *SQLStore) CloseTradeOfferTx(ctx context.Context, arg CloseTradeOfferTxParams) (Approval, error) {
+	var result Approval
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		approval, err := openTradeOffer(ctx, q, arg.ApprovalID, arg.Now)
+		if err != nil {
+			return err
+		}
+
+		for _, side := range []struct {
+			accountID int64
+			status    string
+		}{
+			{approval.FromAccountID, TradeCancelled},
+			{approval.ToAccountID, TradeRejected},
+		} {
+			account, err := q.GetAccount(ctx, side.accountID)
+			if err != nil {
+				return err
+			}
+			if account.Owner != arg.Owner {
+				continue
+			}
+
+			result, err = q.UpdateApprovalStatus(ctx, UpdateApprovalStatusParams{
+				ID:     approval.ID,
+				Status: side.status,
+			})
+			if err != nil {
+				return err
+			}
+			return recordTradeEvent(ctx, q, approval, side.accountID, side.status)
+		}
+		return ErrAccountNotOwned
+	})
+
+	return result, err
+}
+
+// CounterTradeOfferTxParams contains the input parameters of the counter-offer transaction
+type CounterTradeOfferTxParams struct {
+	// ApprovalID is the offer countered, its counterparty proposes the counter-offer
+	ApprovalID int64  `json:"approval_id"`
+	Owner      string `json:"owner"`
+	// FromGalleryIDs and FromAmount are given by the counterparty of the countered offer
+	FromGalleryIDs []int64   `json:"from_gallery_ids"`
+	ToGalleryIDs   []int64   `json:"to_gallery_ids"`
+	FromAmount     int64     `json:"from_amount"`
+	ToAmount       int64     `json:"to_amount"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	Now            time.Time `json:"now"`
+}
+
+// CounterTradeOfferTx replaces an open offer with a counter-offer of its
+// counterparty, made to its proposer and linked to the countered offer
+func (s *SQLStore) CounterTradeOfferTx(ctx context.Context, arg CounterTradeOfferTxParams) (TradeOffer, error) {
+	var result TradeOffer
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		approval, err := openTradeOffer(ctx, q, arg.ApprovalID, arg.Now)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.UpdateApprovalStatus(ctx, UpdateApprovalStatusParams{
+			ID:     approval.ID,
+			Status: TradeCountered,
+		})
+		if err != nil {
+			return err
+		}
+		if err := recordTradeEvent(ctx, q, approval, approval.ToAccountID, TradeCountered); err != nil {
+			return err
+		}
+
+		// the owner check of the new offer makes sure the counterparty counters
+		result, err = createTradeOffer(ctx, q, CreateTradeOfferTxParams{
+			FromAccountID:  approval.ToAccountID,
+			Owner:          arg.Owner,
+			ToAccountID:    approval.FromAccountID,
+			FromGalleryIDs: arg.FromGalleryIDs,
+			ToGalleryIDs:   arg.ToGalleryIDs,
+			FromAmount:     arg.FromAmount,
+			ToAmount:       arg.ToAmount,
+			ExpiresAt:      arg.ExpiresAt,
+		}, &approval)
+		return err
+	})
+
+	return result, err
+}
+
+// ExpireTradeOffersTx expires the open offers past their expiry, recording
+// the change in their history
+func (s *SQLStore) ExpireTradeOffersTx(ctx context.Context, now time.Time) ([]Approval, error) {
+	var result []Approval
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.ExpireApprovals(ctx, now)
+		if err != nil {
+			return err
+		}
+
+		for _, approval := range result {
+			if err := recordTradeEvent(ctx, q, approval, 0, TradeExpired); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -7,21 +7,24 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
-    user_name, hash_password, full_name, email
+    user_name, hash_password, full_name, email, birthdate, region
 ) VALUES (
-    $1, $2, $3, $4
-) RETURNING id, user_name, hash_password, full_name, email, created_at
+    $1, $2, $3, $4, $5, $6
+) RETURNING id, user_name, hash_password, full_name, email, created_at, birthdate, region, timezone, is_admin, timezone_updated_at
 `
 
 type CreateUserParams struct {
-	UserName     string `json:"user_name"`
-	HashPassword string `json:"hash_password"`
-	FullName     string `json:"full_name"`
-	Email        string `json:"email"`
+	UserName     string       `json:"user_name"`
+	HashPassword string       `json:"hash_password"`
+	FullName     string       `json:"full_name"`
+	Email        string       `json:"email"`
+	Birthdate    sql.NullTime `json:"birthdate"`
+	Region       string       `json:"region"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -30,6 +33,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.HashPassword,
 		arg.FullName,
 		arg.Email,
+		arg.Birthdate,
+		arg.Region,
 	)
 	var i User
 	err := row.Scan(
@@ -39,12 +44,17 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.FullName,
 		&i.Email,
 		&i.CreatedAt,
+		&i.Birthdate,
+		&i.Region,
+		&i.Timezone,
+		&i.IsAdmin,
+		&i.TimezoneUpdatedAt,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, user_name, hash_password, full_name, email, created_at FROM users
+SELECT id, user_name, hash_password, full_name, email, created_at, birthdate, region, timezone, is_admin, timezone_updated_at FROM users
 WHERE user_name = $1 LIMIT 1
 `
 
@@ -58,6 +68,44 @@ func (q *Queries) GetUser(ctx context.Context, userName string) (User, error) {
 		&i.FullName,
 		&i.Email,
 		&i.CreatedAt,
+		&i.Birthdate,
+		&i.Region,
+		&i.Timezone,
+		&i.IsAdmin,
+		&i.TimezoneUpdatedAt,
+	)
+	return i, err
+}
+
+const updateUserTimezone = `-- name: UpdateUserTimezone :one
+UPDATE users
+SET timezone = $1, timezone_updated_at = now()
+WHERE user_name = $2
+    AND (timezone_updated_at IS NULL OR timezone_updated_at <= $3)
+RETURNING id, user_name, hash_password, full_name, email, created_at, birthdate, region, timezone, is_admin, timezone_updated_at
+`
+
+type UpdateUserTimezoneParams struct {
+	Timezone      string       `json:"timezone"`
+	UserName      string       `json:"user_name"`
+	ChangedBefore sql.NullTime `json:"changed_before"`
+}
+
+func (q *Queries) UpdateUserTimezone(ctx context.Context, arg UpdateUserTimezoneParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserTimezone, arg.Timezone, arg.UserName, arg.ChangedBefore)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.UserName,
+		&i.HashPassword,
+		&i.FullName,
+		&i.Email,
+		&i.CreatedAt,
+		&i.Birthdate,
+		&i.Region,
+		&i.Timezone,
+		&i.IsAdmin,
+		&i.TimezoneUpdatedAt,
 	)
 	return i, err
 }
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
@@ -30,6 +31,8 @@ func RandomCreateUser(t *testing.T) User {
 	require.Equal(t, arg.FullName, user.FullName)
 	require.Equal(t, arg.Email, user.Email)
 	require.NotZero(t, user.CreatedAt)
+	// users are promoted to admins in the database only
+	require.False(t, user.IsAdmin)
 
 	return user
 }
@@ -51,4 +54,29 @@ func TestGetUser(t *testing.T) {
 	require.Equal(t, user1.FullName, user2.FullName)
 	require.Equal(t, user1.Email, user2.Email)
 	require.WithinDuration(t, user1.CreatedAt, user2.CreatedAt, time.Second)
-}
\ No newline at end of file
+}
+func TestUpdateUserTimezone(t *testing.T) {
+	user1 := RandomCreateUser(t)
+	require.False(t, user1.TimezoneUpdatedAt.Valid)
+
+	arg := UpdateUserTimezoneParams{
+		Timezone: "Asia/Tokyo",
+		UserName: user1.UserName,
+		ChangedBefore: sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true},
+	}
+
+	user2, err := testQueries.UpdateUserTimezone(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Timezone, user2.Timezone)
+	require.True(t, user2.TimezoneUpdatedAt.Valid)
+
+	// changed less than an hour ago
+	arg.Timezone = "UTC"
+	_, err = testQueries.UpdateUserTimezone(context.Background(), arg)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	arg.ChangedBefore = sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true}
+	user3, err := testQueries.UpdateUserTimezone(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Timezone, user3.Timezone)
+}
@@ -0,0 +1,93 @@
+package db
+
+import "errors"
+
+// currency buckets of an account
+const (
+	BucketPaid = "paid"
+	BucketFree = "free"
+)
+
+// spend orders of the pulls of a banner
+const (
+	SpendFreeFirst = "free_first"
+	SpendPaidFirst = "paid_first"
+	// SpendPaidOnly never takes free currency, for banners sold for paid currency only
+	SpendPaidOnly = "paid_only"
+)
+
+var ErrUnknownSpendOrder = errors.New("unknown spend order")
+
+// Charge is the part of a cost taken from each bucket
+type Charge struct {
+	Paid int64 `json:"paid"`
+	Free int64 `json:"free"`
+}
+
+// splitCost takes cost from the buckets of the account in the spend order.
+// It fails with ErrInsufficientBalance when the buckets the order may spend
+// can't cover the cost.
+func splitCost(account Account, order string, cost int64) (Charge, error) {
+	var charge Charge
+
+	switch order {
+	case SpendFreeFirst:
+		charge.Free = min64(cost, account.FreeBalance)
+		charge.Paid = cost - charge.Free
+	case SpendPaidFirst:
+		charge.Paid = min64(cost, account.PaidBalance)
+		charge.Free = cost - charge.Paid
+	case SpendPaidOnly:
+		charge.Paid = cost
+	default:
+		return charge, ErrUnknownSpendOrder
+	}
+
+	// a pull paid with tickets charges nothing, even to an overdrawn bucket
+	if cost == 0 {
+		return charge, nil
+	}
+	if charge.Paid > account.PaidBalance || charge.Free > account.FreeBalance {
+		return charge, ErrInsufficientBalance
+	}
+	return charge, nil
+}
+
+// splitPulls spreads a charge over the pulls it paid for.
+// Every pull costs an even share of the charge, the first pulls taking the
+// remainder, and the buckets are drawn down in the spend order, so the first
+// pulls of a free first charge are the ones paid with free currency.
+func splitPulls(charge Charge, order string, count int) []Charge {
+	first, second := &charge.Free, &charge.Paid
+	if order != SpendFreeFirst {
+		first, second = &charge.Paid, &charge.Free
+	}
+
+	total := charge.Paid + charge.Free
+	pulls := make([]Charge, count)
+	for i := range pulls {
+		cost := total / int64(count)
+		if int64(i) < total%int64(count) {
+			cost++
+		}
+
+		pull := &pulls[i]
+		from := min64(cost, *first)
+		*first -= from
+		*second -= cost - from
+
+		if order == SpendFreeFirst {
+			pull.Free, pull.Paid = from, cost-from
+		} else {
+			pull.Paid, pull.Free = from, cost-from
+		}
+	}
+	return pulls
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
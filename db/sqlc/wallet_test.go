@@ -0,0 +1,59 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCost(t *testing.T) {
+	account := Account{PaidBalance: 50, FreeBalance: 20, Balance: 70}
+
+	testCases := []struct {
+		name   string
+		order  string
+		cost   int64
+		charge Charge
+		err    error
+	}{
+		{"FreeFirst", SpendFreeFirst, 30, Charge{Paid: 10, Free: 20}, nil},
+		{"FreeFirstCoveredByFree", SpendFreeFirst, 15, Charge{Free: 15}, nil},
+		{"PaidFirst", SpendPaidFirst, 60, Charge{Paid: 50, Free: 10}, nil},
+		{"PaidOnly", SpendPaidOnly, 30, Charge{Paid: 30}, nil},
+		{"PaidOnlyInsufficient", SpendPaidOnly, 60, Charge{}, ErrInsufficientBalance},
+		{"Insufficient", SpendFreeFirst, 71, Charge{}, ErrInsufficientBalance},
+		{"UnknownOrder", "", 10, Charge{}, ErrUnknownSpendOrder},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			charge, err := splitCost(account, tc.order, tc.cost)
+			require.ErrorIs(t, err, tc.err)
+			if tc.err == nil {
+				require.Equal(t, tc.charge, charge)
+			}
+		})
+	}
+}
+
+func TestSplitCostFree(t *testing.T) {
+	overdrawn := Account{PaidBalance: -10, FreeBalance: 0, Balance: -10}
+
+	charge, err := splitCost(overdrawn, SpendPaidOnly, 0)
+	require.NoError(t, err)
+	require.Zero(t, charge)
+}
+
+func TestSplitPulls(t *testing.T) {
+	// free currency pays for the first pulls, the remainder goes to the first pull
+	pulls := splitPulls(Charge{Paid: 12, Free: 20}, SpendFreeFirst, 3)
+	require.Equal(t, []Charge{{Free: 11}, {Paid: 2, Free: 9}, {Paid: 10}}, pulls)
+
+	pulls = splitPulls(Charge{Paid: 15, Free: 15}, SpendPaidFirst, 3)
+	require.Equal(t, []Charge{{Paid: 10}, {Paid: 5, Free: 5}, {Free: 10}}, pulls)
+
+	pulls = splitPulls(Charge{Paid: 30}, SpendPaidOnly, 1)
+	require.Equal(t, []Charge{{Paid: 30}}, pulls)
+}
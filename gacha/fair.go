@@ -0,0 +1,52 @@
+package gacha
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+const seedBytes = 32
+
+// NewSeed returns a random hex encoded seed
+func NewSeed() (string, error) {
+	b := make([]byte, seedBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashSeed returns the commitment published for a server seed before it is revealed
+func HashSeed(serverSeed string) string {
+	sum := sha256.Sum256([]byte(serverSeed))
+	return hex.EncodeToString(sum[:])
+}
+
+// Roll derives the number in [0, 1) used by the draw with the given nonce.
+// It is HMAC-SHA256(serverSeed, "clientSeed:nonce") with the first 53 bits of the
+// digest read as a big endian fraction, so anyone holding the revealed seeds can
+// recompute it.
+func Roll(serverSeed, clientSeed string, nonce int64) float64 {
+	mac := hmac.New(sha256.New, []byte(serverSeed))
+	fmt.Fprintf(mac, "%s:%d", clientSeed, nonce)
+	digest := mac.Sum(nil)
+	return float64(binary.BigEndian.Uint64(digest[:8])>>11) / (1 << 53)
+}
+
+// FairSource reads consecutive rolls of a server and client seed pair.
+// Every call uses the current nonce and then advances it.
+type FairSource struct {
+	ServerSeed string
+	ClientSeed string
+	Nonce      int64
+}
+
+func (s *FairSource) Float64() float64 {
+	u := Roll(s.ServerSeed, s.ClientSeed, s.Nonce)
+	s.Nonce++
+	return u
+}
@@ -0,0 +1,41 @@
+package gacha
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSeed(t *testing.T) {
+	seed1, err := NewSeed()
+	require.NoError(t, err)
+	require.Len(t, seed1, 2*seedBytes)
+
+	seed2, err := NewSeed()
+	require.NoError(t, err)
+	require.NotEqual(t, seed1, seed2)
+}
+
+func TestHashSeed(t *testing.T) {
+	// sha256 of "abc"
+	require.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", HashSeed("abc"))
+}
+
+func TestRoll(t *testing.T) {
+	u := Roll("server", "client", 0)
+	require.GreaterOrEqual(t, u, 0.0)
+	require.Less(t, u, 1.0)
+
+	require.Equal(t, u, Roll("server", "client", 0))
+	require.NotEqual(t, u, Roll("server", "client", 1))
+	require.NotEqual(t, u, Roll("server", "other", 0))
+	require.NotEqual(t, u, Roll("other", "client", 0))
+}
+
+func TestFairSource(t *testing.T) {
+	src := &FairSource{ServerSeed: "server", ClientSeed: "client", Nonce: 5}
+
+	require.Equal(t, Roll("server", "client", 5), src.Float64())
+	require.Equal(t, Roll("server", "client", 6), src.Float64())
+	require.Equal(t, int64(7), src.Nonce)
+}
@@ -0,0 +1,75 @@
+package gacha
+
+// Pull describes the draws bought by a single request
+type Pull struct {
+	// Count is the number of draws
+	Count int
+	// MinRating is guaranteed on the last draw when no earlier draw reached it, 0 disables it
+	MinRating int32
+	Pity      Pity
+}
+
+// Draw is an entry of a pull along with the state it was drawn in, which
+// Snapshot.Replay needs to draw it again from its roll
+type Draw struct {
+	Entry Entry
+	// Pulls is the pity counter before the draw
+	Pulls int32
+	// MinRating is the rating the draw was guaranteed, 0 when it wasn't
+	MinRating int32
+}
+
+// DrawMulti picks the entries of a pull starting from a pity counter of pulls.
+// When MinRating is set and none of the first Count-1 entries is rated at least
+// MinRating, the last entry is drawn from the matching sub pool instead.
+// Every entry reads exactly one number from src, so the i-th entry of a pull
+// is decided by the i-th roll of the source.
+// It returns the drawn entries and the pity counter after the pull.
+func DrawMulti(pool *Pool, src Source, pull Pull, pulls int32) ([]Entry, int32, error) {
+	draws, pulls, err := DrawPull(pool, src, pull, pulls)
+	if err != nil {
+		return nil, pulls, err
+	}
+
+	entries := make([]Entry, len(draws))
+	for i, draw := range draws {
+		entries[i] = draw.Entry
+	}
+	return entries, pulls, nil
+}
+
+// DrawPull is DrawMulti returning the state every entry was drawn in
+func DrawPull(pool *Pool, src Source, pull Pull, pulls int32) ([]Draw, int32, error) {
+	if pull.MinRating > 0 {
+		if _, err := pool.AtLeast(pull.MinRating); err != nil {
+			return nil, pulls, err
+		}
+	}
+
+	draws := make([]Draw, pull.Count)
+	met := false
+	for i := range draws {
+		draws[i].Pulls = pulls
+		current, err := pull.Pity.Adjust(pool, pulls)
+		if err != nil {
+			return nil, pulls, err
+		}
+
+		if pull.MinRating > 0 && !met && i == pull.Count-1 {
+			current, err = current.AtLeast(pull.MinRating)
+			if err != nil {
+				return nil, pulls, err
+			}
+			draws[i].MinRating = pull.MinRating
+		}
+
+		entry := current.Draw(src)
+		draws[i].Entry = entry
+		if entry.Rating >= pull.MinRating {
+			met = true
+		}
+		pulls = pull.Pity.Next(pool, pulls, entry)
+	}
+
+	return draws, pulls, nil
+}
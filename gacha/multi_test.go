@@ -0,0 +1,119 @@
+package gacha
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type sequenceSource []float64
+
+func (s *sequenceSource) Float64() float64 {
+	u := (*s)[0]
+	*s = (*s)[1:]
+	return u
+}
+
+func newMultiTestPool(t *testing.T) *Pool {
+	entries := []Entry{
+		{ItemID: 1, Rating: 1, Weight: 1},
+		{ItemID: 2, Rating: 5, Weight: 1},
+	}
+	pool, err := NewPool(entries, Rates{1: 99, 5: 1})
+	require.NoError(t, err)
+	return pool
+}
+
+func TestDrawMultiGuarantee(t *testing.T) {
+	pool := newMultiTestPool(t)
+
+	entries, _, err := DrawMulti(pool, fixedSource(0), Pull{Count: 10, MinRating: 5}, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 10)
+	for _, e := range entries[:9] {
+		require.Equal(t, int32(1), e.Rating)
+	}
+	require.Equal(t, int32(5), entries[9].Rating)
+}
+
+func TestDrawMultiGuaranteeAlreadyMet(t *testing.T) {
+	pool := newMultiTestPool(t)
+
+	src := sequenceSource{0, 0.995, 0}
+	entries, _, err := DrawMulti(pool, &src, Pull{Count: 3, MinRating: 5}, 0)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), entries[0].Rating)
+	require.Equal(t, int32(5), entries[1].Rating)
+	require.Equal(t, int32(1), entries[2].Rating)
+}
+
+func TestDrawMultiWithoutGuarantee(t *testing.T) {
+	pool := newMultiTestPool(t)
+
+	entries, _, err := DrawMulti(pool, fixedSource(0), Pull{Count: 10}, 0)
+	require.NoError(t, err)
+	for _, e := range entries {
+		require.Equal(t, int32(1), e.Rating)
+	}
+}
+
+func TestDrawMultiUnreachableGuarantee(t *testing.T) {
+	pool := newMultiTestPool(t)
+
+	_, _, err := DrawMulti(pool, fixedSource(0), Pull{Count: 10, MinRating: 7}, 0)
+	require.ErrorIs(t, err, ErrEmptyPool)
+}
+
+func TestDrawMultiPityCounter(t *testing.T) {
+	pool := newMultiTestPool(t)
+	pity := Pity{Hard: 5}
+
+	entries, pulls, err := DrawMulti(pool, fixedSource(0), Pull{Count: 3, Pity: pity}, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.Equal(t, int32(3), pulls)
+
+	// the 5th pull since the last top rarity hits hard pity and resets the counter
+	entries, pulls, err = DrawMulti(pool, fixedSource(0), Pull{Count: 3, Pity: pity}, pulls)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), entries[0].Rating)
+	require.Equal(t, int32(5), entries[1].Rating)
+	require.Equal(t, int32(1), entries[2].Rating)
+	require.Equal(t, int32(1), pulls)
+}
+
+func TestDrawPullReplay(t *testing.T) {
+	pool := newMultiTestPool(t)
+	pull := Pull{Count: 10, MinRating: 5, Pity: Pity{Soft: 2, Hard: 4, Step: 10}}
+
+	src := &FairSource{ServerSeed: "server", ClientSeed: "client"}
+	draws, _, err := DrawPull(pool, src, pull, 1)
+	require.NoError(t, err)
+	require.Len(t, draws, 10)
+	require.Equal(t, int32(1), draws[0].Pulls)
+
+	snapshot := NewSnapshot(pool, pull.Pity)
+	data, digest, err := snapshot.Encode()
+	require.NoError(t, err)
+
+	// the snapshot read back replays every draw from its roll
+	var stored Snapshot
+	require.NoError(t, json.Unmarshal(data, &stored))
+	_, storedDigest, err := stored.Encode()
+	require.NoError(t, err)
+	require.Equal(t, digest, storedDigest)
+
+	for i, draw := range draws {
+		entry, err := stored.Replay(Roll("server", "client", int64(i)), draw.Pulls, draw.MinRating)
+		require.NoError(t, err)
+		require.Equal(t, draw.Entry, entry)
+	}
+
+	// other rates are another snapshot
+	other, err := NewPool(pool.Entries(), Rates{1: 1, 5: 1})
+	require.NoError(t, err)
+	_, otherDigest, err := NewSnapshot(other, pull.Pity).Encode()
+	require.NoError(t, err)
+	require.NotEqual(t, digest, otherDigest)
+}
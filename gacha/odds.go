@@ -0,0 +1,44 @@
+package gacha
+
+// TierOdds is the chance of drawing any entry of a rarity tier
+type TierOdds struct {
+	Rating      int32   `json:"rating"`
+	Probability float64 `json:"probability"`
+}
+
+// EntryOdds is the chance of drawing a single entry
+type EntryOdds struct {
+	ItemID      int64   `json:"item_id"`
+	Rating      int32   `json:"rating"`
+	Probability float64 `json:"probability"`
+}
+
+// Odds lists the drop rates of a pool, both per tier and per entry
+type Odds struct {
+	Tiers   []TierOdds  `json:"tiers"`
+	Entries []EntryOdds `json:"entries"`
+}
+
+// Odds computes the drop rates of the pool from the probabilities Draw uses.
+// Entries that cannot be drawn are listed with a probability of 0.
+func (p *Pool) Odds() Odds {
+	odds := Odds{
+		Entries: make([]EntryOdds, len(p.entries)),
+	}
+
+	for i, e := range p.entries {
+		odds.Entries[i] = EntryOdds{
+			ItemID:      e.ItemID,
+			Rating:      e.Rating,
+			Probability: p.probs[i],
+		}
+
+		// entries are ordered by rating, so a tier is a run of entries
+		if n := len(odds.Tiers); n == 0 || odds.Tiers[n-1].Rating != e.Rating {
+			odds.Tiers = append(odds.Tiers, TierOdds{Rating: e.Rating})
+		}
+		odds.Tiers[len(odds.Tiers)-1].Probability += p.probs[i]
+	}
+
+	return odds
+}
@@ -0,0 +1,42 @@
+package gacha
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOdds(t *testing.T) {
+	entries := []Entry{
+		{ItemID: 4, Rating: 5, Weight: 1},
+		{ItemID: 1, Rating: 1, Weight: 1},
+		{ItemID: 2, Rating: 1, Weight: 3},
+		{ItemID: 3, Rating: 3, Weight: 0},
+	}
+	rates := Rates{1: 80, 3: 10, 5: 20}
+
+	pool, err := NewPool(entries, rates)
+	require.NoError(t, err)
+
+	odds := pool.Odds()
+	require.Len(t, odds.Tiers, 3)
+	require.Equal(t, int32(1), odds.Tiers[0].Rating)
+	require.InDelta(t, 0.8, odds.Tiers[0].Probability, 1e-9)
+	require.Equal(t, int32(3), odds.Tiers[1].Rating)
+	require.Zero(t, odds.Tiers[1].Probability)
+	require.Equal(t, int32(5), odds.Tiers[2].Rating)
+	require.InDelta(t, 0.2, odds.Tiers[2].Probability, 1e-9)
+
+	require.Len(t, odds.Entries, 4)
+	require.Equal(t, int64(1), odds.Entries[0].ItemID)
+	require.InDelta(t, 0.2, odds.Entries[0].Probability, 1e-9)
+	require.InDelta(t, 0.6, odds.Entries[1].Probability, 1e-9)
+	require.Zero(t, odds.Entries[2].Probability)
+	require.InDelta(t, 0.2, odds.Entries[3].Probability, 1e-9)
+
+	var total float64
+	for _, e := range odds.Entries {
+		total += e.Probability
+	}
+	require.InDelta(t, 1, total, 1e-9)
+}
@@ -0,0 +1,40 @@
+package gacha
+
+// Pity raises the chance of the top rarity of a pool after a streak of pulls without it
+type Pity struct {
+	// Soft is the number of pulls after which the top tier rate ramps up, 0 disables it
+	Soft int32 `json:"soft"`
+	// Hard is the pull at which the top rarity is guaranteed, 0 disables it
+	Hard int32 `json:"hard"`
+	// Step is the rate added to the top tier for every pull past Soft
+	Step int64 `json:"step"`
+}
+
+// Adjust returns the pool of the next pull after pulls draws without the top rarity
+func (pity Pity) Adjust(pool *Pool, pulls int32) (*Pool, error) {
+	top := pool.TopRating()
+	next := pulls + 1
+
+	if pity.Hard > 0 && next >= pity.Hard {
+		return pool.AtLeast(top)
+	}
+
+	if pity.Soft > 0 && pity.Step > 0 && next > pity.Soft {
+		rates := make(Rates, len(pool.rates))
+		for rating, rate := range pool.rates {
+			rates[rating] = rate
+		}
+		rates[top] += pity.Step * int64(next-pity.Soft)
+		return NewPool(pool.entries, rates)
+	}
+
+	return pool, nil
+}
+
+// Next returns the pity counter after drawing entry from pool
+func (pity Pity) Next(pool *Pool, pulls int32, entry Entry) int32 {
+	if entry.Rating >= pool.TopRating() {
+		return 0
+	}
+	return pulls + 1
+}
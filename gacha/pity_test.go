@@ -0,0 +1,65 @@
+package gacha
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newPityTestPool(t *testing.T) *Pool {
+	entries := []Entry{
+		{ItemID: 1, Rating: 3, Weight: 1},
+		{ItemID: 2, Rating: 6, Weight: 1},
+	}
+	pool, err := NewPool(entries, Rates{3: 90, 6: 10})
+	require.NoError(t, err)
+	return pool
+}
+
+func TestTopRating(t *testing.T) {
+	entries := []Entry{
+		{ItemID: 1, Rating: 3, Weight: 1},
+		{ItemID: 2, Rating: 6, Weight: 1},
+		{ItemID: 3, Rating: 7, Weight: 0},
+	}
+	pool, err := NewPool(entries, Rates{3: 90, 6: 10, 7: 5})
+	require.NoError(t, err)
+	require.Equal(t, int32(6), pool.TopRating())
+}
+
+func TestPityAdjust(t *testing.T) {
+	pool := newPityTestPool(t)
+	pity := Pity{Soft: 5, Hard: 10, Step: 20}
+
+	// before soft pity the pool is unchanged
+	adjusted, err := pity.Adjust(pool, 4)
+	require.NoError(t, err)
+	require.InDelta(t, 0.1, adjusted.Probability(1), 1e-9)
+
+	// the 7th pull is two pulls past soft pity
+	adjusted, err = pity.Adjust(pool, 6)
+	require.NoError(t, err)
+	require.InDelta(t, 50.0/140.0, adjusted.Probability(1), 1e-9)
+
+	// the 10th pull is guaranteed
+	adjusted, err = pity.Adjust(pool, 9)
+	require.NoError(t, err)
+	require.Len(t, adjusted.Entries(), 1)
+	require.Equal(t, int32(6), adjusted.Entries()[0].Rating)
+}
+
+func TestPityDisabled(t *testing.T) {
+	pool := newPityTestPool(t)
+
+	adjusted, err := Pity{}.Adjust(pool, 1000)
+	require.NoError(t, err)
+	require.Same(t, pool, adjusted)
+}
+
+func TestPityNext(t *testing.T) {
+	pool := newPityTestPool(t)
+	pity := Pity{Hard: 10}
+
+	require.Equal(t, int32(4), pity.Next(pool, 3, Entry{ItemID: 1, Rating: 3}))
+	require.Equal(t, int32(0), pity.Next(pool, 3, Entry{ItemID: 2, Rating: 6}))
+}
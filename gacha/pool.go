@@ -0,0 +1,142 @@
+package gacha
+
+import (
+	"errors"
+	"sort"
+)
+
+const (
+	MinRating = 1
+	MaxRating = 7
+)
+
+var ErrEmptyPool = errors.New("gacha pool has no drawable item")
+var ErrInvalidRating = errors.New("rating must be between 1 and 7")
+
+// Entry is a single drawable item of a pool
+type Entry struct {
+	ItemID int64 `json:"item_id"`
+	Rating int32 `json:"rating"`
+	Weight int64 `json:"weight"`
+}
+
+// Rates maps an item rating to the relative weight of its rarity tier
+type Rates map[int32]int64
+
+// Source is a source of uniformly distributed numbers in [0, 1)
+type Source interface {
+	Float64() float64
+}
+
+// Pool is a weighted set of entries grouped into rarity tiers.
+// A tier is drawn first by its rate, then an entry of the tier by its weight.
+type Pool struct {
+	entries    []Entry
+	rates      Rates
+	probs      []float64
+	cumulative []float64
+}
+
+// NewPool builds a pool from entries and per rarity rates.
+// Tiers without any entry are skipped and the remaining rates are renormalized,
+// so the drop rates only depend on the configuration and not on item ids.
+func NewPool(entries []Entry, rates Rates) (*Pool, error) {
+	tierWeights := make(map[int32]int64)
+	for _, e := range entries {
+		if e.Rating < MinRating || e.Rating > MaxRating {
+			return nil, ErrInvalidRating
+		}
+		if e.Weight > 0 && rates[e.Rating] > 0 {
+			tierWeights[e.Rating] += e.Weight
+		}
+	}
+
+	var rateTotal int64
+	for rating := range tierWeights {
+		rateTotal += rates[rating]
+	}
+	if rateTotal == 0 {
+		return nil, ErrEmptyPool
+	}
+
+	sorted := make([]Entry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Rating != sorted[j].Rating {
+			return sorted[i].Rating < sorted[j].Rating
+		}
+		return sorted[i].ItemID < sorted[j].ItemID
+	})
+
+	pool := &Pool{
+		entries:    sorted,
+		rates:      rates,
+		probs:      make([]float64, len(sorted)),
+		cumulative: make([]float64, len(sorted)),
+	}
+
+	var sum float64
+	for i, e := range sorted {
+		if total := tierWeights[e.Rating]; total > 0 && e.Weight > 0 {
+			tier := float64(rates[e.Rating]) / float64(rateTotal)
+			pool.probs[i] = tier * float64(e.Weight) / float64(total)
+		}
+		sum += pool.probs[i]
+		pool.cumulative[i] = sum
+	}
+
+	return pool, nil
+}
+
+// Draw picks a single entry using a number read from src
+func (p *Pool) Draw(src Source) Entry {
+	return p.entries[p.index(src.Float64())]
+}
+
+// index maps u in [0, 1) to the entry whose cumulative range contains it
+func (p *Pool) index(u float64) int {
+	u *= p.cumulative[len(p.cumulative)-1]
+	i := sort.Search(len(p.cumulative), func(i int) bool {
+		return p.cumulative[i] > u
+	})
+	// float rounding can push u past the last bucket
+	if i == len(p.cumulative) {
+		i--
+	}
+	for p.probs[i] == 0 {
+		i--
+	}
+	return i
+}
+
+// Entries returns the entries of the pool ordered by rating and item id
+func (p *Pool) Entries() []Entry {
+	return p.entries
+}
+
+// Probability returns the chance of drawing the i-th entry of Entries
+func (p *Pool) Probability(i int) float64 {
+	return p.probs[i]
+}
+
+// AtLeast returns the sub pool of entries rated minRating or higher.
+// Tiers keep their relative rates, so the sub pool matches the pool
+// conditioned on drawing such an entry.
+func (p *Pool) AtLeast(minRating int32) (*Pool, error) {
+	var entries []Entry
+	for _, e := range p.entries {
+		if e.Rating >= minRating {
+			entries = append(entries, e)
+		}
+	}
+	return NewPool(entries, p.rates)
+}
+
+// TopRating returns the highest rating that can be drawn from the pool
+func (p *Pool) TopRating() int32 {
+	for i := len(p.entries) - 1; ; i-- {
+		if p.probs[i] > 0 {
+			return p.entries[i].Rating
+		}
+	}
+}
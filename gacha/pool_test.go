@@ -0,0 +1,103 @@
+package gacha
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fixedSource float64
+
+func (f fixedSource) Float64() float64 {
+	return float64(f)
+}
+
+func TestNewPool(t *testing.T) {
+	entries := []Entry{
+		{ItemID: 3, Rating: 7, Weight: 1},
+		{ItemID: 1, Rating: 1, Weight: 1},
+		{ItemID: 2, Rating: 1, Weight: 3},
+	}
+	rates := Rates{1: 90, 7: 10}
+
+	pool, err := NewPool(entries, rates)
+	require.NoError(t, err)
+
+	got := pool.Entries()
+	require.Len(t, got, 3)
+	require.Equal(t, int64(1), got[0].ItemID)
+	require.Equal(t, int64(2), got[1].ItemID)
+	require.Equal(t, int64(3), got[2].ItemID)
+
+	require.InDelta(t, 0.225, pool.Probability(0), 1e-9)
+	require.InDelta(t, 0.675, pool.Probability(1), 1e-9)
+	require.InDelta(t, 0.1, pool.Probability(2), 1e-9)
+}
+
+func TestNewPoolRenormalizesMissingTiers(t *testing.T) {
+	entries := []Entry{
+		{ItemID: 1, Rating: 1, Weight: 1},
+		{ItemID: 2, Rating: 5, Weight: 1},
+	}
+	rates := Rates{1: 60, 3: 20, 5: 20}
+
+	pool, err := NewPool(entries, rates)
+	require.NoError(t, err)
+	require.InDelta(t, 0.75, pool.Probability(0), 1e-9)
+	require.InDelta(t, 0.25, pool.Probability(1), 1e-9)
+}
+
+func TestNewPoolErrors(t *testing.T) {
+	_, err := NewPool(nil, Rates{1: 1})
+	require.ErrorIs(t, err, ErrEmptyPool)
+
+	_, err = NewPool([]Entry{{ItemID: 1, Rating: 2, Weight: 1}}, Rates{1: 1})
+	require.ErrorIs(t, err, ErrEmptyPool)
+
+	_, err = NewPool([]Entry{{ItemID: 1, Rating: 8, Weight: 1}}, Rates{8: 1})
+	require.ErrorIs(t, err, ErrInvalidRating)
+}
+
+func TestDraw(t *testing.T) {
+	entries := []Entry{
+		{ItemID: 1, Rating: 1, Weight: 1},
+		{ItemID: 2, Rating: 2, Weight: 0},
+		{ItemID: 3, Rating: 3, Weight: 1},
+	}
+	rates := Rates{1: 50, 2: 25, 3: 50}
+
+	pool, err := NewPool(entries, rates)
+	require.NoError(t, err)
+
+	require.Equal(t, int64(1), pool.Draw(fixedSource(0)).ItemID)
+	require.Equal(t, int64(1), pool.Draw(fixedSource(0.4999)).ItemID)
+	require.Equal(t, int64(3), pool.Draw(fixedSource(0.5)).ItemID)
+	require.Equal(t, int64(3), pool.Draw(fixedSource(0.99999999)).ItemID)
+
+	src := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		require.NotEqual(t, int64(2), pool.Draw(src).ItemID)
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	entries := []Entry{
+		{ItemID: 1, Rating: 1, Weight: 1},
+		{ItemID: 2, Rating: 4, Weight: 1},
+		{ItemID: 3, Rating: 6, Weight: 1},
+	}
+	rates := Rates{1: 70, 4: 20, 6: 10}
+
+	pool, err := NewPool(entries, rates)
+	require.NoError(t, err)
+
+	sub, err := pool.AtLeast(4)
+	require.NoError(t, err)
+	require.Len(t, sub.Entries(), 2)
+	require.InDelta(t, 2.0/3.0, sub.Probability(0), 1e-9)
+	require.InDelta(t, 1.0/3.0, sub.Probability(1), 1e-9)
+
+	_, err = pool.AtLeast(7)
+	require.ErrorIs(t, err, ErrEmptyPool)
+}
@@ -0,0 +1,18 @@
+package gacha
+
+// shardValues is the number of shards a converted copy is worth by rating
+var shardValues = map[int32]int64{
+	1: 1,
+	2: 2,
+	3: 5,
+	4: 10,
+	5: 25,
+	6: 50,
+	7: 100,
+}
+
+// ShardValue returns the shards credited for converting a copy of an item of the given rating.
+// Ratings outside of 1 to 7 are worth nothing.
+func ShardValue(rating int32) int64 {
+	return shardValues[rating]
+}
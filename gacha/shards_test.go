@@ -0,0 +1,17 @@
+package gacha
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardValue(t *testing.T) {
+	// rarer copies are always worth more
+	for rating := int32(2); rating <= 7; rating++ {
+		require.Greater(t, ShardValue(rating), ShardValue(rating-1))
+	}
+
+	require.Zero(t, ShardValue(0))
+	require.Zero(t, ShardValue(8))
+}
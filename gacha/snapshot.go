@@ -0,0 +1,66 @@
+package gacha
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// Snapshot is everything a draw depends on besides its roll and pity counter:
+// the entries and rates of the pool and the pity rules of the banner.
+// It is stored with the draws so they can be replayed after the banner changed.
+type Snapshot struct {
+	Entries []Entry `json:"entries"`
+	Rates   Rates   `json:"rates"`
+	Pity    Pity    `json:"pity"`
+}
+
+// NewSnapshot captures a pool and the pity rules it is drawn with
+func NewSnapshot(pool *Pool, pity Pity) Snapshot {
+	return Snapshot{
+		Entries: pool.entries,
+		Rates:   pool.rates,
+		Pity:    pity,
+	}
+}
+
+// Encode returns the JSON of the snapshot and its digest, the hex SHA-256 of the
+// JSON. The encoding is stable, entries being sorted and rates keyed in order,
+// so a snapshot read back from its JSON encodes to the same digest.
+func (s Snapshot) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(data)
+	return data, hex.EncodeToString(sum[:]), nil
+}
+
+// rollSource returns the same roll on every read
+type rollSource float64
+
+func (r rollSource) Float64() float64 {
+	return float64(r)
+}
+
+// Replay draws again the entry of a roll made with a pity counter of pulls and
+// a guaranteed minRating, 0 when the draw had none, the way DrawPull drew it
+func (s Snapshot) Replay(roll float64, pulls int32, minRating int32) (Entry, error) {
+	pool, err := NewPool(s.Entries, s.Rates)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	current, err := s.Pity.Adjust(pool, pulls)
+	if err != nil {
+		return Entry{}, err
+	}
+	if minRating > 0 {
+		current, err = current.AtLeast(minRating)
+		if err != nil {
+			return Entry{}, err
+		}
+	}
+
+	return current.Draw(rollSource(roll)), nil
+}
@@ -0,0 +1,158 @@
+package gacha
+
+import "math"
+
+// minExpected is the smallest expected count of a chi-square bin.
+// Sparser bins are pooled, as the chi-square approximation breaks down below it.
+const minExpected = 5
+
+// ConfiguredProbabilities computes the chance of every entry straight from the
+// configured weights and rates, without the tables Draw uses.
+// The probabilities are indexed like the entries passed in.
+func ConfiguredProbabilities(entries []Entry, rates Rates) []float64 {
+	tierWeights := make(map[int32]int64)
+	for _, e := range entries {
+		if e.Weight > 0 && rates[e.Rating] > 0 {
+			tierWeights[e.Rating] += e.Weight
+		}
+	}
+
+	var rateTotal int64
+	for rating := range tierWeights {
+		rateTotal += rates[rating]
+	}
+
+	probs := make([]float64, len(entries))
+	if rateTotal == 0 {
+		return probs
+	}
+	for i, e := range entries {
+		if total := tierWeights[e.Rating]; total > 0 && e.Weight > 0 {
+			probs[i] = float64(rates[e.Rating]) / float64(rateTotal) * float64(e.Weight) / float64(total)
+		}
+	}
+	return probs
+}
+
+// Simulate draws n times from pool and counts the draws of every entry.
+// The counts are indexed like pool.Entries.
+func Simulate(pool *Pool, src Source, n int) []int64 {
+	counts := make([]int64, len(pool.entries))
+	for i := 0; i < n; i++ {
+		counts[pool.index(src.Float64())]++
+	}
+	return counts
+}
+
+// ChiSquareResult is the outcome of a goodness of fit test
+type ChiSquareResult struct {
+	Statistic float64 `json:"statistic"`
+	DF        int     `json:"df"`
+	PValue    float64 `json:"p_value"`
+}
+
+// ChiSquare runs Pearson's goodness of fit test of observed counts against the
+// expected probabilities of the same bins.
+// Bins expected to hold fewer than 5 draws are pooled together, and a draw
+// observed in a bin of probability 0 fails the test outright.
+func ChiSquare(observed []int64, probs []float64) ChiSquareResult {
+	var n int64
+	for _, o := range observed {
+		n += o
+	}
+
+	var stat float64
+	var bins int
+	var sparseObserved int64
+	var sparseExpected float64
+	for i, o := range observed {
+		expected := probs[i] * float64(n)
+		switch {
+		case probs[i] == 0:
+			if o > 0 {
+				return ChiSquareResult{Statistic: math.Inf(1), PValue: 0}
+			}
+		case expected < minExpected:
+			sparseObserved += o
+			sparseExpected += expected
+		default:
+			d := float64(o) - expected
+			stat += d * d / expected
+			bins++
+		}
+	}
+	if sparseExpected > 0 {
+		d := float64(sparseObserved) - sparseExpected
+		stat += d * d / sparseExpected
+		bins++
+	}
+
+	result := ChiSquareResult{Statistic: stat, DF: bins - 1, PValue: 1}
+	if result.DF > 0 {
+		result.PValue = gammaQ(float64(result.DF)/2, stat/2)
+	}
+	return result
+}
+
+// TierCounts sums per entry values of pool into its rarity tiers.
+// It returns the ratings of the tiers in ascending order and their sums.
+func TierCounts(pool *Pool, counts []int64) ([]int32, []int64) {
+	var ratings []int32
+	var sums []int64
+	for i, e := range pool.entries {
+		if n := len(ratings); n == 0 || ratings[n-1] != e.Rating {
+			ratings = append(ratings, e.Rating)
+			sums = append(sums, 0)
+		}
+		sums[len(sums)-1] += counts[i]
+	}
+	return ratings, sums
+}
+
+// gammaQ is the regularized upper incomplete gamma function Q(a, x),
+// evaluated with its series below a+1 and its continued fraction above.
+func gammaQ(a, x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	lg, _ := math.Lgamma(a)
+
+	if x < a+1 {
+		sum := 1 / a
+		term := sum
+		for n := 1; n < 1000; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+		return 1 - sum*math.Exp(-x+a*math.Log(x)-lg)
+	}
+
+	// modified Lentz's method
+	const tiny = 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < 1000; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < 1e-15 {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lg) * h
+}
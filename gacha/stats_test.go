@@ -0,0 +1,168 @@
+package gacha
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// significance is the p value below which a simulated distribution fails.
+// The sources are seeded, so a passing configuration keeps passing.
+const significance = 1e-3
+
+func TestGammaQ(t *testing.T) {
+	require.InDelta(t, math.Exp(-2), gammaQ(1, 2), 1e-12)
+	require.InDelta(t, math.Exp(-30), gammaQ(1, 30), 1e-20)
+
+	// chi-square critical values at 5%
+	require.InDelta(t, 0.05, gammaQ(0.5, 3.841459/2), 1e-6)
+	require.InDelta(t, 0.05, gammaQ(5, 18.307038/2), 1e-6)
+	require.InDelta(t, 0.05, gammaQ(50, 124.342113/2), 1e-6)
+}
+
+func TestChiSquare(t *testing.T) {
+	result := ChiSquare([]int64{250, 250, 500}, []float64{0.25, 0.25, 0.5})
+	require.Zero(t, result.Statistic)
+	require.Equal(t, 2, result.DF)
+	require.InDelta(t, 1, result.PValue, 1e-12)
+
+	result = ChiSquare([]int64{300, 200, 500}, []float64{0.25, 0.25, 0.5})
+	require.InDelta(t, 20, result.Statistic, 1e-9)
+	require.Less(t, result.PValue, significance)
+
+	// a single draw of an impossible entry fails the test
+	result = ChiSquare([]int64{500, 499, 1}, []float64{0.5, 0.5, 0})
+	require.True(t, math.IsInf(result.Statistic, 1))
+	require.Zero(t, result.PValue)
+}
+
+func TestChiSquarePoolsSparseBins(t *testing.T) {
+	// the two rare bins expect 1 draw each and are tested as a single bin
+	result := ChiSquare([]int64{499, 499, 2, 0}, []float64{0.499, 0.499, 0.001, 0.001})
+	require.Equal(t, 2, result.DF)
+	require.Zero(t, result.Statistic)
+}
+
+// skewedSource favours low numbers, like a biased generator would
+type skewedSource struct {
+	rnd *rand.Rand
+}
+
+func (s skewedSource) Float64() float64 {
+	u := s.rnd.Float64()
+	return u * (0.98 + 0.02*u)
+}
+
+func TestChiSquareDetectsBias(t *testing.T) {
+	entries, rates := defaultTestConfig()
+	pool, err := NewPool(entries, rates)
+	require.NoError(t, err)
+
+	src := skewedSource{rnd: rand.New(rand.NewSource(1))}
+	counts := Simulate(pool, src, 1000000)
+
+	result := ChiSquare(counts, ConfiguredProbabilities(pool.Entries(), rates))
+	require.Less(t, result.PValue, significance)
+}
+
+// defaultTestConfig mirrors the seeded rarity rates with a few items per tier
+func defaultTestConfig() ([]Entry, Rates) {
+	rates := Rates{1: 4000, 2: 2500, 3: 1500, 4: 1000, 5: 600, 6: 300, 7: 100}
+
+	var entries []Entry
+	var id int64
+	for rating := int32(MinRating); rating <= MaxRating; rating++ {
+		for i := 0; i < 3; i++ {
+			id++
+			entries = append(entries, Entry{ItemID: id, Rating: rating, Weight: 1})
+		}
+	}
+	return entries, rates
+}
+
+func TestDrawDistribution(t *testing.T) {
+	defaultEntries, defaultRates := defaultTestConfig()
+
+	testCases := []struct {
+		name    string
+		entries []Entry
+		rates   Rates
+	}{
+		{
+			name:    "DefaultRates",
+			entries: defaultEntries,
+			rates:   defaultRates,
+		},
+		{
+			name: "WeightedItems",
+			entries: []Entry{
+				{ItemID: 1, Rating: 1, Weight: 10},
+				{ItemID: 2, Rating: 1, Weight: 1},
+				{ItemID: 3, Rating: 5, Weight: 3},
+				{ItemID: 4, Rating: 5, Weight: 1},
+				{ItemID: 5, Rating: 7, Weight: 1},
+			},
+			rates: Rates{1: 900, 5: 95, 7: 5},
+		},
+		{
+			name: "MissingTiersAndDisabledItems",
+			entries: []Entry{
+				{ItemID: 1, Rating: 2, Weight: 1},
+				{ItemID: 2, Rating: 2, Weight: 0},
+				{ItemID: 3, Rating: 6, Weight: 2},
+				{ItemID: 4, Rating: 7, Weight: 1},
+			},
+			rates: Rates{1: 5000, 2: 3000, 6: 300, 7: 0},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			pool, err := NewPool(tc.entries, tc.rates)
+			require.NoError(t, err)
+
+			src := rand.New(rand.NewSource(1))
+			counts := Simulate(pool, src, 1000000)
+			probs := ConfiguredProbabilities(pool.Entries(), tc.rates)
+
+			result := ChiSquare(counts, probs)
+			require.Greater(t, result.PValue, significance, "entries: %+v", result)
+
+			ratings, tierCounts := TierCounts(pool, counts)
+			_, tierProbs := tierProbabilities(pool, probs)
+			require.Len(t, ratings, len(tierProbs))
+
+			result = ChiSquare(tierCounts, tierProbs)
+			require.Greater(t, result.PValue, significance, "tiers: %+v", result)
+		})
+	}
+}
+
+func TestFairSourceDistribution(t *testing.T) {
+	entries, rates := defaultTestConfig()
+	pool, err := NewPool(entries, rates)
+	require.NoError(t, err)
+
+	src := &FairSource{ServerSeed: "distribution", ClientSeed: "test"}
+	counts := Simulate(pool, src, 200000)
+
+	result := ChiSquare(counts, ConfiguredProbabilities(pool.Entries(), rates))
+	require.Greater(t, result.PValue, significance)
+}
+
+func tierProbabilities(pool *Pool, probs []float64) ([]int32, []float64) {
+	var ratings []int32
+	var sums []float64
+	for i, e := range pool.Entries() {
+		if n := len(ratings); n == 0 || ratings[n-1] != e.Rating {
+			ratings = append(ratings, e.Rating)
+			sums = append(sums, 0)
+		}
+		sums[len(sums)-1] += probs[i]
+	}
+	return ratings, sums
+}
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+)
+
+// DeleteExpiredIdempotencyKeys sweeps the idempotency keys past their expiry
+func DeleteExpiredIdempotencyKeys(store db.Store, interval time.Duration) Job {
+	return Job{
+		Name:     "delete_expired_idempotency_keys",
+		Interval: interval,
+		Run: func(ctx context.Context, now time.Time) error {
+			deleted, err := store.DeleteExpiredIdempotencyKeys(ctx, now)
+			if err != nil {
+				return err
+			}
+			if deleted > 0 {
+				log.Printf("deleted %d expired idempotency keys", deleted)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is a task run in the background every Interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context, now time.Time) error
+}
+
+// Run runs every job once, then on every tick of its interval until ctx is
+// done. A failed run is logged and retried on the next tick, and a job
+// without an interval is disabled.
+func Run(ctx context.Context, jobs ...Job) {
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		if job.Interval <= 0 {
+			log.Printf("job %s is disabled", job.Name)
+			continue
+		}
+
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			loop(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+}
+
+func loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	now := time.Now()
+	for {
+		if err := job.Run(ctx, now); err != nil && ctx.Err() == nil {
+			log.Printf("job %s failed: %v", job.Name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case now = <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/sRRRs-7/GachaPon/db/mock"
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/shop"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var runs, failed int32
+	job := Job{
+		Name:     "count",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context, now time.Time) error {
+			if atomic.AddInt32(&runs, 1) >= 3 {
+				cancel()
+			}
+			return nil
+		},
+	}
+	// a failing job keeps running on its ticks
+	failing := Job{
+		Name:     "fail",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context, now time.Time) error {
+			atomic.AddInt32(&failed, 1)
+			return errors.New("boom")
+		},
+	}
+	disabled := Job{
+		Name: "disabled",
+		Run: func(ctx context.Context, now time.Time) error {
+			t.Error("disabled job ran")
+			return nil
+		},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, job, failing, disabled)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("jobs didn't stop")
+	}
+	require.GreaterOrEqual(t, atomic.LoadInt32(&runs), int32(3))
+	require.GreaterOrEqual(t, atomic.LoadInt32(&failed), int32(1))
+}
+
+func TestExpireSubscriptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ExpireSubscriptions(gomock.Any(), gomock.Eq(now)).
+		Times(1).
+		Return([]db.Subscription{{ID: 1, Status: db.SubscriptionExpired}}, nil)
+
+	job := ExpireSubscriptions(store, time.Hour)
+	require.Equal(t, time.Hour, job.Interval)
+	require.NoError(t, job.Run(context.Background(), now))
+
+	store.EXPECT().
+		ExpireSubscriptions(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil, errors.New("boom"))
+	require.Error(t, job.Run(context.Background(), now))
+}
+
+func TestExpireTradeOffers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ExpireTradeOffersTx(gomock.Any(), gomock.Eq(now)).
+		Times(1).
+		Return([]db.Approval{{ID: 1, Status: db.TradeExpired}}, nil)
+
+	job := ExpireTradeOffers(store, time.Hour)
+	require.Equal(t, time.Hour, job.Interval)
+	require.NoError(t, job.Run(context.Background(), now))
+
+	store.EXPECT().
+		ExpireTradeOffersTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil, errors.New("boom"))
+	require.Error(t, job.Run(context.Background(), now))
+}
+
+func TestDeleteExpiredIdempotencyKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		DeleteExpiredIdempotencyKeys(gomock.Any(), gomock.Eq(now)).
+		Times(1).
+		Return(int64(2), nil)
+
+	job := DeleteExpiredIdempotencyKeys(store, time.Hour)
+	require.Equal(t, time.Hour, job.Interval)
+	require.NoError(t, job.Run(context.Background(), now))
+
+	store.EXPECT().
+		DeleteExpiredIdempotencyKeys(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(int64(0), errors.New("boom"))
+	require.Error(t, job.Run(context.Background(), now))
+}
+
+func TestRotateShop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 4, 1, 23, 30, 0, 0, time.FixedZone("JST", 9*60*60))
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		RotateShopTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.RotateShopTxParams) (db.RotateShopTxResult, error) {
+			// the day is the current UTC day
+			require.Equal(t, time.UTC, arg.Day.Location())
+			require.True(t, now.Equal(arg.Day))
+			require.Equal(t, shop.RotationSize, arg.Size)
+			require.NotNil(t, arg.Source)
+			return db.RotateShopTxResult{Offers: []db.ShopOffer{{ID: 1}}, Created: true}, nil
+		})
+
+	job := RotateShop(store, time.Hour)
+	require.Equal(t, time.Hour, job.Interval)
+	require.NoError(t, job.Run(context.Background(), now))
+
+	store.EXPECT().
+		RotateShopTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.RotateShopTxResult{}, errors.New("boom"))
+	require.Error(t, job.Run(context.Background(), now))
+}
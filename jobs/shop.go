@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"time"
+
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/shop"
+)
+
+// RotateShop generates the rotating offers of the current UTC day, a day
+// already rotated is left as it is, so the job can run often
+func RotateShop(store db.Store, interval time.Duration) Job {
+	return Job{
+		Name:     "rotate_shop",
+		Interval: interval,
+		Run: func(ctx context.Context, now time.Time) error {
+			result, err := store.RotateShopTx(ctx, db.RotateShopTxParams{
+				Day:    now.UTC(),
+				Size:   shop.RotationSize,
+				Source: rand.New(rand.NewSource(now.UnixNano())),
+			})
+			if err != nil {
+				return err
+			}
+			if result.Created {
+				log.Printf("rotated %d shop offers for %s", len(result.Offers), shop.Day(now).Format("2006-01-02"))
+			}
+			return nil
+		},
+	}
+}
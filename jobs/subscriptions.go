@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+)
+
+// ExpireSubscriptions moves the passes past their expiry to expired
+func ExpireSubscriptions(store db.Store, interval time.Duration) Job {
+	return Job{
+		Name:     "expire_subscriptions",
+		Interval: interval,
+		Run: func(ctx context.Context, now time.Time) error {
+			expired, err := store.ExpireSubscriptions(ctx, now)
+			if err != nil {
+				return err
+			}
+			if len(expired) > 0 {
+				log.Printf("expired %d subscriptions", len(expired))
+			}
+			return nil
+		},
+	}
+}
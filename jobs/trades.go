@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+)
+
+// ExpireTradeOffers closes the open trade offers past their expiry
+func ExpireTradeOffers(store db.Store, interval time.Duration) Job {
+	return Job{
+		Name:     "expire_trade_offers",
+		Interval: interval,
+		Run: func(ctx context.Context, now time.Time) error {
+			expired, err := store.ExpireTradeOffersTx(ctx, now)
+			if err != nil {
+				return err
+			}
+			if len(expired) > 0 {
+				log.Printf("expired %d trade offers", len(expired))
+			}
+			return nil
+		},
+	}
+}
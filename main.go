@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
 
 	"github.com/sRRRs-7/GachaPon/api"
 	db "github.com/sRRRs-7/GachaPon/db/sqlc"
+	"github.com/sRRRs-7/GachaPon/jobs"
 	"github.com/sRRRs-7/GachaPon/utils"
 )
 
@@ -22,6 +24,7 @@ func main() {
 
 	store := db.NewStore(conn)
 
+	go runJobs(config, store)
 	runGinServer(config, store)
 
 	// go runGatewayServer(config, store)
@@ -41,6 +44,15 @@ func runGinServer(config utils.Config, store db.Store) {
 	}
 }
 
+func runJobs(config utils.Config, store db.Store) {
+	jobs.Run(context.Background(),
+		jobs.ExpireSubscriptions(store, config.SubscriptionExpiryInterval),
+		jobs.ExpireTradeOffers(store, config.TradeExpiryInterval),
+		jobs.RotateShop(store, config.ShopRotationInterval),
+		jobs.DeleteExpiredIdempotencyKeys(store, config.IdempotencyCleanupInterval),
+	)
+}
+
 // func runGrpcServer(config utils.Config, store db.Store) {
 // 	server, err := gapi.NewServer(config, store)
 // 	if err != nil {
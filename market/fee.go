@@ -0,0 +1,18 @@
+package market
+
+// MaxFeePercent is the highest fee the market can keep, a higher rate leaving
+// the seller nothing
+const MaxFeePercent = 100
+
+// Fee is the part of the price of a sale the market keeps at a rate in percent.
+// It is rounded down, so sales too cheap for the rate pay none, and rates out
+// of 0 to MaxFeePercent are clamped.
+func Fee(price, percent int64) int64 {
+	if price <= 0 || percent <= 0 {
+		return 0
+	}
+	if percent > MaxFeePercent {
+		percent = MaxFeePercent
+	}
+	return price * percent / 100
+}
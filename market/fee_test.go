@@ -0,0 +1,31 @@
+package market
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFee(t *testing.T) {
+	testCases := []struct {
+		name    string
+		price   int64
+		percent int64
+		fee     int64
+	}{
+		{name: "Rate", price: 1000, percent: 5, fee: 50},
+		{name: "RoundedDown", price: 119, percent: 5, fee: 5},
+		{name: "TooCheap", price: 19, percent: 5, fee: 0},
+		{name: "NoFee", price: 1000, percent: 0, fee: 0},
+		{name: "NegativeRate", price: 1000, percent: -5, fee: 0},
+		{name: "Clamped", price: 1000, percent: 150, fee: 1000},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fee := Fee(tc.price, tc.percent)
+			require.Equal(t, tc.fee, fee)
+			require.LessOrEqual(t, fee, tc.price)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package pass
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// MaxDays is the number of days of passes an account can hold ahead,
+// renewals going past it are refused
+const MaxDays = 90
+
+var ErrStackFull = errors.New("pass renewals can't go further ahead")
+
+// Pass is a subscription bought with balance that grants free currency every day it lasts
+type Pass struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Price is taken from the balance of the account
+	Price int64 `json:"price"`
+	// DailyAmount is the free currency a claim grants
+	DailyAmount int64 `json:"daily_amount"`
+	Days        int   `json:"days"`
+}
+
+var passes = map[string]Pass{
+	"monthly": {ID: "monthly", Name: "Monthly pass", Price: 500, DailyAmount: 100, Days: 30},
+}
+
+// Find returns the pass with the given id
+func Find(id string) (Pass, bool) {
+	p, ok := passes[id]
+	return p, ok
+}
+
+// List returns every pass on sale, cheapest first
+func List() []Pass {
+	list := make([]Pass, 0, len(passes))
+	for _, p := range passes {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Price < list[j].Price
+	})
+	return list
+}
+
+// Stack returns the period of a pass bought at now. A pass bought while
+// another one lasts starts when the last one expires, so renewals add their
+// days instead of overlapping. expiresAt is the expiry of the last pass of
+// the account, zero when it has none.
+func Stack(now, expiresAt time.Time, days int) (start, end time.Time, err error) {
+	start = now
+	if expiresAt.After(now) {
+		start = expiresAt
+	}
+	end = start.AddDate(0, 0, days)
+	if end.After(now.AddDate(0, 0, MaxDays)) {
+		return time.Time{}, time.Time{}, ErrStackFull
+	}
+	return start, end, nil
+}
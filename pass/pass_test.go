@@ -0,0 +1,77 @@
+package pass
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	p, ok := Find("monthly")
+	require.True(t, ok)
+	require.Equal(t, 30, p.Days)
+	require.Positive(t, p.DailyAmount)
+
+	_, ok = Find("yearly")
+	require.False(t, ok)
+}
+
+func TestList(t *testing.T) {
+	list := List()
+	require.Len(t, list, len(passes))
+	for i := 1; i < len(list); i++ {
+		require.LessOrEqual(t, list[i-1].Price, list[i].Price)
+	}
+}
+
+func TestStack(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		expiresAt time.Time
+		start     time.Time
+		err       error
+	}{
+		{
+			name:  "FirstPass",
+			start: now,
+		},
+		{
+			name:      "Expired",
+			expiresAt: now.Add(-time.Hour),
+			start:     now,
+		},
+		{
+			name:      "Renewal",
+			expiresAt: now.AddDate(0, 0, 12),
+			start:     now.AddDate(0, 0, 12),
+		},
+		{
+			name:      "UpToMaxDays",
+			expiresAt: now.AddDate(0, 0, MaxDays-30),
+			start:     now.AddDate(0, 0, MaxDays-30),
+		},
+		{
+			name:      "StackFull",
+			expiresAt: now.AddDate(0, 0, MaxDays-29),
+			err:       ErrStackFull,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			start, end, err := Stack(now, tc.expiresAt, 30)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.start, start)
+			require.Equal(t, tc.start.AddDate(0, 0, 30), end)
+		})
+	}
+}
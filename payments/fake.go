@@ -0,0 +1,74 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+const FakeProviderName = "fake"
+
+const minSecretSize = 16
+
+// FakeProvider accepts every checkout without collecting money.
+// Its webhooks are JSON encoded events signed with HMAC-SHA256, so local
+// tools and tests can play the provider by signing events with Sign.
+type FakeProvider struct {
+	secret []byte
+}
+
+// NewFakeProvider creates a fake provider verifying webhooks with the given secret
+func NewFakeProvider(webhookSecret string) (*FakeProvider, error) {
+	if len(webhookSecret) < minSecretSize {
+		return nil, fmt.Errorf("invalid webhook secret size: must be at least %d characters", minSecretSize)
+	}
+	return &FakeProvider{secret: []byte(webhookSecret)}, nil
+}
+
+func (p *FakeProvider) Name() string {
+	return FakeProviderName
+}
+
+func (p *FakeProvider) CreateCheckout(ctx context.Context, req CheckoutRequest) (Checkout, error) {
+	ref := fmt.Sprintf("fake_%d", req.OrderID)
+	return Checkout{
+		ProviderRef: ref,
+		PaymentURL:  "https://payments.invalid/checkout/" + ref,
+	}, nil
+}
+
+// Sign returns the signature of a webhook payload
+func (p *FakeProvider) Sign(payload []byte) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (p *FakeProvider) VerifyWebhook(payload []byte, signature string) (Event, error) {
+	var event Event
+
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return event, ErrInvalidSignature
+	}
+	want, _ := hex.DecodeString(p.Sign(payload))
+	if !hmac.Equal(got, want) {
+		return event, ErrInvalidSignature
+	}
+
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return event, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	switch event.Type {
+	case EventPaid, EventFailed, EventRefunded, EventChargeback:
+	default:
+		return event, ErrInvalidEvent
+	}
+	if event.OrderID <= 0 {
+		return event, ErrInvalidEvent
+	}
+	return event, nil
+}
@@ -0,0 +1,60 @@
+package payments
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/sRRRs-7/GachaPon/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeProviderWebhook(t *testing.T) {
+	provider, err := NewFakeProvider(utils.RandomString(32))
+	require.NoError(t, err)
+
+	pack, ok := FindPack("small")
+	require.True(t, ok)
+
+	checkout, err := provider.CreateCheckout(context.Background(), CheckoutRequest{OrderID: 7, Pack: pack})
+	require.NoError(t, err)
+	require.NotEmpty(t, checkout.ProviderRef)
+
+	event := Event{Type: EventPaid, OrderID: 7, ProviderRef: checkout.ProviderRef}
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	got, err := provider.VerifyWebhook(payload, provider.Sign(payload))
+	require.NoError(t, err)
+	require.Equal(t, event, got)
+
+	// a payload changed after signing is rejected
+	tampered, err := json.Marshal(Event{Type: EventPaid, OrderID: 8, ProviderRef: checkout.ProviderRef})
+	require.NoError(t, err)
+	_, err = provider.VerifyWebhook(tampered, provider.Sign(payload))
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = provider.VerifyWebhook(payload, "not hex")
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	other, err := NewFakeProvider(utils.RandomString(32))
+	require.NoError(t, err)
+	_, err = provider.VerifyWebhook(payload, other.Sign(payload))
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	unknown := []byte(`{"type":"payment.unknown","order_id":7}`)
+	_, err = provider.VerifyWebhook(unknown, provider.Sign(unknown))
+	require.ErrorIs(t, err, ErrInvalidEvent)
+}
+
+func TestNewProvider(t *testing.T) {
+	provider, err := NewProvider(FakeProviderName, utils.RandomString(32))
+	require.NoError(t, err)
+	require.Equal(t, FakeProviderName, provider.Name())
+
+	_, err = NewProvider(FakeProviderName, "short")
+	require.Error(t, err)
+
+	_, err = NewProvider("unknown", utils.RandomString(32))
+	require.Error(t, err)
+}
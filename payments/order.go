@@ -0,0 +1,53 @@
+package payments
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidTransition = errors.New("invalid order status transition")
+
+// statuses of a purchase order
+const (
+	StatusPending  = "pending"
+	StatusPaid     = "paid"
+	StatusCredited = "credited"
+	StatusFailed   = "failed"
+	StatusRefunded = "refunded"
+	// StatusChargedBack is a credited order the player disputed with the bank
+	StatusChargedBack = "charged_back"
+)
+
+// transitions lists the statuses an order can move to from each status.
+// A paid order is credited right away, only a credited order can be refunded
+// or charged back.
+var transitions = map[string][]string{
+	StatusPending:  {StatusPaid, StatusFailed},
+	StatusPaid:     {StatusCredited, StatusFailed},
+	StatusCredited: {StatusRefunded, StatusChargedBack},
+}
+
+// Transition checks that an order can move from one status to another
+func Transition(from, to string) error {
+	for _, next := range transitions[from] {
+		if next == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+}
+
+// EventStatus is the status an order reaches once an event is applied
+func EventStatus(event EventType) string {
+	switch event {
+	case EventPaid:
+		return StatusCredited
+	case EventFailed:
+		return StatusFailed
+	case EventRefunded:
+		return StatusRefunded
+	case EventChargeback:
+		return StatusChargedBack
+	}
+	return ""
+}
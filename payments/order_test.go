@@ -0,0 +1,53 @@
+package payments
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransition(t *testing.T) {
+	valid := [][2]string{
+		{StatusPending, StatusPaid},
+		{StatusPending, StatusFailed},
+		{StatusPaid, StatusCredited},
+		{StatusPaid, StatusFailed},
+		{StatusCredited, StatusRefunded},
+		{StatusCredited, StatusChargedBack},
+	}
+	for _, tr := range valid {
+		require.NoError(t, Transition(tr[0], tr[1]), "%s to %s", tr[0], tr[1])
+	}
+
+	invalid := [][2]string{
+		{StatusPending, StatusCredited},
+		{StatusPending, StatusRefunded},
+		{StatusCredited, StatusCredited},
+		{StatusCredited, StatusFailed},
+		{StatusFailed, StatusPaid},
+		{StatusRefunded, StatusCredited},
+		{StatusRefunded, StatusChargedBack},
+		{StatusPaid, StatusChargedBack},
+	}
+	for _, tr := range invalid {
+		require.ErrorIs(t, Transition(tr[0], tr[1]), ErrInvalidTransition, "%s to %s", tr[0], tr[1])
+	}
+}
+
+func TestListPacks(t *testing.T) {
+	list := ListPacks()
+	require.NotEmpty(t, list)
+
+	for i, pack := range list {
+		found, ok := FindPack(pack.ID)
+		require.True(t, ok)
+		require.Equal(t, pack, found)
+		require.Positive(t, pack.Amount)
+		if i > 0 {
+			require.GreaterOrEqual(t, pack.Price, list[i-1].Price)
+		}
+	}
+
+	_, ok := FindPack("missing")
+	require.False(t, ok)
+}
@@ -0,0 +1,39 @@
+package payments
+
+import "sort"
+
+// Pack is a bundle of balance sold for real money
+type Pack struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Amount is the balance credited to the account
+	Amount int64 `json:"amount"`
+	// Price is charged in the minor unit of the currency
+	Price    int64  `json:"price"`
+	Currency string `json:"currency"`
+}
+
+var packs = map[string]Pack{
+	"small":  {ID: "small", Name: "Small pack", Amount: 100, Price: 120, Currency: "JPY"},
+	"medium": {ID: "medium", Name: "Medium pack", Amount: 550, Price: 610, Currency: "JPY"},
+	"large":  {ID: "large", Name: "Large pack", Amount: 1200, Price: 1220, Currency: "JPY"},
+	"mega":   {ID: "mega", Name: "Mega pack", Amount: 6500, Price: 6100, Currency: "JPY"},
+}
+
+// FindPack returns the pack with the given id
+func FindPack(id string) (Pack, bool) {
+	pack, ok := packs[id]
+	return pack, ok
+}
+
+// ListPacks returns every pack on sale, cheapest first
+func ListPacks() []Pack {
+	list := make([]Pack, 0, len(packs))
+	for _, pack := range packs {
+		list = append(list, pack)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Price < list[j].Price
+	})
+	return list
+}
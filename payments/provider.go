@@ -0,0 +1,59 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidSignature = errors.New("webhook signature is invalid")
+var ErrInvalidEvent = errors.New("webhook event is invalid")
+
+// EventType is the kind of a payment event sent by a provider
+type EventType string
+
+const (
+	EventPaid     EventType = "payment.succeeded"
+	EventFailed   EventType = "payment.failed"
+	EventRefunded EventType = "payment.refunded"
+	// EventChargeback is sent when the bank of the player reverses a payment
+	EventChargeback EventType = "payment.chargeback"
+)
+
+// CheckoutRequest asks a provider to collect the price of a pack for an order
+type CheckoutRequest struct {
+	OrderID  int64
+	Username string
+	Pack     Pack
+}
+
+// Checkout is where the player pays an order
+type Checkout struct {
+	// ProviderRef identifies the payment at the provider
+	ProviderRef string `json:"provider_ref"`
+	PaymentURL  string `json:"payment_url"`
+}
+
+// Event is a verified webhook notification about the payment of an order
+type Event struct {
+	Type        EventType `json:"type"`
+	OrderID     int64     `json:"order_id"`
+	ProviderRef string    `json:"provider_ref"`
+}
+
+// PaymentProvider is an interface for collecting payments of purchase orders
+type PaymentProvider interface {
+	Name() string
+	CreateCheckout(ctx context.Context, req CheckoutRequest) (Checkout, error)
+	// VerifyWebhook checks the signature of a webhook payload and decodes its event
+	VerifyWebhook(payload []byte, signature string) (Event, error)
+}
+
+// NewProvider creates the payment provider configured by name
+func NewProvider(name string, webhookSecret string) (PaymentProvider, error) {
+	switch name {
+	case "", FakeProviderName:
+		return NewFakeProvider(webhookSecret)
+	}
+	return nil, fmt.Errorf("unsupported payment provider %s", name)
+}
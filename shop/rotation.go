@@ -0,0 +1,72 @@
+package shop
+
+import (
+	"time"
+
+	"github.com/sRRRs-7/GachaPon/gacha"
+)
+
+const (
+	// RotationSize is the number of offers of a daily rotation
+	RotationSize = 6
+	// RotationPerUserLimit is the number of copies a user can buy from a rotation offer
+	RotationPerUserLimit = 1
+)
+
+// prices is the price of a rotation offer by the rating of its item
+var prices = map[int32]int64{
+	1: 50,
+	2: 100,
+	3: 250,
+	4: 500,
+	5: 1000,
+	6: 2000,
+	7: 4000,
+}
+
+// Candidate is an item a rotation can offer
+type Candidate struct {
+	ItemID int64
+	Rating int32
+}
+
+// Offer is an item picked by a rotation with its price
+type Offer struct {
+	ItemID int64 `json:"item_id"`
+	Price  int64 `json:"price"`
+}
+
+// Price returns the price of a rotation offer of an item of the given rating.
+// Ratings outside of 1 to 7 can't be sold.
+func Price(rating int32) (int64, bool) {
+	price, ok := prices[rating]
+	return price, ok
+}
+
+// Rotate picks up to size distinct candidates, each with the same chance, and
+// prices them by rating. Candidates that can't be sold are left out.
+func Rotate(candidates []Candidate, size int, src gacha.Source) []Offer {
+	pool := make([]Candidate, 0, len(candidates))
+	for _, c := range candidates {
+		if _, ok := Price(c.Rating); ok {
+			pool = append(pool, c)
+		}
+	}
+
+	// partial Fisher-Yates shuffle of the first size candidates
+	var offers []Offer
+	for i := 0; i < size && i < len(pool); i++ {
+		j := i + int(src.Float64()*float64(len(pool)-i))
+		pool[i], pool[j] = pool[j], pool[i]
+
+		price, _ := Price(pool[i].Rating)
+		offers = append(offers, Offer{ItemID: pool[i].ItemID, Price: price})
+	}
+	return offers
+}
+
+// Day returns the rotation day of t, the midnight UTC it starts at
+func Day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,89 @@
+package shop
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sRRRs-7/GachaPon/gacha"
+	"github.com/stretchr/testify/require"
+)
+
+func testCandidates(n int) []Candidate {
+	candidates := make([]Candidate, n)
+	for i := range candidates {
+		candidates[i] = Candidate{ItemID: int64(i + 1), Rating: int32(i%7 + 1)}
+	}
+	return candidates
+}
+
+func TestPrice(t *testing.T) {
+	// rarer items always cost more
+	for rating := int32(2); rating <= 7; rating++ {
+		higher, ok := Price(rating)
+		require.True(t, ok)
+		lower, _ := Price(rating - 1)
+		require.Greater(t, higher, lower)
+	}
+
+	_, ok := Price(0)
+	require.False(t, ok)
+	_, ok = Price(8)
+	require.False(t, ok)
+}
+
+func TestRotate(t *testing.T) {
+	candidates := testCandidates(20)
+	src := &gacha.FairSource{ServerSeed: "server", ClientSeed: "client"}
+
+	offers := Rotate(candidates, RotationSize, src)
+	require.Len(t, offers, RotationSize)
+
+	seen := make(map[int64]bool)
+	for _, offer := range offers {
+		require.False(t, seen[offer.ItemID])
+		seen[offer.ItemID] = true
+
+		rating := int32((offer.ItemID-1)%7 + 1)
+		price, _ := Price(rating)
+		require.Equal(t, price, offer.Price)
+	}
+
+	// the same rolls pick the same offers
+	again := Rotate(testCandidates(20), RotationSize, &gacha.FairSource{ServerSeed: "server", ClientSeed: "client"})
+	require.Equal(t, offers, again)
+}
+
+func TestRotateSmallCatalog(t *testing.T) {
+	candidates := append(testCandidates(3), Candidate{ItemID: 99, Rating: 0})
+	src := &gacha.FairSource{ServerSeed: "server", ClientSeed: "client"}
+
+	offers := Rotate(candidates, RotationSize, src)
+	require.Len(t, offers, 3)
+	for _, offer := range offers {
+		require.NotEqual(t, int64(99), offer.ItemID)
+	}
+
+	require.Empty(t, Rotate(nil, RotationSize, src))
+}
+
+func TestRotateEvenly(t *testing.T) {
+	candidates := testCandidates(10)
+	src := &gacha.FairSource{ServerSeed: "server", ClientSeed: "even"}
+
+	counts := make(map[int64]int)
+	n := 10000
+	for i := 0; i < n; i++ {
+		for _, offer := range Rotate(candidates, 1, src) {
+			counts[offer.ItemID]++
+		}
+	}
+
+	for _, c := range candidates {
+		require.InDelta(t, n/len(candidates), counts[c.ItemID], float64(n/len(candidates))*0.15)
+	}
+}
+
+func TestDay(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	require.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Day(time.Date(2024, 3, 2, 8, 59, 0, 0, jst)))
+}
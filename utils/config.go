@@ -12,6 +12,26 @@ type Config struct {
 	HttpServerAddress    string        `mapstructure:"HTTP_SERVER_ADDRESS"`
 	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	PaymentProvider      string        `mapstructure:"PAYMENT_PROVIDER"`
+	PaymentWebhookSecret string        `mapstructure:"PAYMENT_WEBHOOK_SECRET"`
+	ReversalRevokeItems  bool          `mapstructure:"REVERSAL_REVOKE_ITEMS"`
+	// SubscriptionExpiryInterval is how often expired passes are swept, 0 disables the job
+	SubscriptionExpiryInterval time.Duration `mapstructure:"SUBSCRIPTION_EXPIRY_INTERVAL"`
+	// TradeOfferTTL is how long trade offers stay open
+	TradeOfferTTL time.Duration `mapstructure:"TRADE_OFFER_TTL"`
+	// TradeExpiryInterval is how often expired trade offers are swept, 0 disables the job
+	TradeExpiryInterval time.Duration `mapstructure:"TRADE_EXPIRY_INTERVAL"`
+	// ShopRotationInterval is how often the shop checks the day was rotated, 0 disables the job
+	ShopRotationInterval time.Duration `mapstructure:"SHOP_ROTATION_INTERVAL"`
+	// IdempotencyKeyTTL is how long the responses of idempotency keys are kept
+	IdempotencyKeyTTL time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"`
+	// IdempotencyCleanupInterval is how often expired idempotency keys are swept, 0 disables the job
+	IdempotencyCleanupInterval time.Duration `mapstructure:"IDEMPOTENCY_CLEANUP_INTERVAL"`
+	// TimezoneCooldown is how long a user waits between two changes of time zone,
+	// which would otherwise let the daily login reward be claimed twice a day
+	TimezoneCooldown time.Duration `mapstructure:"TIMEZONE_COOLDOWN"`
+	// MarketFeePercent is the part of the price of a market sale kept from its seller
+	MarketFeePercent int64 `mapstructure:"MARKET_FEE_PERCENT"`
 	// GrpcServerAddress    string        `mapstructure:"GRPC_SERVER_ADDRESS"`
 }
 